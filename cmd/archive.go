@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"lab3/internal/services/service_interfaces"
+	"strconv"
+)
+
+const archiveUsage = "Использование: archive [число месяцев]"
+
+// RunArchive runs the "archive" command: it moves the orders closed more than the given number of months ago
+// to the archive. Without an argument the number of months is taken from the configuration.
+func RunArchive(orderService service_interfaces.IOrderService, months int, args []string) error {
+	if len(args) > 1 {
+		return errors.New(archiveUsage)
+	}
+
+	if len(args) == 1 {
+		var err error
+		months, err = strconv.Atoi(args[0])
+		if err != nil {
+			return errors.New(archiveUsage)
+		}
+	}
+
+	if months <= 0 {
+		return errors.New("Не задан срок архивации: укажите число месяцев или archive.after_months в конфигурации")
+	}
+
+	count, err := orderService.Archive(months)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Перенесено в архив заказов, закрытых более %d мес. назад: %d\n", months, count)
+	return nil
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"os"
+	"text/tabwriter"
+)
+
+const migrateUsage = "Использование: migrate up|down|status"
+
+// RunMigrate runs the "migrate" command: "up" applies pending migrations, "down" reverts the latest one
+// and "status" lists all migrations.
+func RunMigrate(migrator repository_interfaces.IMigrator, args []string) error {
+	if len(args) != 1 {
+		return errors.New(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			fmt.Printf("Применена миграция %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("Нет новых миграций")
+		}
+	case "down":
+		migration, err := migrator.Down()
+		if errors.Is(err, repository_errors.DoesNotExist) {
+			fmt.Println("Нет примененных миграций")
+			return nil
+		} else if err != nil {
+			return err
+		}
+		fmt.Printf("Отменена миграция %04d_%s\n", migration.Version, migration.Name)
+	case "status":
+		migrations, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+
+		t := new(tabwriter.Writer)
+		t.Init(os.Stdout, 1, 4, 2, ' ', 0)
+		fmt.Fprintf(t, " %s\t%s\t%s\n", "Версия", "Название", "Применена")
+		for _, migration := range migrations {
+			appliedAt := "нет"
+			if migration.Applied {
+				appliedAt = migration.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(t, " %04d\t%s\t%s\n", migration.Version, migration.Name, appliedAt)
+		}
+		return t.Flush()
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	return nil
+}
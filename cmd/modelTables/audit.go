@@ -0,0 +1,47 @@
+package modelTables
+
+import (
+	"fmt"
+	"lab3/cmd/cmdUtils"
+	"lab3/internal/models"
+	"lab3/internal/registry"
+	"os"
+	"text/tabwriter"
+)
+
+func AuditEntries(services registry.Services, entries []models.AuditEntry) error {
+	var err error
+
+	t := new(tabwriter.Writer)
+	t.Init(os.Stdout, 1, 4, 2, ' ', 0)
+
+	_, err = fmt.Fprintf(t, "\n %s\t%s\t%s\t%s\t%s\t%s\n",
+		"Время", "Менеджер", "Действие", "Объект", "IP", "Изменения")
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	actors := make(map[string]string)
+	for _, entry := range entries {
+		actor, ok := actors[entry.ActorID.String()]
+		if !ok {
+			actor = entry.ActorID.String()
+			worker, workerErr := services.WorkerService.GetWorkerByID(entry.ActorID)
+			if workerErr == nil {
+				actor = worker.FullName()
+			}
+			actors[entry.ActorID.String()] = actor
+		}
+
+		fmt.Fprintf(t, " %s\t%s\t%s\t%s %s\t%s\t%s -> %s\n",
+			entry.CreatedAt.Format("2006-01-02 15:04:05"), actor, models.AuditActions[entry.Action], entry.TargetType, entry.TargetID, entry.IP,
+			cmdUtils.TruncateString(entry.Before, 60), cmdUtils.TruncateString(entry.After, 60))
+	}
+
+	err = t.Flush()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
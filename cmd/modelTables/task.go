@@ -8,9 +8,17 @@ import (
 	"text/tabwriter"
 )
 
-func Tasks(tasks []models.Task) error {
+// Tasks prints the tasks with the names of their categories from categoryNames.
+func Tasks(tasks []models.Task, categoryNames map[int]string) error {
 	var err error
 
+	categoryName := func(category int) string {
+		if name, ok := categoryNames[category]; ok {
+			return name
+		}
+		return "Неизвестная категория"
+	}
+
 	maxNameLen, maxPriceLen, maxCategoryLen := 0, 0, 0
 	for _, task := range tasks {
 		if len(task.Name) > maxNameLen {
@@ -20,7 +28,7 @@ func Tasks(tasks []models.Task) error {
 		if priceLen > maxPriceLen {
 			maxPriceLen = priceLen
 		}
-		categoryLen := len(models.GetCategoryName(task.Category))
+		categoryLen := len(categoryName(task.Category))
 		if categoryLen > maxCategoryLen {
 			maxCategoryLen = categoryLen
 		}
@@ -39,7 +47,7 @@ func Tasks(tasks []models.Task) error {
 
 	for i, task := range tasks {
 		_, err = fmt.Fprintf(t, "\n %d\t%s\t%.2f\t%s\t",
-			i+1, cmdUtils.TruncateString(task.Name, 27), task.PricePerSingle, cmdUtils.TruncateString(models.GetCategoryName(task.Category), 27))
+			i+1, cmdUtils.TruncateString(task.Name, 27), task.PricePerSingle, cmdUtils.TruncateString(categoryName(task.Category), 27))
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,57 @@
+package auditViews
+
+import (
+	"fmt"
+	utils "lab3/cmd/cmdUtils"
+	"lab3/cmd/modelTables"
+	"lab3/internal/models"
+	"lab3/internal/registry"
+	"time"
+)
+
+// cliAddress stands for the IP address of actions made from the console.
+const cliAddress = "cli"
+
+const auditListLimit = 50
+
+// Record writes an audit entry for an action of a manager made from the console.
+func Record(services registry.Services, actor *models.Worker, action string, targetType string, targetID string, before interface{}, after interface{}) {
+	if actor.Role != models.ManagerRole {
+		return
+	}
+
+	err := services.AuditService.Record(actor, cliAddress, action, targetType, targetID, before, after)
+	if err != nil {
+		fmt.Println("Не удалось записать действие в журнал:", err)
+	}
+}
+
+func AuditLog(services registry.Services) error {
+	fmt.Print("Действия:\n")
+	for action, name := range models.AuditActions {
+		fmt.Printf("  %s -- %s\n", action, name)
+	}
+
+	filter := models.AuditFilter{Limit: auditListLimit}
+	filter.Action = utils.EndlessReadRow("Введите действие или \"-\", чтобы показать все")
+	if filter.Action == "-" {
+		filter.Action = ""
+	}
+
+	days := utils.EndlessReadInt("Введите количество дней, за которые показать записи (0 -- за все время)")
+	if days > 0 {
+		filter.From = time.Now().AddDate(0, 0, -days)
+	}
+
+	entries, err := services.AuditService.Filter(filter)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		fmt.Print("Записей не найдено\n\n")
+		return nil
+	}
+
+	return modelTables.AuditEntries(services, entries)
+}
@@ -6,7 +6,7 @@ import (
 )
 
 func CancelOrder(services registry.Services, order *models.Order) error {
-	_, err := services.OrderService.Update(order.ID, models.CancelledOrderStatus, order.Rate, order.WorkerID)
+	_, err := services.OrderService.Update(order.ID, models.CancelledOrderStatus, order.Rate, order.WorkerID, order.Version)
 	if err != nil {
 		return err
 	}
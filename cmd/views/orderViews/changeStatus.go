@@ -55,7 +55,7 @@ func changeStatus(services registry.Services, order *models.Order) error {
 		return nil
 	}
 
-	_, err = services.OrderService.Update(order.ID, newStatus, order.Rate, order.WorkerID)
+	_, err = services.OrderService.Update(order.ID, newStatus, order.Rate, order.WorkerID, order.Version)
 	if err != nil {
 		return err
 	}
@@ -3,11 +3,12 @@ package orderViews
 import (
 	"fmt"
 	"lab3/cmd/modelTables"
+	"lab3/cmd/views/auditViews"
 	"lab3/internal/models"
 	"lab3/internal/registry"
 )
 
-func GetUnassignedOrder(services registry.Services, order *models.Order) error {
+func GetUnassignedOrder(services registry.Services, manager *models.Worker, order *models.Order) error {
 	tasks, err := services.OrderService.GetTasksInOrder(order.ID)
 	if err != nil {
 		return err
@@ -35,12 +36,12 @@ func GetUnassignedOrder(services registry.Services, order *models.Order) error {
 		if action == 1 {
 			return CancelOrder(services, order)
 		} else if action == 2 {
-			return assignWorker(services, order)
+			return assignWorker(services, manager, order)
 		}
 	}
 }
 
-func assignWorker(services registry.Services, order *models.Order) error {
+func assignWorker(services registry.Services, manager *models.Worker, order *models.Order) error {
 	workers, err := services.WorkerService.GetWorkersByRole(models.MasterRole)
 	if err != nil {
 		return err
@@ -70,11 +71,13 @@ func assignWorker(services registry.Services, order *models.Order) error {
 			continue
 		}
 
+		before := *order
 		order.WorkerID = workers[workerNumber-1].ID
-		_, err = services.OrderService.Update(order.ID, order.Status, order.Rate, order.WorkerID)
+		updatedOrder, err := services.OrderService.Update(order.ID, order.Status, order.Rate, order.WorkerID, order.Version)
 		if err != nil {
 			fmt.Println(err)
 		} else {
+			auditViews.Record(services, manager, models.AuditOrderReassign, models.AuditTargetOrder, order.ID.String(), &before, updatedOrder)
 			fmt.Println("Работник назначен")
 			return nil
 		}
@@ -9,4 +9,3 @@ const AddressRequest = "Введите адрес"
 const RoleRequest = "Введите роль (1 - менеджер, 2 - мастер)"
 
 const PriceRequest = "Введите цену"
-const CategoryRequest = "Введите категорию"
@@ -2,20 +2,26 @@ package taskViews
 
 import (
 	utils "lab3/cmd/cmdUtils"
+	"lab3/cmd/views/auditViews"
 	"lab3/cmd/views/stringConst"
+	"lab3/internal/models"
 	"lab3/internal/registry"
 )
 
-func Create(services registry.Services) error {
+func Create(services registry.Services, manager *models.Worker) error {
 	var name = utils.EndlessReadWord(stringConst.NameRequest)
 	var price = utils.EndlessReadFloat64(stringConst.PriceRequest)
-	var category = utils.EndlessReadInt(stringConst.CategoryRequest)
+	var category = ChooseTaskCategory(services)
+	var taxRate = ChooseTaxRate(services)
 
-	_, err := services.TaskService.Create(name, price, category)
+	task, err := services.TaskService.Create(name, price, category, taxRate)
 	if err != nil {
 		println(err.Error())
+		return nil
 	}
 
+	auditViews.Record(services, manager, models.AuditTaskCreate, models.AuditTargetTask, task.ID.String(), nil, task)
+
 	println("Услуга успешно создана")
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"lab3/cmd/modelTables"
 	"lab3/internal/models"
 	"lab3/internal/registry"
+	"strings"
 )
 
 func AllTasks(services registry.Services) error {
@@ -12,7 +13,7 @@ func AllTasks(services registry.Services) error {
 	if err != nil {
 		return err
 	}
-	return modelTables.Tasks(tasks)
+	return modelTables.Tasks(tasks, CategoryNames(services))
 }
 
 func TasksByCategory(service registry.Services, category int) ([]models.Task, error) {
@@ -24,21 +25,66 @@ func TasksByCategory(service registry.Services, category int) ([]models.Task, er
 	return tasks, err
 }
 
-func ChooseTaskCategory() int {
+// CategoryNames maps the ids of the categories, the archived ones too, to their names.
+func CategoryNames(services registry.Services) map[int]string {
+	names := make(map[int]string)
+
+	categories, _ := services.CategoryService.GetAll()
+	archived, _ := services.CategoryService.GetDeleted()
+	for _, category := range append(categories, archived...) {
+		names[category.ID] = category.Name
+	}
+
+	return names
+}
+
+// ChooseTaskCategory returns the id of the chosen category, or 0 when there are no categories.
+func ChooseTaskCategory(services registry.Services) int {
+	categories, err := services.CategoryService.Catalog(true)
+	if err != nil || len(categories) == 0 {
+		fmt.Println("Нет доступных категорий")
+		return 0
+	}
+
 	fmt.Println("Выберите категорию задачи:")
 
-	for i, category := range models.TaskCategories {
-		fmt.Printf("%d. %s\n", i+1, category)
+	for i, category := range categories {
+		fmt.Printf("%d. %s%s\n", i+1, strings.Repeat("  ", category.Depth), category.Name)
 	}
 
-	var category int
+	var number int
 	for {
-		fmt.Scanf("%d", &category)
-		//print(len(models.TaskCategories), category)
-		if category < 1 || category > len(models.TaskCategories) {
+		fmt.Scanf("%d", &number)
+		if number < 1 || number > len(categories) {
 			fmt.Println("Неверный номер категории")
 		} else {
-			return category
+			return categories[number-1].ID
+		}
+	}
+}
+
+// ChooseTaxRate returns the code of the chosen tax rate, an empty code gives the task the rate of its category.
+func ChooseTaxRate(services registry.Services) string {
+	rates := services.OrderService.TaxRates()
+	if len(rates) == 0 {
+		return ""
+	}
+
+	fmt.Println("Выберите ставку НДС:")
+	fmt.Println("0. Как у категории")
+	for i, rate := range rates {
+		fmt.Printf("%d. %s\n", i+1, rate.Name)
+	}
+
+	var number int
+	for {
+		fmt.Scanf("%d", &number)
+		if number < 0 || number > len(rates) {
+			fmt.Println("Неверный номер ставки")
+		} else if number == 0 {
+			return ""
+		} else {
+			return rates[number-1].Code
 		}
 	}
 }
@@ -61,9 +107,9 @@ func Tasks(services registry.Services) ([]models.Task, error) {
 			tasks, err = services.TaskService.GetAllTasks()
 			err = AllTasks(services)
 		case 2:
-			category := ChooseTaskCategory()
+			category := ChooseTaskCategory(services)
 			tasks, err = TasksByCategory(services, category)
-			err = modelTables.Tasks(tasks)
+			err = modelTables.Tasks(tasks, CategoryNames(services))
 		default:
 			fmt.Println("Такого пункта в меню нету")
 		}
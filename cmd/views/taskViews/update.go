@@ -3,18 +3,25 @@ package taskViews
 import (
 	"fmt"
 	utils "lab3/cmd/cmdUtils"
+	"lab3/cmd/views/auditViews"
 	"lab3/cmd/views/stringConst"
 	"lab3/internal/models"
 	"lab3/internal/registry"
 )
 
-func Update(services registry.Services, task models.Task) (*models.Task, error) {
+func Update(services registry.Services, manager *models.Worker, task models.Task) (*models.Task, error) {
 	var name = utils.EndlessReadRow(stringConst.NameRequest)
 	var price = utils.EndlessReadFloat64(stringConst.PriceRequest)
-	var category = utils.EndlessReadInt(stringConst.CategoryRequest)
+	var category = ChooseTaskCategory(services)
+	var taxRate = ChooseTaxRate(services)
 
-	updatedTask, err := services.TaskService.Update(task.ID, category, name, price)
+	updatedTask, err := services.TaskService.Update(task.ID, category, name, price, taxRate)
+	if err != nil {
+		return nil, err
+	}
+
+	auditViews.Record(services, manager, models.AuditTaskUpdate, models.AuditTargetTask, task.ID.String(), &task, updatedTask)
 
 	fmt.Println("Услуга успешно обновлена")
-	return updatedTask, err
+	return updatedTask, nil
 }
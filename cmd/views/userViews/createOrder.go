@@ -92,7 +92,7 @@ func createOrder(service registry.Services, user *models.User) error {
 		orderedTasks = addTaskToCart(models.OrderedTask{Task: &tasks[taskNum-1], Quantity: amount}, orderedTasks)
 	}
 
-	_, err = service.OrderService.CreateOrder(user.ID, address, deadline, orderedTasks)
+	_, err = service.OrderService.CreateOrder(user.ID, address, deadline, orderedTasks, "")
 
 	if err == nil {
 		fmt.Println("Заказ успешно создан\nДобавлены следующие услуги:")
@@ -31,7 +31,7 @@ func getCompletedOrders(services registry.Services, user *models.User) error {
 		"user_id": user.ID.String(),
 	}
 
-	orders, err := services.OrderService.Filter(params)
+	orders, err := services.OrderService.History(params)
 
 	if err != nil {
 		return err
@@ -142,7 +142,7 @@ func rateOrder(services registry.Services, order *models.Order) error {
 	}
 
 	order.Rate = rate
-	_, err = services.OrderService.Update(order.ID, order.Status, rate, order.WorkerID)
+	_, err = services.OrderService.Update(order.ID, order.Status, rate, order.WorkerID, order.Version)
 	if err != nil {
 		return err
 	}
@@ -31,7 +31,15 @@ func registration(services registry.Services) (*models.User, error) {
 		return nil, err
 	}
 
-	fmt.Printf("Пользователь %s %s успешно зарегистрирован\n\n\n", user.Name, user.Surname)
+	fmt.Printf("Пользователь %s %s успешно зарегистрирован\n", user.Name, user.Surname)
+
+	// the same mail the web sign up sends, a failed one can be sent again from the profile
+	err = services.EmailVerificationService.SendUserVerification(user.ID)
+	if err != nil {
+		fmt.Printf("Не удалось отправить письмо для подтверждения email: %v\n\n\n", err)
+	} else {
+		fmt.Printf("Ссылка для подтверждения email отправлена на %s\n\n\n", user.Email)
+	}
 
 	return user, nil
 }
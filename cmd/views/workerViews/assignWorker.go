@@ -38,7 +38,7 @@ func assignWorker(services registry.Services, order *models.Order) error {
 		}
 
 		order.WorkerID = workers[workerNumber-1].ID
-		_, err = services.OrderService.Update(order.ID, order.Status, order.Rate, order.WorkerID)
+		_, err = services.OrderService.Update(order.ID, order.Status, order.Rate, order.WorkerID, order.Version)
 		if err != nil {
 			fmt.Println(err)
 		} else {
@@ -3,12 +3,13 @@ package workerViews
 import (
 	"fmt"
 	utils "lab3/cmd/cmdUtils"
+	"lab3/cmd/views/auditViews"
 	"lab3/cmd/views/stringConst"
 	"lab3/internal/models"
 	"lab3/internal/registry"
 )
 
-func create(services registry.Services) error {
+func create(services registry.Services, manager *models.Worker) error {
 	var worker *models.Worker
 	var err error
 
@@ -41,6 +42,8 @@ func create(services registry.Services) error {
 		return err
 	}
 
+	auditViews.Record(services, manager, models.AuditWorkerCreate, models.AuditTargetWorker, worker.ID.String(), nil, worker)
+
 	fmt.Printf("%s %s %s успешно зарегистрирован\n\n\n", worker.DisplayRole(), worker.Name, worker.Surname)
 
 	return nil
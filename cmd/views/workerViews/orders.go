@@ -24,7 +24,7 @@ func validateOrderNumber(orderNumber int, orders []models.Order) bool {
 	return orderNumber >= 0 && orderNumber < len(orders)
 }
 
-func unassignedOrders(services registry.Services) error {
+func unassignedOrders(services registry.Services, manager *models.Worker) error {
 	params := map[string]string{
 		"worker_id": "null",
 	}
@@ -55,7 +55,7 @@ func unassignedOrders(services registry.Services) error {
 		return nil
 	}
 
-	return orderViews.GetUnassignedOrder(services, &orders[orderNumber-1])
+	return orderViews.GetUnassignedOrder(services, manager, &orders[orderNumber-1])
 }
 
 func completedOrders(services registry.Services) error {
@@ -63,7 +63,7 @@ func completedOrders(services registry.Services) error {
 		"status": "3",
 	}
 
-	orders, err := services.OrderService.Filter(params)
+	orders, err := services.OrderService.History(params)
 
 	if err != nil {
 		return err
@@ -168,7 +168,7 @@ func completedOrdersByWorker(services registry.Services, worker *models.Worker)
 		"worker_id": worker.ID.String(),
 	}
 
-	orders, err := services.OrderService.Filter(params)
+	orders, err := services.OrderService.History(params)
 
 	if err != nil {
 		return err
@@ -9,12 +9,12 @@ import (
 	"lab3/internal/registry"
 )
 
-func pickTaskForEditing(services registry.Services, tasks []models.Task) error {
+func pickTaskForEditing(services registry.Services, manager *models.Worker, tasks []models.Task) error {
 	var err error
 	var taskID int
 
 	for {
-		err = modelTables.Tasks(tasks)
+		err = modelTables.Tasks(tasks, taskViews.CategoryNames(services))
 		if err != nil {
 			return err
 		}
@@ -27,7 +27,7 @@ func pickTaskForEditing(services registry.Services, tasks []models.Task) error {
 		}
 
 		if taskID > 0 && taskID <= len(tasks) {
-			updatedTask, updErr := taskViews.Update(services, tasks[taskID-1])
+			updatedTask, updErr := taskViews.Update(services, manager, tasks[taskID-1])
 			if updErr != nil {
 				fmt.Println(updErr.Error())
 			} else {
@@ -39,7 +39,7 @@ func pickTaskForEditing(services registry.Services, tasks []models.Task) error {
 	}
 }
 
-func managerTasks(services registry.Services) error {
+func managerTasks(services registry.Services, manager *models.Worker) error {
 	var m menu.Menu
 	m.CreateMenu(
 		[]menu.Item{
@@ -50,24 +50,24 @@ func managerTasks(services registry.Services) error {
 					if err != nil {
 						fmt.Println(err.Error())
 					}
-					return pickTaskForEditing(services, tasks)
+					return pickTaskForEditing(services, manager, tasks)
 				},
 			},
 			{
 				Name: "Просмотреть по категории",
 				Handler: func() error {
-					category := taskViews.ChooseTaskCategory()
+					category := taskViews.ChooseTaskCategory(services)
 					tasks, err := taskViews.TasksByCategory(services, category)
 					if err != nil {
 						fmt.Println(err.Error())
 					}
-					return pickTaskForEditing(services, tasks)
+					return pickTaskForEditing(services, manager, tasks)
 				},
 			},
 			{
 				Name: "Создать новую услугу",
 				Handler: func() error {
-					return taskViews.Create(services)
+					return taskViews.Create(services, manager)
 				},
 			},
 		},
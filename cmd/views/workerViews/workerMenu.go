@@ -3,6 +3,7 @@ package workerViews
 import (
 	"fmt"
 	"lab3/cmd/menu"
+	"lab3/cmd/views/auditViews"
 	"lab3/internal/models"
 	"lab3/internal/registry"
 )
@@ -65,13 +66,13 @@ func managerMainMenu(services registry.Services, worker *models.Worker) error {
 			{
 				Name: "Добавить работника",
 				Handler: func() error {
-					return create(services)
+					return create(services, worker)
 				},
 			},
 			{
 				Name: "Посмотреть неназначенные заказы",
 				Handler: func() error {
-					return unassignedOrders(services)
+					return unassignedOrders(services, worker)
 				},
 			},
 			{
@@ -89,7 +90,13 @@ func managerMainMenu(services registry.Services, worker *models.Worker) error {
 			{
 				Name: "База услуг",
 				Handler: func() error {
-					return managerTasks(services)
+					return managerTasks(services, worker)
+				},
+			},
+			{
+				Name: "Журнал действий",
+				Handler: func() error {
+					return auditViews.AuditLog(services)
 				},
 			},
 		})
@@ -1,17 +1,147 @@
 package config
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
+type SessionConfig struct {
+	Key             string        `mapstructure:"key"`
+	IdleTimeout     time.Duration `mapstructure:"idle_timeout"`
+	AbsoluteTimeout time.Duration `mapstructure:"absolute_timeout"`
+}
+
+type SignInConfig struct {
+	MaxAttempts     int           `mapstructure:"max_attempts"`
+	IPMaxAttempts   int           `mapstructure:"ip_max_attempts"`
+	FreeAttempts    int           `mapstructure:"free_attempts"`
+	BaseDelay       time.Duration `mapstructure:"base_delay"`
+	MaxDelay        time.Duration `mapstructure:"max_delay"`
+	LockoutDuration time.Duration `mapstructure:"lockout_duration"`
+	Window          time.Duration `mapstructure:"window"`
+}
+
+type MailConfig struct {
+	Sender   string `mapstructure:"sender"`
+	Host     string `mapstructure:"host"`
+	Port     string `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+}
+
+type Argon2idConfig struct {
+	Memory      uint32 `mapstructure:"memory"`
+	Iterations  uint32 `mapstructure:"iterations"`
+	Parallelism uint8  `mapstructure:"parallelism"`
+	SaltLength  uint32 `mapstructure:"salt_length"`
+	KeyLength   uint32 `mapstructure:"key_length"`
+}
+
+type PasswordHashConfig struct {
+	Algorithm  string         `mapstructure:"algorithm"`
+	BcryptCost int            `mapstructure:"bcrypt_cost"`
+	Argon2id   Argon2idConfig `mapstructure:"argon2id"`
+}
+
+// MemoryConfig configures dbtype "memory". SnapshotFile is loaded on start when it exists,
+// with SaveSnapshot every change is also written to it.
+type MemoryConfig struct {
+	SnapshotFile string `mapstructure:"snapshot_file"`
+	SaveSnapshot bool   `mapstructure:"save_snapshot"`
+}
+
+// SQLiteConfig configures dbtype "sqlite". The database file at Path is created when it does not exist.
+// A write waits up to BusyTimeout for the lock held by another writer.
+type SQLiteConfig struct {
+	Path        string        `mapstructure:"path"`
+	BusyTimeout time.Duration `mapstructure:"busy_timeout"`
+}
+
+// MongoConfig configures the connection of dbtype "mongodb", the address and the credentials
+// are taken from DbConnectionFlags. Timeout limits every operation, zero leaves them unlimited.
+type MongoConfig struct {
+	MaxPoolSize            uint64        `mapstructure:"max_pool_size"`
+	MinPoolSize            uint64        `mapstructure:"min_pool_size"`
+	MaxConnIdleTime        time.Duration `mapstructure:"max_conn_idle_time"`
+	ConnectTimeout         time.Duration `mapstructure:"connect_timeout"`
+	ServerSelectionTimeout time.Duration `mapstructure:"server_selection_timeout"`
+	Timeout                time.Duration `mapstructure:"timeout"`
+	TLS                    bool          `mapstructure:"tls"`
+	Retry                  RetryConfig   `mapstructure:"retry"`
+}
+
+// CacheConfig configures the read-through caches of the task catalog and the categories.
+// A cache keeps at most MaxEntries results, each for TTL.
+type CacheConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	TTL        time.Duration `mapstructure:"ttl"`
+	MaxEntries int           `mapstructure:"max_entries"`
+}
+
+// ArchiveConfig configures the archiving of closed orders. Every Interval the orders completed or cancelled
+// more than AfterMonths months ago are moved to the archive, zero AfterMonths turns the job off.
+type ArchiveConfig struct {
+	AfterMonths int           `mapstructure:"after_months"`
+	Interval    time.Duration `mapstructure:"interval"`
+}
+
+// InvoiceConfig holds the requisites of the company printed on invoices. An invoice keeps the requisites
+// it was issued with.
+type InvoiceConfig struct {
+	Company              string `mapstructure:"company"`
+	INN                  string `mapstructure:"inn"`
+	KPP                  string `mapstructure:"kpp"`
+	Address              string `mapstructure:"address"`
+	Bank                 string `mapstructure:"bank"`
+	BIC                  string `mapstructure:"bic"`
+	Account              string `mapstructure:"account"`
+	CorrespondentAccount string `mapstructure:"correspondent_account"`
+}
+
+// TaxRateConfig is a VAT rate that categories and tasks refer to by Code, Rate is in percent.
+type TaxRateConfig struct {
+	Code string  `mapstructure:"code"`
+	Name string  `mapstructure:"name"`
+	Rate float64 `mapstructure:"rate"`
+}
+
+// TaxConfig configures the VAT of orders. Categories and tasks without a rate take DefaultRate. The tax is
+// included in the prices of the catalog when PricesIncludeTax, otherwise it is added to them. Without Rates
+// orders are not taxed.
+type TaxConfig struct {
+	PricesIncludeTax bool            `mapstructure:"prices_include_tax"`
+	DefaultRate      string          `mapstructure:"default_rate"`
+	Rates            []TaxRateConfig `mapstructure:"rates"`
+}
+
 type Config struct {
-	DBFlags  DbConnectionFlags `mapstructure:"postgres"`
-	Address  string            `mapstructure:"address"`
-	Port     string            `mapstructure:"port"`
-	LogLevel string            `mapstructure:"loglevel"`
-	LogFile  string            `mapstructure:"logfile"`
-	Mode     string            `mapstructure:"mode"`
-	DBType   string            `mapstructure:"dbtype"`
+	DBFlags              DbConnectionFlags  `mapstructure:"postgres"`
+	Memory               MemoryConfig       `mapstructure:"memory"`
+	SQLite               SQLiteConfig       `mapstructure:"sqlite"`
+	Mongo                MongoConfig        `mapstructure:"mongodb"`
+	Cache                CacheConfig        `mapstructure:"cache"`
+	Archive              ArchiveConfig      `mapstructure:"archive"`
+	Invoice              InvoiceConfig      `mapstructure:"invoice"`
+	Tax                  TaxConfig          `mapstructure:"tax"`
+	Session              SessionConfig      `mapstructure:"session"`
+	SignIn               SignInConfig       `mapstructure:"signin"`
+	Mail                 MailConfig         `mapstructure:"mail"`
+	PasswordHash         PasswordHashConfig `mapstructure:"password_hash"`
+	BaseURL              string             `mapstructure:"base_url"`
+	TrustedProxies       []string           `mapstructure:"trusted_proxies"`
+	PasswordResetTTL     time.Duration      `mapstructure:"password_reset_ttl"`
+	EmailVerificationTTL time.Duration      `mapstructure:"email_verification_ttl"`
+	RequireVerification  bool               `mapstructure:"require_email_verification"`
+	TOTPIssuer           string             `mapstructure:"totp_issuer"`
+	Address              string             `mapstructure:"address"`
+	Port                 string             `mapstructure:"port"`
+	LogLevel             string             `mapstructure:"loglevel"`
+	LogFile              string             `mapstructure:"logfile"`
+	Mode                 string             `mapstructure:"mode"`
+	DBType               string             `mapstructure:"dbtype"`
+	MigrateOnStart       bool               `mapstructure:"migrate_on_start"`
 }
 
 func (c *Config) ParseConfig(configFileName, pathToConfig string) error {
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"context"
+	"crypto/tls"
 	"database/sql"
 	"fmt"
 	"time"
@@ -10,61 +12,137 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"golang.org/x/net/context"
 )
 
+const (
+	defaultMaxOpenConns        = 10
+	defaultSSLMode             = "disable"
+	defaultMongoConnectTimeout = 20 * time.Second
+)
+
+// DbConnectionFlags configures the connection of dbtype "postgres". Pool settings left at zero keep
+// the defaults of database/sql, except MaxOpenConns, which defaults to 10. StatementTimeout makes the
+// server cancel statements running longer, zero leaves them unlimited.
 type DbConnectionFlags struct {
-	Host     string `mapstructure:"host"`
-	User     string `mapstructure:"user"`
-	Password string `mapstructure:"password"`
-	Port     string `mapstructure:"port"`
-	DBName   string `mapstructure:"dbname"`
+	Host             string        `mapstructure:"host"`
+	User             string        `mapstructure:"user"`
+	Password         string        `mapstructure:"password"`
+	Port             string        `mapstructure:"port"`
+	DBName           string        `mapstructure:"dbname"`
+	SSLMode          string        `mapstructure:"sslmode"`
+	ConnectTimeout   time.Duration `mapstructure:"connect_timeout"`
+	StatementTimeout time.Duration `mapstructure:"statement_timeout"`
+	MaxOpenConns     int           `mapstructure:"max_open_conns"`
+	MaxIdleConns     int           `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime  time.Duration `mapstructure:"conn_max_lifetime"`
+	ConnMaxIdleTime  time.Duration `mapstructure:"conn_max_idle_time"`
+	Retry            RetryConfig   `mapstructure:"retry"`
 }
 
-func (p *DbConnectionFlags) InitPostgresDB(logger *log.Logger) (*sql.DB, error) {
-	logger.Debug("POSTGRES! Start init postgreSQL", "user", p.User, "DBName", p.DBName,
-		"host", p.Host, "port", p.Port)
+func (p *DbConnectionFlags) postgresDSN() string {
+	sslMode := p.SSLMode
+	if sslMode == "" {
+		sslMode = defaultSSLMode
+	}
 
-	dsnPGConn := fmt.Sprintf("user=%s dbname=%s password=%s host=%s port=%s sslmode=disable",
+	dsn := fmt.Sprintf("user=%s dbname=%s password=%s host=%s port=%s sslmode=%s",
 		p.User, p.DBName, p.Password,
-		p.Host, p.Port)
+		p.Host, p.Port, sslMode)
+
+	// connect_timeout is given in seconds, less than a second would disable it
+	if p.ConnectTimeout > 0 {
+		dsn += fmt.Sprintf(" connect_timeout=%d", max(int(p.ConnectTimeout.Seconds()), 1))
+	}
+	if p.StatementTimeout > 0 {
+		dsn += fmt.Sprintf(" statement_timeout=%d", p.StatementTimeout.Milliseconds())
+	}
+
+	return dsn
+}
+
+func (p *DbConnectionFlags) InitPostgresDB(logger *log.Logger) (*sql.DB, error) {
+	logger.Debug("POSTGRES! Start init postgreSQL", "user", p.User, "DBName", p.DBName,
+		"host", p.Host, "port", p.Port, "sslmode", p.SSLMode)
 
-	db, err := sql.Open("pgx", dsnPGConn)
+	db, err := sql.Open("pgx", p.postgresDSN())
 	if err != nil {
-		logger.Fatal("POSTGRES! Error in method open")
+		logger.Error("POSTGRES! Error in method open", "err", err)
 		return nil, err
 	}
 
-	err = db.Ping()
+	maxOpenConns := p.MaxOpenConns
+	if maxOpenConns == 0 {
+		maxOpenConns = defaultMaxOpenConns
+	}
+	db.SetMaxOpenConns(maxOpenConns)
+	if p.MaxIdleConns != 0 {
+		db.SetMaxIdleConns(p.MaxIdleConns)
+	}
+	db.SetConnMaxLifetime(p.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(p.ConnMaxIdleTime)
+
+	err = p.Retry.Do(logger, "POSTGRES!", db.Ping)
 	if err != nil {
-		logger.Fatal("POSTGRES! Error in method ping")
+		logger.Error("POSTGRES! Error in method ping", "err", err)
+		db.Close()
 		return nil, err
 	}
 
-	db.SetMaxOpenConns(10)
-
-	logger.Info("POSTGRES! Successfully init postgreSQL")
+	logger.Info("POSTGRES! Successfully init postgreSQL", "max_open_conns", maxOpenConns)
 	return db, nil
 }
 
-func (p *DbConnectionFlags) InitMongoDB(logger *log.Logger) (*mongo.Database, error) {
+func (p *DbConnectionFlags) InitMongoDB(mongoConfig MongoConfig, logger *log.Logger) (*mongo.Database, error) {
 	logger.Debug("MONGO! Start init mongoDB", "user", p.User, "DBName", p.DBName,
 		"host", p.Host, "port", p.Port)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-	defer cancel()
-
 	dsnMongoConn := fmt.Sprintf("mongodb://%s:%s@%s:%s", p.User, p.Password, p.Host, p.Port)
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(dsnMongoConn))
+	clientOptions := options.Client().ApplyURI(dsnMongoConn)
+	if mongoConfig.MaxPoolSize > 0 {
+		clientOptions.SetMaxPoolSize(mongoConfig.MaxPoolSize)
+	}
+	if mongoConfig.MinPoolSize > 0 {
+		clientOptions.SetMinPoolSize(mongoConfig.MinPoolSize)
+	}
+	if mongoConfig.MaxConnIdleTime > 0 {
+		clientOptions.SetMaxConnIdleTime(mongoConfig.MaxConnIdleTime)
+	}
+	if mongoConfig.ConnectTimeout > 0 {
+		clientOptions.SetConnectTimeout(mongoConfig.ConnectTimeout)
+	}
+	if mongoConfig.ServerSelectionTimeout > 0 {
+		clientOptions.SetServerSelectionTimeout(mongoConfig.ServerSelectionTimeout)
+	}
+	if mongoConfig.Timeout > 0 {
+		clientOptions.SetTimeout(mongoConfig.Timeout)
+	}
+	if mongoConfig.TLS {
+		clientOptions.SetTLSConfig(&tls.Config{})
+	}
+
+	// Connect only checks the options, the servers are contacted by the ping
+	client, err := mongo.Connect(context.Background(), clientOptions)
 	if err != nil {
-		logger.Fatal("MONGO! Error in method connect")
+		logger.Error("MONGO! Error in method connect", "err", err)
 		return nil, err
 	}
 
-	// Send a ping to confirm a successful connection
-	var result bson.M
-	if err = client.Database(p.DBName).RunCommand(ctx, bson.D{{"ping", 1}}).Decode(&result); err != nil {
+	pingTimeout := mongoConfig.ConnectTimeout
+	if pingTimeout <= 0 {
+		pingTimeout = defaultMongoConnectTimeout
+	}
+
+	err = mongoConfig.Retry.Do(logger, "MONGO!", func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		defer cancel()
+
+		var result bson.M
+		return client.Database(p.DBName).RunCommand(ctx, bson.D{{Key: "ping", Value: 1}}).Decode(&result)
+	})
+	if err != nil {
+		logger.Error("MONGO! Error in method ping", "err", err)
+		_ = client.Disconnect(context.Background())
 		return nil, err
 	}
 
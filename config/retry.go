@@ -0,0 +1,55 @@
+package config
+
+import (
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+const (
+	defaultInitialRetryDelay = 500 * time.Millisecond
+	defaultMaxRetryDelay     = time.Minute
+)
+
+// RetryConfig configures how the application waits for a database started together with it.
+// The connection is tried Attempts times, the delay between the attempts doubles from
+// InitialDelay up to MaxDelay. Unset delays take the defaults, so the attempts are never made back to back.
+type RetryConfig struct {
+	Attempts     int           `mapstructure:"attempts"`
+	InitialDelay time.Duration `mapstructure:"initial_delay"`
+	MaxDelay     time.Duration `mapstructure:"max_delay"`
+}
+
+// Backoff returns the delay after the failed attempt, counting from 1.
+func (r RetryConfig) Backoff(attempt int) time.Duration {
+	maxDelay := r.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxRetryDelay
+	}
+
+	delay := r.InitialDelay
+	if delay <= 0 {
+		delay = defaultInitialRetryDelay
+	}
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxDelay)
+}
+
+// Do calls connect until it succeeds or the attempts are used up, and returns the last error.
+func (r RetryConfig) Do(logger *log.Logger, prefix string, connect func() error) error {
+	attempts := max(r.Attempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		err := connect()
+		if err == nil || attempt == attempts {
+			return err
+		}
+
+		delay := r.Backoff(attempt)
+		logger.Warn(prefix+" Database is not available, retrying", "attempt", attempt, "attempts", attempts, "delay", delay, "err", err)
+		time.Sleep(delay)
+	}
+}
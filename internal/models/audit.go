@@ -0,0 +1,83 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	AuditWorkerCreate    = "worker.create"
+	AuditWorkerUpdate    = "worker.update"
+	AuditWorkerDelete    = "worker.delete"
+	AuditWorkerRestore   = "worker.restore"
+	AuditUserRestore     = "user.restore"
+	AuditTaskCreate      = "task.create"
+	AuditTaskUpdate      = "task.update"
+	AuditTaskDelete      = "task.delete"
+	AuditTaskRestore     = "task.restore"
+	AuditTaskPrice       = "task.price"
+	AuditTaskPriceCancel = "task.price_cancel"
+	AuditCategoryCreate  = "category.create"
+	AuditCategoryUpdate  = "category.update"
+	AuditCategoryDelete  = "category.delete"
+	AuditCategoryRestore = "category.restore"
+	AuditCategoryMerge   = "category.merge"
+	AuditCategoryMove    = "category.move"
+	AuditOrderReassign   = "order.reassign"
+	AuditOrderStatus     = "order.status"
+)
+
+const (
+	AuditTargetWorker   = "worker"
+	AuditTargetUser     = "user"
+	AuditTargetTask     = "task"
+	AuditTargetCategory = "category"
+	AuditTargetOrder    = "order"
+)
+
+var AuditActions = map[string]string{
+	AuditWorkerCreate:    "Создание исполнителя",
+	AuditWorkerUpdate:    "Изменение исполнителя",
+	AuditWorkerDelete:    "Архивирование исполнителя",
+	AuditWorkerRestore:   "Восстановление исполнителя",
+	AuditUserRestore:     "Восстановление клиента",
+	AuditTaskCreate:      "Создание услуги",
+	AuditTaskUpdate:      "Изменение услуги",
+	AuditTaskDelete:      "Архивирование услуги",
+	AuditTaskRestore:     "Восстановление услуги",
+	AuditTaskPrice:       "Планирование цены услуги",
+	AuditTaskPriceCancel: "Отмена новой цены услуги",
+	AuditCategoryCreate:  "Создание категории",
+	AuditCategoryUpdate:  "Изменение категории",
+	AuditCategoryDelete:  "Архивирование категории",
+	AuditCategoryRestore: "Восстановление категории",
+	AuditCategoryMerge:   "Объединение категорий",
+	AuditCategoryMove:    "Перемещение категории",
+	AuditOrderReassign:   "Переназначение заказа",
+	AuditOrderStatus:     "Изменение статуса заказа",
+}
+
+// AuditEntry records a single administrative action. Before and After hold JSON snapshots
+// of the target and are empty when there is nothing to show, e.g. Before of a created entity.
+type AuditEntry struct {
+	ID         uuid.UUID `json:"id"`
+	ActorID    uuid.UUID `json:"actor_id"`
+	Action     string    `json:"action"`
+	TargetType string    `json:"target_type"`
+	TargetID   string    `json:"target_id"`
+	Before     string    `json:"before"`
+	After      string    `json:"after"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AuditFilter selects audit entries. Zero fields do not restrict the result.
+type AuditFilter struct {
+	ActorID    uuid.UUID
+	Action     string
+	TargetType string
+	TargetID   string
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
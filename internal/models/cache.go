@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// CacheStats describes a read-through cache of a repository since the start of the application.
+type CacheStats struct {
+	Name       string
+	Hits       uint64
+	Misses     uint64
+	Evictions  uint64
+	Entries    int
+	MaxEntries int
+	TTL        time.Duration
+}
+
+// HitRate is the share of reads answered from the cache, 0 when nothing was read yet.
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
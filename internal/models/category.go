@@ -1,6 +1,30 @@
 package models
 
+import "time"
+
 type Category struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
+	// ParentID is the category this one is nested in, 0 for a top level category
+	ParentID int `json:"parent_id"`
+	// Position orders the categories nested in the same parent in the catalog
+	Position int `json:"position"`
+	// Hidden categories and the categories nested in them are not shown to clients
+	Hidden bool `json:"hidden"`
+	// TaxRate is the code of the VAT rate of the tasks in the category, empty when they take the rate of the parent
+	// category or the default one
+	TaxRate string `json:"tax_rate"`
+	// DeletedAt is set when the category is archived
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func (c Category) IsDeleted() bool {
+	return !c.DeletedAt.IsZero()
+}
+
+// CategoryNode is a category in the order of the catalog, with the depth of its nesting.
+type CategoryNode struct {
+	Category
+	// Depth is 0 for a top level category
+	Depth int
 }
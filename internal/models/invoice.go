@@ -0,0 +1,59 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Requisites are the details of the company printed on its invoices.
+type Requisites struct {
+	Company              string `json:"company"`
+	INN                  string `json:"inn"`
+	KPP                  string `json:"kpp"`
+	Address              string `json:"address"`
+	Bank                 string `json:"bank"`
+	BIC                  string `json:"bic"`
+	Account              string `json:"account"`
+	CorrespondentAccount string `json:"correspondent_account"`
+}
+
+// InvoiceLine is a task of the order. Amount is the price times the quantity, Tax is the VAT of the line,
+// included in Amount or not depending on whether prices included tax when the invoice was issued.
+type InvoiceLine struct {
+	Name     string  `json:"name"`
+	Price    float64 `json:"price"`
+	Quantity int     `json:"quantity"`
+	Amount   float64 `json:"amount"`
+	TaxName  string  `json:"tax_name"`
+	Tax      float64 `json:"tax"`
+}
+
+// Invoice is the document issued for a completed order. The lines, the parties and the total are copied
+// when the invoice is issued, so that later changes of the catalog or the requisites do not change it.
+type Invoice struct {
+	ID       uuid.UUID     `json:"id"`
+	Number   int           `json:"number"`
+	OrderID  uuid.UUID     `json:"order_id"`
+	UserID   uuid.UUID     `json:"user_id"`
+	IssuedAt time.Time     `json:"issued_at"`
+	Seller   Requisites    `json:"seller"`
+	Buyer    string        `json:"buyer"`
+	Address  string        `json:"address"`
+	Lines    []InvoiceLine `json:"lines"`
+	// Total is what the client pays, the tax included
+	Total float64 `json:"total"`
+}
+
+// Tax returns the VAT of the invoice.
+func (i Invoice) Tax() float64 {
+	var tax float64
+	for _, line := range i.Lines {
+		tax += line.Tax
+	}
+	return tax
+}
+
+// Net returns the total without the VAT.
+func (i Invoice) Net() float64 {
+	return i.Total - i.Tax()
+}
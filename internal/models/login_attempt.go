@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+const (
+	UserLoginScope   = "user"
+	WorkerLoginScope = "worker"
+	IPLoginScope     = "ip"
+)
+
+// LoginAttempt counts failed sign-in attempts for an account or an IP address.
+// Key has the form "<scope>:<subject>", e.g. "worker:default@admin.com" or "ip:127.0.0.1".
+type LoginAttempt struct {
+	Key         string    `json:"key"`
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"last_failure"`
+	LockedUntil time.Time `json:"locked_until"`
+}
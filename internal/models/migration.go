@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Migration describes a versioned schema change and whether it has been applied to the database.
+type Migration struct {
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	Applied   bool      `json:"applied"`
+	AppliedAt time.Time `json:"applied_at"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	UserPasswordResetPurpose   = "user_password_reset"
+	WorkerPasswordResetPurpose = "worker_password_reset"
+	EmailVerificationPurpose   = "email_verification"
+)
+
+// OneTimeToken is a single-use token sent to a user or a worker by email. Only the hash of the token is stored.
+type OneTimeToken struct {
+	Hash      string    `json:"hash"`
+	Purpose   string    `json:"purpose"`
+	UserID    uuid.UUID `json:"user_id"`
+	WorkerID  uuid.UUID `json:"worker_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
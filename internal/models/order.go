@@ -14,6 +14,14 @@ type Order struct {
 	CreationDate time.Time `json:"creation_date"`
 	Deadline     time.Time `json:"deadline"`
 	Rate         int       `json:"rate"`
+	// IdempotencyKey identifies the submission that created the order, repeated submissions with the same key return this order
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	// Version is increased by every update, an update based on an older version is rejected
+	Version int `json:"version"`
+	// ClosedAt is when the order was completed or cancelled, zero while it is open
+	ClosedAt time.Time `json:"closed_at"`
+	// Archived orders were moved to the archive storage, they are read-only
+	Archived bool `json:"archived,omitempty"`
 }
 
 const NoStatus = 0
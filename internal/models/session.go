@@ -0,0 +1,15 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Session struct {
+	ID           string    `json:"id"`
+	UserID       uuid.UUID `json:"user_id"`
+	WorkerID     uuid.UUID `json:"worker_id"`
+	Data         string    `json:"data"`
+	CreatedAt    time.Time `json:"created_at"`
+	LastActivity time.Time `json:"last_activity"`
+}
@@ -1,44 +1,29 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"time"
+)
 
 type Task struct {
 	ID             uuid.UUID `json:"id"`
 	Name           string    `json:"name"`
 	PricePerSingle float64   `json:"price_per_single"`
 	Category       int       `json:"category"`
+	// TaxRate is the code of the VAT rate of the task, empty when the task takes the rate of its category
+	TaxRate string `json:"tax_rate"`
+	// DeletedAt is set when the task is archived. Archived tasks can not be ordered
+	DeletedAt time.Time `json:"deleted_at"`
 }
 
-var TaskCategories = [8]string{
-	"Генеральная уборка",
-	"Послестроительная уборка",
-	"Мытье окон",
-	"Ежедневная уборка офисов",
-	"Поддерживающая уборка",
-	"Химчистка ковров и мебели",
-	"Уход за твердыми полами",
-	"Глубинная Эко Чистка",
+func (t Task) IsDeleted() bool {
+	return !t.DeletedAt.IsZero()
 }
 
-func GetCategoryName(category int) string {
-	switch category {
-	case 1:
-		return TaskCategories[0]
-	case 2:
-		return TaskCategories[1]
-	case 3:
-		return TaskCategories[2]
-	case 4:
-		return TaskCategories[3]
-	case 5:
-		return TaskCategories[4]
-	case 6:
-		return TaskCategories[5]
-	case 7:
-		return TaskCategories[6]
-	case 8:
-		return TaskCategories[7]
-	default:
-		return "Неизвестная категория"
-	}
+// TaskPrice is a price of a task in force from EffectiveFrom until the next price of the task comes in force.
+type TaskPrice struct {
+	ID            uuid.UUID `json:"id"`
+	TaskID        uuid.UUID `json:"task_id"`
+	Price         float64   `json:"price"`
+	EffectiveFrom time.Time `json:"effective_from"`
 }
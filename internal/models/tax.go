@@ -0,0 +1,30 @@
+package models
+
+// TaxRate is a VAT rate of the configuration, categories and tasks refer to it by Code.
+type TaxRate struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// Rate is in percent
+	Rate float64 `json:"rate"`
+}
+
+// OrderLine is a task of an order with its price, quantity and tax. Net plus Tax is Gross, the price
+// is the net or the gross price of a single task depending on whether prices include tax.
+type OrderLine struct {
+	Task     Task
+	Price    float64
+	Quantity int
+	TaxRate  TaxRate
+	Net      float64
+	Tax      float64
+	Gross    float64
+}
+
+// OrderTotals are the lines of an order and their sums. Gross is what the client pays.
+type OrderTotals struct {
+	Lines            []OrderLine
+	PricesIncludeTax bool
+	Net              float64
+	Tax              float64
+	Gross            float64
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// TwoFactor holds the TOTP secret of a worker. Until Enabled is set, the secret belongs to an unfinished enrollment.
+type TwoFactor struct {
+	WorkerID     uuid.UUID `json:"worker_id"`
+	Secret       string    `json:"secret"`
+	Enabled      bool      `json:"enabled"`
+	LastUsedStep int64     `json:"last_used_step"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TwoFactorEnrollment is what a worker needs to add the account to an authenticator app.
+type TwoFactorEnrollment struct {
+	Secret string
+	URL    string
+	QRCode []byte
+}
@@ -1,13 +1,23 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"time"
+)
 
 type User struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Surname     string    `json:"surname"`
-	Address     string    `json:"address"`
-	PhoneNumber string    `json:"phoneNumber"`
-	Email       string    `json:"email"`
-	Password    string    `json:"password"`
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	Surname       string    `json:"surname"`
+	Address       string    `json:"address"`
+	PhoneNumber   string    `json:"phoneNumber"`
+	Email         string    `json:"email"`
+	Password      string    `json:"password"`
+	EmailVerified bool      `json:"emailVerified"`
+	// DeletedAt is set when the account is archived
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+func (u User) IsDeleted() bool {
+	return !u.DeletedAt.IsZero()
 }
@@ -1,6 +1,9 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"time"
+)
 
 type Worker struct {
 	ID          uuid.UUID
@@ -11,6 +14,8 @@ type Worker struct {
 	Email       string
 	Role        int
 	Password    string
+	// DeletedAt is set when the worker is archived
+	DeletedAt time.Time
 }
 
 const ManagerRole = 1
@@ -28,3 +33,7 @@ func (w Worker) DisplayRole() string {
 func (w Worker) FullName() string {
 	return w.Name + " " + w.Surname
 }
+
+func (w Worker) IsDeleted() bool {
+	return !w.DeletedAt.IsZero()
+}
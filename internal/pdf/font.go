@@ -0,0 +1,101 @@
+package pdf
+
+// The documents use Arial, which every viewer has or substitutes, instead of an embedded font. Codes of
+// the text are Windows-1251, the viewer finds the glyphs of the Cyrillic codes by their names in encoding.
+
+// encoding lists the glyph names of the codes that differ from WinAnsiEncoding, starting with code 0xA8.
+const encoding = `/Differences [168 /afii10023 184 /afii10071 185 /afii61352
+192 /afii10017 /afii10018 /afii10019 /afii10020 /afii10021 /afii10022 /afii10024 /afii10025
+/afii10026 /afii10027 /afii10028 /afii10029 /afii10030 /afii10031 /afii10032 /afii10033
+/afii10034 /afii10035 /afii10036 /afii10037 /afii10038 /afii10039 /afii10040 /afii10041
+/afii10042 /afii10043 /afii10044 /afii10045 /afii10046 /afii10047 /afii10048 /afii10049
+/afii10065 /afii10066 /afii10067 /afii10068 /afii10069 /afii10070 /afii10072 /afii10073
+/afii10074 /afii10075 /afii10076 /afii10077 /afii10078 /afii10079 /afii10080 /afii10081
+/afii10082 /afii10083 /afii10084 /afii10085 /afii10086 /afii10087 /afii10088 /afii10089
+/afii10090 /afii10091 /afii10092 /afii10093 /afii10094 /afii10095 /afii10096 /afii10097]`
+
+const (
+	firstChar = 32
+	lastChar  = 255
+)
+
+// asciiWidths are the widths of codes 32-126 in thousandths of the font size, those of Arial and Arial Bold.
+var asciiWidths = [2][95]int{
+	{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+}
+
+// cyrillicWidths are the widths of А-я, codes 0xC0-0xFF, in Arial and Arial Bold.
+var cyrillicWidths = [2][64]int{
+	{
+		667, 656, 667, 542, 677, 667, 923, 604, 719, 719, 583, 656, 833, 722, 778, 719,
+		667, 722, 611, 635, 760, 667, 740, 667, 917, 938, 792, 885, 656, 719, 1010, 722,
+		556, 573, 531, 365, 583, 556, 669, 458, 559, 559, 438, 583, 688, 552, 556, 542,
+		556, 500, 458, 500, 823, 500, 573, 521, 802, 823, 625, 719, 521, 510, 750, 542,
+	},
+	{
+		722, 719, 722, 567, 719, 667, 1029, 656, 722, 722, 635, 719, 833, 722, 778, 722,
+		667, 722, 611, 667, 885, 667, 740, 698, 1010, 1031, 865, 979, 719, 719, 1073, 740,
+		556, 615, 583, 417, 635, 556, 885, 521, 615, 615, 531, 635, 740, 604, 611, 604,
+		611, 556, 490, 556, 875, 556, 615, 583, 885, 896, 656, 823, 573, 573, 885, 583,
+	},
+}
+
+// widths returns the widths of codes firstChar-lastChar of the regular or the bold font.
+func widths(bold int) []int {
+	result := make([]int, 0, lastChar-firstChar+1)
+	result = append(result, asciiWidths[bold][:]...)
+	for code := 127; code < 0xC0; code++ {
+		switch code {
+		case 0xA8: // Ё
+			result = append(result, cyrillicWidths[bold][5])
+		case 0xB8: // ё
+			result = append(result, cyrillicWidths[bold][37])
+		case 0xB9: // №
+			result = append(result, 1073)
+		case 0xA0: // no-break space
+			result = append(result, 278)
+		default:
+			result = append(result, 0)
+		}
+	}
+	return append(result, cyrillicWidths[bold][:]...)
+}
+
+// encode returns the Windows-1251 codes of text, a character the encoding lacks becomes "?".
+func encode(text string) []byte {
+	result := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r >= firstChar && r < 127:
+			result = append(result, byte(r))
+		case r >= 'А' && r <= 'я':
+			result = append(result, byte(r-'А'+0xC0))
+		case r == 'Ё':
+			result = append(result, 0xA8)
+		case r == 'ё':
+			result = append(result, 0xB8)
+		case r == '№':
+			result = append(result, 0xB9)
+		case r == '\u00a0':
+			result = append(result, 0xA0)
+		default:
+			result = append(result, '?')
+		}
+	}
+	return result
+}
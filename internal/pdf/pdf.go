@@ -0,0 +1,161 @@
+// Package pdf writes simple printable documents of text and lines as PDF, without external tools,
+// so that documents like invoices are made offline. Pages are A4, coordinates are in points
+// from the top left corner of a page.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+type Document struct {
+	pages []*bytes.Buffer
+}
+
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage starts a new page, the following text and lines are drawn on it.
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *Document) page() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+func fontIndex(bold bool) int {
+	if bold {
+		return 1
+	}
+	return 0
+}
+
+// TextWidth returns the width of text set in size points.
+func TextWidth(text string, size float64, bold bool) float64 {
+	fontWidths := widths(fontIndex(bold))
+
+	total := 0
+	for _, code := range encode(text) {
+		total += fontWidths[int(code)-firstChar]
+	}
+	return float64(total) * size / 1000
+}
+
+// Wrap splits text into lines no wider than width, breaking it between words.
+// A word wider than width takes a line of its own.
+func Wrap(text string, size float64, bold bool, width float64) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+
+		if line != "" && TextWidth(candidate, size, bold) > width {
+			lines = append(lines, line)
+			line = word
+		} else {
+			line = candidate
+		}
+	}
+
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// escape makes the codes of text a PDF string literal.
+func escape(text string) string {
+	var result strings.Builder
+	for _, code := range encode(text) {
+		if code == '\\' || code == '(' || code == ')' {
+			result.WriteByte('\\')
+		}
+		result.WriteByte(code)
+	}
+	return result.String()
+}
+
+// Text draws text with its baseline starting at x, y.
+func (d *Document) Text(x, y float64, size float64, bold bool, text string) {
+	fmt.Fprintf(d.page(), "BT /F%d %.2f Tf %.2f %.2f Td (%s) Tj ET\n", fontIndex(bold)+1, size, x, PageHeight-y, escape(text))
+}
+
+// TextRight draws text ending at x, like a number in a column aligned to the right.
+func (d *Document) TextRight(x, y float64, size float64, bold bool, text string) {
+	d.Text(x-TextWidth(text, size, bold), y, size, bold, text)
+}
+
+// Line draws a thin line from x1, y1 to x2, y2.
+func (d *Document) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// Bytes returns the document as a PDF file.
+func (d *Document) Bytes() []byte {
+	d.page()
+
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1 and 2 are the catalog and the page tree, 3 and 4 the fonts, 5 the encoding, 6 and 7 the descriptors
+	// of the fonts, then every page is followed by its content
+	firstPage := 8
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	fonts := []string{"Arial", "Arial,Bold"}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for i, name := range fonts {
+		fontWidths := make([]string, 0, lastChar-firstChar+1)
+		for _, width := range widths(i) {
+			fontWidths = append(fontWidths, fmt.Sprint(width))
+		}
+
+		object(fmt.Sprintf("<< /Type /Font /Subtype /TrueType /BaseFont /%s /Encoding 5 0 R /FirstChar %d /LastChar %d /Widths [%s] /FontDescriptor %d 0 R >>",
+			name, firstChar, lastChar, strings.Join(fontWidths, " "), 6+i))
+	}
+	object("<< /Type /Encoding /BaseEncoding /WinAnsiEncoding " + encoding + " >>")
+	for i, name := range fonts {
+		// the flags mark a nonsymbolic font, the bold one is also forced bold
+		object(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags %d /FontBBox [-665 -325 2000 1040] /ItalicAngle 0 /Ascent 905 /Descent -212 /CapHeight 716 /StemV %d >>",
+			name, 32+262144*i, 80+60*i))
+	}
+
+	for i, content := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
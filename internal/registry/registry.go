@@ -2,11 +2,16 @@ package registry
 
 import (
 	"lab3/config"
+	"lab3/internal/models"
+	"lab3/internal/repository/cache"
+	"lab3/internal/repository/memory"
 	"lab3/internal/repository/mongodb"
 	"lab3/internal/repository/postgres"
 	"lab3/internal/repository/repository_interfaces"
+	"lab3/internal/repository/sqlite"
 	services "lab3/internal/services"
 	"lab3/internal/services/service_interfaces"
+	"lab3/mail_sender"
 	"lab3/password_hash"
 	"os"
 
@@ -14,23 +19,38 @@ import (
 )
 
 type Services struct {
-	UserService     service_interfaces.IUserService
-	WorkerService   service_interfaces.IWorkerService
-	TaskService     service_interfaces.ITaskService
-	OrderService    service_interfaces.IOrderService
-	CategoryService service_interfaces.ICategoryService
+	UserService              service_interfaces.IUserService
+	WorkerService            service_interfaces.IWorkerService
+	TaskService              service_interfaces.ITaskService
+	OrderService             service_interfaces.IOrderService
+	CategoryService          service_interfaces.ICategoryService
+	SessionService           service_interfaces.ISessionService
+	LoginAttemptService      service_interfaces.ILoginAttemptService
+	PasswordResetService     service_interfaces.IPasswordResetService
+	EmailVerificationService service_interfaces.IEmailVerificationService
+	TwoFactorService         service_interfaces.ITwoFactorService
+	AuditService             service_interfaces.IAuditService
+	InvoiceService           service_interfaces.IInvoiceService
+	CacheService             service_interfaces.ICacheService
 }
 
 type Repositories struct {
-	UserRepository     repository_interfaces.IUserRepository
-	WorkerRepository   repository_interfaces.IWorkerRepository
-	TaskRepository     repository_interfaces.ITaskRepository
-	OrderRepository    repository_interfaces.IOrderRepository
-	CategoryRepository repository_interfaces.ICategoryRepository
+	UserRepository         repository_interfaces.IUserRepository
+	WorkerRepository       repository_interfaces.IWorkerRepository
+	TaskRepository         repository_interfaces.ITaskRepository
+	OrderRepository        repository_interfaces.IOrderRepository
+	CategoryRepository     repository_interfaces.ICategoryRepository
+	SessionRepository      repository_interfaces.ISessionRepository
+	LoginAttemptRepository repository_interfaces.ILoginAttemptRepository
+	OneTimeTokenRepository repository_interfaces.IOneTimeTokenRepository
+	TwoFactorRepository    repository_interfaces.ITwoFactorRepository
+	AuditRepository        repository_interfaces.IAuditRepository
+	InvoiceRepository      repository_interfaces.IInvoiceRepository
 }
 
 type App struct {
 	Config       config.Config
+	Migrator     repository_interfaces.IMigrator
 	Repositories *Repositories
 	Services     *Services
 	Logger       *log.Logger
@@ -38,11 +58,17 @@ type App struct {
 
 func (a *App) postgresRepositoriesInitialization(fields *postgres.PostgresConnection) *Repositories {
 	r := &Repositories{
-		UserRepository:     postgres.CreateUserRepository(fields),
-		WorkerRepository:   postgres.CreateWorkerRepository(fields),
-		TaskRepository:     postgres.CreateTaskRepository(fields),
-		OrderRepository:    postgres.CreateOrderRepository(fields),
-		CategoryRepository: postgres.CreateCategoryRepository(fields),
+		UserRepository:         postgres.CreateUserRepository(fields),
+		WorkerRepository:       postgres.CreateWorkerRepository(fields),
+		TaskRepository:         postgres.CreateTaskRepository(fields),
+		OrderRepository:        postgres.CreateOrderRepository(fields),
+		CategoryRepository:     postgres.CreateCategoryRepository(fields),
+		SessionRepository:      postgres.CreateSessionRepository(fields),
+		LoginAttemptRepository: postgres.CreateLoginAttemptRepository(fields),
+		OneTimeTokenRepository: postgres.CreateOneTimeTokenRepository(fields),
+		TwoFactorRepository:    postgres.CreateTwoFactorRepository(fields),
+		AuditRepository:        postgres.CreateAuditRepository(fields),
+		InvoiceRepository:      postgres.CreateInvoiceRepository(fields),
 	}
 	a.Logger.Info("Success initialization of repositories")
 	return r
@@ -50,25 +76,189 @@ func (a *App) postgresRepositoriesInitialization(fields *postgres.PostgresConnec
 
 func (a *App) mongoRepositoriesInitialization(fields *mongodb.MongoConnection) *Repositories {
 	r := &Repositories{
-		UserRepository:     mongodb.CreateUserRepository(fields),
-		WorkerRepository:   mongodb.CreateWorkerRepository(fields),
-		TaskRepository:     mongodb.CreateTaskRepository(fields),
-		OrderRepository:    mongodb.CreateOrderRepository(fields),
-		CategoryRepository: mongodb.CreateCategoryRepository(fields),
+		UserRepository:         mongodb.CreateUserRepository(fields),
+		WorkerRepository:       mongodb.CreateWorkerRepository(fields),
+		TaskRepository:         mongodb.CreateTaskRepository(fields),
+		OrderRepository:        mongodb.CreateOrderRepository(fields),
+		CategoryRepository:     mongodb.CreateCategoryRepository(fields),
+		SessionRepository:      mongodb.CreateSessionRepository(fields),
+		LoginAttemptRepository: mongodb.CreateLoginAttemptRepository(fields),
+		OneTimeTokenRepository: mongodb.CreateOneTimeTokenRepository(fields),
+		TwoFactorRepository:    mongodb.CreateTwoFactorRepository(fields),
+		AuditRepository:        mongodb.CreateAuditRepository(fields),
+		InvoiceRepository:      mongodb.CreateInvoiceRepository(fields),
 	}
 	a.Logger.Info("Success initialization of repositories")
 	return r
 }
 
+func (a *App) memoryRepositoriesInitialization(fields *memory.MemoryConnection) *Repositories {
+	r := &Repositories{
+		UserRepository:         memory.CreateUserRepository(fields),
+		WorkerRepository:       memory.CreateWorkerRepository(fields),
+		TaskRepository:         memory.CreateTaskRepository(fields),
+		OrderRepository:        memory.CreateOrderRepository(fields),
+		CategoryRepository:     memory.CreateCategoryRepository(fields),
+		SessionRepository:      memory.CreateSessionRepository(fields),
+		LoginAttemptRepository: memory.CreateLoginAttemptRepository(fields),
+		OneTimeTokenRepository: memory.CreateOneTimeTokenRepository(fields),
+		TwoFactorRepository:    memory.CreateTwoFactorRepository(fields),
+		AuditRepository:        memory.CreateAuditRepository(fields),
+		InvoiceRepository:      memory.CreateInvoiceRepository(fields),
+	}
+	a.Logger.Info("Success initialization of repositories")
+	return r
+}
+
+func (a *App) sqliteRepositoriesInitialization(fields *sqlite.SQLiteConnection) *Repositories {
+	r := &Repositories{
+		UserRepository:         sqlite.CreateUserRepository(fields),
+		WorkerRepository:       sqlite.CreateWorkerRepository(fields),
+		TaskRepository:         sqlite.CreateTaskRepository(fields),
+		OrderRepository:        sqlite.CreateOrderRepository(fields),
+		CategoryRepository:     sqlite.CreateCategoryRepository(fields),
+		SessionRepository:      sqlite.CreateSessionRepository(fields),
+		LoginAttemptRepository: sqlite.CreateLoginAttemptRepository(fields),
+		OneTimeTokenRepository: sqlite.CreateOneTimeTokenRepository(fields),
+		TwoFactorRepository:    sqlite.CreateTwoFactorRepository(fields),
+		AuditRepository:        sqlite.CreateAuditRepository(fields),
+		InvoiceRepository:      sqlite.CreateInvoiceRepository(fields),
+	}
+	a.Logger.Info("Success initialization of repositories")
+	return r
+}
+
+// migrationsInitialization brings the schema up to date before the repositories are used.
+func (a *App) migrationsInitialization() error {
+	if !a.Config.MigrateOnStart {
+		a.Logger.Info("Migrations on start are disabled")
+		return nil
+	}
+
+	applied, err := a.Migrator.Up()
+	for _, migration := range applied {
+		a.Logger.Info("Migration applied", "version", migration.Version, "name", migration.Name)
+	}
+	if err != nil {
+		a.Logger.Error("Error apply migrations", "err", err)
+		return err
+	}
+
+	a.Logger.Info("Success initialization of migrations")
+	return nil
+}
+
+// emailVerificationRequired tells whether orders need a confirmed email. Without a real mail sender
+// verification links never reach clients, so the requirement is turned off.
+func (a *App) emailVerificationRequired() bool {
+	if !a.Config.RequireVerification {
+		return false
+	}
+
+	if a.Config.Mail.Sender != "smtp" {
+		a.Logger.Warn("Email verification is required but SMTP is not configured, clients will order without verification")
+		return false
+	}
+
+	return true
+}
+
+func (a *App) mailSenderInitialization() mail_sender.MailSender {
+	if a.Config.Mail.Sender == "smtp" {
+		return mail_sender.NewSMTPSender(a.Config.Mail.Host, a.Config.Mail.Port, a.Config.Mail.Username, a.Config.Mail.Password, a.Config.Mail.From)
+	}
+
+	a.Logger.Warn("SMTP is not configured, mail will be kept in memory and not delivered")
+	return mail_sender.NewMemorySender()
+}
+
+// passwordHashInitialization hashes new passwords with the configured algorithm while still
+// accepting hashes made by the other one, so that they are upgraded on the next login.
+func (a *App) passwordHashInitialization() password_hash.PasswordHash {
+	bcryptHash := password_hash.NewBcryptHash(a.Config.PasswordHash.BcryptCost)
+	argon2idHash := password_hash.NewArgon2idHash(password_hash.Argon2idParams{
+		Memory:      a.Config.PasswordHash.Argon2id.Memory,
+		Iterations:  a.Config.PasswordHash.Argon2id.Iterations,
+		Parallelism: a.Config.PasswordHash.Argon2id.Parallelism,
+		SaltLength:  a.Config.PasswordHash.Argon2id.SaltLength,
+		KeyLength:   a.Config.PasswordHash.Argon2id.KeyLength,
+	})
+
+	if a.Config.PasswordHash.Algorithm == "bcrypt" {
+		return password_hash.NewRegistry(bcryptHash, argon2idHash)
+	}
+
+	return password_hash.NewRegistry(argon2idHash, bcryptHash)
+}
+
+// cacheInitialization puts read-through caches in front of the task catalog and the categories,
+// which are read on every page of the catalog and rarely change.
+func (a *App) cacheInitialization(r *Repositories) []repository_interfaces.ICache {
+	if !a.Config.Cache.Enabled {
+		a.Logger.Info("Caching of repositories is disabled")
+		return nil
+	}
+
+	taskCache := cache.New("tasks", a.Config.Cache.TTL, a.Config.Cache.MaxEntries)
+	categoryCache := cache.New("categories", a.Config.Cache.TTL, a.Config.Cache.MaxEntries)
+
+	r.TaskRepository = cache.NewTaskRepository(r.TaskRepository, taskCache)
+	r.CategoryRepository = cache.NewCategoryRepository(r.CategoryRepository, categoryCache)
+
+	a.Logger.Info("Success initialization of caches", "ttl", a.Config.Cache.TTL, "max_entries", a.Config.Cache.MaxEntries)
+	return []repository_interfaces.ICache{taskCache, categoryCache}
+}
+
+func (a *App) taxPolicyInitialization() services.TaxPolicy {
+	policy := services.TaxPolicy{
+		PricesIncludeTax: a.Config.Tax.PricesIncludeTax,
+		DefaultRate:      a.Config.Tax.DefaultRate,
+	}
+
+	defaultFound := false
+	for _, rate := range a.Config.Tax.Rates {
+		policy.Rates = append(policy.Rates, models.TaxRate(rate))
+		defaultFound = defaultFound || rate.Code == policy.DefaultRate
+	}
+
+	if len(policy.Rates) == 0 {
+		a.Logger.Warn("Tax rates are not configured, orders will not be taxed")
+	} else if !defaultFound {
+		a.Logger.Warn("The default tax rate is not configured, tasks without a rate will not be taxed", "default_rate", policy.DefaultRate)
+	}
+	return policy
+}
+
 func (a *App) servicesInitialization(r *Repositories) *Services {
-	passwordHash := password_hash.NewPasswordHash()
+	passwordHash := a.passwordHashInitialization()
+	mailSender := a.mailSenderInitialization()
+	caches := a.cacheInitialization(r)
+	verificationRequired := a.emailVerificationRequired()
+
+	orderService := services.NewOrderService(r.OrderRepository, r.WorkerRepository, r.TaskRepository, r.UserRepository, r.CategoryRepository, a.taxPolicyInitialization(), verificationRequired, a.Logger)
 
 	s := &Services{
 		UserService:     services.NewUserService(r.UserRepository, passwordHash, a.Logger),
 		WorkerService:   services.NewWorkerService(r.WorkerRepository, passwordHash, a.Logger),
-		OrderService:    services.NewOrderService(r.OrderRepository, r.WorkerRepository, r.TaskRepository, r.UserRepository, a.Logger),
-		TaskService:     services.NewTaskService(r.TaskRepository, a.Logger),
+		OrderService:    orderService,
+		TaskService:     services.NewTaskService(r.TaskRepository, r.CategoryRepository, a.Logger),
 		CategoryService: services.NewCategoryService(r.CategoryRepository, r.TaskRepository, a.Logger),
+		SessionService:  services.NewSessionService(r.SessionRepository, a.Config.Session.IdleTimeout, a.Config.Session.AbsoluteTimeout, a.Logger),
+		LoginAttemptService: services.NewLoginAttemptService(r.LoginAttemptRepository, services.LoginAttemptPolicy{
+			MaxAttempts:     a.Config.SignIn.MaxAttempts,
+			IPMaxAttempts:   a.Config.SignIn.IPMaxAttempts,
+			FreeAttempts:    a.Config.SignIn.FreeAttempts,
+			BaseDelay:       a.Config.SignIn.BaseDelay,
+			MaxDelay:        a.Config.SignIn.MaxDelay,
+			LockoutDuration: a.Config.SignIn.LockoutDuration,
+			Window:          a.Config.SignIn.Window,
+		}, a.Logger),
+		PasswordResetService:     services.NewPasswordResetService(r.UserRepository, r.WorkerRepository, r.OneTimeTokenRepository, r.SessionRepository, passwordHash, mailSender, a.Config.BaseURL, a.Config.PasswordResetTTL, a.Logger),
+		EmailVerificationService: services.NewEmailVerificationService(r.UserRepository, r.OneTimeTokenRepository, mailSender, a.Config.BaseURL, a.Config.EmailVerificationTTL, verificationRequired, a.Logger),
+		TwoFactorService:         services.NewTwoFactorService(r.TwoFactorRepository, a.Config.TOTPIssuer, a.Logger),
+		AuditService:             services.NewAuditService(r.AuditRepository, a.Logger),
+		InvoiceService:           services.NewInvoiceService(r.InvoiceRepository, orderService, r.UserRepository, models.Requisites(a.Config.Invoice), a.Logger),
+		CacheService:             services.NewCacheService(caches, a.Logger),
 	}
 	a.Logger.Info("Success initialization of services")
 
@@ -110,17 +300,54 @@ func (a *App) Init() error {
 			return err
 		}
 
+		a.Migrator = postgres.CreateMigrator(fields)
+		err = a.migrationsInitialization()
+		if err != nil {
+			return err
+		}
+
 		a.Repositories = a.postgresRepositoriesInitialization(fields)
 		a.Services = a.servicesInitialization(a.Repositories)
 	} else if a.Config.DBType == "mongodb" {
-		fields, err := mongodb.NewMongoConnection(a.Config.DBFlags, a.Logger)
+		fields, err := mongodb.NewMongoConnection(a.Config.DBFlags, a.Config.Mongo, a.Logger)
 		if err != nil {
 			a.Logger.Fatal("Error create mongodb repository fields", "err", err)
 			return err
 		}
+
+		a.Migrator = mongodb.CreateMigrator(fields)
+		err = a.migrationsInitialization()
+		if err != nil {
+			return err
+		}
 		a.Repositories = a.mongoRepositoriesInitialization(fields)
 		a.Services = a.servicesInitialization(a.Repositories)
 
+	} else if a.Config.DBType == "memory" {
+		fields, err := memory.NewMemoryConnection(a.Config.Memory, a.Logger)
+		if err != nil {
+			a.Logger.Fatal("Error create in-memory repository fields", "err", err)
+			return err
+		}
+
+		a.Migrator = memory.CreateMigrator(fields)
+		a.Repositories = a.memoryRepositoriesInitialization(fields)
+		a.Services = a.servicesInitialization(a.Repositories)
+	} else if a.Config.DBType == "sqlite" {
+		fields, err := sqlite.NewSQLiteConnection(a.Config.SQLite, a.Logger)
+		if err != nil {
+			a.Logger.Fatal("Error create sqlite repository fields", "err", err)
+			return err
+		}
+
+		a.Migrator = sqlite.CreateMigrator(fields)
+		err = a.migrationsInitialization()
+		if err != nil {
+			return err
+		}
+
+		a.Repositories = a.sqliteRepositoriesInitialization(fields)
+		a.Services = a.servicesInitialization(a.Repositories)
 	}
 
 	return nil
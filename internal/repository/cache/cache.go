@@ -0,0 +1,140 @@
+// Package cache wraps repositories with read-through caches, so that rarely changing data such as
+// the task catalog and the categories is not read from the database on every page view.
+//
+// A cache keeps at most MaxEntries results, each for TTL. Every write through a cached repository
+// drops all results of that repository, so that its readers see the change at once. Writes made by
+// other application instances are only seen after TTL.
+package cache
+
+import (
+	"container/list"
+	"lab3/internal/models"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// Cache is a least recently used cache of repository results with expiration.
+type Cache struct {
+	name       string
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// recent holds the entries, the most recently used first
+	recent *list.List
+	// generation is increased by Invalidate, so that a result read before the invalidation is not stored after it
+	generation uint64
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// New creates a cache. A non-positive maxEntries leaves the number of entries unlimited,
+// a non-positive ttl keeps the entries until they are invalidated or evicted.
+func New(name string, ttl time.Duration, maxEntries int) *Cache {
+	return &Cache{
+		name:       name,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]*list.Element),
+		recent:     list.New(),
+	}
+}
+
+// get returns the cached value of key and the current generation.
+func (c *Cache) get(key string) (interface{}, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if ok && c.ttl > 0 && !c.now().Before(element.Value.(*entry).expiresAt) {
+		c.remove(element)
+		ok = false
+	}
+
+	if !ok {
+		c.misses++
+		return nil, c.generation, false
+	}
+
+	c.hits++
+	c.recent.MoveToFront(element)
+	return element.Value.(*entry).value, c.generation, true
+}
+
+// set stores the value of key unless the cache was invalidated since generation was returned by get.
+func (c *Cache) set(key string, value interface{}, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+
+	c.entries[key] = c.recent.PushFront(&entry{key: key, value: value, expiresAt: c.now().Add(c.ttl)})
+
+	for c.maxEntries > 0 && c.recent.Len() > c.maxEntries {
+		c.remove(c.recent.Back())
+		c.evictions++
+	}
+}
+
+func (c *Cache) remove(element *list.Element) {
+	c.recent.Remove(element)
+	delete(c.entries, element.Value.(*entry).key)
+}
+
+// load returns the cached value of key, reading it with fetch on a miss. Errors are not cached.
+func (c *Cache) load(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	value, generation, ok := c.get(key)
+	if ok {
+		return value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.set(key, value, generation)
+	return value, nil
+}
+
+// Invalidate drops all entries.
+func (c *Cache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.entries = make(map[string]*list.Element)
+	c.recent.Init()
+}
+
+func (c *Cache) Stats() models.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return models.CacheStats{
+		Name:       c.name,
+		Hits:       c.hits,
+		Misses:     c.misses,
+		Evictions:  c.evictions,
+		Entries:    c.recent.Len(),
+		MaxEntries: c.maxEntries,
+		TTL:        c.ttl,
+	}
+}
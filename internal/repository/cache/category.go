@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"fmt"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_interfaces"
+)
+
+type CategoryRepository struct {
+	repository repository_interfaces.ICategoryRepository
+	cache      *Cache
+}
+
+// NewCategoryRepository caches the reads of repository in cache.
+func NewCategoryRepository(repository repository_interfaces.ICategoryRepository, cache *Cache) repository_interfaces.ICategoryRepository {
+	return &CategoryRepository{repository: repository, cache: cache}
+}
+
+// categories copies the cached list, so that changes made by the caller do not reach the cache.
+func (c CategoryRepository) categories(key string, fetch func() ([]models.Category, error)) ([]models.Category, error) {
+	value, err := c.cache.load(key, func() (interface{}, error) {
+		categories, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		return append([]models.Category(nil), categories...), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]models.Category(nil), value.([]models.Category)...), nil
+}
+
+func (c CategoryRepository) GetAll() ([]models.Category, error) {
+	return c.categories("all", c.repository.GetAll)
+}
+
+func (c CategoryRepository) GetByID(id int) (*models.Category, error) {
+	value, err := c.cache.load(fmt.Sprintf("id:%d", id), func() (interface{}, error) {
+		category, err := c.repository.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		return *category, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	category := value.(models.Category)
+	return &category, nil
+}
+
+func (c CategoryRepository) Create(category *models.Category) (*models.Category, error) {
+	defer c.cache.Invalidate()
+	return c.repository.Create(category)
+}
+
+func (c CategoryRepository) Update(category *models.Category) (*models.Category, error) {
+	defer c.cache.Invalidate()
+	return c.repository.Update(category)
+}
+
+func (c CategoryRepository) Reorder(ids []int) error {
+	defer c.cache.Invalidate()
+	return c.repository.Reorder(ids)
+}
+
+func (c CategoryRepository) Delete(id int) error {
+	defer c.cache.Invalidate()
+	return c.repository.Delete(id)
+}
+
+func (c CategoryRepository) Restore(id int) error {
+	defer c.cache.Invalidate()
+	return c.repository.Restore(id)
+}
+
+func (c CategoryRepository) GetDeleted() ([]models.Category, error) {
+	return c.categories("deleted", c.repository.GetDeleted)
+}
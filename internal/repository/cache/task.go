@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"fmt"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_interfaces"
+	"lab3/internal/search"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TaskRepository struct {
+	repository repository_interfaces.ITaskRepository
+	cache      *Cache
+}
+
+// NewTaskRepository caches the reads of repository in cache.
+func NewTaskRepository(repository repository_interfaces.ITaskRepository, cache *Cache) repository_interfaces.ITaskRepository {
+	return &TaskRepository{repository: repository, cache: cache}
+}
+
+// task copies the cached task, so that changes made by the caller do not reach the cache.
+func (t TaskRepository) task(key string, fetch func() (*models.Task, error)) (*models.Task, error) {
+	value, err := t.cache.load(key, func() (interface{}, error) {
+		task, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		return *task, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	task := value.(models.Task)
+	return &task, nil
+}
+
+// tasks copies the cached list, so that changes made by the caller do not reach the cache.
+func (t TaskRepository) tasks(key string, fetch func() ([]models.Task, error)) ([]models.Task, error) {
+	value, err := t.cache.load(key, func() (interface{}, error) {
+		tasks, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		return append([]models.Task(nil), tasks...), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]models.Task(nil), value.([]models.Task)...), nil
+}
+
+func (t TaskRepository) Create(task *models.Task) (*models.Task, error) {
+	defer t.cache.Invalidate()
+	return t.repository.Create(task)
+}
+
+func (t TaskRepository) Delete(id uuid.UUID) error {
+	defer t.cache.Invalidate()
+	return t.repository.Delete(id)
+}
+
+func (t TaskRepository) Restore(id uuid.UUID) error {
+	defer t.cache.Invalidate()
+	return t.repository.Restore(id)
+}
+
+func (t TaskRepository) Update(task *models.Task) (*models.Task, error) {
+	defer t.cache.Invalidate()
+	return t.repository.Update(task)
+}
+
+func (t TaskRepository) GetTaskByID(id uuid.UUID) (*models.Task, error) {
+	return t.task("id:"+id.String(), func() (*models.Task, error) {
+		return t.repository.GetTaskByID(id)
+	})
+}
+
+func (t TaskRepository) GetAllTasks() ([]models.Task, error) {
+	return t.tasks("all", t.repository.GetAllTasks)
+}
+
+func (t TaskRepository) GetTasksInCategory(category int) ([]models.Task, error) {
+	return t.tasks(fmt.Sprintf("category:%d", category), func() ([]models.Task, error) {
+		return t.repository.GetTasksInCategory(category)
+	})
+}
+
+func (t TaskRepository) MoveToCategory(from, to int) (int, error) {
+	defer t.cache.Invalidate()
+	return t.repository.MoveToCategory(from, to)
+}
+
+func (t TaskRepository) GetTaskByName(name string) (*models.Task, error) {
+	return t.task("name:"+name, func() (*models.Task, error) {
+		return t.repository.GetTaskByName(name)
+	})
+}
+
+func (t TaskRepository) GetDeletedTasks() ([]models.Task, error) {
+	return t.tasks("deleted", t.repository.GetDeletedTasks)
+}
+
+func (t TaskRepository) Search(query string) ([]models.Task, error) {
+	return t.tasks("search:"+strings.Join(search.Words(query), " "), func() ([]models.Task, error) {
+		return t.repository.Search(query)
+	})
+}
+
+// The prices are not cached. A scheduled price changes the tasks only when ApplyPrices brings it in force.
+func (t TaskRepository) SchedulePrice(price *models.TaskPrice) (*models.TaskPrice, error) {
+	return t.repository.SchedulePrice(price)
+}
+
+func (t TaskRepository) DeletePrice(id uuid.UUID) error {
+	return t.repository.DeletePrice(id)
+}
+
+func (t TaskRepository) GetPrices(taskID uuid.UUID) ([]models.TaskPrice, error) {
+	return t.repository.GetPrices(taskID)
+}
+
+func (t TaskRepository) GetUpcomingPrices(moment time.Time) ([]models.TaskPrice, error) {
+	return t.repository.GetUpcomingPrices(moment)
+}
+
+// ApplyPrices runs on a timer, so the cache is kept while no price comes in force.
+func (t TaskRepository) ApplyPrices(moment time.Time) (int, error) {
+	changed, err := t.repository.ApplyPrices(moment)
+	if changed > 0 || err != nil {
+		t.cache.Invalidate()
+	}
+	return changed, err
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_interfaces"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+type AuditRepository struct {
+	store *Store
+}
+
+func NewAuditRepository(store *Store) repository_interfaces.IAuditRepository {
+	return &AuditRepository{store: store}
+}
+
+func (a AuditRepository) Create(entry *models.AuditEntry) error {
+	created := *entry
+	created.ID = uuid.New()
+
+	err := a.store.write(func(data *snapshot) error {
+		data.Audit = append(data.Audit, created)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	entry.ID = created.ID
+	return nil
+}
+
+func matchesAuditFilter(entry *models.AuditEntry, filter models.AuditFilter) bool {
+	return (filter.ActorID == uuid.Nil || entry.ActorID == filter.ActorID) &&
+		(filter.Action == "" || entry.Action == filter.Action) &&
+		(filter.TargetType == "" || entry.TargetType == filter.TargetType) &&
+		(filter.TargetID == "" || entry.TargetID == filter.TargetID) &&
+		(filter.From.IsZero() || !entry.CreatedAt.Before(filter.From)) &&
+		(filter.To.IsZero() || entry.CreatedAt.Before(filter.To))
+}
+
+func (a AuditRepository) Filter(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	entries := make([]models.AuditEntry, 0)
+	_ = a.store.read(func(data *snapshot) error {
+		for i := range data.Audit {
+			if matchesAuditFilter(&data.Audit[i], filter) {
+				entries = append(entries, data.Audit[i])
+			}
+		}
+		return nil
+	})
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.After(entries[j].CreatedAt)
+	})
+
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+
+	return entries, nil
+}
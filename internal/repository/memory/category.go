@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"sort"
+	"time"
+)
+
+type CategoryRepository struct {
+	store *Store
+}
+
+func NewCategoryRepository(store *Store) *CategoryRepository {
+	return &CategoryRepository{store: store}
+}
+
+func (d *snapshot) categoryIndex(id int) int {
+	for i := range d.Categories {
+		if d.Categories[i].ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (c CategoryRepository) find(match func(category *models.Category) bool) []models.Category {
+	var categories []models.Category
+	_ = c.store.read(func(data *snapshot) error {
+		for i := range data.Categories {
+			if match(&data.Categories[i]) {
+				categories = append(categories, data.Categories[i])
+			}
+		}
+		return nil
+	})
+
+	return categories
+}
+
+func (c CategoryRepository) GetAll() ([]models.Category, error) {
+	categories := c.find(func(category *models.Category) bool {
+		return !category.IsDeleted()
+	})
+
+	sort.SliceStable(categories, func(i, j int) bool {
+		if categories[i].Position != categories[j].Position {
+			return categories[i].Position < categories[j].Position
+		}
+		return categories[i].ID < categories[j].ID
+	})
+
+	return categories, nil
+}
+
+func (c CategoryRepository) GetDeleted() ([]models.Category, error) {
+	categories := c.find(func(category *models.Category) bool {
+		return category.IsDeleted()
+	})
+
+	sort.SliceStable(categories, func(i, j int) bool {
+		return categories[i].DeletedAt.After(categories[j].DeletedAt)
+	})
+
+	return categories, nil
+}
+
+func (c CategoryRepository) GetByID(id int) (*models.Category, error) {
+	var category models.Category
+	err := c.store.read(func(data *snapshot) error {
+		i := data.categoryIndex(id)
+		if i == -1 {
+			return repository_errors.DoesNotExist
+		}
+
+		category = data.Categories[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// Create takes the next value of the sequence, the counterpart of a serial column.
+func (c CategoryRepository) Create(category *models.Category) (*models.Category, error) {
+	if category.Name == "" {
+		return nil, repository_errors.InsertError
+	}
+
+	var created models.Category
+	err := c.store.write(func(data *snapshot) error {
+		if category.ParentID != 0 && data.categoryIndex(category.ParentID) == -1 {
+			return repository_errors.InsertError
+		}
+
+		// the category is placed after all the others
+		position := 0
+		for i := range data.Categories {
+			if data.Categories[i].Position > position {
+				position = data.Categories[i].Position
+			}
+		}
+
+		data.CategorySequence++
+		created = models.Category{
+			ID:       data.CategorySequence,
+			Name:     category.Name,
+			ParentID: category.ParentID,
+			Position: position + 1,
+			Hidden:   category.Hidden,
+			TaxRate:  category.TaxRate,
+		}
+		data.Categories = append(data.Categories, created)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (c CategoryRepository) Update(category *models.Category) (*models.Category, error) {
+	if category.Name == "" {
+		return nil, repository_errors.InsertError
+	}
+
+	var updated models.Category
+	err := c.store.write(func(data *snapshot) error {
+		i := data.categoryIndex(category.ID)
+		if i == -1 || (category.ParentID != 0 && data.categoryIndex(category.ParentID) == -1) {
+			return repository_errors.UpdateError
+		}
+
+		data.Categories[i].Name = category.Name
+		data.Categories[i].ParentID = category.ParentID
+		data.Categories[i].Hidden = category.Hidden
+		data.Categories[i].TaxRate = category.TaxRate
+		updated = data.Categories[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+func (c CategoryRepository) Reorder(ids []int) error {
+	return c.store.write(func(data *snapshot) error {
+		indexes := make([]int, len(ids))
+		for position, id := range ids {
+			indexes[position] = data.categoryIndex(id)
+			if indexes[position] == -1 {
+				return repository_errors.UpdateError
+			}
+		}
+
+		for position, i := range indexes {
+			data.Categories[i].Position = position + 1
+		}
+		return nil
+	})
+}
+
+func (c CategoryRepository) Delete(id int) error {
+	return c.store.write(func(data *snapshot) error {
+		i := data.categoryIndex(id)
+		if i == -1 || data.Categories[i].IsDeleted() {
+			return repository_errors.DoesNotExist
+		}
+
+		data.Categories[i].DeletedAt = time.Now()
+		return nil
+	})
+}
+
+func (c CategoryRepository) Restore(id int) error {
+	return c.store.write(func(data *snapshot) error {
+		i := data.categoryIndex(id)
+		if i == -1 || !data.Categories[i].IsDeleted() {
+			return repository_errors.DoesNotExist
+		}
+
+		data.Categories[i].DeletedAt = time.Time{}
+		return nil
+	})
+}
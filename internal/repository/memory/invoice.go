@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+type InvoiceRepository struct {
+	store *Store
+}
+
+func NewInvoiceRepository(store *Store) repository_interfaces.IInvoiceRepository {
+	return &InvoiceRepository{store: store}
+}
+
+func (i InvoiceRepository) Create(invoice *models.Invoice) (*models.Invoice, error) {
+	created := *invoice
+	created.ID = uuid.New()
+	created.Lines = append([]models.InvoiceLine(nil), invoice.Lines...)
+
+	err := i.store.write(func(data *snapshot) error {
+		created.Number = 1
+		for _, existing := range data.Invoices {
+			if existing.OrderID == invoice.OrderID {
+				return repository_errors.AlreadyExists
+			}
+			if existing.Number >= created.Number {
+				created.Number = existing.Number + 1
+			}
+		}
+
+		data.Invoices = append(data.Invoices, created)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (i InvoiceRepository) find(match func(invoice *models.Invoice) bool) (*models.Invoice, error) {
+	var found *models.Invoice
+	_ = i.store.read(func(data *snapshot) error {
+		for j := range data.Invoices {
+			if match(&data.Invoices[j]) {
+				invoice := data.Invoices[j]
+				found = &invoice
+				return nil
+			}
+		}
+		return nil
+	})
+
+	if found == nil {
+		return nil, repository_errors.DoesNotExist
+	}
+	return found, nil
+}
+
+func (i InvoiceRepository) GetByID(id uuid.UUID) (*models.Invoice, error) {
+	return i.find(func(invoice *models.Invoice) bool { return invoice.ID == id })
+}
+
+func (i InvoiceRepository) GetByOrderID(orderID uuid.UUID) (*models.Invoice, error) {
+	return i.find(func(invoice *models.Invoice) bool { return invoice.OrderID == orderID })
+}
+
+func (i InvoiceRepository) GetAll() ([]models.Invoice, error) {
+	invoices := make([]models.Invoice, 0)
+	_ = i.store.read(func(data *snapshot) error {
+		invoices = append(invoices, data.Invoices...)
+		return nil
+	})
+
+	sort.SliceStable(invoices, func(j, k int) bool {
+		return invoices[j].Number > invoices[k].Number
+	})
+
+	return invoices, nil
+}
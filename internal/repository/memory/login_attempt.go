@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"sort"
+	"time"
+)
+
+type LoginAttemptRepository struct {
+	store *Store
+}
+
+func NewLoginAttemptRepository(store *Store) repository_interfaces.ILoginAttemptRepository {
+	return &LoginAttemptRepository{store: store}
+}
+
+func (d *snapshot) loginAttemptIndex(key string) int {
+	for i := range d.LoginAttempts {
+		if d.LoginAttempts[i].Key == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (l LoginAttemptRepository) GetByKey(key string) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+	err := l.store.read(func(data *snapshot) error {
+		i := data.loginAttemptIndex(key)
+		if i == -1 {
+			return repository_errors.DoesNotExist
+		}
+
+		attempt = data.LoginAttempts[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+func (l LoginAttemptRepository) RegisterFailure(key string, at time.Time, resetBefore time.Time) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+	err := l.store.write(func(data *snapshot) error {
+		i := data.loginAttemptIndex(key)
+		if i == -1 {
+			attempt = models.LoginAttempt{Key: key, Failures: 1, LastFailure: at}
+			data.LoginAttempts = append(data.LoginAttempts, attempt)
+			return nil
+		}
+
+		stored := &data.LoginAttempts[i]
+		lockEnded := !stored.LockedUntil.IsZero() && !stored.LockedUntil.After(at)
+		if lockEnded {
+			stored.LockedUntil = time.Time{}
+		}
+		if stored.LastFailure.Before(resetBefore) || lockEnded {
+			stored.Failures = 1
+		} else {
+			stored.Failures++
+		}
+		stored.LastFailure = at
+
+		attempt = *stored
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+func (l LoginAttemptRepository) Lock(key string, until time.Time) error {
+	return l.store.write(func(data *snapshot) error {
+		i := data.loginAttemptIndex(key)
+		if i == -1 {
+			return repository_errors.DoesNotExist
+		}
+
+		data.LoginAttempts[i].LockedUntil = until
+		return nil
+	})
+}
+
+func (l LoginAttemptRepository) Delete(key string) error {
+	return l.store.write(func(data *snapshot) error {
+		i := data.loginAttemptIndex(key)
+		if i != -1 {
+			data.LoginAttempts = append(data.LoginAttempts[:i], data.LoginAttempts[i+1:]...)
+		}
+		return nil
+	})
+}
+
+func (l LoginAttemptRepository) GetLocked(at time.Time) ([]models.LoginAttempt, error) {
+	var attempts []models.LoginAttempt
+	_ = l.store.read(func(data *snapshot) error {
+		for i := range data.LoginAttempts {
+			if data.LoginAttempts[i].LockedUntil.After(at) {
+				attempts = append(attempts, data.LoginAttempts[i])
+			}
+		}
+		return nil
+	})
+
+	sort.SliceStable(attempts, func(i, j int) bool {
+		return attempts[i].LockedUntil.After(attempts[j].LockedUntil)
+	})
+
+	return attempts, nil
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"lab3/config"
+	"lab3/internal/repository/repository_interfaces"
+
+	"github.com/charmbracelet/log"
+)
+
+type MemoryConnection struct {
+	Store  *Store
+	Config config.MemoryConfig
+}
+
+func NewMemoryConnection(memory config.MemoryConfig, logger *log.Logger) (*MemoryConnection, error) {
+	store, err := NewStore(memory.SnapshotFile, memory.SaveSnapshot)
+	if err != nil {
+		logger.Error("MEMORY! Error load snapshot", "file", memory.SnapshotFile, "err", err)
+		return nil, err
+	}
+
+	if memory.SnapshotFile == "" {
+		logger.Warn("MEMORY! Snapshot file is not configured, data will be lost on exit")
+	} else if !memory.SaveSnapshot {
+		logger.Warn("MEMORY! Snapshot is only loaded, changes will be lost on exit", "file", memory.SnapshotFile)
+	}
+
+	logger.Info("MEMORY! Successfully create in-memory repository fields")
+
+	return &MemoryConnection{Store: store, Config: memory}, nil
+}
+
+func CreateUserRepository(fields *MemoryConnection) repository_interfaces.IUserRepository {
+	return NewUserRepository(fields.Store)
+}
+
+func CreateWorkerRepository(fields *MemoryConnection) repository_interfaces.IWorkerRepository {
+	return NewWorkerRepository(fields.Store)
+}
+
+func CreateOrderRepository(fields *MemoryConnection) repository_interfaces.IOrderRepository {
+	return NewOrderRepository(fields.Store)
+}
+
+func CreateTaskRepository(fields *MemoryConnection) repository_interfaces.ITaskRepository {
+	return NewTaskRepository(fields.Store)
+}
+
+func CreateCategoryRepository(fields *MemoryConnection) repository_interfaces.ICategoryRepository {
+	return NewCategoryRepository(fields.Store)
+}
+
+func CreateSessionRepository(fields *MemoryConnection) repository_interfaces.ISessionRepository {
+	return NewSessionRepository(fields.Store)
+}
+
+func CreateLoginAttemptRepository(fields *MemoryConnection) repository_interfaces.ILoginAttemptRepository {
+	return NewLoginAttemptRepository(fields.Store)
+}
+
+func CreateOneTimeTokenRepository(fields *MemoryConnection) repository_interfaces.IOneTimeTokenRepository {
+	return NewOneTimeTokenRepository(fields.Store)
+}
+
+func CreateTwoFactorRepository(fields *MemoryConnection) repository_interfaces.ITwoFactorRepository {
+	return NewTwoFactorRepository(fields.Store)
+}
+
+func CreateAuditRepository(fields *MemoryConnection) repository_interfaces.IAuditRepository {
+	return NewAuditRepository(fields.Store)
+}
+
+func CreateInvoiceRepository(fields *MemoryConnection) repository_interfaces.IInvoiceRepository {
+	return NewInvoiceRepository(fields.Store)
+}
+
+func CreateMigrator(fields *MemoryConnection) repository_interfaces.IMigrator {
+	return NewMigrator()
+}
@@ -0,0 +1,27 @@
+package memory
+
+import (
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+)
+
+// Migrator of the in-memory backend has nothing to do: the layout of the data is the snapshot type,
+// and a snapshot of an older layout is read with zero values in the new fields.
+type Migrator struct{}
+
+func NewMigrator() repository_interfaces.IMigrator {
+	return &Migrator{}
+}
+
+func (m Migrator) Up() ([]models.Migration, error) {
+	return nil, nil
+}
+
+func (m Migrator) Down() (*models.Migration, error) {
+	return nil, repository_errors.DoesNotExist
+}
+
+func (m Migrator) Status() ([]models.Migration, error) {
+	return []models.Migration{}, nil
+}
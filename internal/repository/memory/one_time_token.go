@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OneTimeTokenRepository struct {
+	store *Store
+}
+
+func NewOneTimeTokenRepository(store *Store) repository_interfaces.IOneTimeTokenRepository {
+	return &OneTimeTokenRepository{store: store}
+}
+
+func (d *snapshot) deleteOneTimeTokens(match func(token *models.OneTimeToken) bool) {
+	tokens := d.OneTimeTokens[:0]
+	for i := range d.OneTimeTokens {
+		if !match(&d.OneTimeTokens[i]) {
+			tokens = append(tokens, d.OneTimeTokens[i])
+		}
+	}
+	d.OneTimeTokens = tokens
+}
+
+func (o OneTimeTokenRepository) Create(token *models.OneTimeToken) error {
+	return o.store.write(func(data *snapshot) error {
+		for i := range data.OneTimeTokens {
+			if data.OneTimeTokens[i].Hash == token.Hash {
+				return repository_errors.InsertError
+			}
+		}
+		if token.UserID != uuid.Nil && data.userIndex(token.UserID) == -1 {
+			return repository_errors.InsertError
+		}
+		if token.WorkerID != uuid.Nil && data.workerIndex(token.WorkerID) == -1 {
+			return repository_errors.InsertError
+		}
+
+		data.OneTimeTokens = append(data.OneTimeTokens, *token)
+		return nil
+	})
+}
+
+func (o OneTimeTokenRepository) Consume(hash string, purpose string, at time.Time) (*models.OneTimeToken, error) {
+	var token models.OneTimeToken
+	err := o.store.write(func(data *snapshot) error {
+		for i := range data.OneTimeTokens {
+			stored := data.OneTimeTokens[i]
+			if stored.Hash == hash && stored.Purpose == purpose && stored.ExpiresAt.After(at) {
+				token = stored
+				data.OneTimeTokens = append(data.OneTimeTokens[:i], data.OneTimeTokens[i+1:]...)
+				return nil
+			}
+		}
+
+		return repository_errors.DoesNotExist
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (o OneTimeTokenRepository) DeleteByUserID(purpose string, userID uuid.UUID) error {
+	return o.store.write(func(data *snapshot) error {
+		data.deleteOneTimeTokens(func(token *models.OneTimeToken) bool {
+			return token.Purpose == purpose && token.UserID == userID
+		})
+		return nil
+	})
+}
+
+func (o OneTimeTokenRepository) DeleteByWorkerID(purpose string, workerID uuid.UUID) error {
+	return o.store.write(func(data *snapshot) error {
+		data.deleteOneTimeTokens(func(token *models.OneTimeToken) bool {
+			return token.Purpose == purpose && token.WorkerID == workerID
+		})
+		return nil
+	})
+}
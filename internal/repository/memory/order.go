@@ -0,0 +1,442 @@
+package memory
+
+import (
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OrderRepository struct {
+	store *Store
+}
+
+func NewOrderRepository(store *Store) repository_interfaces.IOrderRepository {
+	return &OrderRepository{store: store}
+}
+
+func (d *snapshot) orderIndex(id uuid.UUID) int {
+	for i := range d.Orders {
+		if d.Orders[i].ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (d *snapshot) orderLineIndex(orderID uuid.UUID, taskID uuid.UUID) int {
+	for i := range d.OrderLines {
+		if d.OrderLines[i].OrderID == orderID && d.OrderLines[i].TaskID == taskID {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (o OrderRepository) Create(order *models.Order, orderedTasks []models.OrderedTask) (*models.Order, error) {
+	created := *order
+	created.ID = uuid.New()
+	created.Version = 1
+	if created.CreationDate.IsZero() {
+		created.CreationDate = time.Now()
+	}
+
+	err := o.store.write(func(data *snapshot) error {
+		if data.userIndex(created.UserID) == -1 {
+			return repository_errors.InsertError
+		}
+
+		// a key the client has already used inserts nothing, so the order is reported as already existing
+		if created.IdempotencyKey != "" {
+			for i := range data.Orders {
+				if data.Orders[i].UserID == created.UserID && data.Orders[i].IdempotencyKey == created.IdempotencyKey {
+					return repository_errors.AlreadyExists
+				}
+			}
+		}
+
+		for _, task := range orderedTasks {
+			if data.taskIndex(task.Task.ID) == -1 {
+				return repository_errors.InsertError
+			}
+		}
+
+		data.Orders = append(data.Orders, created)
+		for _, task := range orderedTasks {
+			data.OrderLines = append(data.OrderLines, orderLine{OrderID: created.ID, TaskID: task.Task.ID, Quantity: task.Quantity})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (o OrderRepository) Delete(id uuid.UUID) error {
+	return o.store.write(func(data *snapshot) error {
+		i := data.orderIndex(id)
+		if i == -1 {
+			return repository_errors.DoesNotExist
+		}
+
+		lines := data.OrderLines[:0]
+		for _, line := range data.OrderLines {
+			if line.OrderID != id {
+				lines = append(lines, line)
+			}
+		}
+		data.OrderLines = lines
+		data.Orders = append(data.Orders[:i], data.Orders[i+1:]...)
+		return nil
+	})
+}
+
+func (o OrderRepository) Update(order *models.Order) (*models.Order, error) {
+	var updated models.Order
+	err := o.store.write(func(data *snapshot) error {
+		i := data.orderIndex(order.ID)
+		if i == -1 {
+			return repository_errors.DoesNotExist
+		}
+		// the order is only written if nobody has changed it since order.Version was read
+		if data.Orders[i].Version != order.Version {
+			return repository_errors.VersionConflict
+		}
+
+		if order.WorkerID != uuid.Nil && data.workerIndex(order.WorkerID) == -1 {
+			return repository_errors.UpdateError
+		}
+		if order.UserID != uuid.Nil && data.userIndex(order.UserID) == -1 {
+			return repository_errors.UpdateError
+		}
+
+		updated = *order
+		updated.IdempotencyKey = data.Orders[i].IdempotencyKey
+		updated.Version = data.Orders[i].Version + 1
+		data.Orders[i] = updated
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+func (o OrderRepository) GetOrderByID(id uuid.UUID) (*models.Order, error) {
+	orders := o.findInHistory(func(order *models.Order) bool {
+		return order.ID == id
+	})
+	if len(orders) == 0 {
+		return nil, repository_errors.DoesNotExist
+	}
+
+	return &orders[0], nil
+}
+
+func (o OrderRepository) GetOrderByIdempotencyKey(userID uuid.UUID, key string) (*models.Order, error) {
+	orders := o.find(func(order *models.Order) bool {
+		return order.UserID == userID && order.IdempotencyKey == key && key != ""
+	})
+	if len(orders) == 0 {
+		return nil, repository_errors.DoesNotExist
+	}
+
+	return &orders[0], nil
+}
+
+func (o OrderRepository) GetCurrentOrderByUserID(id uuid.UUID) (*models.Order, error) {
+	orders := o.find(func(order *models.Order) bool {
+		return order.UserID == id
+	})
+	if len(orders) == 0 {
+		return nil, repository_errors.DoesNotExist
+	}
+
+	current := orders[0]
+	for _, order := range orders[1:] {
+		if !order.CreationDate.Before(current.CreationDate) {
+			current = order
+		}
+	}
+
+	return &current, nil
+}
+
+func (o OrderRepository) GetAllOrdersByUserID(id uuid.UUID) ([]models.Order, error) {
+	return o.find(func(order *models.Order) bool {
+		return order.UserID == id
+	}), nil
+}
+
+func (o OrderRepository) GetTasksInOrder(id uuid.UUID) ([]models.Task, error) {
+	var tasks []models.Task
+	_ = o.store.read(func(data *snapshot) error {
+		for i := range data.Tasks {
+			if hasLine(data.OrderLines, id, data.Tasks[i].ID) || hasLine(data.ArchivedLines, id, data.Tasks[i].ID) {
+				tasks = append(tasks, data.Tasks[i])
+			}
+		}
+		return nil
+	})
+
+	return tasks, nil
+}
+
+func hasLine(lines []orderLine, orderID uuid.UUID, taskID uuid.UUID) bool {
+	for _, line := range lines {
+		if line.OrderID == orderID && line.TaskID == taskID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o OrderRepository) AddTaskToOrder(orderID uuid.UUID, taskID uuid.UUID) error {
+	return o.store.write(func(data *snapshot) error {
+		if data.orderIndex(orderID) == -1 || data.taskIndex(taskID) == -1 {
+			return repository_errors.InsertError
+		}
+
+		data.OrderLines = append(data.OrderLines, orderLine{OrderID: orderID, TaskID: taskID, Quantity: 1})
+		return nil
+	})
+}
+
+func (o OrderRepository) RemoveTaskFromOrder(orderID uuid.UUID, taskID uuid.UUID) error {
+	return o.store.write(func(data *snapshot) error {
+		lines := data.OrderLines[:0]
+		for _, line := range data.OrderLines {
+			if line.OrderID != orderID || line.TaskID != taskID {
+				lines = append(lines, line)
+			}
+		}
+		data.OrderLines = lines
+		return nil
+	})
+}
+
+func (o OrderRepository) UpdateTaskQuantity(orderID uuid.UUID, taskID uuid.UUID, quantity int) error {
+	return o.store.write(func(data *snapshot) error {
+		for i := range data.OrderLines {
+			if data.OrderLines[i].OrderID == orderID && data.OrderLines[i].TaskID == taskID {
+				data.OrderLines[i].Quantity = quantity
+			}
+		}
+		return nil
+	})
+}
+
+func (o OrderRepository) GetTaskQuantity(orderID uuid.UUID, taskID uuid.UUID) (int, error) {
+	var quantity int
+	err := o.store.read(func(data *snapshot) error {
+		i := data.orderLineIndex(orderID, taskID)
+		if i != -1 {
+			quantity = data.OrderLines[i].Quantity
+			return nil
+		}
+
+		for _, line := range data.ArchivedLines {
+			if line.OrderID == orderID && line.TaskID == taskID {
+				quantity = line.Quantity
+				return nil
+			}
+		}
+
+		return repository_errors.DoesNotExist
+	})
+
+	return quantity, err
+}
+
+func (o OrderRepository) find(match func(order *models.Order) bool) []models.Order {
+	var orders []models.Order
+	_ = o.store.read(func(data *snapshot) error {
+		orders = matchingOrders(data.Orders, match)
+		return nil
+	})
+
+	return orders
+}
+
+// findInHistory is find over both the current and the archived orders.
+func (o OrderRepository) findInHistory(match func(order *models.Order) bool) []models.Order {
+	var orders []models.Order
+	_ = o.store.read(func(data *snapshot) error {
+		orders = append(matchingOrders(data.Orders, match), matchingOrders(data.ArchivedOrders, match)...)
+		return nil
+	})
+
+	return orders
+}
+
+func matchingOrders(orders []models.Order, match func(order *models.Order) bool) []models.Order {
+	var result []models.Order
+	for i := range orders {
+		if match(&orders[i]) {
+			result = append(result, orders[i])
+		}
+	}
+
+	return result
+}
+
+// orderField returns the value of a column of the order as text and whether it is null.
+func orderField(order *models.Order, field string) (string, bool, error) {
+	switch field {
+	case "id":
+		return order.ID.String(), false, nil
+	case "worker_id":
+		return order.WorkerID.String(), order.WorkerID == uuid.Nil, nil
+	case "user_id":
+		return order.UserID.String(), order.UserID == uuid.Nil, nil
+	case "status":
+		return strconv.Itoa(order.Status), false, nil
+	case "address":
+		return order.Address, false, nil
+	case "creation_date":
+		return order.CreationDate.Format("2006-01-02 15:04:05.999999"), false, nil
+	case "deadline":
+		return order.Deadline.Format("2006-01-02 15:04:05.999999"), false, nil
+	case "rate":
+		return strconv.Itoa(order.Rate), false, nil
+	case "idempotency_key":
+		return order.IdempotencyKey, order.IdempotencyKey == "", nil
+	case "version":
+		return strconv.Itoa(order.Version), false, nil
+	case "closed_at":
+		return order.ClosedAt.Format("2006-01-02 15:04:05.999999"), order.ClosedAt.IsZero(), nil
+	default:
+		return "", false, repository_errors.SelectError
+	}
+}
+
+// matchesValue compares a field with one value of a filter parameter. Statuses are compared as numbers,
+// like the status = N condition of the SQL backends.
+func matchesValue(field string, actual string, isNull bool, value string) (bool, error) {
+	switch {
+	case value == "null":
+		return isNull, nil
+	case value == "not null":
+		return !isNull, nil
+	case isNull:
+		return false, nil
+	case field == "status":
+		status, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return false, repository_errors.SelectError
+		}
+		return actual == strconv.Itoa(status), nil
+	default:
+		return actual == value, nil
+	}
+}
+
+func (o OrderRepository) Filter(params map[string]string) ([]models.Order, error) {
+	return o.filter(o.find, params)
+}
+
+func (o OrderRepository) History(params map[string]string) ([]models.Order, error) {
+	orders, err := o.filter(o.findInHistory, params)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(orders, func(i, j int) bool {
+		return orders[i].CreationDate.After(orders[j].CreationDate)
+	})
+	return orders, nil
+}
+
+// filter returns the orders found by find that match the Filter parameters.
+func (o OrderRepository) filter(find func(match func(order *models.Order) bool) []models.Order, params map[string]string) ([]models.Order, error) {
+	// an unknown column fails the query even when there are no orders
+	for field := range params {
+		_, _, err := orderField(&models.Order{}, field)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var filterErr error
+	orders := find(func(order *models.Order) bool {
+		for field, value := range params {
+			actual, isNull, err := orderField(order, field)
+			if err != nil {
+				filterErr = err
+				return false
+			}
+
+			matched := false
+			for _, v := range strings.Split(value, ",") {
+				ok, err := matchesValue(field, actual, isNull, v)
+				if err != nil {
+					filterErr = err
+					return false
+				}
+				if ok {
+					matched = true
+					break
+				}
+			}
+
+			if !matched {
+				return false
+			}
+		}
+
+		return true
+	})
+	if filterErr != nil {
+		return nil, filterErr
+	}
+
+	return orders, nil
+}
+
+func (o OrderRepository) Archive(closedBefore time.Time) (int, error) {
+	count := 0
+	err := o.store.write(func(data *snapshot) error {
+		archived := make(map[uuid.UUID]bool)
+		orders := data.Orders[:0]
+		for _, order := range data.Orders {
+			closed := order.Status == models.CompletedOrderStatus || order.Status == models.CancelledOrderStatus
+			if closed && !order.ClosedAt.IsZero() && order.ClosedAt.Before(closedBefore) {
+				order.Archived = true
+				data.ArchivedOrders = append(data.ArchivedOrders, order)
+				archived[order.ID] = true
+			} else {
+				orders = append(orders, order)
+			}
+		}
+		data.Orders = orders
+
+		lines := data.OrderLines[:0]
+		for _, line := range data.OrderLines {
+			if archived[line.OrderID] {
+				data.ArchivedLines = append(data.ArchivedLines, line)
+			} else {
+				lines = append(lines, line)
+			}
+		}
+		data.OrderLines = lines
+
+		count = len(archived)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
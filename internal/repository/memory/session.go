@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SessionRepository struct {
+	store *Store
+}
+
+func NewSessionRepository(store *Store) repository_interfaces.ISessionRepository {
+	return &SessionRepository{store: store}
+}
+
+func (d *snapshot) sessionIndex(id string) int {
+	for i := range d.Sessions {
+		if d.Sessions[i].ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (d *snapshot) deleteSessions(match func(session *models.Session) bool) {
+	sessions := d.Sessions[:0]
+	for i := range d.Sessions {
+		if !match(&d.Sessions[i]) {
+			sessions = append(sessions, d.Sessions[i])
+		}
+	}
+	d.Sessions = sessions
+}
+
+func (s SessionRepository) Save(session *models.Session) error {
+	if session.ID == "" {
+		return repository_errors.InsertError
+	}
+
+	return s.store.write(func(data *snapshot) error {
+		if session.UserID != uuid.Nil && data.userIndex(session.UserID) == -1 {
+			return repository_errors.InsertError
+		}
+		if session.WorkerID != uuid.Nil && data.workerIndex(session.WorkerID) == -1 {
+			return repository_errors.InsertError
+		}
+
+		i := data.sessionIndex(session.ID)
+		if i == -1 {
+			data.Sessions = append(data.Sessions, *session)
+			return nil
+		}
+
+		saved := *session
+		saved.CreatedAt = data.Sessions[i].CreatedAt
+		data.Sessions[i] = saved
+		return nil
+	})
+}
+
+func (s SessionRepository) GetSessionByID(id string) (*models.Session, error) {
+	var session models.Session
+	err := s.store.read(func(data *snapshot) error {
+		i := data.sessionIndex(id)
+		if i == -1 {
+			return repository_errors.DoesNotExist
+		}
+
+		session = data.Sessions[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (s SessionRepository) UpdateLastActivity(id string, lastActivity time.Time) error {
+	return s.store.write(func(data *snapshot) error {
+		i := data.sessionIndex(id)
+		if i == -1 {
+			return repository_errors.DoesNotExist
+		}
+
+		data.Sessions[i].LastActivity = lastActivity
+		return nil
+	})
+}
+
+func (s SessionRepository) Delete(id string) error {
+	return s.store.write(func(data *snapshot) error {
+		data.deleteSessions(func(session *models.Session) bool {
+			return session.ID == id
+		})
+		return nil
+	})
+}
+
+func (s SessionRepository) DeleteByUserID(userID uuid.UUID) error {
+	return s.store.write(func(data *snapshot) error {
+		data.deleteSessions(func(session *models.Session) bool {
+			return session.UserID == userID
+		})
+		return nil
+	})
+}
+
+func (s SessionRepository) DeleteByWorkerID(workerID uuid.UUID) error {
+	return s.store.write(func(data *snapshot) error {
+		data.deleteSessions(func(session *models.Session) bool {
+			return session.WorkerID == workerID
+		})
+		return nil
+	})
+}
+
+func (s SessionRepository) DeleteExpired(idleBefore time.Time, createdBefore time.Time) error {
+	return s.store.write(func(data *snapshot) error {
+		data.deleteSessions(func(session *models.Session) bool {
+			return session.LastActivity.Before(idleBefore) || session.CreatedAt.Before(createdBefore)
+		})
+		return nil
+	})
+}
@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type orderLine struct {
+	OrderID  uuid.UUID `json:"order_id"`
+	TaskID   uuid.UUID `json:"task_id"`
+	Quantity int       `json:"quantity"`
+}
+
+type recoveryCode struct {
+	WorkerID uuid.UUID `json:"worker_id"`
+	CodeHash string    `json:"code_hash"`
+}
+
+// snapshot is the whole content of the store, it is also the format of the snapshot file.
+// Rows are kept in insertion order, like a heap table read without ORDER BY.
+type snapshot struct {
+	Users            []models.User         `json:"users"`
+	Workers          []models.Worker       `json:"workers"`
+	Tasks            []models.Task         `json:"tasks"`
+	TaskPrices       []models.TaskPrice    `json:"task_prices"`
+	Categories       []models.Category     `json:"categories"`
+	CategorySequence int                   `json:"category_sequence"`
+	Orders           []models.Order        `json:"orders"`
+	OrderLines       []orderLine           `json:"order_lines"`
+	ArchivedOrders   []models.Order        `json:"archived_orders"`
+	ArchivedLines    []orderLine           `json:"archived_order_lines"`
+	Sessions         []models.Session      `json:"sessions"`
+	LoginAttempts    []models.LoginAttempt `json:"login_attempts"`
+	OneTimeTokens    []models.OneTimeToken `json:"one_time_tokens"`
+	TwoFactor        []models.TwoFactor    `json:"two_factor"`
+	RecoveryCodes    []recoveryCode        `json:"recovery_codes"`
+	Audit            []models.AuditEntry   `json:"audit"`
+	Invoices         []models.Invoice      `json:"invoices"`
+}
+
+// clone copies every table. Models hold no pointers, so the copy shares nothing with the original
+// but the lines of invoices, which are never changed.
+func (d *snapshot) clone() snapshot {
+	return snapshot{
+		Users:            append([]models.User(nil), d.Users...),
+		Workers:          append([]models.Worker(nil), d.Workers...),
+		Tasks:            append([]models.Task(nil), d.Tasks...),
+		TaskPrices:       append([]models.TaskPrice(nil), d.TaskPrices...),
+		Categories:       append([]models.Category(nil), d.Categories...),
+		CategorySequence: d.CategorySequence,
+		Orders:           append([]models.Order(nil), d.Orders...),
+		OrderLines:       append([]orderLine(nil), d.OrderLines...),
+		ArchivedOrders:   append([]models.Order(nil), d.ArchivedOrders...),
+		ArchivedLines:    append([]orderLine(nil), d.ArchivedLines...),
+		Sessions:         append([]models.Session(nil), d.Sessions...),
+		LoginAttempts:    append([]models.LoginAttempt(nil), d.LoginAttempts...),
+		OneTimeTokens:    append([]models.OneTimeToken(nil), d.OneTimeTokens...),
+		TwoFactor:        append([]models.TwoFactor(nil), d.TwoFactor...),
+		RecoveryCodes:    append([]recoveryCode(nil), d.RecoveryCodes...),
+		Audit:            append([]models.AuditEntry(nil), d.Audit...),
+		Invoices:         append([]models.Invoice(nil), d.Invoices...),
+	}
+}
+
+// Store holds the data of all in-memory repositories. One lock guards all of it, so that an operation
+// checking another table, like an order checking its client, sees a consistent state.
+type Store struct {
+	mu           sync.RWMutex
+	data         snapshot
+	snapshotFile string
+	saveOnChange bool
+}
+
+// NewStore creates a store. If snapshotFile is set and exists, the store starts with its content.
+// With saveOnChange every change is written to the file before it is reported as done.
+func NewStore(snapshotFile string, saveOnChange bool) (*Store, error) {
+	store := &Store{snapshotFile: snapshotFile, saveOnChange: saveOnChange && snapshotFile != ""}
+	if snapshotFile == "" {
+		return store, nil
+	}
+
+	content, err := os.ReadFile(snapshotFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(content, &store.data)
+	if err != nil {
+		return nil, err
+	}
+
+	// a hand-written snapshot may list categories without the sequence
+	for _, category := range store.data.Categories {
+		if category.ID > store.data.CategorySequence {
+			store.data.CategorySequence = category.ID
+		}
+	}
+
+	return store, nil
+}
+
+// Save writes the content of the store to the snapshot file.
+func (s *Store) Save() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.save()
+}
+
+// save replaces the snapshot file at once, so that a crash never leaves half of a file. The caller holds the lock.
+func (s *Store) save() error {
+	if s.snapshotFile == "" {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(&s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(s.snapshotFile), filepath.Base(s.snapshotFile)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(content)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), s.snapshotFile)
+}
+
+func (s *Store) read(fn func(data *snapshot) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return fn(&s.data)
+}
+
+// write runs fn with the exclusive lock. Like a transaction, a change that cannot be saved
+// to the snapshot file is undone and reported as a failed commit.
+func (s *Store) write(fn func(data *snapshot) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.saveOnChange {
+		return fn(&s.data)
+	}
+
+	backup := s.data.clone()
+	err := fn(&s.data)
+	if err != nil {
+		s.data = backup
+		return err
+	}
+
+	err = s.save()
+	if err != nil {
+		s.data = backup
+		return repository_errors.TransactionCommitError
+	}
+
+	return nil
+}
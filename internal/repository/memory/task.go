@@ -0,0 +1,307 @@
+package memory
+
+import (
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"lab3/internal/search"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TaskRepository struct {
+	store *Store
+}
+
+func NewTaskRepository(store *Store) repository_interfaces.ITaskRepository {
+	return &TaskRepository{store: store}
+}
+
+func (d *snapshot) taskIndex(id uuid.UUID) int {
+	for i := range d.Tasks {
+		if d.Tasks[i].ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (t TaskRepository) Create(task *models.Task) (*models.Task, error) {
+	if task.Name == "" || task.PricePerSingle == 0 {
+		return nil, repository_errors.InsertError
+	}
+
+	created := *task
+	created.ID = uuid.New()
+	created.DeletedAt = time.Time{}
+
+	err := t.store.write(func(data *snapshot) error {
+		if data.categoryIndex(created.Category) == -1 {
+			return repository_errors.InsertError
+		}
+
+		data.Tasks = append(data.Tasks, created)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// Delete archives the task. Ordered tasks keep it, so that old orders still show what was done.
+func (t TaskRepository) Delete(id uuid.UUID) error {
+	return t.store.write(func(data *snapshot) error {
+		i := data.taskIndex(id)
+		if i == -1 || data.Tasks[i].IsDeleted() {
+			return repository_errors.DoesNotExist
+		}
+
+		data.Tasks[i].DeletedAt = time.Now()
+		return nil
+	})
+}
+
+func (t TaskRepository) Restore(id uuid.UUID) error {
+	return t.store.write(func(data *snapshot) error {
+		i := data.taskIndex(id)
+		if i == -1 || !data.Tasks[i].IsDeleted() {
+			return repository_errors.DoesNotExist
+		}
+
+		data.Tasks[i].DeletedAt = time.Time{}
+		return nil
+	})
+}
+
+func (t TaskRepository) Update(task *models.Task) (*models.Task, error) {
+	if task.Name == "" || task.PricePerSingle == 0 {
+		return nil, repository_errors.InsertError
+	}
+
+	var updated models.Task
+	err := t.store.write(func(data *snapshot) error {
+		i := data.taskIndex(task.ID)
+		if i == -1 {
+			return repository_errors.UpdateError
+		}
+		// like a foreign key, the category is checked when it changes
+		if task.Category != data.Tasks[i].Category && data.categoryIndex(task.Category) == -1 {
+			return repository_errors.UpdateError
+		}
+
+		updated = *task
+		updated.DeletedAt = data.Tasks[i].DeletedAt
+		data.Tasks[i] = updated
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+func (t TaskRepository) GetTaskByID(id uuid.UUID) (*models.Task, error) {
+	var task models.Task
+	err := t.store.read(func(data *snapshot) error {
+		i := data.taskIndex(id)
+		if i == -1 {
+			return repository_errors.DoesNotExist
+		}
+
+		task = data.Tasks[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+func (t TaskRepository) find(match func(task *models.Task) bool) []models.Task {
+	var tasks []models.Task
+	_ = t.store.read(func(data *snapshot) error {
+		for i := range data.Tasks {
+			if match(&data.Tasks[i]) {
+				tasks = append(tasks, data.Tasks[i])
+			}
+		}
+		return nil
+	})
+
+	return tasks
+}
+
+func (t TaskRepository) GetTaskByName(name string) (*models.Task, error) {
+	tasks := t.find(func(task *models.Task) bool {
+		return task.Name == name && !task.IsDeleted()
+	})
+	if len(tasks) == 0 {
+		return nil, repository_errors.DoesNotExist
+	}
+
+	return &tasks[0], nil
+}
+
+func (t TaskRepository) GetAllTasks() ([]models.Task, error) {
+	return t.find(func(task *models.Task) bool {
+		return !task.IsDeleted()
+	}), nil
+}
+
+func (t TaskRepository) GetTasksInCategory(category int) ([]models.Task, error) {
+	return t.find(func(task *models.Task) bool {
+		return task.Category == category && !task.IsDeleted()
+	}), nil
+}
+
+func (t TaskRepository) MoveToCategory(from, to int) (int, error) {
+	moved := 0
+	err := t.store.write(func(data *snapshot) error {
+		if data.categoryIndex(to) == -1 {
+			return repository_errors.UpdateError
+		}
+
+		for i := range data.Tasks {
+			if data.Tasks[i].Category == from {
+				data.Tasks[i].Category = to
+				moved++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return moved, nil
+}
+
+func (t TaskRepository) GetDeletedTasks() ([]models.Task, error) {
+	tasks := t.find(func(task *models.Task) bool {
+		return task.IsDeleted()
+	})
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].DeletedAt.After(tasks[j].DeletedAt)
+	})
+
+	return tasks, nil
+}
+
+func (t TaskRepository) Search(query string) ([]models.Task, error) {
+	tasks, err := t.GetAllTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	return search.Tasks(tasks, query), nil
+}
+
+func (t TaskRepository) SchedulePrice(price *models.TaskPrice) (*models.TaskPrice, error) {
+	if price.Price == 0 {
+		return nil, repository_errors.InsertError
+	}
+
+	scheduled := *price
+	err := t.store.write(func(data *snapshot) error {
+		if data.taskIndex(price.TaskID) == -1 {
+			return repository_errors.InsertError
+		}
+
+		for i := range data.TaskPrices {
+			existing := &data.TaskPrices[i]
+			if existing.TaskID == price.TaskID && existing.EffectiveFrom.Equal(price.EffectiveFrom) {
+				existing.Price = price.Price
+				scheduled = *existing
+				return nil
+			}
+		}
+
+		scheduled.ID = uuid.New()
+		data.TaskPrices = append(data.TaskPrices, scheduled)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &scheduled, nil
+}
+
+func (t TaskRepository) DeletePrice(id uuid.UUID) error {
+	return t.store.write(func(data *snapshot) error {
+		for i := range data.TaskPrices {
+			if data.TaskPrices[i].ID == id {
+				data.TaskPrices = append(data.TaskPrices[:i], data.TaskPrices[i+1:]...)
+				return nil
+			}
+		}
+
+		return repository_errors.DoesNotExist
+	})
+}
+
+// findPrices returns the prices matching the filter, the earliest first.
+func (t TaskRepository) findPrices(match func(price *models.TaskPrice) bool) []models.TaskPrice {
+	var prices []models.TaskPrice
+	_ = t.store.read(func(data *snapshot) error {
+		for i := range data.TaskPrices {
+			if match(&data.TaskPrices[i]) {
+				prices = append(prices, data.TaskPrices[i])
+			}
+		}
+		return nil
+	})
+
+	sort.SliceStable(prices, func(i, j int) bool {
+		return prices[i].EffectiveFrom.Before(prices[j].EffectiveFrom)
+	})
+
+	return prices
+}
+
+func (t TaskRepository) GetPrices(taskID uuid.UUID) ([]models.TaskPrice, error) {
+	return t.findPrices(func(price *models.TaskPrice) bool {
+		return price.TaskID == taskID
+	}), nil
+}
+
+func (t TaskRepository) GetUpcomingPrices(moment time.Time) ([]models.TaskPrice, error) {
+	return t.findPrices(func(price *models.TaskPrice) bool {
+		return price.EffectiveFrom.After(moment)
+	}), nil
+}
+
+func (t TaskRepository) ApplyPrices(moment time.Time) (int, error) {
+	changed := 0
+	err := t.store.write(func(data *snapshot) error {
+		current := make(map[uuid.UUID]models.TaskPrice)
+		for _, price := range data.TaskPrices {
+			latest, found := current[price.TaskID]
+			if !price.EffectiveFrom.After(moment) && (!found || price.EffectiveFrom.After(latest.EffectiveFrom)) {
+				current[price.TaskID] = price
+			}
+		}
+
+		for i := range data.Tasks {
+			price, found := current[data.Tasks[i].ID]
+			if found && data.Tasks[i].PricePerSingle != price.Price {
+				data.Tasks[i].PricePerSingle = price.Price
+				changed++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+
+	"github.com/google/uuid"
+)
+
+type TwoFactorRepository struct {
+	store *Store
+}
+
+func NewTwoFactorRepository(store *Store) repository_interfaces.ITwoFactorRepository {
+	return &TwoFactorRepository{store: store}
+}
+
+func (d *snapshot) twoFactorIndex(workerID uuid.UUID) int {
+	for i := range d.TwoFactor {
+		if d.TwoFactor[i].WorkerID == workerID {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (d *snapshot) deleteRecoveryCodes(workerID uuid.UUID) {
+	codes := d.RecoveryCodes[:0]
+	for _, code := range d.RecoveryCodes {
+		if code.WorkerID != workerID {
+			codes = append(codes, code)
+		}
+	}
+	d.RecoveryCodes = codes
+}
+
+func (t TwoFactorRepository) GetByWorkerID(workerID uuid.UUID) (*models.TwoFactor, error) {
+	var twoFactor models.TwoFactor
+	err := t.store.read(func(data *snapshot) error {
+		i := data.twoFactorIndex(workerID)
+		if i == -1 {
+			return repository_errors.DoesNotExist
+		}
+
+		twoFactor = data.TwoFactor[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &twoFactor, nil
+}
+
+func (t TwoFactorRepository) Save(twoFactor *models.TwoFactor) error {
+	return t.store.write(func(data *snapshot) error {
+		if data.workerIndex(twoFactor.WorkerID) == -1 {
+			return repository_errors.InsertError
+		}
+
+		i := data.twoFactorIndex(twoFactor.WorkerID)
+		if i == -1 {
+			data.TwoFactor = append(data.TwoFactor, *twoFactor)
+			return nil
+		}
+
+		saved := *twoFactor
+		saved.CreatedAt = data.TwoFactor[i].CreatedAt
+		data.TwoFactor[i] = saved
+		return nil
+	})
+}
+
+func (t TwoFactorRepository) Delete(workerID uuid.UUID) error {
+	return t.store.write(func(data *snapshot) error {
+		data.deleteRecoveryCodes(workerID)
+
+		i := data.twoFactorIndex(workerID)
+		if i != -1 {
+			data.TwoFactor = append(data.TwoFactor[:i], data.TwoFactor[i+1:]...)
+		}
+		return nil
+	})
+}
+
+func (t TwoFactorRepository) MarkStepUsed(workerID uuid.UUID, step int64) error {
+	return t.store.write(func(data *snapshot) error {
+		i := data.twoFactorIndex(workerID)
+		if i == -1 || data.TwoFactor[i].LastUsedStep >= step {
+			return repository_errors.DoesNotExist
+		}
+
+		data.TwoFactor[i].LastUsedStep = step
+		return nil
+	})
+}
+
+func (t TwoFactorRepository) ReplaceRecoveryCodes(workerID uuid.UUID, hashes []string) error {
+	return t.store.write(func(data *snapshot) error {
+		if data.workerIndex(workerID) == -1 {
+			return repository_errors.InsertError
+		}
+
+		// a repeated hash violates the primary key of the code table
+		unique := make(map[string]bool, len(hashes))
+		for _, hash := range hashes {
+			if unique[hash] {
+				return repository_errors.InsertError
+			}
+			unique[hash] = true
+		}
+
+		data.deleteRecoveryCodes(workerID)
+		for _, hash := range hashes {
+			data.RecoveryCodes = append(data.RecoveryCodes, recoveryCode{WorkerID: workerID, CodeHash: hash})
+		}
+		return nil
+	})
+}
+
+func (t TwoFactorRepository) ConsumeRecoveryCode(workerID uuid.UUID, hash string) error {
+	return t.store.write(func(data *snapshot) error {
+		for i, code := range data.RecoveryCodes {
+			if code.WorkerID == workerID && code.CodeHash == hash {
+				data.RecoveryCodes = append(data.RecoveryCodes[:i], data.RecoveryCodes[i+1:]...)
+				return nil
+			}
+		}
+
+		return repository_errors.DoesNotExist
+	})
+}
+
+func (t TwoFactorRepository) CountRecoveryCodes(workerID uuid.UUID) (int, error) {
+	var count int
+	_ = t.store.read(func(data *snapshot) error {
+		for _, code := range data.RecoveryCodes {
+			if code.WorkerID == workerID {
+				count++
+			}
+		}
+		return nil
+	})
+
+	return count, nil
+}
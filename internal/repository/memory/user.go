@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) repository_interfaces.IUserRepository {
+	return &UserRepository{store: store}
+}
+
+func (d *snapshot) userIndex(id uuid.UUID) int {
+	for i := range d.Users {
+		if d.Users[i].ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// userEmailTaken reports whether another user has the email, archived users included, like the unique column.
+func (d *snapshot) userEmailTaken(email string, except uuid.UUID) bool {
+	for i := range d.Users {
+		if d.Users[i].Email == email && d.Users[i].ID != except {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (u UserRepository) Create(user *models.User) (*models.User, error) {
+	if user.Name == "" || user.Surname == "" || user.Email == "" || user.Password == "" {
+		return nil, repository_errors.InsertError
+	}
+
+	created := *user
+	created.ID = uuid.New()
+	created.DeletedAt = time.Time{}
+
+	err := u.store.write(func(data *snapshot) error {
+		if data.userEmailTaken(created.Email, uuid.Nil) {
+			return repository_errors.InsertError
+		}
+
+		data.Users = append(data.Users, created)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// Delete archives the user. Their orders are kept, so that workers still see the history.
+func (u UserRepository) Delete(id uuid.UUID) error {
+	return u.store.write(func(data *snapshot) error {
+		i := data.userIndex(id)
+		if i == -1 || data.Users[i].IsDeleted() {
+			return repository_errors.DoesNotExist
+		}
+
+		data.Users[i].DeletedAt = time.Now()
+		return nil
+	})
+}
+
+func (u UserRepository) Restore(id uuid.UUID) error {
+	return u.store.write(func(data *snapshot) error {
+		i := data.userIndex(id)
+		if i == -1 || !data.Users[i].IsDeleted() {
+			return repository_errors.DoesNotExist
+		}
+
+		data.Users[i].DeletedAt = time.Time{}
+		return nil
+	})
+}
+
+func (u UserRepository) Update(user *models.User) (*models.User, error) {
+	if user.Name == "" || user.Surname == "" || user.Email == "" || user.Password == "" {
+		return nil, repository_errors.UpdateError
+	}
+
+	var updated models.User
+	err := u.store.write(func(data *snapshot) error {
+		i := data.userIndex(user.ID)
+		if i == -1 || data.userEmailTaken(user.Email, user.ID) {
+			return repository_errors.UpdateError
+		}
+
+		updated = *user
+		updated.DeletedAt = data.Users[i].DeletedAt
+		data.Users[i] = updated
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+func (u UserRepository) GetUserByID(id uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := u.store.read(func(data *snapshot) error {
+		i := data.userIndex(id)
+		if i == -1 {
+			return repository_errors.DoesNotExist
+		}
+
+		user = data.Users[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (u UserRepository) find(match func(user *models.User) bool) []models.User {
+	var users []models.User
+	_ = u.store.read(func(data *snapshot) error {
+		for i := range data.Users {
+			if match(&data.Users[i]) {
+				users = append(users, data.Users[i])
+			}
+		}
+		return nil
+	})
+
+	return users
+}
+
+func (u UserRepository) GetUserByEmail(email string) (*models.User, error) {
+	users := u.find(func(user *models.User) bool {
+		return user.Email == email && !user.IsDeleted()
+	})
+	if len(users) == 0 {
+		return nil, repository_errors.DoesNotExist
+	}
+
+	return &users[0], nil
+}
+
+func (u UserRepository) GetAllUsers() ([]models.User, error) {
+	return u.find(func(user *models.User) bool {
+		return !user.IsDeleted()
+	}), nil
+}
+
+func (u UserRepository) GetDeletedUsers() ([]models.User, error) {
+	users := u.find(func(user *models.User) bool {
+		return user.IsDeleted()
+	})
+
+	sort.SliceStable(users, func(i, j int) bool {
+		return users[i].DeletedAt.After(users[j].DeletedAt)
+	})
+
+	return users, nil
+}
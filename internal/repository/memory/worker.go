@@ -0,0 +1,204 @@
+package memory
+
+import (
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WorkerRepository struct {
+	store *Store
+}
+
+func NewWorkerRepository(store *Store) repository_interfaces.IWorkerRepository {
+	return &WorkerRepository{store: store}
+}
+
+func (d *snapshot) workerIndex(id uuid.UUID) int {
+	for i := range d.Workers {
+		if d.Workers[i].ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// workerEmailTaken reports whether another worker has the email, archived workers included, like the unique column.
+func (d *snapshot) workerEmailTaken(email string, except uuid.UUID) bool {
+	for i := range d.Workers {
+		if d.Workers[i].Email == email && d.Workers[i].ID != except {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w WorkerRepository) Create(worker *models.Worker) (*models.Worker, error) {
+	if worker.Name == "" || worker.Surname == "" || worker.Email == "" || worker.Password == "" {
+		return nil, repository_errors.UpdateError
+	}
+
+	created := *worker
+	created.ID = uuid.New()
+	created.DeletedAt = time.Time{}
+
+	err := w.store.write(func(data *snapshot) error {
+		if data.workerEmailTaken(created.Email, uuid.Nil) {
+			return repository_errors.InsertError
+		}
+
+		data.Workers = append(data.Workers, created)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (w WorkerRepository) Update(worker *models.Worker) (*models.Worker, error) {
+	if worker.Name == "" || worker.Surname == "" || worker.Email == "" || worker.Password == "" {
+		return nil, repository_errors.UpdateError
+	}
+
+	var updated models.Worker
+	err := w.store.write(func(data *snapshot) error {
+		i := data.workerIndex(worker.ID)
+		if i == -1 || data.workerEmailTaken(worker.Email, worker.ID) {
+			return repository_errors.UpdateError
+		}
+
+		updated = *worker
+		updated.DeletedAt = data.Workers[i].DeletedAt
+		data.Workers[i] = updated
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// Delete archives the worker. Their orders keep the assignment, so that the history stays complete.
+func (w WorkerRepository) Delete(id uuid.UUID) error {
+	return w.store.write(func(data *snapshot) error {
+		i := data.workerIndex(id)
+		if i == -1 || data.Workers[i].IsDeleted() {
+			return repository_errors.DoesNotExist
+		}
+
+		data.Workers[i].DeletedAt = time.Now()
+		return nil
+	})
+}
+
+func (w WorkerRepository) Restore(id uuid.UUID) error {
+	return w.store.write(func(data *snapshot) error {
+		i := data.workerIndex(id)
+		if i == -1 || !data.Workers[i].IsDeleted() {
+			return repository_errors.DoesNotExist
+		}
+
+		data.Workers[i].DeletedAt = time.Time{}
+		return nil
+	})
+}
+
+func (w WorkerRepository) GetWorkerByID(id uuid.UUID) (*models.Worker, error) {
+	var worker models.Worker
+	err := w.store.read(func(data *snapshot) error {
+		i := data.workerIndex(id)
+		if i == -1 {
+			return repository_errors.DoesNotExist
+		}
+
+		worker = data.Workers[i]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &worker, nil
+}
+
+func (w WorkerRepository) find(match func(worker *models.Worker) bool) []models.Worker {
+	var workers []models.Worker
+	_ = w.store.read(func(data *snapshot) error {
+		for i := range data.Workers {
+			if match(&data.Workers[i]) {
+				workers = append(workers, data.Workers[i])
+			}
+		}
+		return nil
+	})
+
+	return workers
+}
+
+func (w WorkerRepository) GetAllWorkers() ([]models.Worker, error) {
+	return w.find(func(worker *models.Worker) bool {
+		return !worker.IsDeleted()
+	}), nil
+}
+
+func (w WorkerRepository) GetWorkerByEmail(email string) (*models.Worker, error) {
+	workers := w.find(func(worker *models.Worker) bool {
+		return worker.Email == email && !worker.IsDeleted()
+	})
+	if len(workers) == 0 {
+		return nil, repository_errors.DoesNotExist
+	}
+
+	return &workers[0], nil
+}
+
+func (w WorkerRepository) GetDeletedWorkers() ([]models.Worker, error) {
+	workers := w.find(func(worker *models.Worker) bool {
+		return worker.IsDeleted()
+	})
+
+	sort.SliceStable(workers, func(i, j int) bool {
+		return workers[i].DeletedAt.After(workers[j].DeletedAt)
+	})
+
+	return workers, nil
+}
+
+func (w WorkerRepository) GetWorkersByRole(role int) ([]models.Worker, error) {
+	return w.find(func(worker *models.Worker) bool {
+		return worker.Role == role && !worker.IsDeleted()
+	}), nil
+}
+
+func isRated(order *models.Order) bool {
+	return order.Status == models.CompletedOrderStatus && order.Rate != 0
+}
+
+func (w WorkerRepository) GetAverageOrderRate(worker *models.Worker) (float64, error) {
+	var sum, count int
+	_ = w.store.read(func(data *snapshot) error {
+		// archived orders keep counting
+		for _, order := range append(matchingOrders(data.Orders, isRated), matchingOrders(data.ArchivedOrders, isRated)...) {
+			if order.WorkerID == worker.ID {
+				sum += order.Rate
+				count++
+			}
+		}
+		return nil
+	})
+
+	if count == 0 {
+		return 0, nil
+	}
+
+	return float64(sum) / float64(count), nil
+}
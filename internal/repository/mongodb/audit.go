@@ -0,0 +1,123 @@
+package mongodb
+
+import (
+	"context"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AuditEntryDB struct {
+	ID         uuid.UUID `bson:"_id"`
+	ActorID    uuid.UUID `bson:"actor_id"`
+	Action     string    `bson:"action"`
+	TargetType string    `bson:"target_type"`
+	TargetID   string    `bson:"target_id"`
+	Before     string    `bson:"before"`
+	After      string    `bson:"after"`
+	IP         string    `bson:"ip"`
+	CreatedAt  time.Time `bson:"created_at"`
+}
+
+type AuditRepository struct {
+	db *mongo.Database
+}
+
+func NewAuditRepository(db *mongo.Database) repository_interfaces.IAuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func copyAuditEntryResultToModel(entryDB *AuditEntryDB) *models.AuditEntry {
+	return &models.AuditEntry{
+		ID:         entryDB.ID,
+		ActorID:    entryDB.ActorID,
+		Action:     entryDB.Action,
+		TargetType: entryDB.TargetType,
+		TargetID:   entryDB.TargetID,
+		Before:     entryDB.Before,
+		After:      entryDB.After,
+		IP:         entryDB.IP,
+		CreatedAt:  entryDB.CreatedAt,
+	}
+}
+
+func (a AuditRepository) Create(entry *models.AuditEntry) error {
+	var collection = a.db.Collection("audit_log")
+
+	entry.ID = uuid.New()
+	entryDB := AuditEntryDB{
+		ID:         entry.ID,
+		ActorID:    entry.ActorID,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Before:     entry.Before,
+		After:      entry.After,
+		IP:         entry.IP,
+		CreatedAt:  entry.CreatedAt,
+	}
+
+	_, err := collection.InsertOne(context.Background(), entryDB)
+	if err != nil {
+		return classify(err, repository_errors.InsertError)
+	}
+
+	return nil
+}
+
+func (a AuditRepository) Filter(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	var collection = a.db.Collection("audit_log")
+
+	query := bson.M{}
+	if filter.ActorID != uuid.Nil {
+		query["actor_id"] = filter.ActorID
+	}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+	if filter.TargetType != "" {
+		query["target_type"] = filter.TargetType
+	}
+	if filter.TargetID != "" {
+		query["target_id"] = filter.TargetID
+	}
+	if !filter.From.IsZero() || !filter.To.IsZero() {
+		createdAt := bson.M{}
+		if !filter.From.IsZero() {
+			createdAt["$gte"] = filter.From
+		}
+		if !filter.To.IsZero() {
+			createdAt["$lt"] = filter.To
+		}
+		query["created_at"] = createdAt
+	}
+
+	opts := options.Find().SetSort(bson.M{"created_at": -1})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := collection.Find(context.Background(), query, opts)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var entriesDB []AuditEntryDB
+	err = cursor.All(context.Background(), &entriesDB)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	entries := make([]models.AuditEntry, len(entriesDB))
+	for i := range entriesDB {
+		entries[i] = *copyAuditEntryResultToModel(&entriesDB[i])
+	}
+
+	return entries, nil
+}
@@ -4,14 +4,22 @@ import (
 	"context"
 	"errors"
 	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type CategoryDB struct {
-	ID   int    `bson:"_id"`
-	Name string `bson:"name"`
+	ID        int       `bson:"_id"`
+	Name      string    `bson:"name"`
+	ParentID  int       `bson:"parent_id,omitempty"`
+	Position  int       `bson:"position"`
+	Hidden    bool      `bson:"hidden"`
+	TaxRate   string    `bson:"tax_rate"`
+	DeletedAt time.Time `bson:"deleted_at,omitempty"`
 }
 
 type CategoryRepository struct {
@@ -22,128 +30,181 @@ func NewCategoryRepository(db *mongo.Database) *CategoryRepository {
 	return &CategoryRepository{db: db}
 }
 
+func copyCategoryResultToModel(categoryDB *CategoryDB) *models.Category {
+	return &models.Category{
+		ID:        categoryDB.ID,
+		Name:      categoryDB.Name,
+		ParentID:  categoryDB.ParentID,
+		Position:  categoryDB.Position,
+		Hidden:    categoryDB.Hidden,
+		TaxRate:   categoryDB.TaxRate,
+		DeletedAt: categoryDB.DeletedAt,
+	}
+}
+
+// getNextSequence returns the next value of a counter, the counterpart of a serial column in Postgres.
 func getNextSequence(db *mongo.Database, sequenceName string) (int, error) {
 	collection := db.Collection("counters")
 
 	filter := bson.M{"_id": sequenceName}
 	update := bson.M{"$inc": bson.M{"seq": 1}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
 
 	var result struct {
 		Seq int `bson:"seq"`
 	}
 
-	err := collection.FindOneAndUpdate(context.Background(), filter, update).Decode(&result)
+	err := collection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&result)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			// Если документа не существует, создаем его
-			_, err := collection.InsertOne(context.Background(), bson.M{"_id": sequenceName, "seq": 1})
-			if err != nil {
-				return 0, err
-			}
-			return 1, nil
-		}
 		return 0, err
 	}
 
 	return result.Seq, nil
 }
 
-func (c CategoryRepository) GetAll() ([]models.Category, error) {
+func (c CategoryRepository) find(filter bson.M, opts *options.FindOptions) ([]models.Category, error) {
 	var collection = c.db.Collection("categories")
 	ctx := context.Background()
 
-	cur, err := collection.Find(ctx, bson.M{})
+	cur, err := collection.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, err
+		return nil, classify(err, repository_errors.SelectError)
 	}
-	defer cur.Close(ctx)
 
-	var categories []models.Category
-	for cur.Next(context.Background()) {
-		var category CategoryDB
-		err := cur.Decode(&category)
-		if err != nil {
-			return nil, err
-		}
-		categories = append(categories, models.Category{
-			ID:   category.ID,
-			Name: category.Name,
-		})
+	var categoriesDB []CategoryDB
+	err = cur.All(ctx, &categoriesDB)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
-	if err := cur.Err(); err != nil {
-		return nil, err
+	var categories []models.Category
+	for i := range categoriesDB {
+		categories = append(categories, *copyCategoryResultToModel(&categoriesDB[i]))
 	}
 
 	return categories, nil
 }
 
+func (c CategoryRepository) GetAll() ([]models.Category, error) {
+	return c.find(notDeletedFilter, options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "_id", Value: 1}}))
+}
+
 func (c CategoryRepository) GetByID(id int) (*models.Category, error) {
 	var collection = c.db.Collection("categories")
-	ctx := context.Background()
 
 	var category CategoryDB
-	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&category)
-	if err != nil {
-		return nil, err
+	err := collection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&category)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
-	return &models.Category{
-		ID:   category.ID,
-		Name: category.Name,
-	}, nil
+	return copyCategoryResultToModel(&category), nil
 }
 
 func (c CategoryRepository) Create(category *models.Category) (*models.Category, error) {
+	if category.Name == "" {
+		return nil, repository_errors.InsertError
+	}
+
 	var collection = c.db.Collection("categories")
-	ctx := context.Background()
+	if err := c.checkParent(category, repository_errors.InsertError); err != nil {
+		return nil, err
+	}
 
 	id, err := getNextSequence(c.db, "categoryid")
 	if err != nil {
-		return nil, err
+		return nil, classify(err, repository_errors.InsertError)
+	}
+
+	// the category is placed after all the others
+	var last CategoryDB
+	err = collection.FindOne(context.Background(), bson.M{}, options.FindOne().SetSort(bson.D{{Key: "position", Value: -1}})).Decode(&last)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, classify(err, repository_errors.InsertError)
 	}
 
-	_, err = collection.InsertOne(ctx, bson.M{"_id": id, "name": category.Name})
+	created := CategoryDB{ID: id, Name: category.Name, ParentID: category.ParentID, Position: last.Position + 1, Hidden: category.Hidden, TaxRate: category.TaxRate}
+	_, err = collection.InsertOne(context.Background(), created)
 	if err != nil {
-		return nil, err
+		return nil, classify(err, repository_errors.InsertError)
 	}
 
-	return &models.Category{
-		ID:   id,
-		Name: category.Name,
-	}, nil
+	return copyCategoryResultToModel(&created), nil
 }
 
 func (c CategoryRepository) Update(category *models.Category) (*models.Category, error) {
+	if category.Name == "" {
+		return nil, repository_errors.InsertError
+	}
+
 	var collection = c.db.Collection("categories")
-	ctx := context.Background()
+	if err := c.checkParent(category, repository_errors.UpdateError); err != nil {
+		return nil, err
+	}
 
 	filter := bson.M{"_id": category.ID}
-	update := bson.M{"$set": bson.M{"name": category.Name}}
-	_, err := collection.UpdateOne(ctx, filter, update)
+	set := bson.M{"name": category.Name, "hidden": category.Hidden, "tax_rate": category.TaxRate}
+	update := bson.M{"$set": set}
+	if category.ParentID == 0 {
+		update["$unset"] = bson.M{"parent_id": ""}
+	} else {
+		set["parent_id"] = category.ParentID
+	}
+
+	var updated CategoryDB
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := collection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&updated)
 	if err != nil {
-		return nil, err
+		return nil, classify(err, repository_errors.UpdateError)
 	}
 
-	return &models.Category{
-		ID:   category.ID,
-		Name: category.Name,
-	}, nil
+	return copyCategoryResultToModel(&updated), nil
 }
 
-func (c CategoryRepository) Delete(id int) error {
-	var collection = c.db.Collection("categories")
-	ctx := context.Background()
+// checkParent returns kind when the parent of the category does not exist, the counterpart of the
+// foreign key of the categories in Postgres.
+func (c CategoryRepository) checkParent(category *models.Category, kind error) error {
+	if category.ParentID == 0 {
+		return nil
+	}
 
-	filter := bson.M{"_id": id}
-	result, err := collection.DeleteOne(ctx, filter)
+	count, err := c.db.Collection("categories").CountDocuments(context.Background(), bson.M{"_id": category.ParentID})
 	if err != nil {
-		return err
+		return classify(err, repository_errors.SelectError)
 	}
-
-	if result.DeletedCount == 0 {
-		return errors.New("no task found to delete")
+	if count == 0 {
+		return kind
 	}
 
 	return nil
 }
+
+func (c CategoryRepository) Reorder(ids []int) error {
+	return withTransaction(c.db, func(ctx mongo.SessionContext) error {
+		var collection = c.db.Collection("categories")
+		for position, id := range ids {
+			result, err := collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"position": position + 1}})
+			if err != nil {
+				return classify(err, repository_errors.UpdateError)
+			}
+			if result.MatchedCount == 0 {
+				return repository_errors.UpdateError
+			}
+		}
+		return nil
+	})
+}
+
+func (c CategoryRepository) Delete(id int) error {
+	return softDelete(c.db.Collection("categories"), id)
+}
+
+func (c CategoryRepository) Restore(id int) error {
+	return restore(c.db.Collection("categories"), id)
+}
+
+func (c CategoryRepository) GetDeleted() ([]models.Category, error) {
+	return c.find(deletedFilter, deletedOptions())
+}
@@ -0,0 +1,17 @@
+package mongodb
+
+import (
+	"lab3/internal/repository/repository_errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// classify returns kind, marked as repository_errors.Unavailable when err was caused by a lost
+// connection, a failed server selection or a timeout.
+func classify(err error, kind error) error {
+	return repository_errors.Transient(err, kind, isTransient)
+}
+
+func isTransient(err error) bool {
+	return mongo.IsNetworkError(err) || mongo.IsTimeout(err) || repository_errors.IsConnectionError(err)
+}
@@ -0,0 +1,181 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RequisitesDB struct {
+	Company              string `bson:"company"`
+	INN                  string `bson:"inn"`
+	KPP                  string `bson:"kpp"`
+	Address              string `bson:"address"`
+	Bank                 string `bson:"bank"`
+	BIC                  string `bson:"bic"`
+	Account              string `bson:"account"`
+	CorrespondentAccount string `bson:"correspondent_account"`
+}
+
+type InvoiceLineDB struct {
+	Name     string  `bson:"name"`
+	Price    float64 `bson:"price"`
+	Quantity int     `bson:"quantity"`
+	Amount   float64 `bson:"amount"`
+	TaxName  string  `bson:"tax_name"`
+	Tax      float64 `bson:"tax"`
+}
+
+type InvoiceDB struct {
+	ID       uuid.UUID       `bson:"_id"`
+	Number   int             `bson:"number"`
+	OrderID  uuid.UUID       `bson:"order_id"`
+	UserID   uuid.UUID       `bson:"user_id"`
+	IssuedAt time.Time       `bson:"issued_at"`
+	Seller   RequisitesDB    `bson:"seller"`
+	Buyer    string          `bson:"buyer"`
+	Address  string          `bson:"address"`
+	Lines    []InvoiceLineDB `bson:"lines"`
+	Total    float64         `bson:"total"`
+}
+
+type InvoiceRepository struct {
+	db *mongo.Database
+}
+
+func NewInvoiceRepository(db *mongo.Database) repository_interfaces.IInvoiceRepository {
+	return &InvoiceRepository{db: db}
+}
+
+func copyInvoiceResultToModel(invoiceDB *InvoiceDB) *models.Invoice {
+	lines := make([]models.InvoiceLine, 0, len(invoiceDB.Lines))
+	for _, line := range invoiceDB.Lines {
+		lines = append(lines, models.InvoiceLine(line))
+	}
+
+	return &models.Invoice{
+		ID:       invoiceDB.ID,
+		Number:   invoiceDB.Number,
+		OrderID:  invoiceDB.OrderID,
+		UserID:   invoiceDB.UserID,
+		IssuedAt: invoiceDB.IssuedAt,
+		Seller:   models.Requisites(invoiceDB.Seller),
+		Buyer:    invoiceDB.Buyer,
+		Address:  invoiceDB.Address,
+		Lines:    lines,
+		Total:    invoiceDB.Total,
+	}
+}
+
+func (i InvoiceRepository) Create(invoice *models.Invoice) (*models.Invoice, error) {
+	var collection = i.db.Collection("invoices")
+
+	created := *invoice
+	created.ID = uuid.New()
+
+	lines := make([]InvoiceLineDB, 0, len(invoice.Lines))
+	for _, line := range invoice.Lines {
+		lines = append(lines, InvoiceLineDB(line))
+	}
+
+	// the counter is increased in the same transaction, so a failed invoice leaves no gap in the numbers
+	err := withTransaction(i.db, func(ctx mongo.SessionContext) error {
+		count, err := collection.CountDocuments(ctx, bson.M{"order_id": invoice.OrderID})
+		if err != nil {
+			return classify(err, repository_errors.InsertError)
+		}
+		if count > 0 {
+			return repository_errors.AlreadyExists
+		}
+
+		var counter struct {
+			Seq int `bson:"seq"`
+		}
+		opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+		err = i.db.Collection("counters").FindOneAndUpdate(ctx, bson.M{"_id": "invoice_number"}, bson.M{"$inc": bson.M{"seq": 1}}, opts).Decode(&counter)
+		if err != nil {
+			return classify(err, repository_errors.InsertError)
+		}
+		created.Number = counter.Seq
+
+		_, err = collection.InsertOne(ctx, InvoiceDB{
+			ID:       created.ID,
+			Number:   created.Number,
+			OrderID:  created.OrderID,
+			UserID:   created.UserID,
+			IssuedAt: created.IssuedAt,
+			Seller:   RequisitesDB(created.Seller),
+			Buyer:    created.Buyer,
+			Address:  created.Address,
+			Lines:    lines,
+			Total:    created.Total,
+		})
+
+		// the unique index on the order rejects an invoice issued concurrently
+		if mongo.IsDuplicateKeyError(err) {
+			return repository_errors.AlreadyExists
+		} else if err != nil {
+			return classify(err, repository_errors.InsertError)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (i InvoiceRepository) get(filter bson.M) (*models.Invoice, error) {
+	var collection = i.db.Collection("invoices")
+
+	var invoiceDB InvoiceDB
+	err := collection.FindOne(context.Background(), filter).Decode(&invoiceDB)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	return copyInvoiceResultToModel(&invoiceDB), nil
+}
+
+func (i InvoiceRepository) GetByID(id uuid.UUID) (*models.Invoice, error) {
+	return i.get(bson.M{"_id": id})
+}
+
+func (i InvoiceRepository) GetByOrderID(orderID uuid.UUID) (*models.Invoice, error) {
+	return i.get(bson.M{"order_id": orderID})
+}
+
+func (i InvoiceRepository) GetAll() ([]models.Invoice, error) {
+	var collection = i.db.Collection("invoices")
+
+	opts := options.Find().SetSort(bson.D{{Key: "number", Value: -1}})
+	cursor, err := collection.Find(context.Background(), bson.M{}, opts)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var invoicesDB []InvoiceDB
+	err = cursor.All(context.Background(), &invoicesDB)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	invoices := make([]models.Invoice, 0, len(invoicesDB))
+	for j := range invoicesDB {
+		invoices = append(invoices, *copyInvoiceResultToModel(&invoicesDB[j]))
+	}
+
+	return invoices, nil
+}
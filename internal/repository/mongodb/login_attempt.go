@@ -0,0 +1,135 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LoginAttemptDB struct {
+	Key         string    `bson:"_id"`
+	Failures    int       `bson:"failures"`
+	LastFailure time.Time `bson:"last_failure"`
+	LockedUntil time.Time `bson:"locked_until,omitempty"`
+}
+
+type LoginAttemptRepository struct {
+	db *mongo.Database
+}
+
+func NewLoginAttemptRepository(db *mongo.Database) repository_interfaces.ILoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+func copyLoginAttemptResultToModel(attemptDB *LoginAttemptDB) *models.LoginAttempt {
+	return &models.LoginAttempt{
+		Key:         attemptDB.Key,
+		Failures:    attemptDB.Failures,
+		LastFailure: attemptDB.LastFailure,
+		LockedUntil: attemptDB.LockedUntil,
+	}
+}
+
+func (l LoginAttemptRepository) GetByKey(key string) (*models.LoginAttempt, error) {
+	var collection = l.db.Collection("login_attempts")
+
+	var attempt LoginAttemptDB
+	err := collection.FindOne(context.Background(), bson.M{"_id": key}).Decode(&attempt)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	return copyLoginAttemptResultToModel(&attempt), nil
+}
+
+func (l LoginAttemptRepository) RegisterFailure(key string, at time.Time, resetBefore time.Time) (*models.LoginAttempt, error) {
+	var collection = l.db.Collection("login_attempts")
+
+	lockEnded := bson.M{"$and": bson.A{
+		bson.M{"$eq": bson.A{bson.M{"$type": "$locked_until"}, "date"}},
+		bson.M{"$lte": bson.A{"$locked_until", at}},
+	}}
+
+	// an update pipeline keeps the reset-or-increment decision atomic across app instances
+	update := bson.A{
+		bson.M{"$set": bson.M{
+			"failures": bson.M{"$cond": bson.A{
+				bson.M{"$or": bson.A{
+					bson.M{"$lt": bson.A{bson.M{"$ifNull": bson.A{"$last_failure", time.Time{}}}, resetBefore}},
+					lockEnded,
+				}},
+				1,
+				bson.M{"$add": bson.A{"$failures", 1}},
+			}},
+			"last_failure": at,
+			"locked_until": bson.M{"$cond": bson.A{lockEnded, "$$REMOVE", "$locked_until"}},
+		}},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var attempt LoginAttemptDB
+	err := collection.FindOneAndUpdate(context.Background(), bson.M{"_id": key}, update, opts).Decode(&attempt)
+	if err != nil {
+		return nil, classify(err, repository_errors.InsertError)
+	}
+
+	return copyLoginAttemptResultToModel(&attempt), nil
+}
+
+func (l LoginAttemptRepository) Lock(key string, until time.Time) error {
+	var collection = l.db.Collection("login_attempts")
+
+	result, err := collection.UpdateOne(context.Background(), bson.M{"_id": key}, bson.M{"$set": bson.M{"locked_until": until}})
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (l LoginAttemptRepository) Delete(key string) error {
+	var collection = l.db.Collection("login_attempts")
+
+	_, err := collection.DeleteOne(context.Background(), bson.M{"_id": key})
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	return nil
+}
+
+func (l LoginAttemptRepository) GetLocked(at time.Time) ([]models.LoginAttempt, error) {
+	var collection = l.db.Collection("login_attempts")
+
+	opts := options.Find().SetSort(bson.M{"locked_until": -1})
+	cursor, err := collection.Find(context.Background(), bson.M{"locked_until": bson.M{"$gt": at}}, opts)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+	defer cursor.Close(context.Background())
+
+	var attempts []models.LoginAttempt
+	for cursor.Next(context.Background()) {
+		var attempt LoginAttemptDB
+		if err := cursor.Decode(&attempt); err != nil {
+			return nil, classify(err, repository_errors.SelectError)
+		}
+		attempts = append(attempts, *copyLoginAttemptResultToModel(&attempt))
+	}
+
+	return attempts, nil
+}
@@ -0,0 +1,701 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoMigration is a schema change of the Mongo backend. MongoDB has no DDL scripts, so the steps are functions.
+type mongoMigration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, db *mongo.Database) error
+	Down    func(ctx context.Context, db *mongo.Database) error
+}
+
+type mongoIndex struct {
+	Collection string
+	Name       string
+	Keys       bson.D
+	Unique     bool
+	// Filter limits a unique index to the documents that have the indexed fields
+	Filter bson.M
+	// DefaultLanguage is the language of a text index
+	DefaultLanguage string
+}
+
+var collectionNames = []string{
+	"users",
+	"workers",
+	"orders",
+	"tasks",
+	"order_contains_tasks",
+	"categories",
+	"counters",
+	"sessions",
+	"login_attempts",
+	"one_time_tokens",
+	"worker_two_factor",
+	"audit_log",
+}
+
+var uniqueEmailIndexes = []mongoIndex{
+	{Collection: "users", Name: "users_email_unique", Keys: bson.D{{Key: "email", Value: 1}}, Unique: true},
+	{Collection: "workers", Name: "workers_email_unique", Keys: bson.D{{Key: "email", Value: 1}}, Unique: true},
+}
+
+var queryIndexes = []mongoIndex{
+	{Collection: "orders", Name: "orders_user_id_idx", Keys: bson.D{{Key: "user_id", Value: 1}}},
+	{Collection: "orders", Name: "orders_worker_id_idx", Keys: bson.D{{Key: "worker_id", Value: 1}}},
+	{Collection: "orders", Name: "orders_status_idx", Keys: bson.D{{Key: "status", Value: 1}}},
+	{
+		Collection: "orders",
+		Name:       "orders_idempotency_key_unique",
+		Keys:       bson.D{{Key: "user_id", Value: 1}, {Key: "idempotency_key", Value: 1}},
+		Unique:     true,
+		Filter:     bson.M{"idempotency_key": bson.M{"$type": "string"}},
+	},
+	{Collection: "order_contains_tasks", Name: "order_contains_tasks_order_id_idx", Keys: bson.D{{Key: "order_id", Value: 1}}},
+	{Collection: "order_contains_tasks", Name: "order_contains_tasks_task_id_idx", Keys: bson.D{{Key: "task_id", Value: 1}}},
+	{Collection: "tasks", Name: "tasks_category_idx", Keys: bson.D{{Key: "category", Value: 1}}},
+	{Collection: "sessions", Name: "sessions_user_id_idx", Keys: bson.D{{Key: "user_id", Value: 1}}},
+	{Collection: "sessions", Name: "sessions_worker_id_idx", Keys: bson.D{{Key: "worker_id", Value: 1}}},
+	{Collection: "one_time_tokens", Name: "one_time_tokens_user_id_idx", Keys: bson.D{{Key: "user_id", Value: 1}}},
+	{Collection: "one_time_tokens", Name: "one_time_tokens_worker_id_idx", Keys: bson.D{{Key: "worker_id", Value: 1}}},
+	{Collection: "audit_log", Name: "audit_log_created_at_idx", Keys: bson.D{{Key: "created_at", Value: 1}}},
+	{Collection: "audit_log", Name: "audit_log_target_idx", Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}}},
+}
+
+// taskSearchIndexes serve TaskRepository.Search, a collection can have only one text index.
+var taskSearchIndexes = []mongoIndex{
+	{Collection: "tasks", Name: "tasks_search_idx", Keys: bson.D{{Key: "name", Value: "text"}}, DefaultLanguage: "russian"},
+}
+
+// archiveCollections keep the archived orders and their lines, see OrderRepository.Archive.
+var archiveCollections = []string{"orders_archive", "order_contains_tasks_archive"}
+
+var orderArchiveIndexes = []mongoIndex{
+	{Collection: "orders", Name: "orders_closed_at_idx", Keys: bson.D{{Key: "closed_at", Value: 1}}},
+	{Collection: "orders_archive", Name: "orders_archive_user_id_idx", Keys: bson.D{{Key: "user_id", Value: 1}}},
+	{Collection: "orders_archive", Name: "orders_archive_worker_id_idx", Keys: bson.D{{Key: "worker_id", Value: 1}}},
+	{Collection: "order_contains_tasks_archive", Name: "order_contains_tasks_archive_order_id_idx", Keys: bson.D{{Key: "order_id", Value: 1}}},
+}
+
+var categoryTreeIndexes = []mongoIndex{
+	{Collection: "categories", Name: "categories_parent_id_idx", Keys: bson.D{{Key: "parent_id", Value: 1}}},
+}
+
+var taskPriceIndexes = []mongoIndex{
+	{
+		Collection: "task_prices",
+		Name:       "task_prices_task_id_effective_from_unique",
+		Keys:       bson.D{{Key: "task_id", Value: 1}, {Key: "effective_from", Value: 1}},
+		Unique:     true,
+	},
+	{Collection: "task_prices", Name: "task_prices_effective_from_idx", Keys: bson.D{{Key: "effective_from", Value: 1}}},
+}
+
+var invoiceIndexes = []mongoIndex{
+	{Collection: "invoices", Name: "invoices_number_unique", Keys: bson.D{{Key: "number", Value: 1}}, Unique: true},
+	{Collection: "invoices", Name: "invoices_order_id_unique", Keys: bson.D{{Key: "order_id", Value: 1}}, Unique: true},
+	{Collection: "invoices", Name: "invoices_user_id_idx", Keys: bson.D{{Key: "user_id", Value: 1}}},
+}
+
+// mongoMigrations are ordered by version. A released migration must not be changed, add a new one instead.
+var mongoMigrations = []mongoMigration{
+	{
+		Version: 1,
+		Name:    "initial_collections",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			existing, err := db.ListCollectionNames(ctx, bson.M{})
+			if err != nil {
+				return err
+			}
+
+			exists := make(map[string]bool, len(existing))
+			for _, name := range existing {
+				exists[name] = true
+			}
+
+			for _, name := range collectionNames {
+				if exists[name] {
+					continue
+				}
+				err = db.CreateCollection(ctx, name)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			for i := len(collectionNames) - 1; i >= 0; i-- {
+				err := db.Collection(collectionNames[i]).Drop(ctx)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	},
+	{
+		Version: 2,
+		Name:    "unique_emails",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndexes(ctx, db, uniqueEmailIndexes)
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			return dropIndexes(ctx, db, uniqueEmailIndexes)
+		},
+	},
+	{
+		Version: 3,
+		Name:    "query_indexes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndexes(ctx, db, queryIndexes)
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			return dropIndexes(ctx, db, queryIndexes)
+		},
+	},
+	{
+		Version: 4,
+		Name:    "order_version",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("orders").UpdateMany(ctx, bson.M{"version": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"version": 1}})
+			return err
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("orders").UpdateMany(ctx, bson.M{}, bson.M{"$unset": bson.M{"version": ""}})
+			return err
+		},
+	},
+	{
+		Version: 5,
+		Name:    "order_lines",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			// lines written without order_id belong to no order and were never read
+			_, err := db.Collection("order_contains_tasks").DeleteMany(ctx, bson.M{"order_id": bson.M{"$exists": false}})
+			if err != nil {
+				return err
+			}
+
+			_, err = db.Collection("order_contains_tasks").UpdateMany(ctx, bson.M{"quantity": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"quantity": 1}})
+			if err != nil {
+				return err
+			}
+
+			// an unassigned worker used to be stored as the zero UUID
+			_, err = db.Collection("orders").UpdateMany(ctx, bson.M{"worker_id": uuid.Nil}, bson.M{"$set": bson.M{"worker_id": nil}})
+			return err
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			// the documents stay readable by the previous version, the removed lines cannot be restored
+			return nil
+		},
+	},
+	{
+		Version: 6,
+		Name:    "task_search",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndexes(ctx, db, taskSearchIndexes)
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			return dropIndexes(ctx, db, taskSearchIndexes)
+		},
+	},
+	{
+		Version: 7,
+		Name:    "order_archive",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			// orders closed before closed_at existed are taken as closed at their deadline
+			closed := bson.M{"status": bson.M{"$in": bson.A{3, 4}}, "closed_at": nil}
+			_, err := db.Collection("orders").UpdateMany(ctx, closed, bson.A{bson.M{"$set": bson.M{"closed_at": "$deadline"}}})
+			if err != nil {
+				return err
+			}
+
+			// collections are created beforehand, transactions of older servers cannot create them
+			for _, name := range archiveCollections {
+				err = db.CreateCollection(ctx, name)
+				var commandErr mongo.CommandError
+				if errors.As(err, &commandErr) && commandErr.Name == "NamespaceExists" {
+					continue
+				} else if err != nil {
+					return err
+				}
+			}
+
+			return createIndexes(ctx, db, orderArchiveIndexes)
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			// archived orders are moved back, so that no order is lost
+			for _, name := range []string{"orders", "order_contains_tasks"} {
+				cursor, err := db.Collection(name+"_archive").Find(ctx, bson.M{})
+				if err != nil {
+					return err
+				}
+
+				var documents []bson.M
+				err = cursor.All(ctx, &documents)
+				if err != nil {
+					return err
+				}
+
+				for _, document := range documents {
+					_, err = db.Collection(name).InsertOne(ctx, document)
+					if err != nil && !mongo.IsDuplicateKeyError(err) {
+						return err
+					}
+				}
+			}
+
+			err := dropIndexes(ctx, db, orderArchiveIndexes)
+			if err != nil {
+				return err
+			}
+
+			for _, name := range archiveCollections {
+				err = db.Collection(name).Drop(ctx)
+				if err != nil {
+					return err
+				}
+			}
+
+			_, err = db.Collection("orders").UpdateMany(ctx, bson.M{}, bson.M{"$unset": bson.M{"closed_at": ""}})
+			return err
+		},
+	},
+	{
+		Version: 8,
+		Name:    "category_references",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createReferencedCategories(ctx, db)
+		},
+		// the created categories stay, the tasks keep referring to them
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			return nil
+		},
+	},
+	{
+		Version: 9,
+		Name:    "category_tree",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			// the existing categories keep the order of their ids
+			unordered := bson.M{"position": bson.M{"$exists": false}}
+			_, err := db.Collection("categories").UpdateMany(ctx, unordered, bson.A{bson.M{"$set": bson.M{"position": "$_id", "hidden": false}}})
+			if err != nil {
+				return err
+			}
+
+			return createIndexes(ctx, db, categoryTreeIndexes)
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			err := dropIndexes(ctx, db, categoryTreeIndexes)
+			if err != nil {
+				return err
+			}
+
+			_, err = db.Collection("categories").UpdateMany(ctx, bson.M{}, bson.M{"$unset": bson.M{"parent_id": "", "position": "", "hidden": ""}})
+			return err
+		},
+	},
+	{
+		Version: 10,
+		Name:    "task_prices",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			err := createIndexes(ctx, db, taskPriceIndexes)
+			if err != nil {
+				return err
+			}
+
+			// the current prices start the history of the existing tasks, the first price takes the id of its task
+			pipeline := bson.A{
+				bson.M{"$project": bson.M{
+					"task_id":        "$_id",
+					"price":          "$price_per_single",
+					"effective_from": time.Unix(0, 0).UTC(),
+				}},
+				bson.M{"$merge": bson.M{"into": "task_prices", "whenMatched": "keepExisting"}},
+			}
+			cursor, err := db.Collection("tasks").Aggregate(ctx, pipeline)
+			if err != nil {
+				return err
+			}
+			return cursor.Close(ctx)
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			return db.Collection("task_prices").Drop(ctx)
+		},
+	},
+	{
+		Version: 11,
+		Name:    "invoices",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			// the collection is created before it is written in a transaction
+			return createIndexes(ctx, db, invoiceIndexes)
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("counters").DeleteOne(ctx, bson.M{"_id": "invoice_number"})
+			if err != nil {
+				return err
+			}
+			return db.Collection("invoices").Drop(ctx)
+		},
+	},
+	{
+		Version: 12,
+		Name:    "tax_rates",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			// the existing categories and tasks take the rate of their parents, at last the default one
+			for _, collection := range []string{"categories", "tasks"} {
+				_, err := db.Collection(collection).UpdateMany(ctx, bson.M{"tax_rate": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"tax_rate": ""}})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			for _, collection := range []string{"categories", "tasks"} {
+				_, err := db.Collection(collection).UpdateMany(ctx, bson.M{}, bson.M{"$unset": bson.M{"tax_rate": ""}})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 13,
+		Name:    "verified_existing_users",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			// clients who signed up before email verification existed keep ordering, only new accounts confirm their email
+			_, err := db.Collection("users").UpdateMany(ctx, bson.M{"email_verified": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"email_verified": true}})
+			return err
+		},
+		// the accounts stay verified, they were allowed to order before
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			return nil
+		},
+	},
+}
+
+// builtinCategories are the names of the categories the application used to have built in.
+var builtinCategories = map[int]string{
+	1: "Генеральная уборка",
+	2: "Послестроительная уборка",
+	3: "Мытье окон",
+	4: "Ежедневная уборка офисов",
+	5: "Поддерживающая уборка",
+	6: "Химчистка ковров и мебели",
+	7: "Уход за твердыми полами",
+	8: "Глубинная Эко Чистка",
+}
+
+// createReferencedCategories creates the categories that tasks refer to but that were never created,
+// with their built in names or a placeholder name a manager can change, and moves the category counter past them.
+func createReferencedCategories(ctx context.Context, db *mongo.Database) error {
+	referenced, err := db.Collection("tasks").Distinct(ctx, "category", bson.M{})
+	if err != nil {
+		return err
+	}
+
+	maxID := 0
+	for _, value := range referenced {
+		var id int
+		switch number := value.(type) {
+		case int32:
+			id = int(number)
+		case int64:
+			id = int(number)
+		default:
+			continue
+		}
+
+		category := CategoryDB{ID: id, Name: builtinCategories[id]}
+		if category.Name == "" {
+			category.Name = fmt.Sprintf("Категория %d", id)
+		}
+		_, err = db.Collection("categories").InsertOne(ctx, category)
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+
+		if category.ID > maxID {
+			maxID = category.ID
+		}
+	}
+
+	_, err = db.Collection("counters").UpdateOne(ctx,
+		bson.M{"_id": "categoryid", "seq": bson.M{"$not": bson.M{"$gte": maxID}}},
+		bson.M{"$set": bson.M{"seq": maxID}},
+		options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// the counter is already past the created categories
+		return nil
+	}
+	return err
+}
+
+func createIndexes(ctx context.Context, db *mongo.Database, indexes []mongoIndex) error {
+	for _, index := range indexes {
+		opts := options.Index().SetName(index.Name)
+		if index.Unique {
+			opts.SetUnique(true)
+		}
+		if index.Filter != nil {
+			opts.SetPartialFilterExpression(index.Filter)
+		}
+		if index.DefaultLanguage != "" {
+			opts.SetDefaultLanguage(index.DefaultLanguage)
+		}
+
+		_, err := db.Collection(index.Collection).Indexes().CreateOne(ctx, mongo.IndexModel{Keys: index.Keys, Options: opts})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func dropIndexes(ctx context.Context, db *mongo.Database, indexes []mongoIndex) error {
+	for i := len(indexes) - 1; i >= 0; i-- {
+		_, err := db.Collection(indexes[i].Collection).Indexes().DropOne(ctx, indexes[i].Name)
+		var commandErr mongo.CommandError
+		// the index is already gone
+		if errors.As(err, &commandErr) && commandErr.Name == "IndexNotFound" {
+			continue
+		} else if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type MigrationDB struct {
+	Version   int       `bson:"_id"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+const (
+	// migrationLockID is the document that serialises migrations of several application instances started at once
+	migrationLockID = "migrations"
+	// migrationLockTTL frees the lock of an instance that stopped while migrating, the holder renews it after every step
+	migrationLockTTL  = 10 * time.Minute
+	migrationLockPoll = 500 * time.Millisecond
+)
+
+type Migrator struct {
+	db         *mongo.Database
+	migrations []mongoMigration
+}
+
+func NewMigrator(db *mongo.Database) repository_interfaces.IMigrator {
+	migrations := append([]mongoMigration(nil), mongoMigrations...)
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return &Migrator{db: db, migrations: migrations}
+}
+
+func (m Migrator) applied(ctx context.Context) (map[int]MigrationDB, error) {
+	cursor, err := m.db.Collection("schema_migrations").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var rows []MigrationDB
+	err = cursor.All(ctx, &rows)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	applied := make(map[int]MigrationDB, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+
+	return applied, nil
+}
+
+// lock takes the migration lock for the whole run, waiting while another instance holds it.
+// It returns the owner that renews and releases the lock.
+func (m Migrator) lock(ctx context.Context) (uuid.UUID, error) {
+	var collection = m.db.Collection("migration_lock")
+	owner := uuid.New()
+
+	for {
+		now := time.Now()
+		// the filter matches no held lock, so the upsert of a held one fails on the unique _id
+		_, err := collection.UpdateOne(ctx,
+			bson.M{"_id": migrationLockID, "locked_at": bson.M{"$lt": now.Add(-migrationLockTTL)}},
+			bson.M{"$set": bson.M{"owner": owner, "locked_at": now}},
+			options.Update().SetUpsert(true))
+		if err == nil {
+			return owner, nil
+		} else if !mongo.IsDuplicateKeyError(err) {
+			return uuid.Nil, classify(err, repository_errors.InsertError)
+		}
+
+		time.Sleep(migrationLockPoll)
+	}
+}
+
+func (m Migrator) renewLock(ctx context.Context, owner uuid.UUID) error {
+	_, err := m.db.Collection("migration_lock").UpdateOne(ctx, bson.M{"_id": migrationLockID, "owner": owner}, bson.M{"$set": bson.M{"locked_at": time.Now()}})
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+	return nil
+}
+
+func (m Migrator) unlock(ctx context.Context, owner uuid.UUID) {
+	_, _ = m.db.Collection("migration_lock").DeleteOne(ctx, bson.M{"_id": migrationLockID, "owner": owner})
+}
+
+func (m Migrator) hasUnknownVersions(applied map[int]MigrationDB) bool {
+	known := make(map[int]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+	}
+
+	for version := range applied {
+		if !known[version] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m Migrator) Up() ([]models.Migration, error) {
+	ctx := context.Background()
+	var collection = m.db.Collection("schema_migrations")
+
+	owner, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer m.unlock(ctx, owner)
+
+	// read under the lock, another instance may have applied versions while this one was waiting
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.hasUnknownVersions(applied) {
+		return nil, repository_errors.UnknownMigration
+	}
+
+	var result []models.Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err = migration.Up(ctx, m.db)
+		if err != nil {
+			return result, err
+		}
+
+		// the version is recorded once its step has succeeded, a failed step runs again on the next start
+		record := MigrationDB{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}
+		_, err = collection.InsertOne(ctx, record)
+		if err != nil {
+			return result, classify(err, repository_errors.InsertError)
+		}
+
+		err = m.renewLock(ctx, owner)
+		if err != nil {
+			return result, err
+		}
+
+		result = append(result, models.Migration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Applied:   true,
+			AppliedAt: record.AppliedAt,
+		})
+	}
+
+	return result, nil
+}
+
+func (m Migrator) Down() (*models.Migration, error) {
+	ctx := context.Background()
+	var collection = m.db.Collection("schema_migrations")
+
+	owner, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer m.unlock(ctx, owner)
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.hasUnknownVersions(applied) {
+		return nil, repository_errors.UnknownMigration
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		err = migration.Down(ctx, m.db)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = collection.DeleteOne(ctx, bson.M{"_id": migration.Version})
+		if err != nil {
+			return nil, classify(err, repository_errors.DeleteError)
+		}
+
+		return &models.Migration{Version: migration.Version, Name: migration.Name}, nil
+	}
+
+	return nil, repository_errors.DoesNotExist
+}
+
+func (m Migrator) Status() ([]models.Migration, error) {
+	applied, err := m.applied(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Migration, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := models.Migration{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = row.AppliedAt
+		}
+		result = append(result, status)
+	}
+
+	return result, nil
+}
@@ -14,13 +14,14 @@ type MongoConnection struct {
 	Config config.Config
 }
 
-func NewMongoConnection(Postgres config.DbConnectionFlags, logger *log.Logger) (*MongoConnection, error) {
+func NewMongoConnection(Postgres config.DbConnectionFlags, Mongo config.MongoConfig, logger *log.Logger) (*MongoConnection, error) {
 	fields := new(MongoConnection)
 	var err error
 
 	fields.Config.DBFlags = Postgres
+	fields.Config.Mongo = Mongo
 
-	fields.DB, err = fields.Config.DBFlags.InitMongoDB(logger)
+	fields.DB, err = fields.Config.DBFlags.InitMongoDB(Mongo, logger)
 	if err != nil {
 		logger.Error("POSTGRES! Error parse config for mongoDB")
 		return nil, repository_errors.ConnectionError
@@ -50,3 +51,31 @@ func CreateWorkerRepository(fields *MongoConnection) repository_interfaces.IWork
 func CreateOrderRepository(fields *MongoConnection) repository_interfaces.IOrderRepository {
 	return NewOrderRepository(fields.DB)
 }
+
+func CreateSessionRepository(fields *MongoConnection) repository_interfaces.ISessionRepository {
+	return NewSessionRepository(fields.DB)
+}
+
+func CreateLoginAttemptRepository(fields *MongoConnection) repository_interfaces.ILoginAttemptRepository {
+	return NewLoginAttemptRepository(fields.DB)
+}
+
+func CreateOneTimeTokenRepository(fields *MongoConnection) repository_interfaces.IOneTimeTokenRepository {
+	return NewOneTimeTokenRepository(fields.DB)
+}
+
+func CreateTwoFactorRepository(fields *MongoConnection) repository_interfaces.ITwoFactorRepository {
+	return NewTwoFactorRepository(fields.DB)
+}
+
+func CreateAuditRepository(fields *MongoConnection) repository_interfaces.IAuditRepository {
+	return NewAuditRepository(fields.DB)
+}
+
+func CreateInvoiceRepository(fields *MongoConnection) repository_interfaces.IInvoiceRepository {
+	return NewInvoiceRepository(fields.DB)
+}
+
+func CreateMigrator(fields *MongoConnection) repository_interfaces.IMigrator {
+	return NewMigrator(fields.DB)
+}
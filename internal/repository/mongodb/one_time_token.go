@@ -0,0 +1,98 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type OneTimeTokenDB struct {
+	Hash      string    `bson:"_id"`
+	Purpose   string    `bson:"purpose"`
+	UserID    uuid.UUID `bson:"user_id"`
+	WorkerID  uuid.UUID `bson:"worker_id"`
+	ExpiresAt time.Time `bson:"expires_at"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+type OneTimeTokenRepository struct {
+	db *mongo.Database
+}
+
+func NewOneTimeTokenRepository(db *mongo.Database) repository_interfaces.IOneTimeTokenRepository {
+	return &OneTimeTokenRepository{db: db}
+}
+
+func copyOneTimeTokenResultToModel(tokenDB *OneTimeTokenDB) *models.OneTimeToken {
+	return &models.OneTimeToken{
+		Hash:      tokenDB.Hash,
+		Purpose:   tokenDB.Purpose,
+		UserID:    tokenDB.UserID,
+		WorkerID:  tokenDB.WorkerID,
+		ExpiresAt: tokenDB.ExpiresAt,
+		CreatedAt: tokenDB.CreatedAt,
+	}
+}
+
+func (o OneTimeTokenRepository) Create(token *models.OneTimeToken) error {
+	var collection = o.db.Collection("one_time_tokens")
+
+	_, err := collection.InsertOne(context.Background(), OneTimeTokenDB{
+		Hash:      token.Hash,
+		Purpose:   token.Purpose,
+		UserID:    token.UserID,
+		WorkerID:  token.WorkerID,
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: token.CreatedAt,
+	})
+	if err != nil {
+		return classify(err, repository_errors.InsertError)
+	}
+
+	return nil
+}
+
+func (o OneTimeTokenRepository) Consume(hash string, purpose string, at time.Time) (*models.OneTimeToken, error) {
+	var collection = o.db.Collection("one_time_tokens")
+
+	filter := bson.M{"_id": hash, "purpose": purpose, "expires_at": bson.M{"$gt": at}}
+
+	var token OneTimeTokenDB
+	err := collection.FindOneAndDelete(context.Background(), filter).Decode(&token)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.DeleteError)
+	}
+
+	return copyOneTimeTokenResultToModel(&token), nil
+}
+
+func (o OneTimeTokenRepository) DeleteByUserID(purpose string, userID uuid.UUID) error {
+	var collection = o.db.Collection("one_time_tokens")
+
+	_, err := collection.DeleteMany(context.Background(), bson.M{"purpose": purpose, "user_id": userID})
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	return nil
+}
+
+func (o OneTimeTokenRepository) DeleteByWorkerID(purpose string, workerID uuid.UUID) error {
+	var collection = o.db.Collection("one_time_tokens")
+
+	_, err := collection.DeleteMany(context.Background(), bson.M{"purpose": purpose, "worker_id": workerID})
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	return nil
+}
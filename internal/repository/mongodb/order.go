@@ -5,6 +5,7 @@ import (
 	"errors"
 	"lab3/internal/models"
 	"lab3/internal/repository/repository_errors"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -19,14 +20,27 @@ import (
 )
 
 type OrderDB struct {
-	ID           uuid.UUID `bson:"_id"`
-	WorkerID     uuid.UUID `bson:"worker_id"`
-	UserID       uuid.UUID `bson:"user_id"`
-	Status       int       `bson:"status"`
-	Address      string    `bson:"address"`
-	CreationDate time.Time `bson:"creation_date"`
-	Deadline     time.Time `bson:"deadline"`
-	Rate         int       `bson:"rate"`
+	ID uuid.UUID `bson:"_id"`
+	// WorkerID is null until a worker is assigned, like the worker_id column in Postgres
+	WorkerID     *uuid.UUID `bson:"worker_id"`
+	UserID       uuid.UUID  `bson:"user_id"`
+	Status       int        `bson:"status"`
+	Address      string     `bson:"address"`
+	CreationDate time.Time  `bson:"creation_date"`
+	Deadline     time.Time  `bson:"deadline"`
+	Rate         int        `bson:"rate"`
+	// IdempotencyKey is left out of documents of orders created without a key
+	IdempotencyKey string    `bson:"idempotency_key,omitempty"`
+	Version        int       `bson:"version"`
+	ClosedAt       time.Time `bson:"closed_at,omitempty"`
+}
+
+// OrderedTaskDB is a line of an order in the order_contains_tasks collection, the counterpart of the
+// order_contains_tasks table in Postgres.
+type OrderedTaskDB struct {
+	OrderID  uuid.UUID `bson:"order_id"`
+	TaskID   uuid.UUID `bson:"task_id"`
+	Quantity int       `bson:"quantity"`
 }
 
 type OrderRepository struct {
@@ -38,16 +52,39 @@ func NewOrderRepository(db *mongo.Database) repository_interfaces.IOrderReposito
 }
 
 func copyOrderResultToModel(orderDB *OrderDB) *models.Order {
-	return &models.Order{
-		ID:           orderDB.ID,
-		WorkerID:     orderDB.WorkerID,
-		UserID:       orderDB.UserID,
-		Status:       orderDB.Status,
-		Address:      orderDB.Address,
-		CreationDate: orderDB.CreationDate,
-		Deadline:     orderDB.Deadline,
-		Rate:         orderDB.Rate,
+	order := &models.Order{
+		ID:             orderDB.ID,
+		UserID:         orderDB.UserID,
+		Status:         orderDB.Status,
+		Address:        orderDB.Address,
+		CreationDate:   orderDB.CreationDate,
+		Deadline:       orderDB.Deadline,
+		Rate:           orderDB.Rate,
+		IdempotencyKey: orderDB.IdempotencyKey,
+		Version:        orderDB.Version,
+		ClosedAt:       orderDB.ClosedAt,
+	}
+	if orderDB.WorkerID != nil {
+		order.WorkerID = *orderDB.WorkerID
+	}
+
+	return order
+}
+
+// optionalTime stores the zero time as null, like the closed_at column of an open order in Postgres.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
 	}
+	return &t
+}
+
+// optionalWorkerID stores an unassigned worker as null.
+func optionalWorkerID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
 }
 
 func (o OrderRepository) Create(order *models.Order, orderedTasks []models.OrderedTask) (*models.Order, error) {
@@ -57,28 +94,57 @@ func (o OrderRepository) Create(order *models.Order, orderedTasks []models.Order
 	if order.ID == uuid.Nil {
 		order.ID = uuid.New()
 	}
+	if order.CreationDate.IsZero() {
+		order.CreationDate = time.Now()
+	}
+	order.Version = 1
 
-	_, err := collection.InsertOne(context.Background(), OrderDB{
-		ID:           order.ID,
-		WorkerID:     order.WorkerID,
-		UserID:       order.UserID,
-		Status:       order.Status,
-		Address:      order.Address,
-		CreationDate: order.CreationDate,
-		Deadline:     order.Deadline,
-		Rate:         order.Rate,
-	})
+	err := withTransaction(o.db, func(ctx mongo.SessionContext) error {
+		// the client must exist, like the foreign key in Postgres requires
+		count, err := o.db.Collection("users").CountDocuments(ctx, bson.M{"_id": order.UserID})
+		if err != nil || count == 0 {
+			return classify(err, repository_errors.InsertError)
+		}
 
-	if err != nil {
-		return nil, repository_errors.InsertError
-	}
+		_, err = collection.InsertOne(ctx, OrderDB{
+			ID:             order.ID,
+			WorkerID:       optionalWorkerID(order.WorkerID),
+			UserID:         order.UserID,
+			Status:         order.Status,
+			Address:        order.Address,
+			CreationDate:   order.CreationDate,
+			Deadline:       order.Deadline,
+			Rate:           order.Rate,
+			IdempotencyKey: order.IdempotencyKey,
+			Version:        order.Version,
+		})
+
+		// the unique index on the idempotency key rejects a repeated submission
+		if mongo.IsDuplicateKeyError(err) {
+			return repository_errors.AlreadyExists
+		} else if err != nil {
+			return classify(err, repository_errors.InsertError)
+		}
 
-	var orderedTasksInterface []interface{}
-	for _, data := range orderedTasks {
-		orderedTasksInterface = append(orderedTasksInterface, data)
-	}
+		if len(orderedTasks) == 0 {
+			return nil
+		}
+
+		lines := make([]interface{}, 0, len(orderedTasks))
+		for _, orderedTask := range orderedTasks {
+			lines = append(lines, OrderedTaskDB{OrderID: order.ID, TaskID: orderedTask.Task.ID, Quantity: orderedTask.Quantity})
+		}
+
+		_, err = m2mCollection.InsertMany(ctx, lines)
+		if err != nil {
+			return classify(err, repository_errors.InsertError)
+		}
 
-	_, err = m2mCollection.InsertMany(context.Background(), orderedTasksInterface)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	return order, nil
 }
@@ -86,56 +152,105 @@ func (o OrderRepository) Create(order *models.Order, orderedTasks []models.Order
 func (o OrderRepository) Delete(id uuid.UUID) error {
 	var ordersCollection = o.db.Collection("orders")
 	var m2mCollection = o.db.Collection("order_contains_tasks")
-	var filter = map[string]interface{}{"_id": id}
 
-	_, err := m2mCollection.DeleteMany(context.Background(), filter)
-	if err != nil {
-		return repository_errors.DeleteError
-	}
-	result, err := ordersCollection.DeleteOne(context.Background(), filter)
-	if err != nil {
-		return repository_errors.DeleteError
-	}
+	return withTransaction(o.db, func(ctx mongo.SessionContext) error {
+		_, err := m2mCollection.DeleteMany(ctx, bson.M{"order_id": id})
+		if err != nil {
+			return classify(err, repository_errors.DeleteError)
+		}
 
-	if result.DeletedCount == 0 {
-		return repository_errors.DoesNotExist
-	}
+		result, err := ordersCollection.DeleteOne(ctx, bson.M{"_id": id})
+		if err != nil {
+			return classify(err, repository_errors.DeleteError)
+		}
 
-	return nil
+		if result.DeletedCount == 0 {
+			return repository_errors.DoesNotExist
+		}
+
+		return nil
+	})
 }
 
 func (o OrderRepository) Update(order *models.Order) (*models.Order, error) {
 	var collection = o.db.Collection("orders")
-	var filter = map[string]interface{}{"_id": order.ID}
+	// the document is only written if nobody has changed it since order.Version was read
+	var filter = map[string]interface{}{"_id": order.ID, "version": order.Version}
 
 	update := map[string]interface{}{
 		"$set": map[string]interface{}{
-			"worker_id":     order.WorkerID,
+			"worker_id":     optionalWorkerID(order.WorkerID),
 			"user_id":       order.UserID,
 			"status":        order.Status,
 			"address":       order.Address,
 			"creation_date": order.CreationDate,
 			"deadline":      order.Deadline,
 			"rate":          order.Rate,
+			"closed_at":     optionalTime(order.ClosedAt),
 		},
+		"$inc": map[string]interface{}{"version": 1},
 	}
 
-	_, err := collection.UpdateOne(context.Background(), filter, update)
+	result, err := collection.UpdateOne(context.Background(), filter, update)
 	if err != nil {
-		return nil, repository_errors.UpdateError
+		return nil, classify(err, repository_errors.UpdateError)
 	}
 
-	return order, nil
+	if result.MatchedCount == 0 {
+		return nil, o.updateMissError(order.ID)
+	}
+
+	updatedOrder := *order
+	updatedOrder.Version++
+	return &updatedOrder, nil
+}
+
+// updateMissError tells a missing order from one whose version has changed since it was read.
+func (o OrderRepository) updateMissError(id uuid.UUID) error {
+	count, err := o.db.Collection("orders").CountDocuments(context.Background(), bson.M{"_id": id})
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+	if count == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return repository_errors.VersionConflict
 }
 
 func (o OrderRepository) GetOrderByID(id uuid.UUID) (*models.Order, error) {
-	var collection = o.db.Collection("orders")
 	var filter = map[string]interface{}{"_id": id}
 
+	var order OrderDB
+	err := o.db.Collection("orders").FindOne(context.Background(), filter).Decode(&order)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		err = o.db.Collection("orders_archive").FindOne(context.Background(), filter).Decode(&order)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, repository_errors.DoesNotExist
+		} else if err != nil {
+			return nil, classify(err, repository_errors.SelectError)
+		}
+
+		archived := copyOrderResultToModel(&order)
+		archived.Archived = true
+		return archived, nil
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	return copyOrderResultToModel(&order), nil
+}
+
+func (o OrderRepository) GetOrderByIdempotencyKey(userID uuid.UUID, key string) (*models.Order, error) {
+	var collection = o.db.Collection("orders")
+	var filter = bson.M{"user_id": userID, "idempotency_key": key}
+
 	var order OrderDB
 	err := collection.FindOne(context.Background(), filter).Decode(&order)
-	if err != nil {
+	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	return copyOrderResultToModel(&order), nil
@@ -144,47 +259,75 @@ func (o OrderRepository) GetOrderByID(id uuid.UUID) (*models.Order, error) {
 func (o OrderRepository) GetTasksInOrder(id uuid.UUID) ([]models.Task, error) {
 	var m2mCollection = o.db.Collection("order_contains_tasks")
 	var tasksCollection = o.db.Collection("tasks")
+	ctx := context.Background()
 
-	var order OrderDB
-	err := m2mCollection.FindOne(context.Background(), map[string]interface{}{"order_id": id}).Decode(&order)
-	if err != nil {
-		return nil, repository_errors.DoesNotExist
-	}
-
-	var tasks []models.Task
-	cursor, err := m2mCollection.Find(context.Background(), map[string]interface{}{"order_id": id})
+	lines, err := findLines(ctx, m2mCollection, id)
 	if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, err
 	}
 
-	for cursor.Next(context.Background()) {
-		var orderedTask models.OrderedTask
-		err := cursor.Decode(&orderedTask)
+	// an archived order has its lines in the archive
+	if len(lines) == 0 {
+		lines, err = findLines(ctx, o.db.Collection("order_contains_tasks_archive"), id)
 		if err != nil {
-			return nil, repository_errors.SelectError
+			return nil, err
 		}
+	}
 
-		var task models.Task
-		err = tasksCollection.FindOne(context.Background(), map[string]interface{}{"_id": orderedTask.Task.ID}).Decode(&task)
-		if err != nil {
-			return nil, repository_errors.DoesNotExist
-		}
+	if len(lines) == 0 {
+		return nil, nil
+	}
 
-		tasks = append(tasks, task)
+	taskIDs := make([]uuid.UUID, 0, len(lines))
+	for _, line := range lines {
+		taskIDs = append(taskIDs, line.TaskID)
+	}
+
+	cursor, err := tasksCollection.Find(ctx, bson.M{"_id": bson.M{"$in": taskIDs}})
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var tasksDB []TaskDB
+	err = cursor.All(ctx, &tasksDB)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var tasks []models.Task
+	for i := range tasksDB {
+		tasks = append(tasks, *copyTaskResultToModel(&tasksDB[i]))
 	}
 
 	return tasks, nil
 }
 
+func findLines(ctx context.Context, collection *mongo.Collection, orderID uuid.UUID) ([]OrderedTaskDB, error) {
+	cursor, err := collection.Find(ctx, bson.M{"order_id": orderID})
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var lines []OrderedTaskDB
+	err = cursor.All(ctx, &lines)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	return lines, nil
+}
+
 func (o OrderRepository) GetCurrentOrderByUserID(id uuid.UUID) (*models.Order, error) {
 	var collection = o.db.Collection("orders")
 	var filter = map[string]interface{}{"user_id": id}
 	var order OrderDB
 
-	opts := options.FindOne().SetSort(bson.D{{"creation_date", -1}})
+	opts := options.FindOne().SetSort(bson.D{{Key: "creation_date", Value: -1}})
 	err := collection.FindOne(context.Background(), filter, opts).Decode(&order)
-	if err != nil {
+	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	return copyOrderResultToModel(&order), nil
@@ -196,7 +339,7 @@ func (o OrderRepository) GetAllOrdersByUserID(id uuid.UUID) ([]models.Order, err
 
 	cursor, err := collection.Find(context.Background(), filter)
 	if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	var orders []models.Order
@@ -204,7 +347,7 @@ func (o OrderRepository) GetAllOrdersByUserID(id uuid.UUID) ([]models.Order, err
 		var order OrderDB
 		err := cursor.Decode(&order)
 		if err != nil {
-			return nil, repository_errors.SelectError
+			return nil, classify(err, repository_errors.SelectError)
 		}
 		orders = append(orders, *copyOrderResultToModel(&order))
 	}
@@ -212,75 +355,210 @@ func (o OrderRepository) GetAllOrdersByUserID(id uuid.UUID) ([]models.Order, err
 	return orders, nil
 }
 
-func (o OrderRepository) Filter(params map[string]string) ([]models.Order, error) {
-	var collection = o.db.Collection("orders")
+// filterValue converts a value of the Filter parameters to the type stored in the field.
+func filterValue(field string, value string) (interface{}, error) {
+	if value == "null" {
+		return nil, nil
+	}
+
+	switch field {
+	case "status", "rate", "version":
+		return strconv.Atoi(value)
+	case "_id", "id", "worker_id", "user_id":
+		return uuid.Parse(value)
+	}
 
+	return value, nil
+}
+
+// orderFilter converts the Filter parameters to a query.
+func orderFilter(params map[string]string) (bson.M, error) {
 	filter := bson.M{}
 	for field, value := range params {
+		key := field
+		if field == "id" {
+			key = "_id"
+		}
+
+		if value == "not null" {
+			filter[key] = bson.M{"$ne": nil}
+			continue
+		}
+
 		values := strings.Split(value, ",")
-		if len(values) > 1 {
-			var inValues []interface{}
-			for _, v := range values {
-				if v == "null" {
-					inValues = append(inValues, nil)
-				} else if field == "status" {
-					status, err := strconv.Atoi(v)
-					if err != nil {
-						return nil, err
-					}
-					inValues = append(inValues, status)
-				} else {
-					inValues = append(inValues, v)
-				}
+		inValues := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			converted, err := filterValue(field, v)
+			if err != nil {
+				return nil, classify(err, repository_errors.SelectError)
 			}
-			filter[field] = bson.M{"$in": inValues}
+			inValues = append(inValues, converted)
+		}
+
+		if len(inValues) == 1 {
+			filter[key] = inValues[0]
 		} else {
-			if value == "null" {
-				filter[field] = nil
-			} else if value == "not null" {
-				filter[field] = bson.M{"$ne": nil}
-			} else if field == "status" {
-				status, err := strconv.Atoi(value)
-				if err != nil {
-					return nil, err
-				}
-				filter[field] = status
-			} else {
-				filter[field] = value
-			}
+			filter[key] = bson.M{"$in": inValues}
 		}
 	}
 
-	cursor, err := collection.Find(context.Background(), filter)
+	return filter, nil
+}
+
+func (o OrderRepository) Filter(params map[string]string) ([]models.Order, error) {
+	filter, err := orderFilter(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.findOrders(context.Background(), "orders", filter)
+}
+
+func (o OrderRepository) History(params map[string]string) ([]models.Order, error) {
+	filter, err := orderFilter(params)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	orders, err := o.findOrders(ctx, "orders", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	archived, err := o.findOrders(ctx, "orders_archive", filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range archived {
+		archived[i].Archived = true
+	}
+
+	orders = append(orders, archived...)
+	sort.SliceStable(orders, func(i, j int) bool {
+		return orders[i].CreationDate.After(orders[j].CreationDate)
+	})
+	return orders, nil
+}
+
+// findOrders returns the orders of the collection matching the filter.
+func (o OrderRepository) findOrders(ctx context.Context, collection string, filter bson.M) ([]models.Order, error) {
+	cursor, err := o.db.Collection(collection).Find(ctx, filter)
 	if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var ordersDB []OrderDB
+	err = cursor.All(ctx, &ordersDB)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	var orders []models.Order
-	for cursor.Next(context.Background()) {
-		var order OrderDB
-		err := cursor.Decode(&order)
+	for i := range ordersDB {
+		orders = append(orders, *copyOrderResultToModel(&ordersDB[i]))
+	}
+
+	return orders, nil
+}
+
+// Archive copies the closed orders and their lines to the archive collections and deletes them
+// in one transaction.
+func (o OrderRepository) Archive(closedBefore time.Time) (int, error) {
+	count := 0
+	err := withTransaction(o.db, func(ctx mongo.SessionContext) error {
+		filter := bson.M{
+			"status":    bson.M{"$in": []int{models.CompletedOrderStatus, models.CancelledOrderStatus}},
+			"closed_at": bson.M{"$lt": closedBefore},
+		}
+
+		cursor, err := o.db.Collection("orders").Find(ctx, filter)
 		if err != nil {
-			return nil, repository_errors.SelectError
+			return classify(err, repository_errors.UpdateError)
 		}
-		orders = append(orders, *copyOrderResultToModel(&order))
+
+		var ordersDB []OrderDB
+		err = cursor.All(ctx, &ordersDB)
+		if err != nil {
+			return classify(err, repository_errors.UpdateError)
+		}
+
+		if len(ordersDB) == 0 {
+			return nil
+		}
+
+		orders := make([]interface{}, 0, len(ordersDB))
+		ids := make([]uuid.UUID, 0, len(ordersDB))
+		for _, order := range ordersDB {
+			orders = append(orders, order)
+			ids = append(ids, order.ID)
+		}
+
+		_, err = o.db.Collection("orders_archive").InsertMany(ctx, orders)
+		if err != nil {
+			return classify(err, repository_errors.UpdateError)
+		}
+
+		linesFilter := bson.M{"order_id": bson.M{"$in": ids}}
+		cursor, err = o.db.Collection("order_contains_tasks").Find(ctx, linesFilter)
+		if err != nil {
+			return classify(err, repository_errors.UpdateError)
+		}
+
+		var linesDB []OrderedTaskDB
+		err = cursor.All(ctx, &linesDB)
+		if err != nil {
+			return classify(err, repository_errors.UpdateError)
+		}
+
+		if len(linesDB) > 0 {
+			lines := make([]interface{}, 0, len(linesDB))
+			for _, line := range linesDB {
+				lines = append(lines, line)
+			}
+
+			_, err = o.db.Collection("order_contains_tasks_archive").InsertMany(ctx, lines)
+			if err != nil {
+				return classify(err, repository_errors.UpdateError)
+			}
+		}
+
+		_, err = o.db.Collection("order_contains_tasks").DeleteMany(ctx, linesFilter)
+		if err != nil {
+			return classify(err, repository_errors.UpdateError)
+		}
+
+		_, err = o.db.Collection("orders").DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+		if err != nil {
+			return classify(err, repository_errors.UpdateError)
+		}
+
+		count = len(ids)
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	return orders, nil
+	return count, nil
 }
+
+// AddTaskToOrder adds a line with the quantity of 1. Like the foreign keys in Postgres, both the order
+// and the task must exist.
 func (o OrderRepository) AddTaskToOrder(orderID uuid.UUID, taskID uuid.UUID) error {
 	var m2mCollection = o.db.Collection("order_contains_tasks")
-	var ordersCollection = o.db.Collection("orders")
+	ctx := context.Background()
 
-	var order OrderDB
-	err := ordersCollection.FindOne(context.Background(), map[string]interface{}{"_id": orderID}).Decode(&order)
-	if err != nil {
-		return repository_errors.DoesNotExist
+	for collection, id := range map[string]uuid.UUID{"orders": orderID, "tasks": taskID} {
+		count, err := o.db.Collection(collection).CountDocuments(ctx, bson.M{"_id": id})
+		if err != nil || count == 0 {
+			return classify(err, repository_errors.InsertError)
+		}
 	}
 
-	_, err = m2mCollection.InsertOne(context.Background(), bson.M{"order_id": orderID, "task_id": taskID})
+	_, err := m2mCollection.InsertOne(ctx, OrderedTaskDB{OrderID: orderID, TaskID: taskID, Quantity: 1})
 	if err != nil {
-		return repository_errors.InsertError
+		return classify(err, repository_errors.InsertError)
 	}
 
 	return nil
@@ -288,17 +566,10 @@ func (o OrderRepository) AddTaskToOrder(orderID uuid.UUID, taskID uuid.UUID) err
 
 func (o OrderRepository) RemoveTaskFromOrder(orderID uuid.UUID, taskID uuid.UUID) error {
 	var m2mCollection = o.db.Collection("order_contains_tasks")
-	var ordersCollection = o.db.Collection("orders")
-
-	var order OrderDB
-	err := ordersCollection.FindOne(context.Background(), map[string]interface{}{"_id": orderID}).Decode(&order)
-	if err != nil {
-		return repository_errors.DoesNotExist
-	}
 
-	_, err = m2mCollection.DeleteOne(context.Background(), bson.M{"order_id": orderID, "task_id": taskID})
+	_, err := m2mCollection.DeleteMany(context.Background(), bson.M{"order_id": orderID, "task_id": taskID})
 	if err != nil {
-		return repository_errors.DeleteError
+		return classify(err, repository_errors.DeleteError)
 	}
 
 	return nil
@@ -306,17 +577,10 @@ func (o OrderRepository) RemoveTaskFromOrder(orderID uuid.UUID, taskID uuid.UUID
 
 func (o OrderRepository) UpdateTaskQuantity(orderID uuid.UUID, taskID uuid.UUID, quantity int) error {
 	var m2mCollection = o.db.Collection("order_contains_tasks")
-	var ordersCollection = o.db.Collection("orders")
-
-	var order OrderDB
-	err := ordersCollection.FindOne(context.Background(), map[string]interface{}{"_id": orderID}).Decode(&order)
-	if err != nil {
-		return repository_errors.DoesNotExist
-	}
 
-	_, err = m2mCollection.UpdateOne(context.Background(), bson.M{"order_id": orderID, "task_id": taskID}, bson.M{"$set": bson.M{"quantity": quantity}})
+	_, err := m2mCollection.UpdateMany(context.Background(), bson.M{"order_id": orderID, "task_id": taskID}, bson.M{"$set": bson.M{"quantity": quantity}})
 	if err != nil {
-		return repository_errors.UpdateError
+		return classify(err, repository_errors.UpdateError)
 	}
 
 	return nil
@@ -325,14 +589,18 @@ func (o OrderRepository) UpdateTaskQuantity(orderID uuid.UUID, taskID uuid.UUID,
 func (o OrderRepository) GetTaskQuantity(orderID uuid.UUID, taskID uuid.UUID) (int, error) {
 	var m2mCollection = o.db.Collection("order_contains_tasks")
 
-	var orderedTask models.OrderedTask
-	err := m2mCollection.FindOne(context.Background(), bson.M{"order_id": orderID, "task_id": taskID}).Decode(&orderedTask)
-	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return 0, repository_errors.DoesNotExist
-		}
-		return 0, repository_errors.SelectError
+	var line OrderedTaskDB
+	filter := bson.M{"order_id": orderID, "task_id": taskID}
+	err := m2mCollection.FindOne(context.Background(), filter).Decode(&line)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// an archived order has its lines in the archive
+		err = o.db.Collection("order_contains_tasks_archive").FindOne(context.Background(), filter).Decode(&line)
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, repository_errors.DoesNotExist
+	} else if err != nil {
+		return 0, classify(err, repository_errors.SelectError)
 	}
 
-	return orderedTask.Quantity, nil
+	return line.Quantity, nil
 }
@@ -0,0 +1,148 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SessionDB struct {
+	ID           string    `bson:"_id"`
+	UserID       uuid.UUID `bson:"user_id"`
+	WorkerID     uuid.UUID `bson:"worker_id"`
+	Data         string    `bson:"data"`
+	CreatedAt    time.Time `bson:"created_at"`
+	LastActivity time.Time `bson:"last_activity"`
+}
+
+type SessionRepository struct {
+	db *mongo.Database
+}
+
+func NewSessionRepository(db *mongo.Database) repository_interfaces.ISessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func copySessionResultToModel(sessionDB *SessionDB) *models.Session {
+	return &models.Session{
+		ID:           sessionDB.ID,
+		UserID:       sessionDB.UserID,
+		WorkerID:     sessionDB.WorkerID,
+		Data:         sessionDB.Data,
+		CreatedAt:    sessionDB.CreatedAt,
+		LastActivity: sessionDB.LastActivity,
+	}
+}
+
+func (s SessionRepository) Save(session *models.Session) error {
+	var collection = s.db.Collection("sessions")
+	if session.ID == "" {
+		return repository_errors.InsertError
+	}
+
+	filter := bson.M{"_id": session.ID}
+	update := bson.M{
+		"$set": bson.M{
+			"user_id":       session.UserID,
+			"worker_id":     session.WorkerID,
+			"data":          session.Data,
+			"last_activity": session.LastActivity,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": session.CreatedAt,
+		},
+	}
+
+	_, err := collection.UpdateOne(context.Background(), filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return classify(err, repository_errors.InsertError)
+	}
+
+	return nil
+}
+
+func (s SessionRepository) GetSessionByID(id string) (*models.Session, error) {
+	var collection = s.db.Collection("sessions")
+
+	var session SessionDB
+	err := collection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&session)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	return copySessionResultToModel(&session), nil
+}
+
+func (s SessionRepository) UpdateLastActivity(id string, lastActivity time.Time) error {
+	var collection = s.db.Collection("sessions")
+
+	result, err := collection.UpdateOne(context.Background(), bson.M{"_id": id}, bson.M{"$set": bson.M{"last_activity": lastActivity}})
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (s SessionRepository) Delete(id string) error {
+	var collection = s.db.Collection("sessions")
+
+	_, err := collection.DeleteOne(context.Background(), bson.M{"_id": id})
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	return nil
+}
+
+func (s SessionRepository) DeleteByUserID(userID uuid.UUID) error {
+	var collection = s.db.Collection("sessions")
+
+	_, err := collection.DeleteMany(context.Background(), bson.M{"user_id": userID})
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	return nil
+}
+
+func (s SessionRepository) DeleteByWorkerID(workerID uuid.UUID) error {
+	var collection = s.db.Collection("sessions")
+
+	_, err := collection.DeleteMany(context.Background(), bson.M{"worker_id": workerID})
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	return nil
+}
+
+func (s SessionRepository) DeleteExpired(idleBefore time.Time, createdBefore time.Time) error {
+	var collection = s.db.Collection("sessions")
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"last_activity": bson.M{"$lt": idleBefore}},
+		bson.M{"created_at": bson.M{"$lt": createdBefore}},
+	}}
+
+	_, err := collection.DeleteMany(context.Background(), filter)
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	return nil
+}
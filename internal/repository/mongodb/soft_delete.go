@@ -0,0 +1,56 @@
+package mongodb
+
+import (
+	"context"
+	"lab3/internal/repository/repository_errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Archived documents have deleted_at set. A nil value matches both a missing and a null field.
+var (
+	notDeletedFilter = bson.M{"deleted_at": nil}
+	deletedFilter    = bson.M{"deleted_at": bson.M{"$ne": nil}}
+)
+
+// deletedOptions lists the most recently archived documents first.
+func deletedOptions() *options.FindOptions {
+	return options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}})
+}
+
+// softDelete archives the document, unless it is archived already.
+func softDelete(collection *mongo.Collection, id interface{}) error {
+	filter := bson.M{"_id": id, "deleted_at": nil}
+	update := bson.M{"$set": bson.M{"deleted_at": time.Now()}}
+
+	result, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+// restore returns an archived document to the normal reads.
+func restore(collection *mongo.Collection, id interface{}) error {
+	filter := bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}}
+	update := bson.M{"$unset": bson.M{"deleted_at": ""}}
+
+	result, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
@@ -5,10 +5,14 @@ import (
 	"lab3/internal/models"
 	"lab3/internal/repository/repository_errors"
 	"lab3/internal/repository/repository_interfaces"
+	"lab3/internal/search"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"errors"
 )
@@ -18,6 +22,8 @@ type TaskDB struct {
 	Name           string    `bson:"name"`
 	PricePerSingle float64   `bson:"price_per_single"`
 	Category       int       `bson:"category"`
+	TaxRate        string    `bson:"tax_rate"`
+	DeletedAt      time.Time `bson:"deleted_at,omitempty"`
 }
 
 type TaskRepository struct {
@@ -34,11 +40,21 @@ func copyTaskResultToModel(taskDB *TaskDB) *models.Task {
 		Name:           taskDB.Name,
 		PricePerSingle: taskDB.PricePerSingle,
 		Category:       taskDB.Category,
+		TaxRate:        taskDB.TaxRate,
+		DeletedAt:      taskDB.DeletedAt,
 	}
 }
 
 func (t TaskRepository) Create(task *models.Task) (*models.Task, error) {
+	if task.Name == "" || task.PricePerSingle == 0 {
+		return nil, repository_errors.InsertError
+	}
+
 	ctx := context.Background()
+	if err := t.checkCategory(ctx, task.Category, repository_errors.InsertError); err != nil {
+		return nil, err
+	}
+
 	var collection = t.db.Collection("tasks")
 	if task.ID == uuid.Nil {
 		task.ID = uuid.New()
@@ -48,10 +64,11 @@ func (t TaskRepository) Create(task *models.Task) (*models.Task, error) {
 		Name:           task.Name,
 		PricePerSingle: task.PricePerSingle,
 		Category:       task.Category,
+		TaxRate:        task.TaxRate,
 	})
 
 	if err != nil {
-		return nil, repository_errors.InsertError
+		return nil, classify(err, repository_errors.InsertError)
 	}
 
 	return &models.Task{
@@ -59,27 +76,28 @@ func (t TaskRepository) Create(task *models.Task) (*models.Task, error) {
 		Name:           task.Name,
 		PricePerSingle: task.PricePerSingle,
 		Category:       task.Category,
+		TaxRate:        task.TaxRate,
 	}, nil
 }
 
 func (t TaskRepository) Delete(id uuid.UUID) error {
-	var collection = t.db.Collection("tasks")
-	var filter = bson.M{"_id": id}
+	return softDelete(t.db.Collection("tasks"), id)
+}
 
-	result, err := collection.DeleteOne(context.Background(), filter)
+func (t TaskRepository) Restore(id uuid.UUID) error {
+	return restore(t.db.Collection("tasks"), id)
+}
 
-	if err != nil {
-		return repository_errors.DeleteError
+func (t TaskRepository) Update(task *models.Task) (*models.Task, error) {
+	if task.Name == "" || task.PricePerSingle == 0 {
+		return nil, repository_errors.InsertError
 	}
 
-	if result.DeletedCount == 0 {
-		return errors.New("no task found to delete")
+	ctx := context.Background()
+	if err := t.checkCategory(ctx, task.Category, repository_errors.UpdateError); err != nil {
+		return nil, err
 	}
 
-	return nil
-}
-
-func (t TaskRepository) Update(task *models.Task) (*models.Task, error) {
 	var collection = t.db.Collection("tasks")
 	var filter = bson.M{"_id": task.ID}
 	update := bson.M{
@@ -87,11 +105,12 @@ func (t TaskRepository) Update(task *models.Task) (*models.Task, error) {
 			"name":             task.Name,
 			"price_per_single": task.PricePerSingle,
 			"category":         task.Category,
+			"tax_rate":         task.TaxRate,
 		},
 	}
-	_, err := collection.UpdateOne(context.Background(), filter, update)
-	if err != nil {
-		return nil, repository_errors.UpdateError
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil || result.MatchedCount == 0 {
+		return nil, classify(err, repository_errors.UpdateError)
 	}
 
 	return task, nil
@@ -108,7 +127,7 @@ func (t TaskRepository) GetTaskByID(id uuid.UUID) (*models.Task, error) {
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, repository_errors.DoesNotExist
 	} else if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	taskModels := copyTaskResultToModel(&task)
@@ -118,7 +137,7 @@ func (t TaskRepository) GetTaskByID(id uuid.UUID) (*models.Task, error) {
 
 func (t TaskRepository) GetTaskByName(name string) (*models.Task, error) {
 	var collection = t.db.Collection("tasks")
-	var filter = bson.M{"name": name}
+	var filter = bson.M{"name": name, "deleted_at": nil}
 
 	var task TaskDB
 	err := collection.FindOne(context.Background(), filter).Decode(&task)
@@ -126,64 +145,225 @@ func (t TaskRepository) GetTaskByName(name string) (*models.Task, error) {
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, repository_errors.DoesNotExist
 	} else if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	return copyTaskResultToModel(&task), nil
 }
 
 func (t TaskRepository) GetAllTasks() ([]models.Task, error) {
+	return t.find(notDeletedFilter, nil)
+}
+
+func (t TaskRepository) GetTasksInCategory(category int) ([]models.Task, error) {
+	return t.find(bson.M{"category": category, "deleted_at": nil}, nil)
+}
+
+func (t TaskRepository) MoveToCategory(from, to int) (int, error) {
+	ctx := context.Background()
+	if err := t.checkCategory(ctx, to, repository_errors.UpdateError); err != nil {
+		return 0, err
+	}
+
 	var collection = t.db.Collection("tasks")
+	result, err := collection.UpdateMany(ctx, bson.M{"category": from}, bson.M{"$set": bson.M{"category": to}})
+	if err != nil {
+		return 0, classify(err, repository_errors.UpdateError)
+	}
 
-	filter := bson.M{}
+	return int(result.ModifiedCount), nil
+}
 
-	cur, err := collection.Find(context.Background(), filter)
+// checkCategory returns kind when the category does not exist, the counterpart of the foreign key of
+// the tasks in Postgres. Categories are only archived, so a category found here can not disappear.
+func (t TaskRepository) checkCategory(ctx context.Context, category int, kind error) error {
+	count, err := t.db.Collection("categories").CountDocuments(ctx, bson.M{"_id": category})
 	if err != nil {
-		return nil, err
+		return classify(err, repository_errors.SelectError)
+	}
+	if count == 0 {
+		return kind
 	}
-	defer cur.Close(context.Background())
 
-	var tasks []models.Task
-	for cur.Next(context.Background()) {
-		var task TaskDB
-		err := cur.Decode(&task)
-		if err != nil {
-			return nil, err
-		}
-		tasks = append(tasks, *copyTaskResultToModel(&task))
+	return nil
+}
+
+func (t TaskRepository) GetDeletedTasks() ([]models.Task, error) {
+	return t.find(deletedFilter, deletedOptions())
+}
+
+// find returns the tasks matching the filter in the given order.
+func (t TaskRepository) find(filter bson.M, opts *options.FindOptions) ([]models.Task, error) {
+	var collection = t.db.Collection("tasks")
+	ctx := context.Background()
+
+	cur, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
-	if err := cur.Err(); err != nil {
-		return nil, err
+	var tasksDB []TaskDB
+	err = cur.All(ctx, &tasksDB)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var tasks []models.Task
+	for i := range tasksDB {
+		tasks = append(tasks, *copyTaskResultToModel(&tasksDB[i]))
 	}
 
 	return tasks, nil
 }
 
-func (t TaskRepository) GetTasksInCategory(category int) ([]models.Task, error) {
-	var collection = t.db.Collection("tasks")
+// Search finds the candidates with the text index, which matches any of the words, and keeps
+// the tasks that have all of them.
+func (t TaskRepository) Search(query string) ([]models.Task, error) {
+	words := search.Words(query)
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	filter := bson.M{
+		"$text":      bson.M{"$search": strings.Join(words, " "), "$language": "russian"},
+		"deleted_at": nil,
+	}
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "name", Value: 1}})
 
-	filter := bson.M{"category": category}
-	cur, err := collection.Find(context.Background(), filter)
+	candidates, err := t.find(filter, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	defer cur.Close(context.Background())
-
 	var tasks []models.Task
-	for cur.Next(context.Background()) {
-		var task TaskDB
-		err := cur.Decode(&task)
-		if err != nil {
-			return nil, err
+	for _, task := range candidates {
+		if _, ok := search.Rank(task.Name, words); ok {
+			tasks = append(tasks, task)
 		}
-		tasks = append(tasks, *copyTaskResultToModel(&task))
 	}
 
-	if err := cur.Err(); err != nil {
-		return nil, err
+	return tasks, nil
+}
+
+type TaskPriceDB struct {
+	ID            uuid.UUID `bson:"_id"`
+	TaskID        uuid.UUID `bson:"task_id"`
+	Price         float64   `bson:"price"`
+	EffectiveFrom time.Time `bson:"effective_from"`
+}
+
+// SchedulePrice checks the task first, the counterpart of the foreign key of the prices in Postgres.
+// Tasks are only archived, so a task found here can not disappear.
+func (t TaskRepository) SchedulePrice(price *models.TaskPrice) (*models.TaskPrice, error) {
+	if price.Price == 0 {
+		return nil, repository_errors.InsertError
 	}
 
-	return tasks, nil
+	ctx := context.Background()
+	count, err := t.db.Collection("tasks").CountDocuments(ctx, bson.M{"_id": price.TaskID})
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+	if count == 0 {
+		return nil, repository_errors.InsertError
+	}
+
+	filter := bson.M{"task_id": price.TaskID, "effective_from": price.EffectiveFrom}
+	update := bson.M{
+		"$set":         bson.M{"price": price.Price},
+		"$setOnInsert": bson.M{"_id": uuid.New()},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var priceDB TaskPriceDB
+	err = t.db.Collection("task_prices").FindOneAndUpdate(ctx, filter, update, opts).Decode(&priceDB)
+	if err != nil {
+		return nil, classify(err, repository_errors.InsertError)
+	}
+
+	scheduled := *price
+	scheduled.ID = priceDB.ID
+	return &scheduled, nil
+}
+
+func (t TaskRepository) DeletePrice(id uuid.UUID) error {
+	result, err := t.db.Collection("task_prices").DeleteOne(context.Background(), bson.M{"_id": id})
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+	if result.DeletedCount == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (t TaskRepository) GetPrices(taskID uuid.UUID) ([]models.TaskPrice, error) {
+	return t.findPrices(bson.M{"task_id": taskID})
+}
+
+func (t TaskRepository) GetUpcomingPrices(moment time.Time) ([]models.TaskPrice, error) {
+	return t.findPrices(bson.M{"effective_from": bson.M{"$gt": moment}})
+}
+
+// findPrices returns the prices matching the filter, the earliest first.
+func (t TaskRepository) findPrices(filter bson.M) ([]models.TaskPrice, error) {
+	ctx := context.Background()
+	opts := options.Find().SetSort(bson.D{{Key: "effective_from", Value: 1}})
+
+	cur, err := t.db.Collection("task_prices").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var pricesDB []TaskPriceDB
+	err = cur.All(ctx, &pricesDB)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var prices []models.TaskPrice
+	for _, price := range pricesDB {
+		prices = append(prices, models.TaskPrice(price))
+	}
+
+	return prices, nil
+}
+
+func (t TaskRepository) ApplyPrices(moment time.Time) (int, error) {
+	ctx := context.Background()
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"effective_from": bson.M{"$lte": moment}}},
+		bson.M{"$sort": bson.D{{Key: "task_id", Value: 1}, {Key: "effective_from", Value: -1}}},
+		bson.M{"$group": bson.M{"_id": "$task_id", "price": bson.M{"$first": "$price"}}},
+	}
+
+	cur, err := t.db.Collection("task_prices").Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, classify(err, repository_errors.SelectError)
+	}
+
+	var current []struct {
+		TaskID uuid.UUID `bson:"_id"`
+		Price  float64   `bson:"price"`
+	}
+	err = cur.All(ctx, &current)
+	if err != nil {
+		return 0, classify(err, repository_errors.SelectError)
+	}
+
+	changed := 0
+	for _, price := range current {
+		filter := bson.M{"_id": price.TaskID, "price_per_single": bson.M{"$ne": price.Price}}
+		result, err := t.db.Collection("tasks").UpdateOne(ctx, filter, bson.M{"$set": bson.M{"price_per_single": price.Price}})
+		if err != nil {
+			return changed, classify(err, repository_errors.UpdateError)
+		}
+		changed += int(result.ModifiedCount)
+	}
+
+	return changed, nil
 }
@@ -0,0 +1,35 @@
+package mongodb
+
+import (
+	"context"
+	"lab3/internal/repository/repository_errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// withTransaction runs fn in a multi-document transaction. Transactions need a replica set,
+// a single-node one is enough. The error returned by fn is passed through, so fn maps driver
+// errors to repository_errors itself.
+func withTransaction(db *mongo.Database, fn func(ctx mongo.SessionContext) error) error {
+	ctx := context.Background()
+
+	session, err := db.Client().StartSession()
+	if err != nil {
+		return classify(err, repository_errors.TransactionBeginError)
+	}
+	defer session.EndSession(ctx)
+
+	var fnErr error
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		fnErr = fn(sessionCtx)
+		return nil, fnErr
+	})
+
+	if fnErr != nil {
+		return fnErr
+	} else if err != nil {
+		return classify(err, repository_errors.TransactionCommitError)
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TwoFactorDB struct {
+	WorkerID      uuid.UUID `bson:"_id"`
+	Secret        string    `bson:"secret"`
+	Enabled       bool      `bson:"enabled"`
+	LastUsedStep  int64     `bson:"last_used_step"`
+	CreatedAt     time.Time `bson:"created_at"`
+	RecoveryCodes []string  `bson:"recovery_codes"`
+}
+
+type TwoFactorRepository struct {
+	db *mongo.Database
+}
+
+func NewTwoFactorRepository(db *mongo.Database) repository_interfaces.ITwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+func copyTwoFactorResultToModel(twoFactorDB *TwoFactorDB) *models.TwoFactor {
+	return &models.TwoFactor{
+		WorkerID:     twoFactorDB.WorkerID,
+		Secret:       twoFactorDB.Secret,
+		Enabled:      twoFactorDB.Enabled,
+		LastUsedStep: twoFactorDB.LastUsedStep,
+		CreatedAt:    twoFactorDB.CreatedAt,
+	}
+}
+
+func (t TwoFactorRepository) GetByWorkerID(workerID uuid.UUID) (*models.TwoFactor, error) {
+	var collection = t.db.Collection("worker_two_factor")
+
+	var twoFactor TwoFactorDB
+	err := collection.FindOne(context.Background(), bson.M{"_id": workerID}).Decode(&twoFactor)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	return copyTwoFactorResultToModel(&twoFactor), nil
+}
+
+func (t TwoFactorRepository) Save(twoFactor *models.TwoFactor) error {
+	var collection = t.db.Collection("worker_two_factor")
+
+	update := bson.M{
+		"$set": bson.M{
+			"secret":         twoFactor.Secret,
+			"enabled":        twoFactor.Enabled,
+			"last_used_step": twoFactor.LastUsedStep,
+		},
+		"$setOnInsert": bson.M{
+			"created_at":     twoFactor.CreatedAt,
+			"recovery_codes": bson.A{},
+		},
+	}
+
+	_, err := collection.UpdateOne(context.Background(), bson.M{"_id": twoFactor.WorkerID}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return classify(err, repository_errors.InsertError)
+	}
+
+	return nil
+}
+
+func (t TwoFactorRepository) Delete(workerID uuid.UUID) error {
+	var collection = t.db.Collection("worker_two_factor")
+
+	_, err := collection.DeleteOne(context.Background(), bson.M{"_id": workerID})
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	return nil
+}
+
+func (t TwoFactorRepository) MarkStepUsed(workerID uuid.UUID, step int64) error {
+	var collection = t.db.Collection("worker_two_factor")
+
+	filter := bson.M{"_id": workerID, "last_used_step": bson.M{"$lt": step}}
+	result, err := collection.UpdateOne(context.Background(), filter, bson.M{"$set": bson.M{"last_used_step": step}})
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (t TwoFactorRepository) ReplaceRecoveryCodes(workerID uuid.UUID, hashes []string) error {
+	var collection = t.db.Collection("worker_two_factor")
+
+	result, err := collection.UpdateOne(context.Background(), bson.M{"_id": workerID}, bson.M{"$set": bson.M{"recovery_codes": hashes}})
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (t TwoFactorRepository) ConsumeRecoveryCode(workerID uuid.UUID, hash string) error {
+	var collection = t.db.Collection("worker_two_factor")
+
+	filter := bson.M{"_id": workerID, "recovery_codes": hash}
+	result, err := collection.UpdateOne(context.Background(), filter, bson.M{"$pull": bson.M{"recovery_codes": hash}})
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	if result.MatchedCount == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (t TwoFactorRepository) CountRecoveryCodes(workerID uuid.UUID) (int, error) {
+	var collection = t.db.Collection("worker_two_factor")
+
+	var twoFactor TwoFactorDB
+	err := collection.FindOne(context.Background(), bson.M{"_id": workerID}).Decode(&twoFactor)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	} else if err != nil {
+		return 0, classify(err, repository_errors.SelectError)
+	}
+
+	return len(twoFactor.RecoveryCodes), nil
+}
@@ -5,22 +5,26 @@ import (
 	"lab3/internal/models"
 	"lab3/internal/repository/repository_errors"
 	"lab3/internal/repository/repository_interfaces"
+	"time"
 
 	"errors"
 
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type UserDB struct {
-	ID          uuid.UUID `bson:"_id"`
-	Name        string    `bson:"name"`
-	Surname     string    `bson:"surname"`
-	Address     string    `bson:"address"`
-	PhoneNumber string    `bson:"phone_number"`
-	Email       string    `bson:"email"`
-	Password    string    `bson:"password"`
+	ID            uuid.UUID `bson:"_id"`
+	Name          string    `bson:"name"`
+	Surname       string    `bson:"surname"`
+	Address       string    `bson:"address"`
+	PhoneNumber   string    `bson:"phone_number"`
+	Email         string    `bson:"email"`
+	Password      string    `bson:"password"`
+	EmailVerified bool      `bson:"email_verified"`
+	DeletedAt     time.Time `bson:"deleted_at,omitempty"`
 }
 
 type UserRepository struct {
@@ -33,17 +37,23 @@ func NewUserRepository(db *mongo.Database) repository_interfaces.IUserRepository
 
 func copyUserResultToModel(userDB *UserDB) *models.User {
 	return &models.User{
-		ID:          userDB.ID,
-		Name:        userDB.Name,
-		Surname:     userDB.Surname,
-		Address:     userDB.Address,
-		PhoneNumber: userDB.PhoneNumber,
-		Email:       userDB.Email,
-		Password:    userDB.Password,
+		ID:            userDB.ID,
+		Name:          userDB.Name,
+		Surname:       userDB.Surname,
+		Address:       userDB.Address,
+		PhoneNumber:   userDB.PhoneNumber,
+		Email:         userDB.Email,
+		Password:      userDB.Password,
+		EmailVerified: userDB.EmailVerified,
+		DeletedAt:     userDB.DeletedAt,
 	}
 }
 
 func (u UserRepository) Create(user *models.User) (*models.User, error) {
+	if user.Name == "" || user.Surname == "" || user.Email == "" || user.Password == "" {
+		return nil, repository_errors.InsertError
+	}
+
 	var collection = u.db.Collection("users")
 	ctx := context.Background()
 	if user.ID == uuid.Nil {
@@ -51,75 +61,76 @@ func (u UserRepository) Create(user *models.User) (*models.User, error) {
 	}
 
 	_, err := collection.InsertOne(ctx, UserDB{
-		ID:          user.ID,
-		Name:        user.Name,
-		Surname:     user.Surname,
-		Address:     user.Address,
-		PhoneNumber: user.PhoneNumber,
-		Email:       user.Email,
-		Password:    user.Password,
+		ID:            user.ID,
+		Name:          user.Name,
+		Surname:       user.Surname,
+		Address:       user.Address,
+		PhoneNumber:   user.PhoneNumber,
+		Email:         user.Email,
+		Password:      user.Password,
+		EmailVerified: user.EmailVerified,
 	})
 
 	if err != nil {
-		return nil, repository_errors.InsertError
+		return nil, classify(err, repository_errors.InsertError)
 	}
 
 	return &models.User{
-		ID:          user.ID,
-		Name:        user.Name,
-		Surname:     user.Surname,
-		Address:     user.Address,
-		PhoneNumber: user.PhoneNumber,
-		Email:       user.Email,
-		Password:    user.Password,
+		ID:            user.ID,
+		Name:          user.Name,
+		Surname:       user.Surname,
+		Address:       user.Address,
+		PhoneNumber:   user.PhoneNumber,
+		Email:         user.Email,
+		Password:      user.Password,
+		EmailVerified: user.EmailVerified,
 	}, nil
 }
 
+// Delete archives the user. Their orders are kept, so that workers still see the history.
 func (u UserRepository) Delete(id uuid.UUID) error {
-	var usersCollection = u.db.Collection("users")
-	var ordersCollection = u.db.Collection("orders")
-	ctx := context.Background()
-	_, err := ordersCollection.DeleteMany(ctx, bson.M{"user_id": id})
-	if err != nil {
-		return err
-	}
-
-	_, err = usersCollection.DeleteOne(ctx, bson.M{"_id": id})
-	if err != nil {
-		return err
-	}
+	return softDelete(u.db.Collection("users"), id)
+}
 
-	return nil
+func (u UserRepository) Restore(id uuid.UUID) error {
+	return restore(u.db.Collection("users"), id)
 }
+
 func (u UserRepository) Update(user *models.User) (*models.User, error) {
+	if user.Name == "" || user.Surname == "" || user.Email == "" || user.Password == "" {
+		return nil, repository_errors.UpdateError
+	}
+
 	var collection = u.db.Collection("users")
 	ctx := context.Background()
 
 	filter := bson.M{"_id": user.ID}
 	update := bson.M{
 		"$set": bson.M{
-			"name":         user.Name,
-			"surname":      user.Surname,
-			"address":      user.Address,
-			"phone_number": user.PhoneNumber,
-			"email":        user.Email,
-			"password":     user.Password,
+			"name":           user.Name,
+			"surname":        user.Surname,
+			"address":        user.Address,
+			"phone_number":   user.PhoneNumber,
+			"email":          user.Email,
+			"password":       user.Password,
+			"email_verified": user.EmailVerified,
 		},
 	}
 
-	_, err := collection.UpdateOne(ctx, filter, update)
-	if err != nil {
-		return nil, repository_errors.UpdateError
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil || result.MatchedCount == 0 {
+		return nil, classify(err, repository_errors.UpdateError)
 	}
 
 	return &models.User{
-		ID:          user.ID,
-		Name:        user.Name,
-		Surname:     user.Surname,
-		Address:     user.Address,
-		PhoneNumber: user.PhoneNumber,
-		Email:       user.Email,
-		Password:    user.Password,
+		ID:            user.ID,
+		Name:          user.Name,
+		Surname:       user.Surname,
+		Address:       user.Address,
+		PhoneNumber:   user.PhoneNumber,
+		Email:         user.Email,
+		Password:      user.Password,
+		EmailVerified: user.EmailVerified,
 	}, nil
 }
 
@@ -134,74 +145,56 @@ func (u UserRepository) GetUserByID(id uuid.UUID) (*models.User, error) {
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, repository_errors.DoesNotExist
 	} else if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
-	return &models.User{
-		ID:          user.ID,
-		Name:        user.Name,
-		Surname:     user.Surname,
-		Address:     user.Address,
-		PhoneNumber: user.PhoneNumber,
-		Email:       user.Email,
-		Password:    user.Password,
-	}, nil
+	return copyUserResultToModel(&user), nil
 }
 
 func (u UserRepository) GetUserByEmail(email string) (*models.User, error) {
 	var collection = u.db.Collection("users")
 	ctx := context.Background()
 
-	filter := bson.M{"email": email}
+	filter := bson.M{"email": email, "deleted_at": nil}
 	var user UserDB
 	err := collection.FindOne(ctx, filter).Decode(&user)
 
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, repository_errors.DoesNotExist
 	} else if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
-	return &models.User{
-		ID:          user.ID,
-		Name:        user.Name,
-		Surname:     user.Surname,
-		Address:     user.Address,
-		PhoneNumber: user.PhoneNumber,
-		Email:       user.Email,
-		Password:    user.Password,
-	}, nil
+	return copyUserResultToModel(&user), nil
 }
 
 func (u UserRepository) GetAllUsers() ([]models.User, error) {
+	return u.find(notDeletedFilter, nil)
+}
+
+func (u UserRepository) GetDeletedUsers() ([]models.User, error) {
+	return u.find(deletedFilter, deletedOptions())
+}
+
+// find returns the users matching the filter in the given order.
+func (u UserRepository) find(filter bson.M, opts *options.FindOptions) ([]models.User, error) {
 	var usersCollection = u.db.Collection("users")
 	ctx := context.Background()
 
-	cur, err := usersCollection.Find(ctx, bson.M{})
+	cur, err := usersCollection.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, err
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
-	var userModels []models.User
-	for cur.Next(context.Background()) {
-		var user UserDB
-		err := cur.Decode(&user)
-		if err != nil {
-			return nil, err
-		}
-		userModels = append(userModels, models.User{
-			ID:          user.ID,
-			Name:        user.Name,
-			Surname:     user.Surname,
-			Address:     user.Address,
-			PhoneNumber: user.PhoneNumber,
-			Email:       user.Email,
-			Password:    user.Password,
-		})
+	var usersDB []UserDB
+	err = cur.All(ctx, &usersDB)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
-	if err := cur.Err(); err != nil {
-		return nil, err
+	var userModels []models.User
+	for i := range usersDB {
+		userModels = append(userModels, *copyUserResultToModel(&usersDB[i]))
 	}
 
 	return userModels, nil
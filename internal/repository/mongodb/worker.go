@@ -6,9 +6,11 @@ import (
 	"lab3/internal/models"
 	"lab3/internal/repository/repository_errors"
 	"lab3/internal/repository/repository_interfaces"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/google/uuid"
 )
@@ -22,6 +24,7 @@ type WorkerDB struct {
 	Email       string    `bson:"email"`
 	Role        int       `bson:"role"`
 	Password    string    `bson:"password"`
+	DeletedAt   time.Time `bson:"deleted_at,omitempty"`
 }
 
 type WorkerRepository struct {
@@ -42,10 +45,15 @@ func copyWorkerResultToModel(workerDB *WorkerDB) *models.Worker {
 		Email:       workerDB.Email,
 		Role:        workerDB.Role,
 		Password:    workerDB.Password,
+		DeletedAt:   workerDB.DeletedAt,
 	}
 }
 
 func (w WorkerRepository) Create(worker *models.Worker) (*models.Worker, error) {
+	if worker.Name == "" || worker.Surname == "" || worker.Email == "" || worker.Password == "" {
+		return nil, repository_errors.UpdateError
+	}
+
 	var collection = w.db.Collection("workers")
 	if worker.ID == uuid.Nil {
 		worker.ID = uuid.New()
@@ -63,7 +71,7 @@ func (w WorkerRepository) Create(worker *models.Worker) (*models.Worker, error)
 	})
 
 	if err != nil {
-		return nil, repository_errors.InsertError
+		return nil, classify(err, repository_errors.InsertError)
 	}
 
 	return &models.Worker{
@@ -79,8 +87,12 @@ func (w WorkerRepository) Create(worker *models.Worker) (*models.Worker, error)
 }
 
 func (w WorkerRepository) Update(worker *models.Worker) (*models.Worker, error) {
+	if worker.Name == "" || worker.Surname == "" || worker.Email == "" || worker.Password == "" {
+		return nil, repository_errors.UpdateError
+	}
+
 	var collection = w.db.Collection("workers")
-	var filter = bson.M{"id": worker.ID}
+	var filter = bson.M{"_id": worker.ID}
 	var update = bson.M{"$set": bson.M{
 		"name":         worker.Name,
 		"surname":      worker.Surname,
@@ -91,24 +103,20 @@ func (w WorkerRepository) Update(worker *models.Worker) (*models.Worker, error)
 		"password":     worker.Password,
 	}}
 
-	_, err := collection.UpdateOne(context.Background(), filter, update)
-	if err != nil {
-		return nil, repository_errors.UpdateError
+	result, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil || result.MatchedCount == 0 {
+		return nil, classify(err, repository_errors.UpdateError)
 	}
 
 	return worker, nil
 }
 
 func (w WorkerRepository) Delete(id uuid.UUID) error {
-	var collection = w.db.Collection("workers")
-	var filter = bson.M{"id": id}
-	_, err := collection.DeleteOne(context.Background(), filter)
-
-	if err != nil {
-		return repository_errors.DeleteError
-	}
+	return softDelete(w.db.Collection("workers"), id)
+}
 
-	return nil
+func (w WorkerRepository) Restore(id uuid.UUID) error {
+	return restore(w.db.Collection("workers"), id)
 }
 
 func (w WorkerRepository) GetWorkerByID(id uuid.UUID) (*models.Worker, error) {
@@ -117,40 +125,38 @@ func (w WorkerRepository) GetWorkerByID(id uuid.UUID) (*models.Worker, error) {
 	var worker WorkerDB
 
 	err := collection.FindOne(context.Background(), filter).Decode(&worker)
-	if err != nil {
+	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	return copyWorkerResultToModel(&worker), nil
 }
 
 func (w WorkerRepository) GetAllWorkers() ([]models.Worker, error) {
+	return w.find(notDeletedFilter, nil)
+}
+
+// find returns the workers matching the filter in the given order.
+func (w WorkerRepository) find(filter bson.M, opts *options.FindOptions) ([]models.Worker, error) {
 	var collection = w.db.Collection("workers")
-	cur, err := collection.Find(context.Background(), bson.M{})
+	ctx := context.Background()
+
+	cur, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var workersDB []WorkerDB
+	err = cur.All(ctx, &workersDB)
 	if err != nil {
-		return nil, err
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	var workerModels []models.Worker
-	for cur.Next(context.Background()) {
-		var worker WorkerDB
-		err := cur.Decode(&worker)
-		if err != nil {
-			return nil, err
-		}
-		workerModels = append(workerModels, models.Worker{
-			ID:          worker.ID,
-			Name:        worker.Name,
-			Surname:     worker.Surname,
-			Address:     worker.Address,
-			PhoneNumber: worker.PhoneNumber,
-			Email:       worker.Email,
-			Password:    worker.Password,
-		})
-	}
-
-	if err := cur.Err(); err != nil {
-		return nil, err
+	for i := range workersDB {
+		workerModels = append(workerModels, *copyWorkerResultToModel(&workersDB[i]))
 	}
 
 	return workerModels, nil
@@ -158,70 +164,53 @@ func (w WorkerRepository) GetAllWorkers() ([]models.Worker, error) {
 
 func (w WorkerRepository) GetWorkerByEmail(email string) (*models.Worker, error) {
 	var collection = w.db.Collection("workers")
-	var filter = bson.M{"email": email}
+	var filter = bson.M{"email": email, "deleted_at": nil}
 	var worker WorkerDB
 
 	err := collection.FindOne(context.Background(), filter).Decode(&worker)
-	if err != nil {
+	if errors.Is(err, mongo.ErrNoDocuments) {
 		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	return copyWorkerResultToModel(&worker), nil
 }
 
-func (w WorkerRepository) GetWorkersByRole(role int) ([]models.Worker, error) {
-	var collection = w.db.Collection("workers")
-	var filter = bson.M{"role": role}
-	cur, err := collection.Find(context.Background(), filter)
-	if err != nil {
-		return nil, err
-	}
-
-	var workerModels []models.Worker
-	for cur.Next(context.Background()) {
-		var worker WorkerDB
-		err := cur.Decode(&worker)
-		if err != nil {
-			return nil, err
-		}
-		workerModels = append(workerModels, models.Worker{
-			ID:          worker.ID,
-			Name:        worker.Name,
-			Surname:     worker.Surname,
-			Address:     worker.Address,
-			PhoneNumber: worker.PhoneNumber,
-			Email:       worker.Email,
-			Password:    worker.Password,
-		})
-	}
-	return workerModels, nil
+func (w WorkerRepository) GetDeletedWorkers() ([]models.Worker, error) {
+	return w.find(deletedFilter, deletedOptions())
+}
 
+func (w WorkerRepository) GetWorkersByRole(role int) ([]models.Worker, error) {
+	return w.find(bson.M{"role": role, "deleted_at": nil}, nil)
 }
 
 func (w WorkerRepository) GetAverageOrderRate(worker *models.Worker) (float64, error) {
 	var ordersCollection = w.db.Collection("orders")
 	ctx := context.Background()
 
+	// archived orders keep counting
 	pipeline := mongo.Pipeline{
-		{{"$match", bson.D{
-			{"worker_id", worker.ID},
-			{"status", 3},
-			{"rate", bson.M{"$ne": 0}},
+		{{Key: "$unionWith", Value: bson.D{{Key: "coll", Value: "orders_archive"}}}},
+		{{Key: "$match", Value: bson.D{
+			{Key: "worker_id", Value: worker.ID},
+			{Key: "status", Value: 3},
+			{Key: "rate", Value: bson.M{"$ne": 0}},
 		}}},
-		{{"$group", bson.D{
-			{"_id", nil},
-			{"averageRate", bson.M{"$avg": "$rate"}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "averageRate", Value: bson.M{"$avg": "$rate"}},
 		}}},
 	}
 
 	cursor, err := ordersCollection.Aggregate(ctx, pipeline)
 	if err != nil {
-		return 0, repository_errors.SelectError
+		return 0, classify(err, repository_errors.SelectError)
 	}
 
 	var results []bson.M
 	if err = cursor.All(ctx, &results); err != nil {
-		return 0, repository_errors.SelectError
+		return 0, classify(err, repository_errors.SelectError)
 	}
 
 	if len(results) == 0 {
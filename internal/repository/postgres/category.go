@@ -3,6 +3,7 @@ package postgres
 import (
 	"database/sql"
 	"errors"
+	"time"
 
 	"lab3/internal/models"
 	"lab3/internal/repository/repository_errors"
@@ -11,8 +12,13 @@ import (
 )
 
 type Category struct {
-	ID   int
-	Name string
+	ID        int           `db:"id"`
+	Name      string        `db:"name"`
+	ParentID  sql.NullInt64 `db:"parent_id"`
+	Position  int           `db:"position"`
+	Hidden    bool          `db:"hidden"`
+	TaxRate   string        `db:"tax_rate"`
+	DeletedAt sql.NullTime  `db:"deleted_at"`
 }
 
 type CategoryRepository struct {
@@ -23,21 +29,47 @@ func NewCategoryRepository(db *sqlx.DB) *CategoryRepository {
 	return &CategoryRepository{db: db}
 }
 
+func copyCategoryResultToModel(category *Category) *models.Category {
+	return &models.Category{
+		ID:        category.ID,
+		Name:      category.Name,
+		ParentID:  int(category.ParentID.Int64),
+		Position:  category.Position,
+		Hidden:    category.Hidden,
+		TaxRate:   category.TaxRate,
+		DeletedAt: category.DeletedAt.Time,
+	}
+}
+
+// parentID stores a top level category with a NULL parent.
+func parentID(category *models.Category) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(category.ParentID), Valid: category.ParentID != 0}
+}
+
 func (c CategoryRepository) GetAll() ([]models.Category, error) {
 	var categories []Category
-	err := c.db.Select(&categories, "SELECT * FROM categories")
+	err := c.db.Select(&categories, "SELECT * FROM categories WHERE deleted_at IS NULL ORDER BY position, id")
 	if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	var categoryModels []models.Category
 	for i := range categories {
-		categoryModel := models.Category{
-			ID:   categories[i].ID,
-			Name: categories[i].Name,
-		}
+		categoryModels = append(categoryModels, *copyCategoryResultToModel(&categories[i]))
+	}
+	return categoryModels, nil
+}
 
-		categoryModels = append(categoryModels, categoryModel)
+func (c CategoryRepository) GetDeleted() ([]models.Category, error) {
+	var categories []Category
+	err := c.db.Select(&categories, "SELECT * FROM categories WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC")
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var categoryModels []models.Category
+	for i := range categories {
+		categoryModels = append(categoryModels, *copyCategoryResultToModel(&categories[i]))
 	}
 	return categoryModels, nil
 }
@@ -48,12 +80,9 @@ func (c CategoryRepository) GetByID(id int) (*models.Category, error) {
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, repository_errors.DoesNotExist
 	} else if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
-	return &models.Category{
-		ID:   category.ID,
-		Name: category.Name,
-	}, nil
+	return copyCategoryResultToModel(&category), nil
 }
 
 func (c CategoryRepository) Create(category *models.Category) (*models.Category, error) {
@@ -61,19 +90,18 @@ func (c CategoryRepository) Create(category *models.Category) (*models.Category,
 		return nil, repository_errors.InsertError
 	}
 
-	query := `INSERT INTO categories(name) VALUES ($1) RETURNING id;`
+	query := `INSERT INTO categories(name, parent_id, hidden, tax_rate, position)
+		VALUES ($1, $2, $3, $4, (SELECT coalesce(max(position), 0) + 1 FROM categories)) RETURNING id, position;`
 
-	var categoryID int
-	err := c.db.QueryRow(query, category.Name).Scan(&categoryID)
+	created := *category
+	created.DeletedAt = time.Time{}
+	err := c.db.QueryRow(query, category.Name, parentID(category), category.Hidden, category.TaxRate).Scan(&created.ID, &created.Position)
 
 	if err != nil {
-		return nil, err
+		return nil, classify(err, repository_errors.InsertError)
 	}
 
-	return &models.Category{
-		ID:   categoryID,
-		Name: category.Name,
-	}, nil
+	return &created, nil
 }
 
 func (c CategoryRepository) Update(category *models.Category) (*models.Category, error) {
@@ -81,25 +109,72 @@ func (c CategoryRepository) Update(category *models.Category) (*models.Category,
 		return nil, repository_errors.InsertError
 	}
 
-	query := `UPDATE categories SET name = $2 WHERE id = $1 RETURNING id;`
+	query := `UPDATE categories SET name = $2, parent_id = $3, hidden = $4, tax_rate = $5 WHERE id = $1 RETURNING position;`
 
-	var categoryID int
-	err := c.db.QueryRow(query, category.ID, category.Name).Scan(&categoryID)
+	updated := *category
+	err := c.db.QueryRow(query, category.ID, category.Name, parentID(category), category.Hidden, category.TaxRate).Scan(&updated.Position)
 
 	if err != nil {
-		return nil, err
+		return nil, classify(err, repository_errors.UpdateError)
 	}
 
-	return &models.Category{
-		ID:   categoryID,
-		Name: category.Name,
-	}, nil
+	return &updated, nil
+}
+
+// Reorder updates the positions in one transaction, so that a failed reorder leaves the old order.
+func (c CategoryRepository) Reorder(ids []int) error {
+	transaction, err := c.db.Begin()
+	if err != nil {
+		return classify(err, repository_errors.TransactionBeginError)
+	}
+
+	for position, id := range ids {
+		result, err := transaction.Exec("UPDATE categories SET position = $2 WHERE id = $1", id, position+1)
+		if err == nil {
+			var rowsAffected int64
+			rowsAffected, err = result.RowsAffected()
+			if err == nil && rowsAffected == 0 {
+				err = repository_errors.UpdateError
+			}
+		}
+		if err != nil {
+			if rollbackErr := transaction.Rollback(); rollbackErr != nil {
+				return repository_errors.TransactionRollbackError
+			}
+			return classify(err, repository_errors.UpdateError)
+		}
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return classify(err, repository_errors.TransactionCommitError)
+	}
+
+	return nil
 }
 
 func (c CategoryRepository) Delete(id int) error {
-	result, err := c.db.Exec("DELETE FROM categories WHERE id = $1", id)
+	result, err := c.db.Exec("UPDATE categories SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL", id)
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (c CategoryRepository) Restore(id int) error {
+	result, err := c.db.Exec("UPDATE categories SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL", id)
 	if err != nil {
-		return repository_errors.DeleteError
+		return classify(err, repository_errors.UpdateError)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -108,7 +183,7 @@ func (c CategoryRepository) Delete(id int) error {
 	}
 
 	if rowsAffected == 0 {
-		return errors.New("no category found to delete")
+		return repository_errors.DoesNotExist
 	}
 
 	return nil
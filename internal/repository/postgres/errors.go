@@ -0,0 +1,9 @@
+package postgres
+
+import "lab3/internal/repository/repository_errors"
+
+// classify returns kind, marked as repository_errors.Unavailable when err was caused by a lost
+// connection or a timeout.
+func classify(err error, kind error) error {
+	return repository_errors.Transient(err, kind, repository_errors.IsConnectionError)
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type LoginAttemptDB struct {
+	Key         string       `db:"key"`
+	Failures    int          `db:"failures"`
+	LastFailure time.Time    `db:"last_failure"`
+	LockedUntil sql.NullTime `db:"locked_until"`
+}
+
+type LoginAttemptRepository struct {
+	db *sqlx.DB
+}
+
+func NewLoginAttemptRepository(db *sqlx.DB) repository_interfaces.ILoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+func copyLoginAttemptResultToModel(attemptDB *LoginAttemptDB) *models.LoginAttempt {
+	return &models.LoginAttempt{
+		Key:         attemptDB.Key,
+		Failures:    attemptDB.Failures,
+		LastFailure: attemptDB.LastFailure,
+		LockedUntil: attemptDB.LockedUntil.Time,
+	}
+}
+
+func (l LoginAttemptRepository) GetByKey(key string) (*models.LoginAttempt, error) {
+	query := `SELECT key, failures, last_failure, locked_until FROM login_attempts WHERE key = $1;`
+	attemptDB := &LoginAttemptDB{}
+	err := l.db.Get(attemptDB, query, key)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	return copyLoginAttemptResultToModel(attemptDB), nil
+}
+
+func (l LoginAttemptRepository) RegisterFailure(key string, at time.Time, resetBefore time.Time) (*models.LoginAttempt, error) {
+	query := `INSERT INTO login_attempts(key, failures, last_failure) VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_attempts.last_failure < $3 OR login_attempts.locked_until <= $2 THEN 1 ELSE login_attempts.failures + 1 END,
+			last_failure = EXCLUDED.last_failure,
+			locked_until = CASE WHEN login_attempts.locked_until <= $2 THEN NULL ELSE login_attempts.locked_until END
+		RETURNING key, failures, last_failure, locked_until;`
+
+	attemptDB := &LoginAttemptDB{}
+	err := l.db.Get(attemptDB, query, key, at, resetBefore)
+	if err != nil {
+		return nil, classify(err, repository_errors.InsertError)
+	}
+
+	return copyLoginAttemptResultToModel(attemptDB), nil
+}
+
+func (l LoginAttemptRepository) Lock(key string, until time.Time) error {
+	result, err := l.db.Exec(`UPDATE login_attempts SET locked_until = $1 WHERE key = $2;`, until, key)
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+
+	if rowsAffected == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (l LoginAttemptRepository) Delete(key string) error {
+	_, err := l.db.Exec(`DELETE FROM login_attempts WHERE key = $1;`, key)
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	return nil
+}
+
+func (l LoginAttemptRepository) GetLocked(at time.Time) ([]models.LoginAttempt, error) {
+	query := `SELECT key, failures, last_failure, locked_until FROM login_attempts WHERE locked_until > $1 ORDER BY locked_until DESC;`
+	var attemptsDB []LoginAttemptDB
+	err := l.db.Select(&attemptsDB, query, at)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var attempts []models.LoginAttempt
+	for i := range attemptsDB {
+		attempts = append(attempts, *copyLoginAttemptResultToModel(&attemptsDB[i]))
+	}
+
+	return attempts, nil
+}
@@ -0,0 +1,6 @@
+drop table if exists categories;
+drop table if exists order_contains_tasks;
+drop table if exists tasks;
+drop table if exists orders;
+drop table if exists workers;
+drop table if exists users;
@@ -0,0 +1,60 @@
+-- The schema as it was created by db/sql/init.sql before the migrations. Databases created by that script
+-- already have these tables, so the statements leave them as they are, the later migrations bring them up to date.
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+create table if not exists users
+(
+    id           uuid primary key default uuid_generate_v4(),
+    name         text,
+    surname      text,
+    email        text unique,
+    phone_number text,
+    address      text,
+    password     text
+);
+
+create table if not exists workers
+(
+    id           uuid primary key default uuid_generate_v4(),
+    name         text,
+    surname      text,
+    email        text unique,
+    phone_number text,
+    address      text,
+    password     text,
+    role         int
+);
+
+create table if not exists orders
+(
+    id            uuid primary key                                default uuid_generate_v4(),
+    worker_id     uuid references workers (id) on delete set null default null,
+    user_id       uuid references users (id) on delete set null   default null,
+    status        int2                                            default 0,
+    address       text,
+    deadline      timestamp,
+    creation_date timestamp                                       default now(),
+    rate          int2                                            default 0
+);
+
+create table if not exists tasks
+(
+    id               uuid primary key default uuid_generate_v4(),
+    name             text,
+    price_per_single float8,
+    category         int2
+);
+
+create table if not exists order_contains_tasks
+(
+    id       uuid primary key default uuid_generate_v4(),
+    order_id uuid references orders (id),
+    task_id  uuid references tasks (id),
+    quantity int2             default 1
+);
+
+create table if not exists categories
+(
+    id   serial unique,
+    name varchar
+);
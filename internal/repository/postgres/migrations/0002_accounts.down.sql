@@ -0,0 +1,9 @@
+drop table if exists audit_log;
+drop table if exists worker_recovery_codes;
+drop table if exists worker_two_factor;
+drop table if exists one_time_tokens;
+drop table if exists login_attempts;
+drop table if exists sessions;
+alter table orders drop constraint if exists orders_user_id_idempotency_key_key;
+alter table orders drop column if exists idempotency_key;
+alter table users drop column if exists email_verified;
@@ -0,0 +1,91 @@
+-- Sessions, sign-in throttling, one-time tokens, two-factor authentication and the audit log, with the columns
+-- they added to the tables of the initial schema. Every statement tolerates objects that already exist.
+-- Clients who signed up before email verification existed keep ordering, only new accounts confirm their email.
+do
+$$
+    begin
+        if not exists (select 1
+                       from information_schema.columns
+                       where table_name = 'users'
+                         and column_name = 'email_verified') then
+            alter table users add column email_verified boolean default false;
+            update users set email_verified = true;
+        end if;
+    end
+$$;
+
+-- idempotency_key identifies a submission of the order form, so that a repeated submission does not create a second order
+alter table orders add column if not exists idempotency_key text default null;
+do
+$$
+    begin
+        if not exists (select 1 from pg_constraint where conname = 'orders_user_id_idempotency_key_key') then
+            alter table orders add constraint orders_user_id_idempotency_key_key unique (user_id, idempotency_key);
+        end if;
+    end
+$$;
+
+create table if not exists sessions
+(
+    id            text primary key,
+    user_id       uuid references users (id) on delete cascade   default null,
+    worker_id     uuid references workers (id) on delete cascade default null,
+    data          text,
+    created_at    timestamp                                      default now(),
+    last_activity timestamp                                      default now()
+);
+create index if not exists sessions_user_id_idx on sessions (user_id);
+create index if not exists sessions_worker_id_idx on sessions (worker_id);
+
+create table if not exists login_attempts
+(
+    key          text primary key,
+    failures     int       default 0,
+    last_failure timestamp default now(),
+    locked_until timestamp default null
+);
+
+create table if not exists one_time_tokens
+(
+    hash       text primary key,
+    purpose    text      not null,
+    user_id    uuid references users (id) on delete cascade   default null,
+    worker_id  uuid references workers (id) on delete cascade default null,
+    expires_at timestamp not null,
+    created_at timestamp                                      default now()
+);
+
+create table if not exists worker_two_factor
+(
+    worker_id      uuid primary key references workers (id) on delete cascade,
+    secret         text not null,
+    enabled        boolean   default false,
+    last_used_step bigint    default 0,
+    created_at     timestamp default now()
+);
+
+create table if not exists worker_recovery_codes
+(
+    worker_id uuid references workers (id) on delete cascade,
+    code_hash text not null,
+    primary key (worker_id, code_hash)
+);
+
+create table if not exists audit_log
+(
+    id          uuid primary key default uuid_generate_v4(),
+    actor_id    uuid      not null,
+    action      text      not null,
+    target_type text      not null,
+    target_id   text      not null,
+    before      text      default '',
+    after       text      default '',
+    ip          text      default '',
+    created_at  timestamp default now()
+);
+create index if not exists audit_log_created_at_idx on audit_log (created_at);
+create index if not exists audit_log_target_idx on audit_log (target_type, target_id);
+
+-- entries are immutable
+create or replace rule audit_log_no_update as on update to audit_log do instead nothing;
+create or replace rule audit_log_no_delete as on delete to audit_log do instead nothing;
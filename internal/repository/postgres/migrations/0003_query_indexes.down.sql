@@ -0,0 +1,8 @@
+drop index if exists one_time_tokens_worker_id_idx;
+drop index if exists one_time_tokens_user_id_idx;
+drop index if exists tasks_category_idx;
+drop index if exists order_contains_tasks_task_id_idx;
+drop index if exists order_contains_tasks_order_id_idx;
+drop index if exists orders_status_idx;
+drop index if exists orders_worker_id_idx;
+drop index if exists orders_user_id_idx;
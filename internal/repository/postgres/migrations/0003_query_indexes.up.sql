@@ -0,0 +1,8 @@
+create index if not exists orders_user_id_idx on orders (user_id);
+create index if not exists orders_worker_id_idx on orders (worker_id);
+create index if not exists orders_status_idx on orders (status);
+create index if not exists order_contains_tasks_order_id_idx on order_contains_tasks (order_id);
+create index if not exists order_contains_tasks_task_id_idx on order_contains_tasks (task_id);
+create index if not exists tasks_category_idx on tasks (category);
+create index if not exists one_time_tokens_user_id_idx on one_time_tokens (user_id);
+create index if not exists one_time_tokens_worker_id_idx on one_time_tokens (worker_id);
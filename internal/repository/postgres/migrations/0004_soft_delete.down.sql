@@ -0,0 +1,4 @@
+alter table categories drop column if exists deleted_at;
+alter table tasks drop column if exists deleted_at;
+alter table workers drop column if exists deleted_at;
+alter table users drop column if exists deleted_at;
@@ -0,0 +1,6 @@
+-- deleted_at marks archived rows. They are hidden from lists and lookups by name or email,
+-- but stay in place, so that old orders still resolve their client, worker and tasks.
+alter table users add column if not exists deleted_at timestamp default null;
+alter table workers add column if not exists deleted_at timestamp default null;
+alter table tasks add column if not exists deleted_at timestamp default null;
+alter table categories add column if not exists deleted_at timestamp default null;
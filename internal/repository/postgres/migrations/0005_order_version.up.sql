@@ -0,0 +1,2 @@
+-- version is increased by every update of an order, so that a write based on a stale read is rejected.
+alter table orders add column if not exists version int not null default 1;
@@ -0,0 +1 @@
+drop index if exists tasks_search_idx;
@@ -0,0 +1,2 @@
+-- task names are searched by words in any of their forms, the expression must match the one of TaskRepository.Search
+create index if not exists tasks_search_idx on tasks using gin (to_tsvector('russian', coalesce(name, '')));
@@ -0,0 +1,12 @@
+-- archived orders are moved back, so that no order is lost
+insert into orders (id, worker_id, user_id, status, address, deadline, creation_date, rate, idempotency_key, version, closed_at)
+select id, worker_id, user_id, status, address, deadline, creation_date, rate, idempotency_key, version, closed_at
+from orders_archive;
+insert into order_contains_tasks (id, order_id, task_id, quantity)
+select id, order_id, task_id, quantity
+from order_contains_tasks_archive;
+
+drop table if exists order_contains_tasks_archive;
+drop table if exists orders_archive;
+drop index if exists orders_closed_at_idx;
+alter table orders drop column if exists closed_at;
@@ -0,0 +1,32 @@
+-- closed_at is when an order was completed or cancelled, it starts the period after which the order is archived.
+-- Orders closed before the column existed are taken as closed at their deadline.
+alter table orders add column if not exists closed_at timestamp default null;
+update orders set closed_at = coalesce(deadline, creation_date) where status in (3, 4) and closed_at is null;
+create index if not exists orders_closed_at_idx on orders (closed_at);
+
+-- archived orders keep the columns of orders and are only read
+create table if not exists orders_archive
+(
+    id              uuid primary key,
+    worker_id       uuid references workers (id) on delete set null default null,
+    user_id         uuid references users (id) on delete set null   default null,
+    status          int2,
+    address         text,
+    deadline        timestamp,
+    creation_date   timestamp,
+    rate            int2,
+    idempotency_key text                                            default null,
+    version         int not null                                    default 1,
+    closed_at       timestamp
+);
+create index if not exists orders_archive_user_id_idx on orders_archive (user_id);
+create index if not exists orders_archive_worker_id_idx on orders_archive (worker_id);
+
+create table if not exists order_contains_tasks_archive
+(
+    id       uuid primary key,
+    order_id uuid references orders_archive (id),
+    task_id  uuid references tasks (id),
+    quantity int2
+);
+create index if not exists order_contains_tasks_archive_order_id_idx on order_contains_tasks_archive (order_id);
@@ -0,0 +1,2 @@
+alter table tasks drop constraint if exists tasks_category_fkey;
+alter table tasks alter column category type int2;
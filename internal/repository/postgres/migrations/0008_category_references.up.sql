@@ -0,0 +1,20 @@
+-- Tasks reference their category. Categories that tasks refer to but that were never created get the
+-- names the application used to have built in, the rest a placeholder name a manager can change.
+insert into categories (id, name)
+select distinct tasks.category, coalesce(builtin.name, 'Категория ' || tasks.category)
+from tasks
+         left join (values (1, 'Генеральная уборка'),
+                           (2, 'Послестроительная уборка'),
+                           (3, 'Мытье окон'),
+                           (4, 'Ежедневная уборка офисов'),
+                           (5, 'Поддерживающая уборка'),
+                           (6, 'Химчистка ковров и мебели'),
+                           (7, 'Уход за твердыми полами'),
+                           (8, 'Глубинная Эко Чистка')) builtin (id, name) on builtin.id = tasks.category
+where tasks.category is not null
+  and not exists (select 1 from categories where categories.id = tasks.category);
+-- the next created category takes the id after the largest one
+select setval(pg_get_serial_sequence('categories', 'id'), coalesce((select max(id) from categories), 0) + 1, false);
+
+alter table tasks alter column category type int;
+alter table tasks add constraint tasks_category_fkey foreign key (category) references categories (id);
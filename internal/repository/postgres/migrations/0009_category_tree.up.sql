@@ -0,0 +1,7 @@
+-- Categories are nested in a parent category and ordered by position among the categories of the same parent.
+-- Hidden categories are kept out of the catalog that clients see. The existing categories keep the order of their ids.
+alter table categories add column if not exists parent_id int references categories (id) default null;
+alter table categories add column if not exists position int not null default 0;
+alter table categories add column if not exists hidden boolean not null default false;
+update categories set position = id where position = 0;
+create index if not exists categories_parent_id_idx on categories (parent_id);
@@ -0,0 +1,16 @@
+-- task_prices is the price history of tasks. A price is in force from effective_from until the next price of the task,
+-- tasks.price_per_single keeps the price in force now. The current prices start the history of the existing tasks.
+create table if not exists task_prices
+(
+    id             uuid primary key default uuid_generate_v4(),
+    task_id        uuid      not null references tasks (id),
+    price          float8    not null,
+    effective_from timestamp not null,
+    unique (task_id, effective_from)
+);
+create index if not exists task_prices_effective_from_idx on task_prices (effective_from);
+
+insert into task_prices (task_id, price, effective_from)
+select id, price_per_single, timestamp '1970-01-01 00:00:00'
+from tasks
+on conflict do nothing;
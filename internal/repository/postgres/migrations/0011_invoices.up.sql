@@ -0,0 +1,20 @@
+-- invoices of completed orders. The seller requisites and the lines are JSON copied when the invoice is issued,
+-- order_id has no reference because archiving moves orders to orders_archive.
+create table if not exists invoices
+(
+    id        uuid primary key default uuid_generate_v4(),
+    number    int       not null unique,
+    order_id  uuid      not null unique,
+    user_id   uuid      not null,
+    issued_at timestamp not null,
+    seller    text      not null default '{}',
+    buyer     text      not null default '',
+    address   text      not null default '',
+    lines     text      not null default '[]',
+    total     float8    not null
+);
+create index if not exists invoices_user_id_idx on invoices (user_id);
+
+-- invoices are immutable
+create or replace rule invoices_no_update as on update to invoices do instead nothing;
+create or replace rule invoices_no_delete as on delete to invoices do instead nothing;
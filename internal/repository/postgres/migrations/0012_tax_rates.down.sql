@@ -0,0 +1,2 @@
+alter table tasks drop column if exists tax_rate;
+alter table categories drop column if exists tax_rate;
@@ -0,0 +1,4 @@
+-- tax_rate is the code of a VAT rate of the configuration. A task without one takes the rate of its category,
+-- a category without one takes the rate of its parent, the top level categories take the default rate.
+alter table categories add column if not exists tax_rate text not null default '';
+alter table tasks add column if not exists tax_rate text not null default '';
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type OneTimeTokenDB struct {
+	Hash      string    `db:"hash"`
+	Purpose   string    `db:"purpose"`
+	UserID    uuid.UUID `db:"user_id"`
+	WorkerID  uuid.UUID `db:"worker_id"`
+	ExpiresAt time.Time `db:"expires_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type OneTimeTokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewOneTimeTokenRepository(db *sqlx.DB) repository_interfaces.IOneTimeTokenRepository {
+	return &OneTimeTokenRepository{db: db}
+}
+
+func copyOneTimeTokenResultToModel(tokenDB *OneTimeTokenDB) *models.OneTimeToken {
+	return &models.OneTimeToken{
+		Hash:      tokenDB.Hash,
+		Purpose:   tokenDB.Purpose,
+		UserID:    tokenDB.UserID,
+		WorkerID:  tokenDB.WorkerID,
+		ExpiresAt: tokenDB.ExpiresAt,
+		CreatedAt: tokenDB.CreatedAt,
+	}
+}
+
+func (o OneTimeTokenRepository) Create(token *models.OneTimeToken) error {
+	query := `INSERT INTO one_time_tokens(hash, purpose, user_id, worker_id, expires_at, created_at) VALUES ($1, $2, $3, $4, $5, $6);`
+
+	_, err := o.db.Exec(query, token.Hash, token.Purpose, nullableUUID(token.UserID), nullableUUID(token.WorkerID), token.ExpiresAt, token.CreatedAt)
+	if err != nil {
+		return classify(err, repository_errors.InsertError)
+	}
+
+	return nil
+}
+
+func (o OneTimeTokenRepository) Consume(hash string, purpose string, at time.Time) (*models.OneTimeToken, error) {
+	query := `DELETE FROM one_time_tokens WHERE hash = $1 AND purpose = $2 AND expires_at > $3
+		RETURNING hash, purpose, user_id, worker_id, expires_at, created_at;`
+
+	tokenDB := &OneTimeTokenDB{}
+	err := o.db.Get(tokenDB, query, hash, purpose, at)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.DeleteError)
+	}
+
+	return copyOneTimeTokenResultToModel(tokenDB), nil
+}
+
+func (o OneTimeTokenRepository) DeleteByUserID(purpose string, userID uuid.UUID) error {
+	_, err := o.db.Exec(`DELETE FROM one_time_tokens WHERE purpose = $1 AND user_id = $2;`, purpose, userID)
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	return nil
+}
+
+func (o OneTimeTokenRepository) DeleteByWorkerID(purpose string, workerID uuid.UUID) error {
+	_, err := o.db.Exec(`DELETE FROM one_time_tokens WHERE purpose = $1 AND worker_id = $2;`, purpose, workerID)
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	return nil
+}
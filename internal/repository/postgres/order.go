@@ -7,6 +7,7 @@ import (
 	"lab3/internal/models"
 	"lab3/internal/repository/repository_errors"
 	"lab3/internal/repository/repository_interfaces"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,6 +24,12 @@ type OrderDB struct {
 	CreationDate time.Time `db:"creation_date"`
 	Deadline     time.Time `db:"deadline"`
 	Rate         int       `db:"rate"`
+	// IdempotencyKey is NULL for orders created without a key
+	IdempotencyKey sql.NullString `db:"idempotency_key"`
+	Version        int            `db:"version"`
+	ClosedAt       sql.NullTime   `db:"closed_at"`
+	// Archived is only selected by the queries that also read orders_archive
+	Archived bool `db:"archived"`
 }
 
 type OrderRepository struct {
@@ -35,33 +42,49 @@ func NewOrderRepository(db *sqlx.DB) repository_interfaces.IOrderRepository {
 
 func copyOrderResultToModel(orderDB *OrderDB) *models.Order {
 	return &models.Order{
-		ID:           orderDB.ID,
-		WorkerID:     orderDB.WorkerID,
-		UserID:       orderDB.UserID,
-		Status:       orderDB.Status,
-		Address:      orderDB.Address,
-		CreationDate: orderDB.CreationDate,
-		Deadline:     orderDB.Deadline,
-		Rate:         orderDB.Rate,
+		ID:             orderDB.ID,
+		WorkerID:       orderDB.WorkerID,
+		UserID:         orderDB.UserID,
+		Status:         orderDB.Status,
+		Address:        orderDB.Address,
+		CreationDate:   orderDB.CreationDate,
+		Deadline:       orderDB.Deadline,
+		Rate:           orderDB.Rate,
+		IdempotencyKey: orderDB.IdempotencyKey.String,
+		Version:        orderDB.Version,
+		ClosedAt:       orderDB.ClosedAt.Time,
+		Archived:       orderDB.Archived,
 	}
 }
 
+// orderSelectList lists the columns shared by orders and orders_archive, in the order of a union of both.
+const orderSelectList = "id, worker_id, user_id, status, address, deadline, creation_date, rate, idempotency_key, version, closed_at"
+
+// orderHistory is the union of the current and the archived orders.
+const orderHistory = "(SELECT " + orderSelectList + ", false AS archived FROM orders UNION ALL SELECT " + orderSelectList + ", true AS archived FROM orders_archive) history"
+
 func (o OrderRepository) Create(order *models.Order, orderedTasks []models.OrderedTask) (*models.Order, error) {
 	transaction, err := o.db.Begin()
 	if err != nil {
-		return nil, repository_errors.TransactionBeginError
+		return nil, classify(err, repository_errors.TransactionBeginError)
 	}
 
-	query := `INSERT INTO orders(user_id, status, address, deadline) VALUES ($1, $2, $3, $4) RETURNING id;`
+	// a conflicting idempotency key inserts nothing, so the order is reported as already existing
+	query := `INSERT INTO orders(user_id, status, address, deadline, idempotency_key) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, idempotency_key) DO NOTHING RETURNING id, version;`
 
-	err = transaction.QueryRow(query, order.UserID, order.Status, order.Address, order.Deadline).Scan(&order.ID)
+	idempotencyKey := sql.NullString{String: order.IdempotencyKey, Valid: order.IdempotencyKey != ""}
+	err = transaction.QueryRow(query, order.UserID, order.Status, order.Address, order.Deadline, idempotencyKey).Scan(&order.ID, &order.Version)
 
 	if err != nil {
-		err = transaction.Rollback()
-		if err != nil {
+		rollbackErr := transaction.Rollback()
+		if rollbackErr != nil {
 			return nil, repository_errors.TransactionRollbackError
 		}
-		return nil, repository_errors.InsertError
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository_errors.AlreadyExists
+		}
+		return nil, classify(err, repository_errors.InsertError)
 	}
 
 	for _, task := range orderedTasks {
@@ -78,7 +101,7 @@ func (o OrderRepository) Create(order *models.Order, orderedTasks []models.Order
 
 	err = transaction.Commit()
 	if err != nil {
-		return nil, repository_errors.TransactionCommitError
+		return nil, classify(err, repository_errors.TransactionCommitError)
 	}
 
 	return order, nil
@@ -88,7 +111,7 @@ func (o OrderRepository) Delete(id uuid.UUID) error {
 	// Start a new transaction
 	tx, err := o.db.Begin()
 	if err != nil {
-		return repository_errors.TransactionBeginError
+		return classify(err, repository_errors.TransactionBeginError)
 	}
 
 	// Delete the records in the order_contains_tasks table that reference the order
@@ -122,43 +145,67 @@ func (o OrderRepository) Delete(id uuid.UUID) error {
 	}
 
 	if rowsAffected == 0 {
-		return errors.New("no order found to delete")
+		err := tx.Rollback()
+		if err != nil {
+			return repository_errors.TransactionRollbackError
+		}
+		return repository_errors.DoesNotExist
 	}
 
 	// Commit the transaction
 	err = tx.Commit()
 	if err != nil {
-		return repository_errors.TransactionCommitError
+		return classify(err, repository_errors.TransactionCommitError)
 	}
 
 	return nil
 }
 
 func (o OrderRepository) Update(order *models.Order) (*models.Order, error) {
-	query := `UPDATE orders SET worker_id = $1, user_id = $2, status = $3, address = $4, creation_date = $5, deadline = $6, rate = $7 WHERE id = $8 RETURNING id, worker_id, user_id, status, address, creation_date, deadline, rate;`
+	// the row is only written if nobody has changed it since order.Version was read
+	query := `UPDATE orders SET worker_id = $1, user_id = $2, status = $3, address = $4, creation_date = $5, deadline = $6, rate = $7, closed_at = $8, version = version + 1
+		WHERE id = $9 AND version = $10 RETURNING id, worker_id, user_id, status, address, creation_date, deadline, rate, closed_at, version;`
 
 	var workerID interface{}
 	if order.WorkerID != uuid.Nil {
 		workerID = order.WorkerID
 	}
+	closedAt := sql.NullTime{Time: order.ClosedAt, Valid: !order.ClosedAt.IsZero()}
 
 	var updatedOrder models.Order
-	err := o.db.QueryRow(query, workerID, order.UserID, order.Status, order.Address, order.CreationDate, order.Deadline, order.Rate, order.ID).Scan(&updatedOrder.ID, &updatedOrder.WorkerID, &updatedOrder.UserID, &updatedOrder.Status, &updatedOrder.Address, &updatedOrder.CreationDate, &updatedOrder.Deadline, &updatedOrder.Rate)
-	if err != nil {
-		return nil, repository_errors.UpdateError
+	err := o.db.QueryRow(query, workerID, order.UserID, order.Status, order.Address, order.CreationDate, order.Deadline, order.Rate, closedAt, order.ID, order.Version).Scan(&updatedOrder.ID, &updatedOrder.WorkerID, &updatedOrder.UserID, &updatedOrder.Status, &updatedOrder.Address, &updatedOrder.CreationDate, &updatedOrder.Deadline, &updatedOrder.Rate, &closedAt, &updatedOrder.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, o.updateMissError(order.ID)
+	} else if err != nil {
+		return nil, classify(err, repository_errors.UpdateError)
 	}
+	updatedOrder.ClosedAt = closedAt.Time
 	return &updatedOrder, nil
 }
 
+// updateMissError tells a missing order from one whose version has changed since it was read.
+func (o OrderRepository) updateMissError(id uuid.UUID) error {
+	var exists bool
+	err := o.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM orders WHERE id = $1);`, id).Scan(&exists)
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+	if !exists {
+		return repository_errors.DoesNotExist
+	}
+
+	return repository_errors.VersionConflict
+}
+
 func (o OrderRepository) GetOrderByID(id uuid.UUID) (*models.Order, error) {
-	query := `SELECT * FROM orders WHERE id = $1;`
+	query := `SELECT * FROM ` + orderHistory + ` WHERE id = $1;`
 	orderDB := &OrderDB{}
 	err := o.db.Get(orderDB, query, id)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, repository_errors.DoesNotExist
 	} else if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	orderModels := copyOrderResultToModel(orderDB)
@@ -166,12 +213,27 @@ func (o OrderRepository) GetOrderByID(id uuid.UUID) (*models.Order, error) {
 	return orderModels, nil
 }
 
+func (o OrderRepository) GetOrderByIdempotencyKey(userID uuid.UUID, key string) (*models.Order, error) {
+	query := `SELECT * FROM orders WHERE user_id = $1 AND idempotency_key = $2;`
+	orderDB := &OrderDB{}
+	err := o.db.Get(orderDB, query, userID, key)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	return copyOrderResultToModel(orderDB), nil
+}
+
 func (o OrderRepository) GetTasksInOrder(id uuid.UUID) ([]models.Task, error) {
-	query := `SELECT * FROM tasks WHERE id IN (SELECT task_id FROM order_contains_tasks WHERE order_id = $1);`
+	query := `SELECT * FROM tasks WHERE id IN (SELECT task_id FROM order_contains_tasks WHERE order_id = $1
+		UNION ALL SELECT task_id FROM order_contains_tasks_archive WHERE order_id = $1);`
 	var tasksDB []TaskDB
 	err := o.db.Select(&tasksDB, query, id)
 	if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	var taskModels []models.Task
@@ -192,7 +254,7 @@ func (o OrderRepository) GetCurrentOrderByUserID(id uuid.UUID) (*models.Order, e
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, repository_errors.DoesNotExist
 	} else if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	orderModels := copyOrderResultToModel(orderDB)
@@ -207,7 +269,7 @@ func (o OrderRepository) GetAllOrdersByUserID(id uuid.UUID) ([]models.Order, err
 	err := o.db.Select(&orderDB, query, id)
 
 	if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	var orderModels []models.Order
@@ -219,55 +281,80 @@ func (o OrderRepository) GetAllOrdersByUserID(id uuid.UUID) ([]models.Order, err
 	return orderModels, nil
 }
 
-func (o OrderRepository) Filter(params map[string]string) ([]models.Order, error) {
-	var query strings.Builder
-	query.WriteString("SELECT * FROM orders")
-
-	if len(params) > 0 {
-		query.WriteString(" WHERE ")
-		for field, value := range params {
-			// Разделяем значения по запятой
-			values := strings.Split(value, ",")
-			if len(values) > 1 {
-				// Если есть несколько значений, создаем условие SQL с OR
-				query.WriteString("(")
-				for _, v := range values {
-					if v == "null" {
-						query.WriteString(fmt.Sprintf("%s IS NULL OR ", field))
-					} else if field == "status" {
-						query.WriteString(fmt.Sprintf("%s = %s OR ", field, v))
-					} else {
-						query.WriteString(fmt.Sprintf("%s::text LIKE '%s' OR ", field, v))
-					}
+// orderColumns are the columns Filter accepts. The names get into the query, so that only known ones are allowed.
+var orderColumns = map[string]bool{
+	"id":              true,
+	"worker_id":       true,
+	"user_id":         true,
+	"status":          true,
+	"address":         true,
+	"creation_date":   true,
+	"deadline":        true,
+	"rate":            true,
+	"idempotency_key": true,
+	"version":         true,
+	"closed_at":       true,
+}
+
+// orderConditions builds the WHERE clause of the Filter parameters, it is empty without parameters.
+func orderConditions(params map[string]string) (string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+
+	for field, value := range params {
+		if !orderColumns[field] {
+			return "", nil, repository_errors.SelectError
+		}
+
+		// comma separated values are alternatives
+		var alternatives []string
+		for _, v := range strings.Split(value, ",") {
+			if v == "null" {
+				alternatives = append(alternatives, fmt.Sprintf("%s IS NULL", field))
+			} else if v == "not null" {
+				alternatives = append(alternatives, fmt.Sprintf("%s IS NOT NULL", field))
+			} else if field == "status" {
+				status, err := strconv.Atoi(v)
+				if err != nil {
+					return "", nil, repository_errors.SelectError
 				}
-				// Удаляем последний " OR "
-				str := query.String()[:query.Len()-4]
-				query.Reset()
-				query.WriteString(str)
-				query.WriteString(") AND ")
+				args = append(args, status)
+				alternatives = append(alternatives, fmt.Sprintf("%s = $%d", field, len(args)))
 			} else {
-				if value == "null" {
-					query.WriteString(fmt.Sprintf("%s IS NULL AND ", field))
-				} else if value == "not null" {
-					query.WriteString(fmt.Sprintf("%s IS NOT NULL AND ", field))
-				} else if field == "status" {
-					query.WriteString(fmt.Sprintf("%s = %s AND ", field, value))
-				} else {
-					query.WriteString(fmt.Sprintf("%s::text LIKE '%s' AND ", field, value))
-				}
+				args = append(args, v)
+				alternatives = append(alternatives, fmt.Sprintf("%s::text LIKE $%d", field, len(args)))
 			}
 		}
-		// Удаляем последний " AND "
-		str := query.String()[:query.Len()-5]
-		query.Reset()
-		query.WriteString(str)
+		conditions = append(conditions, "("+strings.Join(alternatives, " OR ")+")")
+	}
+
+	if len(conditions) == 0 {
+		return "", nil, nil
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+func (o OrderRepository) Filter(params map[string]string) ([]models.Order, error) {
+	return o.selectOrders("orders", params, "")
+}
+
+func (o OrderRepository) History(params map[string]string) ([]models.Order, error) {
+	return o.selectOrders(orderHistory, params, " ORDER BY creation_date DESC")
+}
+
+// selectOrders returns the orders of the table matching the Filter parameters.
+func (o OrderRepository) selectOrders(table string, params map[string]string, orderBy string) ([]models.Order, error) {
+	where, args, err := orderConditions(params)
+	if err != nil {
+		return nil, err
 	}
 
 	var orderDB []OrderDB
-	err := o.db.Select(&orderDB, query.String())
+	err = o.db.Select(&orderDB, "SELECT * FROM "+table+where+orderBy, args...)
 
 	if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	var orderModels []models.Order
@@ -279,12 +366,35 @@ func (o OrderRepository) Filter(params map[string]string) ([]models.Order, error
 	return orderModels, nil
 }
 
+// Archive moves the orders and their tasks in one statement. The foreign keys are checked at its end,
+// when the lines already refer to the archived orders.
+func (o OrderRepository) Archive(closedBefore time.Time) (int, error) {
+	query := `WITH moved AS (
+			DELETE FROM orders WHERE status IN ($1, $2) AND closed_at < $3 RETURNING ` + orderSelectList + `
+		), archived AS (
+			INSERT INTO orders_archive (` + orderSelectList + `) SELECT ` + orderSelectList + ` FROM moved RETURNING id
+		), moved_lines AS (
+			DELETE FROM order_contains_tasks WHERE order_id IN (SELECT id FROM moved) RETURNING id, order_id, task_id, quantity
+		), archived_lines AS (
+			INSERT INTO order_contains_tasks_archive (id, order_id, task_id, quantity) SELECT id, order_id, task_id, quantity FROM moved_lines
+		)
+		SELECT count(*) FROM archived;`
+
+	var count int
+	err := o.db.QueryRow(query, models.CompletedOrderStatus, models.CancelledOrderStatus, closedBefore).Scan(&count)
+	if err != nil {
+		return 0, classify(err, repository_errors.UpdateError)
+	}
+
+	return count, nil
+}
+
 func (o OrderRepository) AddTaskToOrder(orderID uuid.UUID, taskID uuid.UUID) error {
 	query := `INSERT INTO order_contains_tasks(order_id, task_id) VALUES ($1, $2);`
 	_, err := o.db.Exec(query, orderID, taskID)
 
 	if err != nil {
-		return repository_errors.InsertError
+		return classify(err, repository_errors.InsertError)
 	}
 
 	return nil
@@ -295,7 +405,7 @@ func (o OrderRepository) RemoveTaskFromOrder(orderID uuid.UUID, taskID uuid.UUID
 	_, err := o.db.Exec(query, orderID, taskID)
 
 	if err != nil {
-		return repository_errors.DeleteError
+		return classify(err, repository_errors.DeleteError)
 	}
 
 	return nil
@@ -306,21 +416,22 @@ func (o OrderRepository) UpdateTaskQuantity(orderID uuid.UUID, taskID uuid.UUID,
 	_, err := o.db.Exec(query, quantity, orderID, taskID)
 
 	if err != nil {
-		return repository_errors.UpdateError
+		return classify(err, repository_errors.UpdateError)
 	}
 
 	return nil
 }
 
 func (o OrderRepository) GetTaskQuantity(orderID uuid.UUID, taskID uuid.UUID) (int, error) {
-	query := `SELECT quantity FROM order_contains_tasks WHERE order_id = $1 AND task_id = $2 LIMIT 1;`
+	query := `SELECT quantity FROM order_contains_tasks WHERE order_id = $1 AND task_id = $2
+		UNION ALL SELECT quantity FROM order_contains_tasks_archive WHERE order_id = $1 AND task_id = $2 LIMIT 1;`
 	var quantity int
 
 	err := o.db.Get(&quantity, query, orderID, taskID)
 	if errors.Is(err, sql.ErrNoRows) {
 		return 0, repository_errors.DoesNotExist
 	} else if err != nil {
-		return 0, repository_errors.SelectError
+		return 0, classify(err, repository_errors.SelectError)
 	}
 
 	return quantity, nil
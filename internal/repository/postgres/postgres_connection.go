@@ -61,3 +61,45 @@ func CreateCategoryRepository(fields *PostgresConnection) repository_interfaces.
 
 	return NewCategoryRepository(dbx)
 }
+
+func CreateSessionRepository(fields *PostgresConnection) repository_interfaces.ISessionRepository {
+	dbx := sqlx.NewDb(fields.DB, "pgx")
+
+	return NewSessionRepository(dbx)
+}
+
+func CreateLoginAttemptRepository(fields *PostgresConnection) repository_interfaces.ILoginAttemptRepository {
+	dbx := sqlx.NewDb(fields.DB, "pgx")
+
+	return NewLoginAttemptRepository(dbx)
+}
+
+func CreateOneTimeTokenRepository(fields *PostgresConnection) repository_interfaces.IOneTimeTokenRepository {
+	dbx := sqlx.NewDb(fields.DB, "pgx")
+
+	return NewOneTimeTokenRepository(dbx)
+}
+
+func CreateTwoFactorRepository(fields *PostgresConnection) repository_interfaces.ITwoFactorRepository {
+	dbx := sqlx.NewDb(fields.DB, "pgx")
+
+	return NewTwoFactorRepository(dbx)
+}
+
+func CreateAuditRepository(fields *PostgresConnection) repository_interfaces.IAuditRepository {
+	dbx := sqlx.NewDb(fields.DB, "pgx")
+
+	return NewAuditRepository(dbx)
+}
+
+func CreateInvoiceRepository(fields *PostgresConnection) repository_interfaces.IInvoiceRepository {
+	dbx := sqlx.NewDb(fields.DB, "pgx")
+
+	return NewInvoiceRepository(dbx)
+}
+
+func CreateMigrator(fields *PostgresConnection) repository_interfaces.IMigrator {
+	dbx := sqlx.NewDb(fields.DB, "pgx")
+
+	return NewMigrator(dbx)
+}
@@ -6,16 +6,21 @@ import (
 	"lab3/internal/models"
 	"lab3/internal/repository/repository_errors"
 	"lab3/internal/repository/repository_interfaces"
+	"lab3/internal/search"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
 type TaskDB struct {
-	ID             uuid.UUID `db:"id"`
-	Name           string    `db:"name"`
-	PricePerSingle float64   `db:"price_per_single"`
-	Category       int       `db:"category"`
+	ID             uuid.UUID    `db:"id"`
+	Name           string       `db:"name"`
+	PricePerSingle float64      `db:"price_per_single"`
+	Category       int          `db:"category"`
+	TaxRate        string       `db:"tax_rate"`
+	DeletedAt      sql.NullTime `db:"deleted_at"`
 }
 
 type TaskRepository struct {
@@ -32,6 +37,8 @@ func copyTaskResultToModel(taskDB *TaskDB) *models.Task {
 		Name:           taskDB.Name,
 		PricePerSingle: taskDB.PricePerSingle,
 		Category:       taskDB.Category,
+		TaxRate:        taskDB.TaxRate,
+		DeletedAt:      taskDB.DeletedAt.Time,
 	}
 }
 
@@ -40,13 +47,13 @@ func (t TaskRepository) Create(task *models.Task) (*models.Task, error) {
 		return nil, repository_errors.InsertError
 	}
 
-	query := `INSERT INTO tasks(name, price_per_single, category) VALUES ($1, $2, $3) RETURNING id;`
+	query := `INSERT INTO tasks(name, price_per_single, category, tax_rate) VALUES ($1, $2, $3, $4) RETURNING id;`
 
 	var taskID uuid.UUID
-	err := t.db.QueryRow(query, task.Name, task.PricePerSingle, task.Category).Scan(&taskID)
+	err := t.db.QueryRow(query, task.Name, task.PricePerSingle, task.Category, task.TaxRate).Scan(&taskID)
 
 	if err != nil {
-		return nil, repository_errors.InsertError
+		return nil, classify(err, repository_errors.InsertError)
 	}
 
 	return &models.Task{
@@ -54,15 +61,36 @@ func (t TaskRepository) Create(task *models.Task) (*models.Task, error) {
 		Name:           task.Name,
 		PricePerSingle: task.PricePerSingle,
 		Category:       task.Category,
+		TaxRate:        task.TaxRate,
 	}, nil
 }
 
 func (t TaskRepository) Delete(id uuid.UUID) error {
-	query := `DELETE FROM tasks WHERE id = $1;`
+	query := `UPDATE tasks SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL;`
+	result, err := t.db.Exec(query, id)
+
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (t TaskRepository) Restore(id uuid.UUID) error {
+	query := `UPDATE tasks SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL;`
 	result, err := t.db.Exec(query, id)
 
 	if err != nil {
-		return repository_errors.DeleteError
+		return classify(err, repository_errors.UpdateError)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -71,7 +99,7 @@ func (t TaskRepository) Delete(id uuid.UUID) error {
 	}
 
 	if rowsAffected == 0 {
-		return errors.New("no task found to delete")
+		return repository_errors.DoesNotExist
 	}
 
 	return nil
@@ -82,12 +110,12 @@ func (t TaskRepository) Update(task *models.Task) (*models.Task, error) {
 		return nil, repository_errors.InsertError
 	}
 
-	query := `UPDATE tasks SET name = $1, price_per_single = $2, category = $3 WHERE tasks.id = $4 RETURNING id, name, price_per_single, category;`
+	query := `UPDATE tasks SET name = $1, price_per_single = $2, category = $3, tax_rate = $5 WHERE tasks.id = $4 RETURNING id, name, price_per_single, category, tax_rate;`
 
 	var updatedTask models.Task
-	err := t.db.QueryRow(query, task.Name, task.PricePerSingle, task.Category, task.ID).Scan(&updatedTask.ID, &updatedTask.Name, &updatedTask.PricePerSingle, &updatedTask.Category)
+	err := t.db.QueryRow(query, task.Name, task.PricePerSingle, task.Category, task.ID, task.TaxRate).Scan(&updatedTask.ID, &updatedTask.Name, &updatedTask.PricePerSingle, &updatedTask.Category, &updatedTask.TaxRate)
 	if err != nil {
-		return nil, repository_errors.UpdateError
+		return nil, classify(err, repository_errors.UpdateError)
 	}
 	return &updatedTask, nil
 }
@@ -100,7 +128,7 @@ func (t TaskRepository) GetTaskByID(id uuid.UUID) (*models.Task, error) {
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, repository_errors.DoesNotExist
 	} else if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	taskModels := copyTaskResultToModel(taskDB)
@@ -109,27 +137,27 @@ func (t TaskRepository) GetTaskByID(id uuid.UUID) (*models.Task, error) {
 }
 
 func (t TaskRepository) GetTaskByName(name string) (*models.Task, error) {
-	query := `SELECT * FROM tasks WHERE name = $1 LIMIT 1;`
+	query := `SELECT * FROM tasks WHERE name = $1 AND deleted_at IS NULL LIMIT 1;`
 	taskDB := &TaskDB{}
 	err := t.db.Get(taskDB, query, name)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, repository_errors.DoesNotExist
 	} else if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	return copyTaskResultToModel(taskDB), nil
 }
 
 func (t TaskRepository) GetAllTasks() ([]models.Task, error) {
-	query := `SELECT id, name, price_per_single, category FROM tasks;`
+	query := `SELECT id, name, price_per_single, category, tax_rate FROM tasks WHERE deleted_at IS NULL;`
 	var taskDB []TaskDB
 
 	err := t.db.Select(&taskDB, query)
 
 	if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	var taskModels []models.Task
@@ -142,13 +170,55 @@ func (t TaskRepository) GetAllTasks() ([]models.Task, error) {
 }
 
 func (t TaskRepository) GetTasksInCategory(category int) ([]models.Task, error) {
-	query := `SELECT * FROM tasks WHERE category = $1;`
+	query := `SELECT * FROM tasks WHERE category = $1 AND deleted_at IS NULL;`
 	var taskDB []TaskDB
 
 	err := t.db.Select(&taskDB, query, category)
 
 	if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var taskModels []models.Task
+	for i := range taskDB {
+		task := copyTaskResultToModel(&taskDB[i])
+		taskModels = append(taskModels, *task)
+	}
+
+	return taskModels, nil
+}
+
+func (t TaskRepository) MoveToCategory(from, to int) (int, error) {
+	var exists bool
+	err := t.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1);`, to).Scan(&exists)
+	if err != nil {
+		return 0, classify(err, repository_errors.SelectError)
+	}
+	if !exists {
+		return 0, repository_errors.UpdateError
+	}
+
+	result, err := t.db.Exec(`UPDATE tasks SET category = $2 WHERE category = $1;`, from, to)
+	if err != nil {
+		return 0, classify(err, repository_errors.UpdateError)
+	}
+
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(moved), nil
+}
+
+func (t TaskRepository) GetDeletedTasks() ([]models.Task, error) {
+	query := `SELECT * FROM tasks WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC;`
+	var taskDB []TaskDB
+
+	err := t.db.Select(&taskDB, query)
+
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	var taskModels []models.Task
@@ -159,3 +229,123 @@ func (t TaskRepository) GetTasksInCategory(category int) ([]models.Task, error)
 
 	return taskModels, nil
 }
+
+func (t TaskRepository) Search(query string) ([]models.Task, error) {
+	words := search.Words(query)
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	// every word is required, the last one may be unfinished
+	for i := range words {
+		words[i] += ":*"
+	}
+
+	sqlQuery := `SELECT tasks.* FROM tasks, to_tsquery('russian', $1) query
+		WHERE deleted_at IS NULL AND to_tsvector('russian', coalesce(name, '')) @@ query
+		ORDER BY ts_rank(to_tsvector('russian', coalesce(name, '')), query) DESC, name;`
+	var taskDB []TaskDB
+
+	err := t.db.Select(&taskDB, sqlQuery, strings.Join(words, " & "))
+
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var taskModels []models.Task
+	for i := range taskDB {
+		task := copyTaskResultToModel(&taskDB[i])
+		taskModels = append(taskModels, *task)
+	}
+
+	return taskModels, nil
+}
+
+type TaskPriceDB struct {
+	ID            uuid.UUID `db:"id"`
+	TaskID        uuid.UUID `db:"task_id"`
+	Price         float64   `db:"price"`
+	EffectiveFrom time.Time `db:"effective_from"`
+}
+
+func copyTaskPricesToModels(pricesDB []TaskPriceDB) []models.TaskPrice {
+	var prices []models.TaskPrice
+	for _, price := range pricesDB {
+		prices = append(prices, models.TaskPrice(price))
+	}
+	return prices
+}
+
+func (t TaskRepository) SchedulePrice(price *models.TaskPrice) (*models.TaskPrice, error) {
+	if price.Price == 0 {
+		return nil, repository_errors.InsertError
+	}
+
+	query := `INSERT INTO task_prices(task_id, price, effective_from) VALUES ($1, $2, $3)
+		ON CONFLICT (task_id, effective_from) DO UPDATE SET price = excluded.price RETURNING id;`
+
+	scheduled := *price
+	err := t.db.QueryRow(query, price.TaskID, price.Price, price.EffectiveFrom).Scan(&scheduled.ID)
+	if err != nil {
+		return nil, classify(err, repository_errors.InsertError)
+	}
+
+	return &scheduled, nil
+}
+
+func (t TaskRepository) DeletePrice(id uuid.UUID) error {
+	result, err := t.db.Exec(`DELETE FROM task_prices WHERE id = $1;`, id)
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (t TaskRepository) GetPrices(taskID uuid.UUID) ([]models.TaskPrice, error) {
+	var pricesDB []TaskPriceDB
+	err := t.db.Select(&pricesDB, `SELECT * FROM task_prices WHERE task_id = $1 ORDER BY effective_from;`, taskID)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	return copyTaskPricesToModels(pricesDB), nil
+}
+
+func (t TaskRepository) GetUpcomingPrices(moment time.Time) ([]models.TaskPrice, error) {
+	var pricesDB []TaskPriceDB
+	err := t.db.Select(&pricesDB, `SELECT * FROM task_prices WHERE effective_from > $1 ORDER BY effective_from;`, moment)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	return copyTaskPricesToModels(pricesDB), nil
+}
+
+func (t TaskRepository) ApplyPrices(moment time.Time) (int, error) {
+	query := `UPDATE tasks SET price_per_single = current.price
+		FROM (SELECT DISTINCT ON (task_id) task_id, price FROM task_prices
+			WHERE effective_from <= $1 ORDER BY task_id, effective_from DESC) current
+		WHERE tasks.id = current.task_id AND tasks.price_per_single IS DISTINCT FROM current.price;`
+
+	result, err := t.db.Exec(query, moment)
+	if err != nil {
+		return 0, classify(err, repository_errors.UpdateError)
+	}
+
+	changed, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(changed), nil
+}
@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type TwoFactorDB struct {
+	WorkerID     uuid.UUID `db:"worker_id"`
+	Secret       string    `db:"secret"`
+	Enabled      bool      `db:"enabled"`
+	LastUsedStep int64     `db:"last_used_step"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+type TwoFactorRepository struct {
+	db *sqlx.DB
+}
+
+func NewTwoFactorRepository(db *sqlx.DB) repository_interfaces.ITwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+func copyTwoFactorResultToModel(twoFactorDB *TwoFactorDB) *models.TwoFactor {
+	return &models.TwoFactor{
+		WorkerID:     twoFactorDB.WorkerID,
+		Secret:       twoFactorDB.Secret,
+		Enabled:      twoFactorDB.Enabled,
+		LastUsedStep: twoFactorDB.LastUsedStep,
+		CreatedAt:    twoFactorDB.CreatedAt,
+	}
+}
+
+func (t TwoFactorRepository) GetByWorkerID(workerID uuid.UUID) (*models.TwoFactor, error) {
+	query := `SELECT worker_id, secret, enabled, last_used_step, created_at FROM worker_two_factor WHERE worker_id = $1;`
+	twoFactorDB := &TwoFactorDB{}
+	err := t.db.Get(twoFactorDB, query, workerID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	return copyTwoFactorResultToModel(twoFactorDB), nil
+}
+
+func (t TwoFactorRepository) Save(twoFactor *models.TwoFactor) error {
+	query := `INSERT INTO worker_two_factor(worker_id, secret, enabled, last_used_step, created_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (worker_id) DO UPDATE SET secret = EXCLUDED.secret, enabled = EXCLUDED.enabled, last_used_step = EXCLUDED.last_used_step;`
+
+	_, err := t.db.Exec(query, twoFactor.WorkerID, twoFactor.Secret, twoFactor.Enabled, twoFactor.LastUsedStep, twoFactor.CreatedAt)
+	if err != nil {
+		return classify(err, repository_errors.InsertError)
+	}
+
+	return nil
+}
+
+func (t TwoFactorRepository) Delete(workerID uuid.UUID) error {
+	tx, err := t.db.Begin()
+	if err != nil {
+		return classify(err, repository_errors.TransactionBeginError)
+	}
+
+	_, err = tx.Exec(`DELETE FROM worker_recovery_codes WHERE worker_id = $1;`, workerID)
+	if err != nil {
+		err := tx.Rollback()
+		if err != nil {
+			return repository_errors.TransactionRollbackError
+		}
+		return repository_errors.DeleteError
+	}
+
+	_, err = tx.Exec(`DELETE FROM worker_two_factor WHERE worker_id = $1;`, workerID)
+	if err != nil {
+		err := tx.Rollback()
+		if err != nil {
+			return repository_errors.TransactionRollbackError
+		}
+		return repository_errors.DeleteError
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return classify(err, repository_errors.TransactionCommitError)
+	}
+
+	return nil
+}
+
+func (t TwoFactorRepository) MarkStepUsed(workerID uuid.UUID, step int64) error {
+	result, err := t.db.Exec(`UPDATE worker_two_factor SET last_used_step = $1 WHERE worker_id = $2 AND last_used_step < $1;`, step, workerID)
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+
+	if rowsAffected == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (t TwoFactorRepository) ReplaceRecoveryCodes(workerID uuid.UUID, hashes []string) error {
+	tx, err := t.db.Begin()
+	if err != nil {
+		return classify(err, repository_errors.TransactionBeginError)
+	}
+
+	_, err = tx.Exec(`DELETE FROM worker_recovery_codes WHERE worker_id = $1;`, workerID)
+	if err != nil {
+		err := tx.Rollback()
+		if err != nil {
+			return repository_errors.TransactionRollbackError
+		}
+		return repository_errors.DeleteError
+	}
+
+	for _, hash := range hashes {
+		_, err = tx.Exec(`INSERT INTO worker_recovery_codes(worker_id, code_hash) VALUES ($1, $2);`, workerID, hash)
+		if err != nil {
+			err := tx.Rollback()
+			if err != nil {
+				return repository_errors.TransactionRollbackError
+			}
+			return repository_errors.InsertError
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return classify(err, repository_errors.TransactionCommitError)
+	}
+
+	return nil
+}
+
+func (t TwoFactorRepository) ConsumeRecoveryCode(workerID uuid.UUID, hash string) error {
+	result, err := t.db.Exec(`DELETE FROM worker_recovery_codes WHERE worker_id = $1 AND code_hash = $2;`, workerID, hash)
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	if rowsAffected == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (t TwoFactorRepository) CountRecoveryCodes(workerID uuid.UUID) (int, error) {
+	var count int
+	err := t.db.Get(&count, `SELECT COUNT(*) FROM worker_recovery_codes WHERE worker_id = $1;`, workerID)
+	if err != nil {
+		return 0, classify(err, repository_errors.SelectError)
+	}
+
+	return count, nil
+}
@@ -12,14 +12,15 @@ import (
 )
 
 type WorkerDB struct {
-	ID          uuid.UUID `db:"id"`
-	Name        string    `db:"name"`
-	Surname     string    `db:"surname"`
-	Address     string    `db:"address"`
-	PhoneNumber string    `db:"phone_number"`
-	Email       string    `db:"email"`
-	Role        int       `db:"role"`
-	Password    string    `db:"password"`
+	ID          uuid.UUID    `db:"id"`
+	Name        string       `db:"name"`
+	Surname     string       `db:"surname"`
+	Address     string       `db:"address"`
+	PhoneNumber string       `db:"phone_number"`
+	Email       string       `db:"email"`
+	Role        int          `db:"role"`
+	Password    string       `db:"password"`
+	DeletedAt   sql.NullTime `db:"deleted_at"`
 }
 
 type WorkerRepository struct {
@@ -40,6 +41,7 @@ func copyWorkerResultToModel(workerDB *WorkerDB) *models.Worker {
 		Email:       workerDB.Email,
 		Role:        workerDB.Role,
 		Password:    workerDB.Password,
+		DeletedAt:   workerDB.DeletedAt.Time,
 	}
 }
 
@@ -54,7 +56,7 @@ func (w WorkerRepository) Create(worker *models.Worker) (*models.Worker, error)
 	err := w.db.QueryRow(query, worker.Name, worker.Surname, worker.Address, worker.PhoneNumber, worker.Email, worker.Role, worker.Password).Scan(&workerID)
 
 	if err != nil {
-		return nil, repository_errors.InsertError
+		return nil, classify(err, repository_errors.InsertError)
 	}
 
 	return &models.Worker{
@@ -79,17 +81,37 @@ func (w WorkerRepository) Update(worker *models.Worker) (*models.Worker, error)
 	var updatedWorker models.Worker
 	err := w.db.QueryRow(query, worker.Name, worker.Surname, worker.Address, worker.PhoneNumber, worker.Email, worker.Role, worker.Password, worker.ID).Scan(&updatedWorker.ID, &updatedWorker.Name, &updatedWorker.Surname, &updatedWorker.Address, &updatedWorker.PhoneNumber, &updatedWorker.Email, &updatedWorker.Role, &updatedWorker.Password)
 	if err != nil {
-		return nil, repository_errors.UpdateError
+		return nil, classify(err, repository_errors.UpdateError)
 	}
 	return &updatedWorker, nil
 }
 
 func (w WorkerRepository) Delete(id uuid.UUID) error {
-	query := `DELETE FROM workers WHERE id = $1;`
+	query := `UPDATE workers SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL;`
+	result, err := w.db.Exec(query, id)
+
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (w WorkerRepository) Restore(id uuid.UUID) error {
+	query := `UPDATE workers SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL;`
 	result, err := w.db.Exec(query, id)
 
 	if err != nil {
-		return repository_errors.DeleteError
+		return classify(err, repository_errors.UpdateError)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -98,7 +120,7 @@ func (w WorkerRepository) Delete(id uuid.UUID) error {
 	}
 
 	if rowsAffected == 0 {
-		return errors.New("no worker found to delete")
+		return repository_errors.DoesNotExist
 	}
 
 	return nil
@@ -112,7 +134,7 @@ func (w WorkerRepository) GetWorkerByID(id uuid.UUID) (*models.Worker, error) {
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, repository_errors.DoesNotExist
 	} else if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	workerModels := copyWorkerResultToModel(workerDB)
@@ -121,13 +143,13 @@ func (w WorkerRepository) GetWorkerByID(id uuid.UUID) (*models.Worker, error) {
 }
 
 func (w WorkerRepository) GetAllWorkers() ([]models.Worker, error) {
-	query := `SELECT id, name, surname, address, phone_number, email, role FROM workers;`
+	query := `SELECT id, name, surname, address, phone_number, email, role FROM workers WHERE deleted_at IS NULL;`
 	var workerDB []WorkerDB
 
 	err := w.db.Select(&workerDB, query)
 
 	if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	var workerModels []models.Worker
@@ -140,14 +162,14 @@ func (w WorkerRepository) GetAllWorkers() ([]models.Worker, error) {
 }
 
 func (w WorkerRepository) GetWorkerByEmail(email string) (*models.Worker, error) {
-	query := `SELECT * FROM workers WHERE email = $1;`
+	query := `SELECT * FROM workers WHERE email = $1 AND deleted_at IS NULL;`
 	workerDB := &WorkerDB{}
 	err := w.db.Get(workerDB, query, email)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, repository_errors.DoesNotExist
 	} else if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	workerModels := copyWorkerResultToModel(workerDB)
@@ -155,14 +177,33 @@ func (w WorkerRepository) GetWorkerByEmail(email string) (*models.Worker, error)
 	return workerModels, nil
 }
 
+func (w WorkerRepository) GetDeletedWorkers() ([]models.Worker, error) {
+	query := `SELECT * FROM workers WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC;`
+	var workerDB []WorkerDB
+
+	err := w.db.Select(&workerDB, query)
+
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var workerModels []models.Worker
+	for i := range workerDB {
+		worker := copyWorkerResultToModel(&workerDB[i])
+		workerModels = append(workerModels, *worker)
+	}
+
+	return workerModels, nil
+}
+
 func (w WorkerRepository) GetWorkersByRole(role int) ([]models.Worker, error) {
-	query := `SELECT * FROM workers WHERE role = $1;`
+	query := `SELECT * FROM workers WHERE role = $1 AND deleted_at IS NULL;`
 	var workerDB []WorkerDB
 
 	err := w.db.Select(&workerDB, query, role)
 
 	if err != nil {
-		return nil, repository_errors.SelectError
+		return nil, classify(err, repository_errors.SelectError)
 	}
 
 	var workerModels []models.Worker
@@ -176,13 +217,15 @@ func (w WorkerRepository) GetWorkersByRole(role int) ([]models.Worker, error) {
 }
 
 func (w WorkerRepository) GetAverageOrderRate(worker *models.Worker) (float64, error) {
-	query := `SELECT AVG(rate) FROM orders WHERE worker_id = $1 AND status = 3 AND rate != 0;`
+	// archived orders keep counting
+	query := `SELECT COALESCE(AVG(rate), 0) FROM (SELECT worker_id, status, rate FROM orders UNION ALL SELECT worker_id, status, rate FROM orders_archive) history
+		WHERE worker_id = $1 AND status = 3 AND rate != 0;`
 	var averageRate float64
 
 	err := w.db.Get(&averageRate, query, worker.ID)
 
 	if err != nil {
-		return 0, repository_errors.SelectError
+		return 0, classify(err, repository_errors.SelectError)
 	}
 
 	return averageRate, nil
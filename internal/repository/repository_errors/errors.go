@@ -8,9 +8,16 @@ var (
 	SelectError              = errors.New("DB ERROR: Select operation was not successful")
 	UpdateError              = errors.New("DB ERROR: Update operation was not successful")
 	DoesNotExist             = errors.New("GET operation has failed. Such row does not exist")
+	AlreadyExists            = errors.New("DB ERROR: Such row already exists")
+	VersionConflict          = errors.New("DB ERROR: The row was changed by a concurrent update")
 	TransactionBeginError    = errors.New("DB ERROR: Transaction begin error")
 	TransactionRollbackError = errors.New("DB ERROR: Transaction rollback error")
 	TransactionCommitError   = errors.New("DB ERROR: Transaction commit error")
 
 	ConnectionError = errors.New("DB ERROR: Connection error")
+	// Unavailable marks errors caused by a lost connection, a busy database or a timeout.
+	// The same operation may succeed when it is retried later.
+	Unavailable = errors.New("DB ERROR: Database is temporarily unavailable")
+
+	UnknownMigration = errors.New("DB ERROR: Database has migrations unknown to this version of the application")
 )
@@ -0,0 +1,72 @@
+package repository_errors
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// transientError is an error of kind caused by an unavailable database. errors.Is matches
+// both kind and Unavailable, so callers checking the kind are not affected.
+type transientError struct {
+	kind  error
+	cause error
+}
+
+func (e *transientError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", e.kind, Unavailable, e.cause)
+}
+
+func (e *transientError) Is(target error) bool {
+	return target == Unavailable || target == e.kind
+}
+
+func (e *transientError) Unwrap() error {
+	return e.cause
+}
+
+// Transient returns kind, marked as Unavailable when isTransient reports cause as transient.
+func Transient(cause error, kind error, isTransient func(error) bool) error {
+	if cause == nil || !isTransient(cause) {
+		return kind
+	}
+
+	return &transientError{kind: kind, cause: cause}
+}
+
+// unavailableSQLStates are the SQLSTATE codes of an unavailable database besides the class 08
+// of connection exceptions: too many connections, a cancelled statement and a shutting down server.
+var unavailableSQLStates = map[string]bool{
+	"53300": true,
+	"57014": true,
+	"57P01": true,
+	"57P02": true,
+	"57P03": true,
+}
+
+// IsConnectionError reports errors of the network and of database/sql drivers caused by a lost
+// or refused connection or by a timeout.
+func IsConnectionError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// the errors of the postgres drivers report their SQLSTATE code
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		code := stateErr.SQLState()
+		return strings.HasPrefix(code, "08") || unavailableSQLStates[code]
+	}
+
+	return false
+}
@@ -0,0 +1,10 @@
+package repository_interfaces
+
+import "lab3/internal/models"
+
+// IAuditRepository stores audit entries. Entries are never updated or deleted.
+type IAuditRepository interface {
+	Create(entry *models.AuditEntry) error
+	// Filter returns matching entries, newest first.
+	Filter(filter models.AuditFilter) ([]models.AuditEntry, error)
+}
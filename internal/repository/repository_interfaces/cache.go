@@ -0,0 +1,10 @@
+package repository_interfaces
+
+import "lab3/internal/models"
+
+// ICache is a read-through cache of a repository.
+type ICache interface {
+	Stats() models.CacheStats
+	// Invalidate drops all cached results, so that the next reads go to the database
+	Invalidate()
+}
@@ -3,9 +3,21 @@ package repository_interfaces
 import "lab3/internal/models"
 
 type ICategoryRepository interface {
+	// GetAll returns the not archived categories ordered by position, then by id
 	GetAll() ([]models.Category, error)
 	GetByID(id int) (*models.Category, error)
+	// Create assigns the next free id and places the category after all the others
 	Create(category *models.Category) (*models.Category, error)
+	// Update changes the name, the parent and the hidden flag. It fails with UpdateError when the
+	// category does not exist
 	Update(category *models.Category) (*models.Category, error)
+	// Reorder gives the categories the positions of their ids in ids. It fails with UpdateError and
+	// changes nothing when one of them does not exist
+	Reorder(ids []int) error
+	// Delete archives the category. Archived categories are still returned by GetByID
 	Delete(id int) error
+	// Restore fails with DoesNotExist when the category is not archived
+	Restore(id int) error
+	// GetDeleted returns the archived categories, the most recently archived first
+	GetDeleted() ([]models.Category, error)
 }
@@ -0,0 +1,18 @@
+package repository_interfaces
+
+import (
+	"github.com/google/uuid"
+	"lab3/internal/models"
+)
+
+// IInvoiceRepository stores the invoices of completed orders. Invoices are never updated or deleted.
+type IInvoiceRepository interface {
+	// Create numbers the invoice next to the last issued one. It fails with AlreadyExists when the order
+	// already has an invoice
+	Create(invoice *models.Invoice) (*models.Invoice, error)
+	GetByID(id uuid.UUID) (*models.Invoice, error)
+	// GetByOrderID fails with DoesNotExist when no invoice was issued for the order
+	GetByOrderID(orderID uuid.UUID) (*models.Invoice, error)
+	// GetAll returns the invoices, the latest first
+	GetAll() ([]models.Invoice, error)
+}
@@ -0,0 +1,18 @@
+package repository_interfaces
+
+import (
+	"lab3/internal/models"
+	"time"
+)
+
+type ILoginAttemptRepository interface {
+	GetByKey(key string) (*models.LoginAttempt, error)
+	// RegisterFailure atomically increments the failure counter. A counter whose last failure
+	// happened before resetBefore or whose lockout has ended by at starts again from one, the ended lockout is cleared.
+	RegisterFailure(key string, at time.Time, resetBefore time.Time) (*models.LoginAttempt, error)
+	// Lock fails with DoesNotExist when no failure is registered for the key
+	Lock(key string, until time.Time) error
+	Delete(key string) error
+	// GetLocked returns the keys locked after at, the longest locked first
+	GetLocked(at time.Time) ([]models.LoginAttempt, error)
+}
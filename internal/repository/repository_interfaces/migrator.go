@@ -0,0 +1,12 @@
+package repository_interfaces
+
+import "lab3/internal/models"
+
+type IMigrator interface {
+	// Up applies all pending migrations in order and returns the applied ones.
+	Up() ([]models.Migration, error)
+	// Down reverts the latest applied migration and returns it, DoesNotExist if nothing is applied.
+	Down() (*models.Migration, error)
+	// Status returns every known migration ordered by version.
+	Status() ([]models.Migration, error)
+}
@@ -0,0 +1,15 @@
+package repository_interfaces
+
+import (
+	"github.com/google/uuid"
+	"lab3/internal/models"
+	"time"
+)
+
+type IOneTimeTokenRepository interface {
+	Create(token *models.OneTimeToken) error
+	// Consume deletes and returns a token with the given hash and purpose that has not expired at the given time.
+	Consume(hash string, purpose string, at time.Time) (*models.OneTimeToken, error)
+	DeleteByUserID(purpose string, userID uuid.UUID) error
+	DeleteByWorkerID(purpose string, workerID uuid.UUID) error
+}
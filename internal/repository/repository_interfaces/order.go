@@ -3,19 +3,38 @@ package repository_interfaces
 import (
 	"github.com/google/uuid"
 	"lab3/internal/models"
+	"time"
 )
 
 type IOrderRepository interface {
+	// Create writes the order with its tasks at once and sets the version to 1. The client must exist,
+	// a key the client has already used fails with AlreadyExists.
 	Create(order *models.Order, orderedTasks []models.OrderedTask) (*models.Order, error)
+	// Delete removes the order together with its tasks
 	Delete(id uuid.UUID) error
+	// Update writes the order if its version is still order.Version and increases the version.
+	// A changed version fails with VersionConflict, a missing order with DoesNotExist.
 	Update(order *models.Order) (*models.Order, error)
+	// GetOrderByID also finds archived orders, they are returned with Archived set
 	GetOrderByID(id uuid.UUID) (*models.Order, error)
+	// GetTasksInOrder returns no tasks and no error for an order without tasks. Archived orders keep their tasks.
 	GetTasksInOrder(id uuid.UUID) ([]models.Task, error)
+	GetOrderByIdempotencyKey(userID uuid.UUID, key string) (*models.Order, error)
+	// GetCurrentOrderByUserID returns the latest created order of the client
 	GetCurrentOrderByUserID(id uuid.UUID) (*models.Order, error)
 	GetAllOrdersByUserID(id uuid.UUID) ([]models.Order, error)
+	// AddTaskToOrder adds the task with the quantity of 1. It fails with InsertError when the order or the task does not exist
 	AddTaskToOrder(orderID uuid.UUID, taskID uuid.UUID) error
 	RemoveTaskFromOrder(orderID uuid.UUID, taskID uuid.UUID) error
 	UpdateTaskQuantity(orderID uuid.UUID, taskID uuid.UUID, quantity int) error
+	// GetTaskQuantity also reads the tasks of archived orders
 	GetTaskQuantity(orderID uuid.UUID, taskID uuid.UUID) (int, error)
+	// Filter matches every parameter, a field to a value or to one of comma separated values.
+	// "null" and "not null" match an empty and a set field.
 	Filter(params map[string]string) ([]models.Order, error)
+	// History matches the parameters like Filter, but among both the current and the archived orders.
+	History(params map[string]string) ([]models.Order, error)
+	// Archive moves the completed and cancelled orders closed before closedBefore, together with their
+	// tasks, to the archive storage at once. It returns the number of moved orders.
+	Archive(closedBefore time.Time) (int, error)
 }
@@ -0,0 +1,20 @@
+package repository_interfaces
+
+import (
+	"github.com/google/uuid"
+	"lab3/internal/models"
+	"time"
+)
+
+type ISessionRepository interface {
+	// Save creates the session or replaces the one with the same id, keeping its creation time
+	Save(session *models.Session) error
+	GetSessionByID(id string) (*models.Session, error)
+	// UpdateLastActivity fails with DoesNotExist when the session does not exist
+	UpdateLastActivity(id string, lastActivity time.Time) error
+	Delete(id string) error
+	DeleteByUserID(userID uuid.UUID) error
+	DeleteByWorkerID(workerID uuid.UUID) error
+	// DeleteExpired deletes the sessions idle since before idleBefore or created before createdBefore
+	DeleteExpired(idleBefore time.Time, createdBefore time.Time) error
+}
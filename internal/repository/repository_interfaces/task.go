@@ -3,14 +3,40 @@ package repository_interfaces
 import (
 	"github.com/google/uuid"
 	"lab3/internal/models"
+	"time"
 )
 
 type ITaskRepository interface {
+	// Create fails with InsertError when the category of the task does not exist
 	Create(task *models.Task) (*models.Task, error)
+	// Delete archives the task. Archived tasks are still returned by GetTaskByID
 	Delete(id uuid.UUID) error
+	// Restore fails with DoesNotExist when the task is not archived
+	Restore(id uuid.UUID) error
+	// Update fails with UpdateError when the task or its category does not exist
 	Update(task *models.Task) (*models.Task, error)
 	GetTaskByID(id uuid.UUID) (*models.Task, error)
 	GetAllTasks() ([]models.Task, error)
 	GetTasksInCategory(category int) ([]models.Task, error)
+	// MoveToCategory moves every task of category from, the archived ones too, to category to and returns
+	// how many were moved. It fails with UpdateError when category to does not exist
+	MoveToCategory(from, to int) (int, error)
 	GetTaskByName(name string) (*models.Task, error)
+	// GetDeletedTasks returns the archived tasks, the most recently archived first
+	GetDeletedTasks() ([]models.Task, error)
+	// Search returns the not archived tasks whose names have every word of query in any of its
+	// forms, the most relevant first. A query without words finds nothing
+	Search(query string) ([]models.Task, error)
+	// SchedulePrice records a price of the task in force from price.EffectiveFrom. A price of the same task from
+	// the same moment is replaced. It fails with InsertError when the task does not exist
+	SchedulePrice(price *models.TaskPrice) (*models.TaskPrice, error)
+	// DeletePrice fails with DoesNotExist when there is no such price
+	DeletePrice(id uuid.UUID) error
+	// GetPrices returns the prices of the task, the earliest first
+	GetPrices(taskID uuid.UUID) ([]models.TaskPrice, error)
+	// GetUpcomingPrices returns the prices of all tasks that come in force after moment, the earliest first
+	GetUpcomingPrices(moment time.Time) ([]models.TaskPrice, error)
+	// ApplyPrices sets the price of every task to its latest price in force at moment and returns
+	// how many tasks changed their price. Tasks without prices in force keep their price
+	ApplyPrices(moment time.Time) (int, error)
 }
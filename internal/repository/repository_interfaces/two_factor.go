@@ -0,0 +1,22 @@
+package repository_interfaces
+
+import (
+	"github.com/google/uuid"
+	"lab3/internal/models"
+)
+
+type ITwoFactorRepository interface {
+	GetByWorkerID(workerID uuid.UUID) (*models.TwoFactor, error)
+	// Save creates the settings of the worker or replaces them
+	Save(twoFactor *models.TwoFactor) error
+	// Delete removes the settings together with the recovery codes
+	Delete(workerID uuid.UUID) error
+	// MarkStepUsed records the time step of an accepted code. It fails with DoesNotExist when
+	// the step is not newer than the last used one, so that a code cannot be replayed.
+	MarkStepUsed(workerID uuid.UUID, step int64) error
+
+	ReplaceRecoveryCodes(workerID uuid.UUID, hashes []string) error
+	// ConsumeRecoveryCode deletes the code, DoesNotExist if the worker has no such code
+	ConsumeRecoveryCode(workerID uuid.UUID, hash string) error
+	CountRecoveryCodes(workerID uuid.UUID) (int, error)
+}
@@ -6,10 +6,17 @@ import (
 )
 
 type IUserRepository interface {
+	// Create fails with InsertError when the email is taken, archived users included
 	Create(user *models.User) (*models.User, error)
+	// Update fails with UpdateError when the user does not exist
 	Update(user *models.User) (*models.User, error)
+	// Delete archives the user. Archived users are still returned by GetUserByID, their orders are kept
 	Delete(id uuid.UUID) error
+	// Restore fails with DoesNotExist when the user is not archived
+	Restore(id uuid.UUID) error
 	GetUserByID(id uuid.UUID) (*models.User, error)
 	GetAllUsers() ([]models.User, error)
 	GetUserByEmail(email string) (*models.User, error)
+	// GetDeletedUsers returns the archived users, the most recently archived first
+	GetDeletedUsers() ([]models.User, error)
 }
@@ -6,13 +6,21 @@ import (
 )
 
 type IWorkerRepository interface {
+	// Create fails with InsertError when the email is taken, archived workers included
 	Create(worker *models.Worker) (*models.Worker, error)
+	// Update fails with UpdateError when the worker does not exist
 	Update(worker *models.Worker) (*models.Worker, error)
+	// Delete archives the worker. Archived workers are still returned by GetWorkerByID
 	Delete(id uuid.UUID) error
+	// Restore fails with DoesNotExist when the worker is not archived
+	Restore(id uuid.UUID) error
 	GetWorkerByID(id uuid.UUID) (*models.Worker, error)
 	GetAllWorkers() ([]models.Worker, error)
 	GetWorkerByEmail(email string) (*models.Worker, error)
+	// GetDeletedWorkers returns the archived workers, the most recently archived first
+	GetDeletedWorkers() ([]models.Worker, error)
 
 	GetWorkersByRole(role int) ([]models.Worker, error)
+	// GetAverageOrderRate averages the rated completed orders of the worker, 0 if there are none
 	GetAverageOrderRate(worker *models.Worker) (float64, error)
 }
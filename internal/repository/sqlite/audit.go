@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"fmt"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type AuditEntryDB struct {
+	ID         uuid.UUID `db:"id"`
+	ActorID    uuid.UUID `db:"actor_id"`
+	Action     string    `db:"action"`
+	TargetType string    `db:"target_type"`
+	TargetID   string    `db:"target_id"`
+	Before     string    `db:"before"`
+	After      string    `db:"after"`
+	IP         string    `db:"ip"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+type AuditRepository struct {
+	db *sqlx.DB
+}
+
+func NewAuditRepository(db *sqlx.DB) repository_interfaces.IAuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func copyAuditEntryResultToModel(entryDB *AuditEntryDB) *models.AuditEntry {
+	return &models.AuditEntry{
+		ID:         entryDB.ID,
+		ActorID:    entryDB.ActorID,
+		Action:     entryDB.Action,
+		TargetType: entryDB.TargetType,
+		TargetID:   entryDB.TargetID,
+		Before:     entryDB.Before,
+		After:      entryDB.After,
+		IP:         entryDB.IP,
+		CreatedAt:  entryDB.CreatedAt,
+	}
+}
+
+func (a AuditRepository) Create(entry *models.AuditEntry) error {
+	query := `INSERT INTO audit_log(id, actor_id, action, target_type, target_id, before, after, ip, created_at) VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9);`
+
+	id := uuid.New()
+	_, err := a.db.Exec(query, id, entry.ActorID, entry.Action, entry.TargetType, entry.TargetID, entry.Before, entry.After, entry.IP, utc(entry.CreatedAt))
+	if err != nil {
+		return classify(err, repository_errors.InsertError)
+	}
+
+	entry.ID = id
+	return nil
+}
+
+func (a AuditRepository) Filter(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.ActorID != uuid.Nil {
+		addCondition("actor_id = ?%d", filter.ActorID)
+	}
+	if filter.Action != "" {
+		addCondition("action = ?%d", filter.Action)
+	}
+	if filter.TargetType != "" {
+		addCondition("target_type = ?%d", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		addCondition("target_id = ?%d", filter.TargetID)
+	}
+	if !filter.From.IsZero() {
+		addCondition("created_at >= ?%d", utc(filter.From))
+	}
+	if !filter.To.IsZero() {
+		addCondition("created_at < ?%d", utc(filter.To))
+	}
+
+	var query strings.Builder
+	query.WriteString("SELECT id, actor_id, action, target_type, target_id, before, after, ip, created_at FROM audit_log")
+	if len(conditions) > 0 {
+		query.WriteString(" WHERE ")
+		query.WriteString(strings.Join(conditions, " AND "))
+	}
+	query.WriteString(" ORDER BY created_at DESC")
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query.WriteString(fmt.Sprintf(" LIMIT ?%d", len(args)))
+	}
+
+	var entriesDB []AuditEntryDB
+	err := a.db.Select(&entriesDB, query.String(), args...)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	entries := make([]models.AuditEntry, len(entriesDB))
+	for i := range entriesDB {
+		entries[i] = *copyAuditEntryResultToModel(&entriesDB[i])
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,126 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type InvoiceDB struct {
+	ID       uuid.UUID `db:"id"`
+	Number   int       `db:"number"`
+	OrderID  uuid.UUID `db:"order_id"`
+	UserID   uuid.UUID `db:"user_id"`
+	IssuedAt time.Time `db:"issued_at"`
+	Seller   string    `db:"seller"`
+	Buyer    string    `db:"buyer"`
+	Address  string    `db:"address"`
+	Lines    string    `db:"lines"`
+	Total    float64   `db:"total"`
+}
+
+type InvoiceRepository struct {
+	db *sqlx.DB
+}
+
+func NewInvoiceRepository(db *sqlx.DB) repository_interfaces.IInvoiceRepository {
+	return &InvoiceRepository{db: db}
+}
+
+func copyInvoiceResultToModel(invoiceDB *InvoiceDB) (*models.Invoice, error) {
+	invoice := &models.Invoice{
+		ID:       invoiceDB.ID,
+		Number:   invoiceDB.Number,
+		OrderID:  invoiceDB.OrderID,
+		UserID:   invoiceDB.UserID,
+		IssuedAt: invoiceDB.IssuedAt,
+		Buyer:    invoiceDB.Buyer,
+		Address:  invoiceDB.Address,
+		Total:    invoiceDB.Total,
+	}
+
+	err := json.Unmarshal([]byte(invoiceDB.Seller), &invoice.Seller)
+	if err != nil {
+		return nil, repository_errors.SelectError
+	}
+	err = json.Unmarshal([]byte(invoiceDB.Lines), &invoice.Lines)
+	if err != nil {
+		return nil, repository_errors.SelectError
+	}
+
+	return invoice, nil
+}
+
+func (i InvoiceRepository) Create(invoice *models.Invoice) (*models.Invoice, error) {
+	seller, err := json.Marshal(invoice.Seller)
+	if err != nil {
+		return nil, repository_errors.InsertError
+	}
+	lines, err := json.Marshal(invoice.Lines)
+	if err != nil {
+		return nil, repository_errors.InsertError
+	}
+
+	// writers take the database lock one by one, so the next number is read and taken at once
+	query := `INSERT INTO invoices(id, number, order_id, user_id, issued_at, seller, buyer, address, lines, total)
+		SELECT ?1, coalesce(max(number), 0) + 1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9 FROM invoices WHERE true
+		ON CONFLICT (order_id) DO NOTHING RETURNING number;`
+
+	created := *invoice
+	created.ID = uuid.New()
+	err = i.db.QueryRow(query, created.ID, invoice.OrderID, invoice.UserID, utc(invoice.IssuedAt), string(seller), invoice.Buyer, invoice.Address, string(lines), invoice.Total).Scan(&created.Number)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository_errors.AlreadyExists
+	} else if err != nil {
+		return nil, classify(err, repository_errors.InsertError)
+	}
+
+	return &created, nil
+}
+
+func (i InvoiceRepository) get(query string, arg interface{}) (*models.Invoice, error) {
+	invoiceDB := &InvoiceDB{}
+	err := i.db.Get(invoiceDB, query, arg)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	return copyInvoiceResultToModel(invoiceDB)
+}
+
+func (i InvoiceRepository) GetByID(id uuid.UUID) (*models.Invoice, error) {
+	return i.get(`SELECT * FROM invoices WHERE id = ?1;`, id)
+}
+
+func (i InvoiceRepository) GetByOrderID(orderID uuid.UUID) (*models.Invoice, error) {
+	return i.get(`SELECT * FROM invoices WHERE order_id = ?1;`, orderID)
+}
+
+func (i InvoiceRepository) GetAll() ([]models.Invoice, error) {
+	var invoicesDB []InvoiceDB
+	err := i.db.Select(&invoicesDB, `SELECT * FROM invoices ORDER BY number DESC;`)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	invoices := make([]models.Invoice, 0, len(invoicesDB))
+	for j := range invoicesDB {
+		invoice, err := copyInvoiceResultToModel(&invoicesDB[j])
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, *invoice)
+	}
+
+	return invoices, nil
+}
@@ -0,0 +1,12 @@
+drop table if exists audit_log;
+drop table if exists worker_recovery_codes;
+drop table if exists worker_two_factor;
+drop table if exists one_time_tokens;
+drop table if exists login_attempts;
+drop table if exists sessions;
+drop table if exists categories;
+drop table if exists order_contains_tasks;
+drop table if exists tasks;
+drop table if exists orders;
+drop table if exists workers;
+drop table if exists users;
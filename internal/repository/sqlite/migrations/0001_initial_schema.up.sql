@@ -0,0 +1,142 @@
+-- The schema of the postgres backend with all of its migrations applied. Identifiers of users, workers,
+-- orders, tasks and audit entries are uuids generated by the application and stored as text, times are
+-- stored in UTC, so that their text compares in time order.
+create table users
+(
+    id             text primary key,
+    name           text,
+    surname        text,
+    email          text unique,
+    phone_number   text,
+    address        text,
+    password       text,
+    email_verified boolean   default false,
+    deleted_at     timestamp default null
+);
+
+create table workers
+(
+    id           text primary key,
+    name         text,
+    surname      text,
+    email        text unique,
+    phone_number text,
+    address      text,
+    password     text,
+    role         int,
+    deleted_at   timestamp default null
+);
+
+create table orders
+(
+    id              text primary key,
+    worker_id       text references workers (id) on delete set null default null,
+    user_id         text references users (id) on delete set null   default null,
+    status          int                                             default 0,
+    address         text,
+    deadline        timestamp,
+    creation_date   timestamp                                       default current_timestamp,
+    rate            int                                             default 0,
+    -- idempotency_key identifies a submission of the order form, so that a repeated submission does not create a second order
+    idempotency_key text                                            default null,
+    -- version is increased by every update of an order, so that a write based on a stale read is rejected.
+    version         int not null                                    default 1,
+    unique (user_id, idempotency_key)
+);
+create index orders_user_id_idx on orders (user_id);
+create index orders_worker_id_idx on orders (worker_id);
+create index orders_status_idx on orders (status);
+
+create table tasks
+(
+    id               text primary key,
+    name             text,
+    price_per_single real,
+    category         int,
+    deleted_at       timestamp default null
+);
+create index tasks_category_idx on tasks (category);
+
+create table order_contains_tasks
+(
+    id       integer primary key autoincrement,
+    order_id text references orders (id),
+    task_id  text references tasks (id),
+    quantity int default 1
+);
+create index order_contains_tasks_order_id_idx on order_contains_tasks (order_id);
+create index order_contains_tasks_task_id_idx on order_contains_tasks (task_id);
+
+create table categories
+(
+    id         integer primary key autoincrement,
+    name       text,
+    deleted_at timestamp default null
+);
+
+create table sessions
+(
+    id            text primary key,
+    user_id       text references users (id) on delete cascade   default null,
+    worker_id     text references workers (id) on delete cascade default null,
+    data          text,
+    created_at    timestamp                                      default current_timestamp,
+    last_activity timestamp                                      default current_timestamp
+);
+create index sessions_user_id_idx on sessions (user_id);
+create index sessions_worker_id_idx on sessions (worker_id);
+
+create table login_attempts
+(
+    key          text primary key,
+    failures     int       default 0,
+    last_failure timestamp default current_timestamp,
+    locked_until timestamp default null
+);
+
+create table one_time_tokens
+(
+    hash       text primary key,
+    purpose    text      not null,
+    user_id    text references users (id) on delete cascade   default null,
+    worker_id  text references workers (id) on delete cascade default null,
+    expires_at timestamp not null,
+    created_at timestamp                                      default current_timestamp
+);
+create index one_time_tokens_user_id_idx on one_time_tokens (user_id);
+create index one_time_tokens_worker_id_idx on one_time_tokens (worker_id);
+
+create table worker_two_factor
+(
+    worker_id      text primary key references workers (id) on delete cascade,
+    secret         text not null,
+    enabled        boolean   default false,
+    last_used_step bigint    default 0,
+    created_at     timestamp default current_timestamp
+);
+
+create table worker_recovery_codes
+(
+    worker_id text references workers (id) on delete cascade,
+    code_hash text not null,
+    primary key (worker_id, code_hash)
+);
+
+create table audit_log
+(
+    id          text primary key,
+    actor_id    text      not null,
+    action      text      not null,
+    target_type text      not null,
+    target_id   text      not null,
+    before      text      default '',
+    after       text      default '',
+    ip          text      default '',
+    created_at  timestamp default current_timestamp
+);
+create index audit_log_created_at_idx on audit_log (created_at);
+create index audit_log_target_idx on audit_log (target_type, target_id);
+
+-- entries are immutable
+create trigger audit_log_no_update before update on audit_log begin select raise(ignore); end;
+create trigger audit_log_no_delete before delete on audit_log begin select raise(ignore); end;
@@ -0,0 +1,12 @@
+-- archived orders are moved back, so that no order is lost
+insert into orders (id, worker_id, user_id, status, address, deadline, creation_date, rate, idempotency_key, version, closed_at)
+select id, worker_id, user_id, status, address, deadline, creation_date, rate, idempotency_key, version, closed_at
+from orders_archive;
+insert into order_contains_tasks (order_id, task_id, quantity)
+select order_id, task_id, quantity
+from order_contains_tasks_archive;
+
+drop table order_contains_tasks_archive;
+drop table orders_archive;
+drop index orders_closed_at_idx;
+alter table orders drop column closed_at;
@@ -0,0 +1,15 @@
+pragma defer_foreign_keys = on;
+create table tasks_old
+(
+    id               text primary key,
+    name             text,
+    price_per_single real,
+    category         int,
+    deleted_at       timestamp default null
+);
+insert into tasks_old (id, name, price_per_single, category, deleted_at)
+select id, name, price_per_single, category, deleted_at
+from tasks;
+drop table tasks;
+alter table tasks_old rename to tasks;
+create index tasks_category_idx on tasks (category);
@@ -0,0 +1,33 @@
+-- Tasks reference their category. Categories that tasks refer to but that were never created get the
+-- names the application used to have built in, the rest a placeholder name a manager can change.
+insert into categories (id, name)
+select distinct tasks.category, coalesce(builtin.name, 'Категория ' || tasks.category)
+from tasks
+         left join (select 1 as id, 'Генеральная уборка' as name
+                    union all select 2, 'Послестроительная уборка'
+                    union all select 3, 'Мытье окон'
+                    union all select 4, 'Ежедневная уборка офисов'
+                    union all select 5, 'Поддерживающая уборка'
+                    union all select 6, 'Химчистка ковров и мебели'
+                    union all select 7, 'Уход за твердыми полами'
+                    union all select 8, 'Глубинная Эко Чистка') builtin on builtin.id = tasks.category
+where tasks.category is not null
+  and not exists (select 1 from categories where categories.id = tasks.category);
+
+-- SQLite can not add a foreign key to a table, so the table is rebuilt. The order lines keep referring
+-- to the tasks while the old table is dropped, their check is deferred to the end of the migration.
+pragma defer_foreign_keys = on;
+create table tasks_new
+(
+    id               text primary key,
+    name             text,
+    price_per_single real,
+    category         int references categories (id),
+    deleted_at       timestamp default null
+);
+insert into tasks_new (id, name, price_per_single, category, deleted_at)
+select id, name, price_per_single, category, deleted_at
+from tasks;
+drop table tasks;
+alter table tasks_new rename to tasks;
+create index tasks_category_idx on tasks (category);
@@ -0,0 +1,4 @@
+drop index categories_parent_id_idx;
+alter table categories drop column hidden;
+alter table categories drop column position;
+alter table categories drop column parent_id;
@@ -0,0 +1,16 @@
+-- task_prices is the price history of tasks. A price is in force from effective_from until the next price of the task,
+-- tasks.price_per_single keeps the price in force now. The current prices start the history of the existing tasks.
+create table task_prices
+(
+    id             text primary key,
+    task_id        text      not null references tasks (id),
+    price          real      not null,
+    effective_from timestamp not null,
+    unique (task_id, effective_from)
+);
+create index task_prices_effective_from_idx on task_prices (effective_from);
+
+-- the first price of an existing task takes the id of the task, which is a unique uuid too
+insert into task_prices (id, task_id, price, effective_from)
+select id, id, price_per_single, '1970-01-01 00:00:00+00:00'
+from tasks;
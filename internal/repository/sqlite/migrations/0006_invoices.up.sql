@@ -0,0 +1,20 @@
+-- invoices of completed orders. The seller requisites and the lines are JSON copied when the invoice is issued,
+-- order_id has no reference because archiving moves orders to orders_archive.
+create table invoices
+(
+    id        text primary key,
+    number    integer   not null unique,
+    order_id  text      not null unique,
+    user_id   text      not null,
+    issued_at timestamp not null,
+    seller    text      not null default '{}',
+    buyer     text      not null default '',
+    address   text      not null default '',
+    lines     text      not null default '[]',
+    total     real      not null
+);
+create index invoices_user_id_idx on invoices (user_id);
+
+-- invoices are immutable
+create trigger invoices_no_update before update on invoices begin select raise(ignore); end;
+create trigger invoices_no_delete before delete on invoices begin select raise(ignore); end;
@@ -0,0 +1,2 @@
+alter table tasks drop column tax_rate;
+alter table categories drop column tax_rate;
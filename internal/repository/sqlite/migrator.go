@@ -0,0 +1,247 @@
+package sqlite
+
+import (
+	"database/sql"
+	"embed"
+	"io/fs"
+	"lab3/internal/models"
+	"lab3/internal/repository/migrations"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+type Migrator struct {
+	db *sqlx.DB
+}
+
+func NewMigrator(db *sqlx.DB) repository_interfaces.IMigrator {
+	return &Migrator{db: db}
+}
+
+type appliedMigrationDB struct {
+	Version   int       `db:"version"`
+	Name      string    `db:"name"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+func (m Migrator) migrations() ([]migrations.SQLMigration, error) {
+	files, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	return migrations.LoadSQL(files)
+}
+
+func (m Migrator) ensureVersionTable() error {
+	query := `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    int primary key,
+		name       text not null,
+		applied_at timestamp default current_timestamp
+	);`
+
+	_, err := m.db.Exec(query)
+	if err != nil {
+		return classify(err, repository_errors.InsertError)
+	}
+
+	return nil
+}
+
+func (m Migrator) applied() (map[int]appliedMigrationDB, error) {
+	var rows []appliedMigrationDB
+	err := m.db.Select(&rows, `SELECT version, name, applied_at FROM schema_migrations;`)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	applied := make(map[int]appliedMigrationDB, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+
+	return applied, nil
+}
+
+// hasUnknownVersions reports whether the database was migrated by a newer version of the application.
+func hasUnknownVersions(all []migrations.SQLMigration, applied map[int]appliedMigrationDB) bool {
+	known := make(map[int]bool, len(all))
+	for _, migration := range all {
+		known[migration.Version] = true
+	}
+
+	for version := range applied {
+		if !known[version] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// run executes a migration step and records the change of the version in one transaction, so that
+// a failed step leaves neither the schema nor the version table changed.
+func (m Migrator) run(migration migrations.SQLMigration, up bool) error {
+	transaction, err := m.db.Begin()
+	if err != nil {
+		return classify(err, repository_errors.TransactionBeginError)
+	}
+
+	err = m.runInTransaction(transaction, migration, up)
+	if err != nil {
+		rollbackErr := transaction.Rollback()
+		if rollbackErr != nil {
+			return repository_errors.TransactionRollbackError
+		}
+		return err
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return classify(err, repository_errors.TransactionCommitError)
+	}
+
+	return nil
+}
+
+func (m Migrator) runInTransaction(transaction *sql.Tx, migration migrations.SQLMigration, up bool) error {
+	// transactions take the write lock on begin (_txlock=immediate), so another process
+	// may have run the step while this one was waiting for it
+	var count int
+	err := transaction.QueryRow(`SELECT count(*) FROM schema_migrations WHERE version = ?1;`, migration.Version).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if (count == 1) == up {
+		return nil
+	}
+
+	if up {
+		_, err = transaction.Exec(migration.Up)
+		if err != nil {
+			return err
+		}
+		_, err = transaction.Exec(`INSERT INTO schema_migrations(version, name) VALUES (?1, ?2);`, migration.Version, migration.Name)
+		return err
+	}
+
+	_, err = transaction.Exec(migration.Down)
+	if err != nil {
+		return err
+	}
+	_, err = transaction.Exec(`DELETE FROM schema_migrations WHERE version = ?1;`, migration.Version)
+	return err
+}
+
+func (m Migrator) Up() ([]models.Migration, error) {
+	all, err := m.migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.ensureVersionTable()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	if hasUnknownVersions(all, applied) {
+		return nil, repository_errors.UnknownMigration
+	}
+
+	var result []models.Migration
+	for _, migration := range all {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err = m.run(migration, true)
+		if err != nil {
+			return result, err
+		}
+
+		result = append(result, models.Migration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Applied:   true,
+			AppliedAt: time.Now(),
+		})
+	}
+
+	return result, nil
+}
+
+func (m Migrator) Down() (*models.Migration, error) {
+	all, err := m.migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.ensureVersionTable()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	if hasUnknownVersions(all, applied) {
+		return nil, repository_errors.UnknownMigration
+	}
+
+	for i := len(all) - 1; i >= 0; i-- {
+		if _, ok := applied[all[i].Version]; !ok {
+			continue
+		}
+
+		err = m.run(all[i], false)
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.Migration{Version: all[i].Version, Name: all[i].Name}, nil
+	}
+
+	return nil, repository_errors.DoesNotExist
+}
+
+func (m Migrator) Status() ([]models.Migration, error) {
+	all, err := m.migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.ensureVersionTable()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Migration, 0, len(all))
+	for _, migration := range all {
+		status := models.Migration{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = row.AppliedAt
+		}
+		result = append(result, status)
+	}
+
+	return result, nil
+}
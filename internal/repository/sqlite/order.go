@@ -0,0 +1,456 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type OrderDB struct {
+	ID           uuid.UUID `db:"id"`
+	WorkerID     uuid.UUID `db:"worker_id"`
+	UserID       uuid.UUID `db:"user_id"`
+	Status       int       `db:"status"`
+	Address      string    `db:"address"`
+	CreationDate time.Time `db:"creation_date"`
+	Deadline     time.Time `db:"deadline"`
+	Rate         int       `db:"rate"`
+	// IdempotencyKey is NULL for orders created without a key
+	IdempotencyKey sql.NullString `db:"idempotency_key"`
+	Version        int            `db:"version"`
+	ClosedAt       sql.NullTime   `db:"closed_at"`
+	// Archived is only selected by the queries that also read orders_archive
+	Archived bool `db:"archived"`
+}
+
+type OrderRepository struct {
+	db *sqlx.DB
+}
+
+func NewOrderRepository(db *sqlx.DB) repository_interfaces.IOrderRepository {
+	return &OrderRepository{db: db}
+}
+
+func copyOrderResultToModel(orderDB *OrderDB) *models.Order {
+	return &models.Order{
+		ID:             orderDB.ID,
+		WorkerID:       orderDB.WorkerID,
+		UserID:         orderDB.UserID,
+		Status:         orderDB.Status,
+		Address:        orderDB.Address,
+		CreationDate:   orderDB.CreationDate,
+		Deadline:       orderDB.Deadline,
+		Rate:           orderDB.Rate,
+		IdempotencyKey: orderDB.IdempotencyKey.String,
+		Version:        orderDB.Version,
+		ClosedAt:       orderDB.ClosedAt.Time,
+		Archived:       orderDB.Archived,
+	}
+}
+
+// orderSelectList lists the columns shared by orders and orders_archive, in the order of a union of both.
+const orderSelectList = "id, worker_id, user_id, status, address, deadline, creation_date, rate, idempotency_key, version, closed_at"
+
+// orderHistory is the union of the current and the archived orders.
+const orderHistory = "(SELECT " + orderSelectList + ", false AS archived FROM orders UNION ALL SELECT " + orderSelectList + ", true AS archived FROM orders_archive) history"
+
+func (o OrderRepository) Create(order *models.Order, orderedTasks []models.OrderedTask) (*models.Order, error) {
+	transaction, err := o.db.Begin()
+	if err != nil {
+		return nil, classify(err, repository_errors.TransactionBeginError)
+	}
+
+	// a conflicting idempotency key inserts nothing, so the order is reported as already existing
+	query := `INSERT INTO orders(id, user_id, status, address, creation_date, deadline, idempotency_key) VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)
+		ON CONFLICT (user_id, idempotency_key) DO NOTHING RETURNING id, creation_date, version;`
+
+	idempotencyKey := sql.NullString{String: order.IdempotencyKey, Valid: order.IdempotencyKey != ""}
+	err = transaction.QueryRow(query, uuid.New(), order.UserID, order.Status, order.Address, utc(time.Now()), utc(order.Deadline), idempotencyKey).Scan(&order.ID, &order.CreationDate, &order.Version)
+
+	if err != nil {
+		rollbackErr := transaction.Rollback()
+		if rollbackErr != nil {
+			return nil, repository_errors.TransactionRollbackError
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository_errors.AlreadyExists
+		}
+		return nil, classify(err, repository_errors.InsertError)
+	}
+
+	for _, task := range orderedTasks {
+		query = `INSERT INTO order_contains_tasks(order_id, task_id, quantity) VALUES (?1, ?2, ?3);`
+		_, err = transaction.Exec(query, order.ID, task.Task.ID, task.Quantity)
+		if err != nil {
+			err = transaction.Rollback()
+			if err != nil {
+				return nil, repository_errors.TransactionRollbackError
+			}
+			return nil, repository_errors.InsertError
+		}
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return nil, classify(err, repository_errors.TransactionCommitError)
+	}
+
+	return order, nil
+}
+
+func (o OrderRepository) Delete(id uuid.UUID) error {
+	// Start a new transaction
+	tx, err := o.db.Begin()
+	if err != nil {
+		return classify(err, repository_errors.TransactionBeginError)
+	}
+
+	// Delete the records in the order_contains_tasks table that reference the order
+	_, err = tx.Exec(`DELETE FROM order_contains_tasks WHERE order_id = ?1;`, id)
+	if err != nil {
+		err := tx.Rollback()
+		if err != nil {
+			return repository_errors.TransactionRollbackError
+		}
+		return repository_errors.DeleteError
+	}
+
+	// Delete the order
+	result, err := tx.Exec(`DELETE FROM orders WHERE id = ?1;`, id)
+	if err != nil {
+		err := tx.Rollback()
+		if err != nil {
+			return repository_errors.TransactionRollbackError
+		}
+		return repository_errors.DeleteError
+	}
+
+	// Check if the order was actually deleted
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		err := tx.Rollback()
+		if err != nil {
+			return repository_errors.TransactionRollbackError
+		}
+		return repository_errors.DeleteError
+	}
+
+	if rowsAffected == 0 {
+		err := tx.Rollback()
+		if err != nil {
+			return repository_errors.TransactionRollbackError
+		}
+		return repository_errors.DoesNotExist
+	}
+
+	// Commit the transaction
+	err = tx.Commit()
+	if err != nil {
+		return classify(err, repository_errors.TransactionCommitError)
+	}
+
+	return nil
+}
+
+func (o OrderRepository) Update(order *models.Order) (*models.Order, error) {
+	// the row is only written if nobody has changed it since order.Version was read
+	query := `UPDATE orders SET worker_id = ?1, user_id = ?2, status = ?3, address = ?4, creation_date = ?5, deadline = ?6, rate = ?7, closed_at = ?8, version = version + 1
+		WHERE id = ?9 AND version = ?10 RETURNING id, worker_id, user_id, status, address, creation_date, deadline, rate, closed_at, version;`
+
+	var workerID interface{}
+	if order.WorkerID != uuid.Nil {
+		workerID = order.WorkerID
+	}
+	closedAt := sql.NullTime{Time: utc(order.ClosedAt), Valid: !order.ClosedAt.IsZero()}
+
+	var updatedOrder models.Order
+	err := o.db.QueryRow(query, workerID, order.UserID, order.Status, order.Address, utc(order.CreationDate), utc(order.Deadline), order.Rate, closedAt, order.ID, order.Version).Scan(&updatedOrder.ID, &updatedOrder.WorkerID, &updatedOrder.UserID, &updatedOrder.Status, &updatedOrder.Address, &updatedOrder.CreationDate, &updatedOrder.Deadline, &updatedOrder.Rate, &closedAt, &updatedOrder.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, o.updateMissError(order.ID)
+	} else if err != nil {
+		return nil, classify(err, repository_errors.UpdateError)
+	}
+	updatedOrder.ClosedAt = closedAt.Time
+	return &updatedOrder, nil
+}
+
+// updateMissError tells a missing order from one whose version has changed since it was read.
+func (o OrderRepository) updateMissError(id uuid.UUID) error {
+	var exists bool
+	err := o.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM orders WHERE id = ?1);`, id).Scan(&exists)
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+	if !exists {
+		return repository_errors.DoesNotExist
+	}
+
+	return repository_errors.VersionConflict
+}
+
+func (o OrderRepository) GetOrderByID(id uuid.UUID) (*models.Order, error) {
+	query := `SELECT * FROM ` + orderHistory + ` WHERE id = ?1;`
+	orderDB := &OrderDB{}
+	err := o.db.Get(orderDB, query, id)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	orderModels := copyOrderResultToModel(orderDB)
+
+	return orderModels, nil
+}
+
+func (o OrderRepository) GetOrderByIdempotencyKey(userID uuid.UUID, key string) (*models.Order, error) {
+	query := `SELECT * FROM orders WHERE user_id = ?1 AND idempotency_key = ?2;`
+	orderDB := &OrderDB{}
+	err := o.db.Get(orderDB, query, userID, key)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	return copyOrderResultToModel(orderDB), nil
+}
+
+func (o OrderRepository) GetTasksInOrder(id uuid.UUID) ([]models.Task, error) {
+	query := `SELECT * FROM tasks WHERE id IN (SELECT task_id FROM order_contains_tasks WHERE order_id = ?1
+		UNION ALL SELECT task_id FROM order_contains_tasks_archive WHERE order_id = ?1);`
+	var tasksDB []TaskDB
+	err := o.db.Select(&tasksDB, query, id)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var taskModels []models.Task
+
+	for i := range tasksDB {
+		order := copyTaskResultToModel(&tasksDB[i])
+		taskModels = append(taskModels, *order)
+	}
+
+	return taskModels, nil
+}
+
+func (o OrderRepository) GetCurrentOrderByUserID(id uuid.UUID) (*models.Order, error) {
+	query := `SELECT * FROM orders WHERE user_id = ?1 ORDER BY creation_date DESC LIMIT 1;`
+	orderDB := &OrderDB{}
+	err := o.db.Get(orderDB, query, id)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	orderModels := copyOrderResultToModel(orderDB)
+
+	return orderModels, nil
+}
+
+func (o OrderRepository) GetAllOrdersByUserID(id uuid.UUID) ([]models.Order, error) {
+	query := `SELECT * FROM orders WHERE user_id = ?1;`
+	var orderDB []OrderDB
+
+	err := o.db.Select(&orderDB, query, id)
+
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var orderModels []models.Order
+	for i := range orderDB {
+		order := copyOrderResultToModel(&orderDB[i])
+		orderModels = append(orderModels, *order)
+	}
+
+	return orderModels, nil
+}
+
+// orderColumns are the columns Filter accepts. The names get into the query, so that only known ones are allowed.
+var orderColumns = map[string]bool{
+	"id":              true,
+	"worker_id":       true,
+	"user_id":         true,
+	"status":          true,
+	"address":         true,
+	"creation_date":   true,
+	"deadline":        true,
+	"rate":            true,
+	"idempotency_key": true,
+	"version":         true,
+	"closed_at":       true,
+}
+
+// orderConditions builds the WHERE clause of the Filter parameters, it is empty without parameters.
+func orderConditions(params map[string]string) (string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+
+	for field, value := range params {
+		if !orderColumns[field] {
+			return "", nil, repository_errors.SelectError
+		}
+
+		// comma separated values are alternatives
+		var alternatives []string
+		for _, v := range strings.Split(value, ",") {
+			if v == "null" {
+				alternatives = append(alternatives, fmt.Sprintf("%s IS NULL", field))
+			} else if v == "not null" {
+				alternatives = append(alternatives, fmt.Sprintf("%s IS NOT NULL", field))
+			} else if field == "status" {
+				status, err := strconv.Atoi(v)
+				if err != nil {
+					return "", nil, classify(err, repository_errors.SelectError)
+				}
+				args = append(args, status)
+				alternatives = append(alternatives, fmt.Sprintf("%s = ?%d", field, len(args)))
+			} else {
+				args = append(args, v)
+				alternatives = append(alternatives, fmt.Sprintf("CAST(%s AS TEXT) = ?%d", field, len(args)))
+			}
+		}
+		conditions = append(conditions, "("+strings.Join(alternatives, " OR ")+")")
+	}
+
+	if len(conditions) == 0 {
+		return "", nil, nil
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+func (o OrderRepository) Filter(params map[string]string) ([]models.Order, error) {
+	return o.selectOrders("orders", params, "")
+}
+
+func (o OrderRepository) History(params map[string]string) ([]models.Order, error) {
+	return o.selectOrders(orderHistory, params, " ORDER BY creation_date DESC")
+}
+
+// selectOrders returns the orders of the table matching the Filter parameters.
+func (o OrderRepository) selectOrders(table string, params map[string]string, orderBy string) ([]models.Order, error) {
+	where, args, err := orderConditions(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var orderDB []OrderDB
+	err = o.db.Select(&orderDB, "SELECT * FROM "+table+where+orderBy, args...)
+
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var orderModels []models.Order
+	for i := range orderDB {
+		order := copyOrderResultToModel(&orderDB[i])
+		orderModels = append(orderModels, *order)
+	}
+
+	return orderModels, nil
+}
+
+// archiveQueries copy the closed orders and their tasks to the archive and delete them, in this order,
+// so that the foreign keys hold after every step.
+var archiveQueries = []string{
+	`INSERT INTO orders_archive (` + orderSelectList + `) SELECT ` + orderSelectList + ` FROM orders
+		WHERE status IN (?1, ?2) AND closed_at < ?3;`,
+	`INSERT INTO order_contains_tasks_archive (id, order_id, task_id, quantity) SELECT id, order_id, task_id, quantity FROM order_contains_tasks
+		WHERE order_id IN (SELECT id FROM orders WHERE status IN (?1, ?2) AND closed_at < ?3);`,
+	`DELETE FROM order_contains_tasks WHERE order_id IN (SELECT id FROM orders WHERE status IN (?1, ?2) AND closed_at < ?3);`,
+	`DELETE FROM orders WHERE status IN (?1, ?2) AND closed_at < ?3;`,
+}
+
+func (o OrderRepository) Archive(closedBefore time.Time) (int, error) {
+	tx, err := o.db.Begin()
+	if err != nil {
+		return 0, classify(err, repository_errors.TransactionBeginError)
+	}
+
+	var count int64
+	for i, query := range archiveQueries {
+		result, err := tx.Exec(query, models.CompletedOrderStatus, models.CancelledOrderStatus, utc(closedBefore))
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				return 0, repository_errors.TransactionRollbackError
+			}
+			return 0, classify(err, repository_errors.UpdateError)
+		}
+
+		if i == 0 {
+			count, _ = result.RowsAffected()
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, classify(err, repository_errors.TransactionCommitError)
+	}
+
+	return int(count), nil
+}
+
+func (o OrderRepository) AddTaskToOrder(orderID uuid.UUID, taskID uuid.UUID) error {
+	query := `INSERT INTO order_contains_tasks(order_id, task_id) VALUES (?1, ?2);`
+	_, err := o.db.Exec(query, orderID, taskID)
+
+	if err != nil {
+		return classify(err, repository_errors.InsertError)
+	}
+
+	return nil
+}
+
+func (o OrderRepository) RemoveTaskFromOrder(orderID uuid.UUID, taskID uuid.UUID) error {
+	query := `DELETE FROM order_contains_tasks WHERE order_id = ?1 AND task_id = ?2;`
+	_, err := o.db.Exec(query, orderID, taskID)
+
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	return nil
+}
+
+func (o OrderRepository) UpdateTaskQuantity(orderID uuid.UUID, taskID uuid.UUID, quantity int) error {
+	query := `UPDATE order_contains_tasks SET quantity = ?1 WHERE order_id = ?2 AND task_id = ?3;`
+	_, err := o.db.Exec(query, quantity, orderID, taskID)
+
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+
+	return nil
+}
+
+func (o OrderRepository) GetTaskQuantity(orderID uuid.UUID, taskID uuid.UUID) (int, error) {
+	query := `SELECT quantity FROM order_contains_tasks WHERE order_id = ?1 AND task_id = ?2
+		UNION ALL SELECT quantity FROM order_contains_tasks_archive WHERE order_id = ?1 AND task_id = ?2 LIMIT 1;`
+	var quantity int
+
+	err := o.db.Get(&quantity, query, orderID, taskID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, repository_errors.DoesNotExist
+	} else if err != nil {
+		return 0, classify(err, repository_errors.SelectError)
+	}
+
+	return quantity, nil
+}
@@ -0,0 +1,134 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type SessionDB struct {
+	ID           string    `db:"id"`
+	UserID       uuid.UUID `db:"user_id"`
+	WorkerID     uuid.UUID `db:"worker_id"`
+	Data         string    `db:"data"`
+	CreatedAt    time.Time `db:"created_at"`
+	LastActivity time.Time `db:"last_activity"`
+}
+
+type SessionRepository struct {
+	db *sqlx.DB
+}
+
+func NewSessionRepository(db *sqlx.DB) repository_interfaces.ISessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func copySessionResultToModel(sessionDB *SessionDB) *models.Session {
+	return &models.Session{
+		ID:           sessionDB.ID,
+		UserID:       sessionDB.UserID,
+		WorkerID:     sessionDB.WorkerID,
+		Data:         sessionDB.Data,
+		CreatedAt:    sessionDB.CreatedAt,
+		LastActivity: sessionDB.LastActivity,
+	}
+}
+
+// nullableUUID maps uuid.Nil to SQL NULL so that optional references stay empty.
+func nullableUUID(id uuid.UUID) interface{} {
+	if id == uuid.Nil {
+		return nil
+	}
+	return id
+}
+
+func (s SessionRepository) Save(session *models.Session) error {
+	if session.ID == "" {
+		return repository_errors.InsertError
+	}
+
+	query := `INSERT INTO sessions(id, user_id, worker_id, data, created_at, last_activity) VALUES (?1, ?2, ?3, ?4, ?5, ?6)
+		ON CONFLICT (id) DO UPDATE SET user_id = EXCLUDED.user_id, worker_id = EXCLUDED.worker_id, data = EXCLUDED.data, last_activity = EXCLUDED.last_activity;`
+
+	_, err := s.db.Exec(query, session.ID, nullableUUID(session.UserID), nullableUUID(session.WorkerID), session.Data, utc(session.CreatedAt), utc(session.LastActivity))
+	if err != nil {
+		return classify(err, repository_errors.InsertError)
+	}
+
+	return nil
+}
+
+func (s SessionRepository) GetSessionByID(id string) (*models.Session, error) {
+	query := `SELECT id, user_id, worker_id, data, created_at, last_activity FROM sessions WHERE id = ?1;`
+	sessionDB := &SessionDB{}
+	err := s.db.Get(sessionDB, query, id)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	return copySessionResultToModel(sessionDB), nil
+}
+
+func (s SessionRepository) UpdateLastActivity(id string, lastActivity time.Time) error {
+	query := `UPDATE sessions SET last_activity = ?1 WHERE id = ?2;`
+	result, err := s.db.Exec(query, utc(lastActivity), id)
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+
+	if rowsAffected == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (s SessionRepository) Delete(id string) error {
+	_, err := s.db.Exec(`DELETE FROM sessions WHERE id = ?1;`, id)
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	return nil
+}
+
+func (s SessionRepository) DeleteByUserID(userID uuid.UUID) error {
+	_, err := s.db.Exec(`DELETE FROM sessions WHERE user_id = ?1;`, userID)
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	return nil
+}
+
+func (s SessionRepository) DeleteByWorkerID(workerID uuid.UUID) error {
+	_, err := s.db.Exec(`DELETE FROM sessions WHERE worker_id = ?1;`, workerID)
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	return nil
+}
+
+func (s SessionRepository) DeleteExpired(idleBefore time.Time, createdBefore time.Time) error {
+	_, err := s.db.Exec(`DELETE FROM sessions WHERE last_activity < ?1 OR created_at < ?2;`, utc(idleBefore), utc(createdBefore))
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	return nil
+}
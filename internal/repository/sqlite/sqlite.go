@@ -0,0 +1,32 @@
+// Package sqlite implements the repositories on a single SQLite file, for installations without
+// a database server. SQLite has no uuid and timestamp types: ids are stored as text, and times
+// as UTC text, which sorts in time order.
+package sqlite
+
+import (
+	"errors"
+	"lab3/internal/repository/repository_errors"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// utc brings a time to UTC before it is stored or compared, so that the text of all stored times is comparable.
+func utc(t time.Time) time.Time {
+	return t.UTC()
+}
+
+// classify returns kind, marked as repository_errors.Unavailable when err was caused by the
+// database staying locked by another writer for longer than the busy timeout.
+func classify(err error, kind error) error {
+	return repository_errors.Transient(err, kind, isTransient)
+}
+
+func isTransient(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+
+	return repository_errors.IsConnectionError(err)
+}
@@ -0,0 +1,136 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"lab3/config"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type SQLiteConnection struct {
+	DB     *sql.DB
+	Config config.SQLiteConfig
+}
+
+const defaultBusyTimeout = 5 * time.Second
+
+// Open opens the database file at path, creating it when it does not exist. Foreign keys are checked,
+// and every transaction takes the write lock when it begins, so that concurrent writers wait for each
+// other, up to busyTimeout, instead of failing with "database is locked" in the middle of a transaction.
+func Open(path string, busyTimeout time.Duration) (*sql.DB, error) {
+	if busyTimeout <= 0 {
+		busyTimeout = defaultBusyTimeout
+	}
+
+	dsn := fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=%d&_journal_mode=WAL&_txlock=immediate", path, busyTimeout.Milliseconds())
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite has a single writer, more connections would only wait for the lock
+	db.SetMaxOpenConns(1)
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func NewSQLiteConnection(sqlite config.SQLiteConfig, logger *log.Logger) (*SQLiteConnection, error) {
+	if sqlite.Path == "" {
+		logger.Error("SQLITE! Database file is not configured")
+		return nil, repository_errors.ConnectionError
+	}
+
+	db, err := Open(sqlite.Path, sqlite.BusyTimeout)
+	if err != nil {
+		logger.Error("SQLITE! Error open database file", "path", sqlite.Path, "err", err)
+		return nil, repository_errors.ConnectionError
+	}
+
+	logger.Info("SQLITE! Successfully create sqlite repository fields", "path", sqlite.Path)
+
+	return &SQLiteConnection{DB: db, Config: sqlite}, nil
+}
+
+func CreateUserRepository(fields *SQLiteConnection) repository_interfaces.IUserRepository {
+	dbx := sqlx.NewDb(fields.DB, "sqlite3")
+
+	return NewUserRepository(dbx)
+}
+
+func CreateWorkerRepository(fields *SQLiteConnection) repository_interfaces.IWorkerRepository {
+	dbx := sqlx.NewDb(fields.DB, "sqlite3")
+
+	return NewWorkerRepository(dbx)
+}
+
+func CreateOrderRepository(fields *SQLiteConnection) repository_interfaces.IOrderRepository {
+	dbx := sqlx.NewDb(fields.DB, "sqlite3")
+
+	return NewOrderRepository(dbx)
+}
+
+func CreateTaskRepository(fields *SQLiteConnection) repository_interfaces.ITaskRepository {
+	dbx := sqlx.NewDb(fields.DB, "sqlite3")
+
+	return NewTaskRepository(dbx)
+}
+
+func CreateCategoryRepository(fields *SQLiteConnection) repository_interfaces.ICategoryRepository {
+	dbx := sqlx.NewDb(fields.DB, "sqlite3")
+
+	return NewCategoryRepository(dbx)
+}
+
+func CreateSessionRepository(fields *SQLiteConnection) repository_interfaces.ISessionRepository {
+	dbx := sqlx.NewDb(fields.DB, "sqlite3")
+
+	return NewSessionRepository(dbx)
+}
+
+func CreateLoginAttemptRepository(fields *SQLiteConnection) repository_interfaces.ILoginAttemptRepository {
+	dbx := sqlx.NewDb(fields.DB, "sqlite3")
+
+	return NewLoginAttemptRepository(dbx)
+}
+
+func CreateOneTimeTokenRepository(fields *SQLiteConnection) repository_interfaces.IOneTimeTokenRepository {
+	dbx := sqlx.NewDb(fields.DB, "sqlite3")
+
+	return NewOneTimeTokenRepository(dbx)
+}
+
+func CreateTwoFactorRepository(fields *SQLiteConnection) repository_interfaces.ITwoFactorRepository {
+	dbx := sqlx.NewDb(fields.DB, "sqlite3")
+
+	return NewTwoFactorRepository(dbx)
+}
+
+func CreateAuditRepository(fields *SQLiteConnection) repository_interfaces.IAuditRepository {
+	dbx := sqlx.NewDb(fields.DB, "sqlite3")
+
+	return NewAuditRepository(dbx)
+}
+
+func CreateInvoiceRepository(fields *SQLiteConnection) repository_interfaces.IInvoiceRepository {
+	dbx := sqlx.NewDb(fields.DB, "sqlite3")
+
+	return NewInvoiceRepository(dbx)
+}
+
+func CreateMigrator(fields *SQLiteConnection) repository_interfaces.IMigrator {
+	dbx := sqlx.NewDb(fields.DB, "sqlite3")
+
+	return NewMigrator(dbx)
+}
@@ -0,0 +1,335 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"lab3/internal/search"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type TaskDB struct {
+	ID             uuid.UUID    `db:"id"`
+	Name           string       `db:"name"`
+	PricePerSingle float64      `db:"price_per_single"`
+	Category       int          `db:"category"`
+	TaxRate        string       `db:"tax_rate"`
+	DeletedAt      sql.NullTime `db:"deleted_at"`
+}
+
+type TaskRepository struct {
+	db *sqlx.DB
+}
+
+func NewTaskRepository(db *sqlx.DB) repository_interfaces.ITaskRepository {
+	return &TaskRepository{db: db}
+}
+
+func copyTaskResultToModel(taskDB *TaskDB) *models.Task {
+	return &models.Task{
+		ID:             taskDB.ID,
+		Name:           taskDB.Name,
+		PricePerSingle: taskDB.PricePerSingle,
+		Category:       taskDB.Category,
+		TaxRate:        taskDB.TaxRate,
+		DeletedAt:      taskDB.DeletedAt.Time,
+	}
+}
+
+func (t TaskRepository) Create(task *models.Task) (*models.Task, error) {
+	if task.Name == "" || task.PricePerSingle == 0 {
+		return nil, repository_errors.InsertError
+	}
+
+	query := `INSERT INTO tasks(id, name, price_per_single, category, tax_rate) VALUES (?1, ?2, ?3, ?4, ?5);`
+
+	taskID := uuid.New()
+	_, err := t.db.Exec(query, taskID, task.Name, task.PricePerSingle, task.Category, task.TaxRate)
+
+	if err != nil {
+		return nil, classify(err, repository_errors.InsertError)
+	}
+
+	return &models.Task{
+		ID:             taskID,
+		Name:           task.Name,
+		PricePerSingle: task.PricePerSingle,
+		Category:       task.Category,
+		TaxRate:        task.TaxRate,
+	}, nil
+}
+
+func (t TaskRepository) Delete(id uuid.UUID) error {
+	query := `UPDATE tasks SET deleted_at = ?2 WHERE id = ?1 AND deleted_at IS NULL;`
+	result, err := t.db.Exec(query, id, utc(time.Now()))
+
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (t TaskRepository) Restore(id uuid.UUID) error {
+	query := `UPDATE tasks SET deleted_at = NULL WHERE id = ?1 AND deleted_at IS NOT NULL;`
+	result, err := t.db.Exec(query, id)
+
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (t TaskRepository) Update(task *models.Task) (*models.Task, error) {
+	if task.Name == "" || task.PricePerSingle == 0 {
+		return nil, repository_errors.InsertError
+	}
+
+	query := `UPDATE tasks SET name = ?1, price_per_single = ?2, category = ?3, tax_rate = ?5 WHERE tasks.id = ?4 RETURNING id, name, price_per_single, category, tax_rate;`
+
+	var updatedTask models.Task
+	err := t.db.QueryRow(query, task.Name, task.PricePerSingle, task.Category, task.ID, task.TaxRate).Scan(&updatedTask.ID, &updatedTask.Name, &updatedTask.PricePerSingle, &updatedTask.Category, &updatedTask.TaxRate)
+	if err != nil {
+		return nil, classify(err, repository_errors.UpdateError)
+	}
+	return &updatedTask, nil
+}
+
+func (t TaskRepository) GetTaskByID(id uuid.UUID) (*models.Task, error) {
+	query := `SELECT * FROM tasks WHERE id = ?1;`
+	taskDB := &TaskDB{}
+	err := t.db.Get(taskDB, query, id)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	taskModels := copyTaskResultToModel(taskDB)
+
+	return taskModels, nil
+}
+
+func (t TaskRepository) GetTaskByName(name string) (*models.Task, error) {
+	query := `SELECT * FROM tasks WHERE name = ?1 AND deleted_at IS NULL LIMIT 1;`
+	taskDB := &TaskDB{}
+	err := t.db.Get(taskDB, query, name)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	return copyTaskResultToModel(taskDB), nil
+}
+
+func (t TaskRepository) GetAllTasks() ([]models.Task, error) {
+	query := `SELECT id, name, price_per_single, category, tax_rate FROM tasks WHERE deleted_at IS NULL;`
+	var taskDB []TaskDB
+
+	err := t.db.Select(&taskDB, query)
+
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var taskModels []models.Task
+	for i := range taskDB {
+		user := copyTaskResultToModel(&taskDB[i])
+		taskModels = append(taskModels, *user)
+	}
+
+	return taskModels, nil
+}
+
+func (t TaskRepository) GetTasksInCategory(category int) ([]models.Task, error) {
+	query := `SELECT * FROM tasks WHERE category = ?1 AND deleted_at IS NULL;`
+	var taskDB []TaskDB
+
+	err := t.db.Select(&taskDB, query, category)
+
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var taskModels []models.Task
+	for i := range taskDB {
+		task := copyTaskResultToModel(&taskDB[i])
+		taskModels = append(taskModels, *task)
+	}
+
+	return taskModels, nil
+}
+
+func (t TaskRepository) MoveToCategory(from, to int) (int, error) {
+	var exists bool
+	err := t.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM categories WHERE id = ?1);`, to).Scan(&exists)
+	if err != nil {
+		return 0, classify(err, repository_errors.SelectError)
+	}
+	if !exists {
+		return 0, repository_errors.UpdateError
+	}
+
+	result, err := t.db.Exec(`UPDATE tasks SET category = ?2 WHERE category = ?1;`, from, to)
+	if err != nil {
+		return 0, classify(err, repository_errors.UpdateError)
+	}
+
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(moved), nil
+}
+
+func (t TaskRepository) GetDeletedTasks() ([]models.Task, error) {
+	query := `SELECT * FROM tasks WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC;`
+	var taskDB []TaskDB
+
+	err := t.db.Select(&taskDB, query)
+
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var taskModels []models.Task
+	for i := range taskDB {
+		task := copyTaskResultToModel(&taskDB[i])
+		taskModels = append(taskModels, *task)
+	}
+
+	return taskModels, nil
+}
+
+// Search matches the names in the application, SQLite has no stemming for Russian. The catalog
+// is small enough to be read whole.
+func (t TaskRepository) Search(query string) ([]models.Task, error) {
+	if len(search.Words(query)) == 0 {
+		return nil, nil
+	}
+
+	tasks, err := t.GetAllTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	return search.Tasks(tasks, query), nil
+}
+
+type TaskPriceDB struct {
+	ID            uuid.UUID `db:"id"`
+	TaskID        uuid.UUID `db:"task_id"`
+	Price         float64   `db:"price"`
+	EffectiveFrom time.Time `db:"effective_from"`
+}
+
+func copyTaskPricesToModels(pricesDB []TaskPriceDB) []models.TaskPrice {
+	var prices []models.TaskPrice
+	for _, price := range pricesDB {
+		prices = append(prices, models.TaskPrice(price))
+	}
+	return prices
+}
+
+func (t TaskRepository) SchedulePrice(price *models.TaskPrice) (*models.TaskPrice, error) {
+	if price.Price == 0 {
+		return nil, repository_errors.InsertError
+	}
+
+	query := `INSERT INTO task_prices(id, task_id, price, effective_from) VALUES (?1, ?2, ?3, ?4)
+		ON CONFLICT (task_id, effective_from) DO UPDATE SET price = excluded.price RETURNING id;`
+
+	scheduled := *price
+	err := t.db.QueryRow(query, uuid.New(), price.TaskID, price.Price, utc(price.EffectiveFrom)).Scan(&scheduled.ID)
+	if err != nil {
+		return nil, classify(err, repository_errors.InsertError)
+	}
+
+	return &scheduled, nil
+}
+
+func (t TaskRepository) DeletePrice(id uuid.UUID) error {
+	result, err := t.db.Exec(`DELETE FROM task_prices WHERE id = ?1;`, id)
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (t TaskRepository) GetPrices(taskID uuid.UUID) ([]models.TaskPrice, error) {
+	var pricesDB []TaskPriceDB
+	err := t.db.Select(&pricesDB, `SELECT * FROM task_prices WHERE task_id = ?1 ORDER BY effective_from;`, taskID)
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	return copyTaskPricesToModels(pricesDB), nil
+}
+
+func (t TaskRepository) GetUpcomingPrices(moment time.Time) ([]models.TaskPrice, error) {
+	var pricesDB []TaskPriceDB
+	err := t.db.Select(&pricesDB, `SELECT * FROM task_prices WHERE effective_from > ?1 ORDER BY effective_from;`, utc(moment))
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	return copyTaskPricesToModels(pricesDB), nil
+}
+
+func (t TaskRepository) ApplyPrices(moment time.Time) (int, error) {
+	query := `UPDATE tasks SET price_per_single = current.price
+		FROM (SELECT task_id, price FROM task_prices latest
+			WHERE effective_from = (SELECT max(effective_from) FROM task_prices
+				WHERE task_id = latest.task_id AND effective_from <= ?1)) current
+		WHERE tasks.id = current.task_id AND tasks.price_per_single IS NOT current.price;`
+
+	result, err := t.db.Exec(query, utc(moment))
+	if err != nil {
+		return 0, classify(err, repository_errors.UpdateError)
+	}
+
+	changed, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(changed), nil
+}
@@ -0,0 +1,199 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type UserDB struct {
+	ID            uuid.UUID    `db:"id"`
+	Name          string       `db:"name"`
+	Surname       string       `db:"surname"`
+	Address       string       `db:"address"`
+	PhoneNumber   string       `db:"phone_number"`
+	Email         string       `db:"email"`
+	Password      string       `db:"password"`
+	EmailVerified bool         `db:"email_verified"`
+	DeletedAt     sql.NullTime `db:"deleted_at"`
+}
+
+type UserRepository struct {
+	db *sqlx.DB
+}
+
+func NewUserRepository(db *sqlx.DB) repository_interfaces.IUserRepository {
+	return &UserRepository{db: db}
+}
+
+func copyUserResultToModel(userDB *UserDB) *models.User {
+	return &models.User{
+		ID:            userDB.ID,
+		Name:          userDB.Name,
+		Surname:       userDB.Surname,
+		Address:       userDB.Address,
+		PhoneNumber:   userDB.PhoneNumber,
+		Email:         userDB.Email,
+		Password:      userDB.Password,
+		EmailVerified: userDB.EmailVerified,
+		DeletedAt:     userDB.DeletedAt.Time,
+	}
+}
+
+func (u UserRepository) Create(user *models.User) (*models.User, error) {
+	if user.Name == "" || user.Surname == "" || user.Email == "" || user.Password == "" {
+		return nil, repository_errors.InsertError
+	}
+
+	query := `INSERT INTO users(id, name, surname, address, phone_number, email, password, email_verified) VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8);`
+
+	userID := uuid.New()
+	_, err := u.db.Exec(query, userID, user.Name, user.Surname, user.Address, user.PhoneNumber, user.Email, user.Password, user.EmailVerified)
+
+	if err != nil {
+		return nil, classify(err, repository_errors.InsertError)
+	}
+
+	return &models.User{
+		ID:            userID,
+		Name:          user.Name,
+		Surname:       user.Surname,
+		Address:       user.Address,
+		PhoneNumber:   user.PhoneNumber,
+		Email:         user.Email,
+		Password:      user.Password,
+		EmailVerified: user.EmailVerified,
+	}, nil
+}
+
+// Delete archives the user. Their orders are kept, so that workers still see the history.
+func (u UserRepository) Delete(id uuid.UUID) error {
+	query := `UPDATE users SET deleted_at = ?2 WHERE id = ?1 AND deleted_at IS NULL;`
+	result, err := u.db.Exec(query, id, utc(time.Now()))
+
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (u UserRepository) Restore(id uuid.UUID) error {
+	query := `UPDATE users SET deleted_at = NULL WHERE id = ?1 AND deleted_at IS NOT NULL;`
+	result, err := u.db.Exec(query, id)
+
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (u UserRepository) Update(user *models.User) (*models.User, error) {
+	if user.Name == "" || user.Surname == "" || user.Email == "" || user.Password == "" {
+		return nil, repository_errors.UpdateError
+	}
+
+	query := `UPDATE users SET name = ?1, surname = ?2, email = ?3, phone_number = ?4, address = ?5, password = ?6, email_verified = ?7 WHERE users.id = ?8 RETURNING id, name, surname, address, phone_number, email, password, email_verified;`
+
+	var updatedUser models.User
+	err := u.db.QueryRow(query, user.Name, user.Surname, user.Email, user.PhoneNumber, user.Address, user.Password, user.EmailVerified, user.ID).Scan(&updatedUser.ID, &updatedUser.Name, &updatedUser.Surname, &updatedUser.Address, &updatedUser.PhoneNumber, &updatedUser.Email, &updatedUser.Password, &updatedUser.EmailVerified)
+	if err != nil {
+		return nil, classify(err, repository_errors.UpdateError)
+	}
+	return &updatedUser, nil
+}
+
+func (u UserRepository) GetUserByID(id uuid.UUID) (*models.User, error) {
+	query := `SELECT * FROM users WHERE id = ?1;`
+	userDB := &UserDB{}
+	err := u.db.Get(userDB, query, id)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	userModels := copyUserResultToModel(userDB)
+
+	return userModels, nil
+}
+
+func (u UserRepository) GetUserByEmail(email string) (*models.User, error) {
+	query := `SELECT * FROM users WHERE email = ?1 AND deleted_at IS NULL;`
+	userDB := &UserDB{}
+	err := u.db.Get(userDB, query, email)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	userModels := copyUserResultToModel(userDB)
+
+	return userModels, nil
+}
+
+func (u UserRepository) GetAllUsers() ([]models.User, error) {
+	query := `SELECT name, surname, address, phone_number, email FROM users WHERE deleted_at IS NULL;`
+	var userDB []UserDB
+
+	err := u.db.Select(&userDB, query)
+
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var userModels []models.User
+	for i := range userDB {
+		user := copyUserResultToModel(&userDB[i])
+		userModels = append(userModels, *user)
+	}
+
+	return userModels, nil
+}
+
+func (u UserRepository) GetDeletedUsers() ([]models.User, error) {
+	query := `SELECT * FROM users WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC;`
+	var userDB []UserDB
+
+	err := u.db.Select(&userDB, query)
+
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var userModels []models.User
+	for i := range userDB {
+		user := copyUserResultToModel(&userDB[i])
+		userModels = append(userModels, *user)
+	}
+
+	return userModels, nil
+}
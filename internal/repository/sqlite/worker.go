@@ -0,0 +1,234 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type WorkerDB struct {
+	ID          uuid.UUID    `db:"id"`
+	Name        string       `db:"name"`
+	Surname     string       `db:"surname"`
+	Address     string       `db:"address"`
+	PhoneNumber string       `db:"phone_number"`
+	Email       string       `db:"email"`
+	Role        int          `db:"role"`
+	Password    string       `db:"password"`
+	DeletedAt   sql.NullTime `db:"deleted_at"`
+}
+
+type WorkerRepository struct {
+	db *sqlx.DB
+}
+
+func NewWorkerRepository(db *sqlx.DB) repository_interfaces.IWorkerRepository {
+	return &WorkerRepository{db: db}
+}
+
+func copyWorkerResultToModel(workerDB *WorkerDB) *models.Worker {
+	return &models.Worker{
+		ID:          workerDB.ID,
+		Name:        workerDB.Name,
+		Surname:     workerDB.Surname,
+		Address:     workerDB.Address,
+		PhoneNumber: workerDB.PhoneNumber,
+		Email:       workerDB.Email,
+		Role:        workerDB.Role,
+		Password:    workerDB.Password,
+		DeletedAt:   workerDB.DeletedAt.Time,
+	}
+}
+
+func (w WorkerRepository) Create(worker *models.Worker) (*models.Worker, error) {
+	if worker.Name == "" || worker.Surname == "" || worker.Email == "" || worker.Password == "" {
+		return nil, repository_errors.UpdateError
+	}
+
+	query := `INSERT INTO workers(id, name, surname, address, phone_number, email, role, password) VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8);`
+
+	workerID := uuid.New()
+	_, err := w.db.Exec(query, workerID, worker.Name, worker.Surname, worker.Address, worker.PhoneNumber, worker.Email, worker.Role, worker.Password)
+
+	if err != nil {
+		return nil, classify(err, repository_errors.InsertError)
+	}
+
+	return &models.Worker{
+		ID:          workerID,
+		Name:        worker.Name,
+		Surname:     worker.Surname,
+		Address:     worker.Address,
+		PhoneNumber: worker.PhoneNumber,
+		Email:       worker.Email,
+		Role:        worker.Role,
+		Password:    worker.Password,
+	}, nil
+}
+
+func (w WorkerRepository) Update(worker *models.Worker) (*models.Worker, error) {
+	if worker.Name == "" || worker.Surname == "" || worker.Email == "" || worker.Password == "" {
+		return nil, repository_errors.UpdateError
+	}
+
+	query := `UPDATE workers SET name = ?1, surname = ?2, address = ?3, phone_number = ?4, email = ?5, role = ?6, password = ?7 WHERE workers.id = ?8 RETURNING id, name, surname, address, phone_number, email, role, password;`
+
+	var updatedWorker models.Worker
+	err := w.db.QueryRow(query, worker.Name, worker.Surname, worker.Address, worker.PhoneNumber, worker.Email, worker.Role, worker.Password, worker.ID).Scan(&updatedWorker.ID, &updatedWorker.Name, &updatedWorker.Surname, &updatedWorker.Address, &updatedWorker.PhoneNumber, &updatedWorker.Email, &updatedWorker.Role, &updatedWorker.Password)
+	if err != nil {
+		return nil, classify(err, repository_errors.UpdateError)
+	}
+	return &updatedWorker, nil
+}
+
+func (w WorkerRepository) Delete(id uuid.UUID) error {
+	query := `UPDATE workers SET deleted_at = ?2 WHERE id = ?1 AND deleted_at IS NULL;`
+	result, err := w.db.Exec(query, id, utc(time.Now()))
+
+	if err != nil {
+		return classify(err, repository_errors.DeleteError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (w WorkerRepository) Restore(id uuid.UUID) error {
+	query := `UPDATE workers SET deleted_at = NULL WHERE id = ?1 AND deleted_at IS NOT NULL;`
+	result, err := w.db.Exec(query, id)
+
+	if err != nil {
+		return classify(err, repository_errors.UpdateError)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository_errors.DoesNotExist
+	}
+
+	return nil
+}
+
+func (w WorkerRepository) GetWorkerByID(id uuid.UUID) (*models.Worker, error) {
+	query := `SELECT * FROM workers WHERE id = ?1;`
+	workerDB := &WorkerDB{}
+	err := w.db.Get(workerDB, query, id)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	workerModels := copyWorkerResultToModel(workerDB)
+
+	return workerModels, nil
+}
+
+func (w WorkerRepository) GetAllWorkers() ([]models.Worker, error) {
+	query := `SELECT id, name, surname, address, phone_number, email, role FROM workers WHERE deleted_at IS NULL;`
+	var workerDB []WorkerDB
+
+	err := w.db.Select(&workerDB, query)
+
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var workerModels []models.Worker
+	for i := range workerDB {
+		worker := copyWorkerResultToModel(&workerDB[i])
+		workerModels = append(workerModels, *worker)
+	}
+
+	return workerModels, nil
+}
+
+func (w WorkerRepository) GetWorkerByEmail(email string) (*models.Worker, error) {
+	query := `SELECT * FROM workers WHERE email = ?1 AND deleted_at IS NULL;`
+	workerDB := &WorkerDB{}
+	err := w.db.Get(workerDB, query, email)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository_errors.DoesNotExist
+	} else if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	workerModels := copyWorkerResultToModel(workerDB)
+
+	return workerModels, nil
+}
+
+func (w WorkerRepository) GetDeletedWorkers() ([]models.Worker, error) {
+	query := `SELECT * FROM workers WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC;`
+	var workerDB []WorkerDB
+
+	err := w.db.Select(&workerDB, query)
+
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var workerModels []models.Worker
+	for i := range workerDB {
+		worker := copyWorkerResultToModel(&workerDB[i])
+		workerModels = append(workerModels, *worker)
+	}
+
+	return workerModels, nil
+}
+
+func (w WorkerRepository) GetWorkersByRole(role int) ([]models.Worker, error) {
+	query := `SELECT * FROM workers WHERE role = ?1 AND deleted_at IS NULL;`
+	var workerDB []WorkerDB
+
+	err := w.db.Select(&workerDB, query, role)
+
+	if err != nil {
+		return nil, classify(err, repository_errors.SelectError)
+	}
+
+	var workerModels []models.Worker
+	for i := range workerDB {
+		worker := copyWorkerResultToModel(&workerDB[i])
+		workerModels = append(workerModels, *worker)
+	}
+
+	return workerModels, nil
+
+}
+
+func (w WorkerRepository) GetAverageOrderRate(worker *models.Worker) (float64, error) {
+	// archived orders keep counting
+	query := `SELECT COALESCE(AVG(rate), 0) FROM (SELECT worker_id, status, rate FROM orders UNION ALL SELECT worker_id, status, rate FROM orders_archive) history
+		WHERE worker_id = ?1 AND status = 3 AND rate != 0;`
+	var averageRate float64
+
+	err := w.db.Get(&averageRate, query, worker.ID)
+
+	if err != nil {
+		return 0, classify(err, repository_errors.SelectError)
+	}
+
+	return averageRate, nil
+
+}
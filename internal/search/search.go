@@ -0,0 +1,83 @@
+// Package search finds tasks by words of their names for the backends without full-text search
+// of their own. Words are compared by their stems, so "уборку" finds "Генеральная уборка", and
+// the last word of a query may be unfinished, as while it is being typed.
+package search
+
+import (
+	"lab3/internal/models"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Words splits text into lower case words of letters and digits.
+func Words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Rank reports whether name has every word of query, a word of the name matching when its stem
+// starts with the stem of the query word. Whole word matches rank higher than prefix ones.
+func Rank(name string, query []string) (int, bool) {
+	var nameStems []string
+	for _, word := range Words(name) {
+		nameStems = append(nameStems, Stem(word))
+	}
+
+	rank := 0
+	for _, word := range query {
+		queryStem := Stem(word)
+
+		best := 0
+		for _, nameStem := range nameStems {
+			if nameStem == queryStem {
+				best = 2
+				break
+			} else if strings.HasPrefix(nameStem, queryStem) {
+				best = 1
+			}
+		}
+
+		if best == 0 {
+			return 0, false
+		}
+		rank += best
+	}
+
+	return rank, true
+}
+
+// Tasks returns the tasks matching query, the best matches first, equally good ones by name.
+func Tasks(tasks []models.Task, query string) []models.Task {
+	words := Words(query)
+	if len(words) == 0 {
+		return nil
+	}
+
+	type rankedTask struct {
+		task models.Task
+		rank int
+	}
+
+	var found []rankedTask
+	for _, task := range tasks {
+		if rank, ok := Rank(task.Name, words); ok {
+			found = append(found, rankedTask{task: task, rank: rank})
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].rank != found[j].rank {
+			return found[i].rank > found[j].rank
+		}
+		return found[i].task.Name < found[j].task.Name
+	})
+
+	result := make([]models.Task, len(found))
+	for i := range found {
+		result[i] = found[i].task
+	}
+
+	return result
+}
@@ -0,0 +1,134 @@
+package search
+
+import "strings"
+
+// The Russian stemmer of the Snowball project, the one behind the russian configuration of
+// Postgres full-text search and the russian text indexes of MongoDB, so that the fallback finds
+// what the databases find. See https://snowballstem.org/algorithms/russian/stemmer.html
+
+const vowels = "аеиоуыэюя"
+
+var (
+	perfectiveGerund1 = []string{"вшись", "вши", "в"}
+	perfectiveGerund2 = []string{"ившись", "ывшись", "ивши", "ывши", "ив", "ыв"}
+	adjective         = []string{"ими", "ыми", "его", "ого", "ему", "ому", "ее", "ие", "ые", "ое", "ей", "ий", "ый", "ой", "ем", "им", "ым", "ом", "их", "ых", "ую", "юю", "ая", "яя", "ою", "ею"}
+	participle1       = []string{"ем", "нн", "вш", "ющ", "щ"}
+	participle2       = []string{"ивш", "ывш", "ующ"}
+	reflexive         = []string{"ся", "сь"}
+	verb1             = []string{"ете", "йте", "ешь", "нно", "ла", "на", "ли", "ем", "ло", "но", "ет", "ют", "ны", "ть", "й", "л", "н"}
+	verb2             = []string{"ейте", "уйте", "ила", "ыла", "ена", "ите", "или", "ыли", "ило", "ыло", "ено", "ует", "уют", "ены", "ить", "ыть", "ишь", "ей", "уй", "ил", "ыл", "им", "ым", "ен", "ят", "ит", "ыт", "ую", "ю"}
+	noun              = []string{"иями", "ями", "ами", "ией", "иям", "ием", "иях", "ев", "ов", "ие", "ье", "еи", "ии", "ей", "ой", "ий", "ям", "ем", "ам", "ом", "ах", "ях", "ию", "ью", "ия", "ья", "а", "е", "и", "й", "о", "у", "ы", "ь", "ю", "я"}
+	superlative       = []string{"ейше", "ейш"}
+	derivational      = []string{"ость", "ост"}
+)
+
+func isVowel(r rune) bool {
+	return strings.ContainsRune(vowels, r)
+}
+
+// regions returns the starts of RV, the part after the first vowel, and of R2, the part after
+// the second vowel followed by a consonant.
+func regions(word []rune) (int, int) {
+	rv, r1, r2 := len(word), len(word), len(word)
+
+	for i, r := range word {
+		if isVowel(r) {
+			rv = i + 1
+			break
+		}
+	}
+
+	for i := 1; i < len(word); i++ {
+		if !isVowel(word[i]) && isVowel(word[i-1]) {
+			r1 = i + 1
+			break
+		}
+	}
+
+	for i := r1 + 1; i < len(word); i++ {
+		if !isVowel(word[i]) && isVowel(word[i-1]) {
+			r2 = i + 1
+			break
+		}
+	}
+
+	return rv, r2
+}
+
+// removeEnding removes the longest of endings found in word[start:]. The endings of the first
+// group must follow "а" or "я", which stay in the word.
+func removeEnding(word []rune, start int, group1 []string, group2 []string) ([]rune, bool) {
+	best := 0
+	for _, ending := range group2 {
+		if n := len([]rune(ending)); n > best && hasSuffix(word[start:], ending) {
+			best = n
+		}
+	}
+
+	for _, ending := range group1 {
+		n := len([]rune(ending))
+		if n <= best || !hasSuffix(word[start:], ending) || len(word)-n-1 < start {
+			continue
+		}
+		if preceding := word[len(word)-n-1]; preceding == 'а' || preceding == 'я' {
+			best = n
+		}
+	}
+
+	if best == 0 {
+		return word, false
+	}
+	return word[:len(word)-best], true
+}
+
+func hasSuffix(word []rune, suffix string) bool {
+	return strings.HasSuffix(string(word), suffix)
+}
+
+// Stem reduces a lower case Russian word to its stem, e.g. "подоконников" to "подоконник".
+// Words in other languages are returned as is.
+func Stem(word string) string {
+	runes := []rune(strings.ReplaceAll(word, "ё", "е"))
+	rv, r2 := regions(runes)
+
+	// step 1
+	stemmed, ok := removeEnding(runes, rv, perfectiveGerund1, perfectiveGerund2)
+	if !ok {
+		stemmed, _ = removeEnding(runes, rv, nil, reflexive)
+
+		var found bool
+		if withoutAdjective, ok := removeEnding(stemmed, rv, nil, adjective); ok {
+			stemmed, _ = removeEnding(withoutAdjective, rv, participle1, participle2)
+			found = true
+		}
+		if !found {
+			stemmed, found = removeEnding(stemmed, rv, verb1, verb2)
+		}
+		if !found {
+			stemmed, _ = removeEnding(stemmed, rv, nil, noun)
+		}
+	}
+
+	// step 2
+	stemmed, _ = removeEnding(stemmed, rv, nil, []string{"и"})
+
+	// step 3
+	if r2 <= len(stemmed) {
+		stemmed, _ = removeEnding(stemmed, r2, nil, derivational)
+	}
+
+	// step 4
+	if undoubled, ok := removeEnding(stemmed, rv, nil, []string{"нн"}); ok {
+		return string(undoubled) + "н"
+	}
+	if withoutSuperlative, ok := removeEnding(stemmed, rv, nil, superlative); ok {
+		stemmed = withoutSuperlative
+		if undoubled, ok := removeEnding(stemmed, rv, nil, []string{"нн"}); ok {
+			return string(undoubled) + "н"
+		}
+		return string(stemmed)
+	}
+	stemmed, _ = removeEnding(stemmed, rv, nil, []string{"ь"})
+
+	return string(stemmed)
+}
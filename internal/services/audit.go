@@ -0,0 +1,136 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_interfaces"
+	"lab3/internal/services/service_interfaces"
+	"reflect"
+	"time"
+)
+
+// auditWorker is the snapshot of a worker written to the audit log, without the password hash.
+type auditWorker struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Surname     string    `json:"surname"`
+	Address     string    `json:"address"`
+	PhoneNumber string    `json:"phone_number"`
+	Email       string    `json:"email"`
+	Role        int       `json:"role"`
+	DeletedAt   time.Time `json:"deleted_at"`
+}
+
+// auditUser is the snapshot of a client written to the audit log, without the password hash.
+type auditUser struct {
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	Surname       string    `json:"surname"`
+	Address       string    `json:"address"`
+	PhoneNumber   string    `json:"phone_number"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+	DeletedAt     time.Time `json:"deleted_at"`
+}
+
+type AuditService struct {
+	AuditRepository repository_interfaces.IAuditRepository
+	logger          *log.Logger
+}
+
+func NewAuditService(AuditRepository repository_interfaces.IAuditRepository, logger *log.Logger) service_interfaces.IAuditService {
+	return &AuditService{
+		AuditRepository: AuditRepository,
+		logger:          logger,
+	}
+}
+
+func (a AuditService) Record(actor *models.Worker, ip string, action string, targetType string, targetID string, before interface{}, after interface{}) error {
+	beforeSnapshot, err := auditSnapshot(before)
+	if err != nil {
+		a.logger.Error("SERVICE: Error occurred during audit snapshot encoding", "error", err)
+		return err
+	}
+
+	afterSnapshot, err := auditSnapshot(after)
+	if err != nil {
+		a.logger.Error("SERVICE: Error occurred during audit snapshot encoding", "error", err)
+		return err
+	}
+
+	entry := &models.AuditEntry{
+		ActorID:    actor.ID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     beforeSnapshot,
+		After:      afterSnapshot,
+		IP:         ip,
+		CreatedAt:  time.Now(),
+	}
+
+	err = a.AuditRepository.Create(entry)
+	if err != nil {
+		a.logger.Error("SERVICE: Create method failed", "action", action, "target_id", targetID, "error", err)
+		return err
+	}
+
+	a.logger.Info("SERVICE: Audit entry recorded", "actor_id", actor.ID, "action", action, "target_id", targetID)
+	return nil
+}
+
+func (a AuditService) Filter(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	entries, err := a.AuditRepository.Filter(filter)
+	if err != nil {
+		a.logger.Error("SERVICE: Filter method failed", "error", err)
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func auditSnapshot(value interface{}) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return "", nil
+	}
+
+	if worker, ok := value.(*models.Worker); ok {
+		value = auditWorker{
+			ID:          worker.ID,
+			Name:        worker.Name,
+			Surname:     worker.Surname,
+			Address:     worker.Address,
+			PhoneNumber: worker.PhoneNumber,
+			Email:       worker.Email,
+			Role:        worker.Role,
+			DeletedAt:   worker.DeletedAt,
+		}
+	}
+
+	if user, ok := value.(*models.User); ok {
+		value = auditUser{
+			ID:            user.ID,
+			Name:          user.Name,
+			Surname:       user.Surname,
+			Address:       user.Address,
+			PhoneNumber:   user.PhoneNumber,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			DeletedAt:     user.DeletedAt,
+		}
+	}
+
+	snapshot, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(snapshot), nil
+}
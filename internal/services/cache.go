@@ -0,0 +1,37 @@
+package interfaces
+
+import (
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_interfaces"
+
+	"github.com/charmbracelet/log"
+)
+
+type CacheService struct {
+	caches []repository_interfaces.ICache
+	logger *log.Logger
+}
+
+func NewCacheService(caches []repository_interfaces.ICache, logger *log.Logger) *CacheService {
+	return &CacheService{
+		caches: caches,
+		logger: logger,
+	}
+}
+
+func (c *CacheService) Stats() []models.CacheStats {
+	stats := make([]models.CacheStats, len(c.caches))
+	for i, cache := range c.caches {
+		stats[i] = cache.Stats()
+	}
+
+	return stats
+}
+
+func (c *CacheService) Invalidate() {
+	for _, cache := range c.caches {
+		cache.Invalidate()
+	}
+
+	c.logger.Info("SERVICE: Caches invalidated", "count", len(c.caches))
+}
@@ -1,8 +1,12 @@
 package interfaces
 
 import (
+	"errors"
 	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
 	"lab3/internal/repository/repository_interfaces"
+	"lab3/internal/services/service_errors"
+	"lab3/internal/validators"
 
 	"github.com/charmbracelet/log"
 )
@@ -21,12 +25,25 @@ func NewCategoryService(CategoryRepository repository_interfaces.ICategoryReposi
 	}
 }
 
-func (c *CategoryService) Create(name string) (*models.Category, error) {
+func (c *CategoryService) Create(name string, parentID int, hidden bool, taxRate string) (*models.Category, error) {
+	if !validators.ValidName(name) {
+		c.logger.Error("Invalid category name")
+		return nil, service_errors.InvalidName
+	}
+
 	category := &models.Category{
-		Name: name,
+		Name:     name,
+		ParentID: parentID,
+		Hidden:   hidden,
+		TaxRate:  taxRate,
 	}
 
-	category, err := c.CategoryRepository.Create(category)
+	err := c.checkParent(category)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err = c.CategoryRepository.Create(category)
 	if err != nil {
 		c.logger.Error("Error creating category")
 		return nil, err
@@ -36,7 +53,17 @@ func (c *CategoryService) Create(name string) (*models.Category, error) {
 }
 
 func (c *CategoryService) Update(category *models.Category) (*models.Category, error) {
-	category, err := c.CategoryRepository.Update(category)
+	if !validators.ValidName(category.Name) {
+		c.logger.Error("Invalid category name")
+		return nil, service_errors.InvalidName
+	}
+
+	err := c.checkParent(category)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err = c.CategoryRepository.Update(category)
 	if err != nil {
 		c.logger.Error("Error updating category")
 		return nil, err
@@ -45,14 +72,233 @@ func (c *CategoryService) Update(category *models.Category) (*models.Category, e
 	return category, nil
 }
 
+// checkParent accepts a category nested in an active category that is not nested in the category itself.
+func (c *CategoryService) checkParent(category *models.Category) error {
+	visited := map[int]bool{}
+	for id := category.ParentID; id != 0; {
+		if id == category.ID || visited[id] {
+			c.logger.Error("Category is nested in itself", "category", category.ID, "parent", category.ParentID)
+			return service_errors.InvalidCategory
+		}
+		visited[id] = true
+
+		parent, err := c.CategoryRepository.GetByID(id)
+		if errors.Is(err, repository_errors.DoesNotExist) || (err == nil && id == category.ParentID && parent.IsDeleted()) {
+			c.logger.Error("Invalid parent category", "category", category.ID, "parent", category.ParentID)
+			return service_errors.InvalidCategory
+		} else if err != nil {
+			c.logger.Error("Error getting category by id")
+			return err
+		}
+		id = parent.ParentID
+	}
+
+	return nil
+}
+
+// Delete archives the category once it has no tasks and subcategories, so that nothing is left out of the catalog.
 func (c *CategoryService) Delete(id int) error {
-	err := c.CategoryRepository.Delete(id)
+	children, err := c.children(id)
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		c.logger.Error("Category has subcategories", "category", id, "subcategories", len(children))
+		return service_errors.CategoryHasSubcategories
+	}
+
+	tasks, err := c.TaskRepository.GetTasksInCategory(id)
+	if err != nil {
+		c.logger.Error("Error getting tasks in category")
+		return err
+	}
+	if len(tasks) > 0 {
+		c.logger.Error("Category has tasks", "category", id, "tasks", len(tasks))
+		return service_errors.CategoryIsNotEmpty
+	}
+
+	err = c.CategoryRepository.Delete(id)
 	if err != nil {
 		c.logger.Error("Error deleting category")
 	}
 	return err
 }
 
+func (c *CategoryService) Merge(id int, into int) error {
+	category, err := c.CategoryRepository.GetByID(id)
+	if err != nil {
+		c.logger.Error("Error getting category by id")
+		return err
+	}
+
+	target, err := c.CategoryRepository.GetByID(into)
+	if errors.Is(err, repository_errors.DoesNotExist) || (err == nil && (id == into || target.IsDeleted())) {
+		c.logger.Error("Invalid category to merge into", "category", id, "into", into)
+		return service_errors.InvalidCategory
+	} else if err != nil {
+		c.logger.Error("Error getting category by id")
+		return err
+	}
+
+	// the subcategories are moved to the target, so it must not be nested in the category
+	err = c.checkParent(&models.Category{ID: id, ParentID: into})
+	if err != nil {
+		return err
+	}
+
+	children, err := c.children(id)
+	if err != nil {
+		return err
+	}
+	for i := range children {
+		children[i].ParentID = into
+		_, err = c.Update(&children[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	moved, err := c.TaskRepository.MoveToCategory(id, into)
+	if err != nil {
+		c.logger.Error("Error moving tasks to category")
+		return err
+	}
+
+	// a merge that was interrupted after the move is finished by merging again
+	if !category.IsDeleted() {
+		err = c.CategoryRepository.Delete(id)
+		if err != nil {
+			c.logger.Error("Error deleting category")
+			return err
+		}
+	}
+
+	c.logger.Info("Merged category", "category", id, "into", into, "tasks", moved)
+	return nil
+}
+
+// children returns the active categories nested directly in the category.
+func (c *CategoryService) children(id int) ([]models.Category, error) {
+	categories, err := c.CategoryRepository.GetAll()
+	if err != nil {
+		c.logger.Error("Error getting all categories")
+		return nil, err
+	}
+
+	var children []models.Category
+	for _, category := range categories {
+		if category.ParentID == id {
+			children = append(children, category)
+		}
+	}
+	return children, nil
+}
+
+// Catalog orders the categories depth-first, the subcategories right after their parent.
+// A category nested in an archived one is shown at the top level.
+func (c *CategoryService) Catalog(includeHidden bool) ([]models.CategoryNode, error) {
+	categories, err := c.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	active := map[int]bool{}
+	for _, category := range categories {
+		active[category.ID] = true
+	}
+
+	children := map[int][]models.Category{}
+	for _, category := range categories {
+		parentID := category.ParentID
+		if !active[parentID] {
+			parentID = 0
+		}
+		children[parentID] = append(children[parentID], category)
+	}
+
+	var catalog []models.CategoryNode
+	var walk func(parentID int, depth int)
+	walk = func(parentID int, depth int) {
+		for _, category := range children[parentID] {
+			if category.Hidden && !includeHidden {
+				continue
+			}
+			catalog = append(catalog, models.CategoryNode{Category: category, Depth: depth})
+			walk(category.ID, depth+1)
+		}
+	}
+	walk(0, 0)
+
+	return catalog, nil
+}
+
+// Move swaps the category with the category offset places away among the categories nested in the same parent.
+// Moving past the first or the last place keeps the order.
+func (c *CategoryService) Move(id int, offset int) error {
+	categories, err := c.GetAll()
+	if err != nil {
+		return err
+	}
+
+	current := -1
+	for i, category := range categories {
+		if category.ID == id {
+			current = i
+		}
+	}
+	if current < 0 {
+		c.logger.Error("Category to move is not active", "category", id)
+		return service_errors.InvalidCategory
+	}
+
+	var siblings []int
+	for i, category := range categories {
+		if category.ParentID == categories[current].ParentID {
+			siblings = append(siblings, i)
+		}
+	}
+
+	for i, sibling := range siblings {
+		if sibling != current {
+			continue
+		}
+		if i+offset < 0 || i+offset >= len(siblings) {
+			return nil
+		}
+		other := siblings[i+offset]
+		categories[current], categories[other] = categories[other], categories[current]
+	}
+
+	ids := make([]int, len(categories))
+	for i, category := range categories {
+		ids[i] = category.ID
+	}
+
+	err = c.CategoryRepository.Reorder(ids)
+	if err != nil {
+		c.logger.Error("Error reordering categories")
+	}
+	return err
+}
+
+func (c *CategoryService) Restore(id int) error {
+	err := c.CategoryRepository.Restore(id)
+	if err != nil {
+		c.logger.Error("Error restoring category")
+	}
+	return err
+}
+
+func (c *CategoryService) GetDeleted() ([]models.Category, error) {
+	categories, err := c.CategoryRepository.GetDeleted()
+	if err != nil {
+		c.logger.Error("Error getting archived categories")
+		return nil, err
+	}
+
+	return categories, nil
+}
+
 func (c *CategoryService) GetAll() ([]models.Category, error) {
 	categories, err := c.CategoryRepository.GetAll()
 	if err != nil {
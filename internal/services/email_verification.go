@@ -0,0 +1,105 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"lab3/internal/services/service_errors"
+	"lab3/internal/services/service_interfaces"
+	"lab3/mail_sender"
+	"net/url"
+	"time"
+)
+
+const emailVerificationSubject = "Подтверждение адреса электронной почты"
+
+type EmailVerificationService struct {
+	UserRepository         repository_interfaces.IUserRepository
+	OneTimeTokenRepository repository_interfaces.IOneTimeTokenRepository
+	mailSender             mail_sender.MailSender
+	baseURL                string
+	tokenTTL               time.Duration
+	required               bool
+	logger                 *log.Logger
+}
+
+func NewEmailVerificationService(UserRepository repository_interfaces.IUserRepository, OneTimeTokenRepository repository_interfaces.IOneTimeTokenRepository, mailSender mail_sender.MailSender, baseURL string, tokenTTL time.Duration, required bool, logger *log.Logger) service_interfaces.IEmailVerificationService {
+	return &EmailVerificationService{
+		UserRepository:         UserRepository,
+		OneTimeTokenRepository: OneTimeTokenRepository,
+		mailSender:             mailSender,
+		baseURL:                baseURL,
+		tokenTTL:               tokenTTL,
+		required:               required,
+		logger:                 logger,
+	}
+}
+
+func (e EmailVerificationService) Required() bool {
+	return e.required
+}
+
+func (e EmailVerificationService) SendUserVerification(userID uuid.UUID) error {
+	user, err := e.UserRepository.GetUserByID(userID)
+	if err != nil {
+		e.logger.Error("SERVICE: GetUserByID method failed", "id", userID, "error", err)
+		return err
+	}
+
+	if user.EmailVerified {
+		e.logger.Info("SERVICE: Email is already verified", "user_id", user.ID)
+		return service_errors.EmailAlreadyVerified
+	}
+
+	token, expiresAt, err := issueOneTimeToken(e.OneTimeTokenRepository, models.EmailVerificationPurpose, user.ID, uuid.Nil, e.tokenTTL)
+	if err != nil {
+		e.logger.Error("SERVICE: Error occurred during email verification token issue", "error", err)
+		return err
+	}
+
+	link := e.baseURL + "/auth/verify-email?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Здравствуйте, %s!\n\n"+
+		"Чтобы подтвердить адрес электронной почты, перейдите по ссылке:\n%s\n\n"+
+		"Ссылка действительна до %s. Пока адрес не подтвержден, оформление заказов недоступно.\n",
+		user.Name, link, expiresAt.Format("02-01-2006 15:04"))
+
+	err = e.mailSender.Send(user.Email, emailVerificationSubject, body)
+	if err != nil {
+		e.logger.Error("SERVICE: Error occurred during sending verification mail", "email", user.Email, "error", err)
+		return err
+	}
+
+	e.logger.Info("SERVICE: Verification link sent", "user_id", user.ID)
+	return nil
+}
+
+func (e EmailVerificationService) VerifyUser(token string) (*models.User, error) {
+	stored, err := e.OneTimeTokenRepository.Consume(hashOneTimeToken(token), models.EmailVerificationPurpose, time.Now())
+	if errors.Is(err, repository_errors.DoesNotExist) {
+		e.logger.Info("SERVICE: Invalid or expired email verification token")
+		return nil, service_errors.InvalidToken
+	} else if err != nil {
+		e.logger.Error("SERVICE: Consume method failed", "error", err)
+		return nil, err
+	}
+
+	user, err := e.UserRepository.GetUserByID(stored.UserID)
+	if err != nil {
+		e.logger.Error("SERVICE: GetUserByID method failed", "id", stored.UserID, "error", err)
+		return nil, err
+	}
+
+	user.EmailVerified = true
+	user, err = e.UserRepository.Update(user)
+	if err != nil {
+		e.logger.Error("SERVICE: Update method failed", "error", err)
+		return nil, err
+	}
+
+	e.logger.Info("SERVICE: Successfully verified email", "user_id", user.ID)
+	return user, nil
+}
@@ -0,0 +1,142 @@
+package interfaces
+
+import (
+	"errors"
+	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"lab3/internal/services/service_errors"
+	"lab3/internal/services/service_interfaces"
+	"time"
+)
+
+type InvoiceService struct {
+	InvoiceRepository repository_interfaces.IInvoiceRepository
+	OrderService      service_interfaces.IOrderService
+	UserRepository    repository_interfaces.IUserRepository
+	seller            models.Requisites
+	logger            *log.Logger
+}
+
+// NewInvoiceService creates a service issuing invoices with the requisites of seller. The lines and the tax
+// of an invoice are those OrderService computes for the order.
+func NewInvoiceService(invoiceRepository repository_interfaces.IInvoiceRepository, orderService service_interfaces.IOrderService, userRepository repository_interfaces.IUserRepository, seller models.Requisites, logger *log.Logger) service_interfaces.IInvoiceService {
+	return &InvoiceService{
+		InvoiceRepository: invoiceRepository,
+		OrderService:      orderService,
+		UserRepository:    userRepository,
+		seller:            seller,
+		logger:            logger,
+	}
+}
+
+// lines returns the lines of the order with the prices in force when the order was created and the total
+// the client pays.
+func (i InvoiceService) lines(order *models.Order) ([]models.InvoiceLine, float64, error) {
+	totals, err := i.OrderService.GetTotals(order.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	lines := make([]models.InvoiceLine, 0, len(totals.Lines))
+	for _, line := range totals.Lines {
+		amount := line.Net
+		if totals.PricesIncludeTax {
+			amount = line.Gross
+		}
+
+		lines = append(lines, models.InvoiceLine{
+			Name:     line.Task.Name,
+			Price:    line.Price,
+			Quantity: line.Quantity,
+			Amount:   amount,
+			TaxName:  line.TaxRate.Name,
+			Tax:      line.Tax,
+		})
+	}
+
+	return lines, totals.Gross, nil
+}
+
+func (i InvoiceService) Issue(orderID uuid.UUID) (*models.Invoice, error) {
+	invoice, err := i.InvoiceRepository.GetByOrderID(orderID)
+	if err == nil {
+		return invoice, nil
+	} else if !errors.Is(err, repository_errors.DoesNotExist) {
+		i.logger.Error("SERVICE: GetByOrderID method failed", "order_id", orderID, "error", err)
+		return nil, err
+	}
+
+	order, err := i.OrderService.GetOrderByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != models.CompletedOrderStatus {
+		i.logger.Error("SERVICE: Order is not completed", "id", orderID, "status", order.Status)
+		return nil, service_errors.OrderIsNotCompleted
+	}
+
+	user, err := i.UserRepository.GetUserByID(order.UserID)
+	if err != nil {
+		i.logger.Error("SERVICE: GetUserByID method failed", "id", order.UserID, "error", err)
+		return nil, err
+	}
+
+	lines, total, err := i.lines(order)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err = i.InvoiceRepository.Create(&models.Invoice{
+		OrderID:  order.ID,
+		UserID:   order.UserID,
+		IssuedAt: time.Now(),
+		Seller:   i.seller,
+		Buyer:    user.Name + " " + user.Surname,
+		Address:  order.Address,
+		Lines:    lines,
+		Total:    total,
+	})
+	// the invoice was issued by a concurrent request
+	if errors.Is(err, repository_errors.AlreadyExists) {
+		return i.InvoiceRepository.GetByOrderID(orderID)
+	} else if err != nil {
+		i.logger.Error("SERVICE: Create method failed", "order_id", orderID, "error", err)
+		return nil, err
+	}
+
+	i.logger.Info("SERVICE: Successfully issued invoice", "order_id", orderID, "number", invoice.Number)
+	return invoice, nil
+}
+
+func (i InvoiceService) GetByID(id uuid.UUID) (*models.Invoice, error) {
+	invoice, err := i.InvoiceRepository.GetByID(id)
+	if err != nil {
+		i.logger.Error("SERVICE: GetByID method failed", "id", id, "error", err)
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+func (i InvoiceService) GetByOrderID(orderID uuid.UUID) (*models.Invoice, error) {
+	invoice, err := i.InvoiceRepository.GetByOrderID(orderID)
+	if err != nil && !errors.Is(err, repository_errors.DoesNotExist) {
+		i.logger.Error("SERVICE: GetByOrderID method failed", "order_id", orderID, "error", err)
+	}
+
+	return invoice, err
+}
+
+func (i InvoiceService) GetAll() ([]models.Invoice, error) {
+	invoices, err := i.InvoiceRepository.GetAll()
+	if err != nil {
+		i.logger.Error("SERVICE: GetAll method failed", "error", err)
+		return nil, err
+	}
+
+	return invoices, nil
+}
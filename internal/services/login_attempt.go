@@ -0,0 +1,171 @@
+package interfaces
+
+import (
+	"errors"
+	"github.com/charmbracelet/log"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"lab3/internal/services/service_errors"
+	"lab3/internal/services/service_interfaces"
+	"strings"
+	"time"
+)
+
+// LoginAttemptPolicy configures sign-in throttling. Zero limits disable the corresponding lockout.
+type LoginAttemptPolicy struct {
+	// MaxAttempts is the number of failures after which an account is locked.
+	MaxAttempts int
+	// IPMaxAttempts is the number of failures after which an IP address is locked.
+	IPMaxAttempts int
+	// FreeAttempts is the number of failures allowed without any delay.
+	FreeAttempts int
+	// BaseDelay is doubled for every failure above FreeAttempts, up to MaxDelay.
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	LockoutDuration time.Duration
+	// Window is the period after the last failure when the counter is reset.
+	Window time.Duration
+}
+
+type LoginAttemptService struct {
+	LoginAttemptRepository repository_interfaces.ILoginAttemptRepository
+	policy                 LoginAttemptPolicy
+	logger                 *log.Logger
+}
+
+func NewLoginAttemptService(LoginAttemptRepository repository_interfaces.ILoginAttemptRepository, policy LoginAttemptPolicy, logger *log.Logger) service_interfaces.ILoginAttemptService {
+	return &LoginAttemptService{
+		LoginAttemptRepository: LoginAttemptRepository,
+		policy:                 policy,
+		logger:                 logger,
+	}
+}
+
+func accountKey(scope string, email string) string {
+	return scope + ":" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return models.IPLoginScope + ":" + ip
+}
+
+func (l LoginAttemptService) delay(failures int) time.Duration {
+	if failures < l.policy.FreeAttempts || l.policy.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := l.policy.BaseDelay
+	for i := l.policy.FreeAttempts; i < failures; i++ {
+		delay *= 2
+		if l.policy.MaxDelay > 0 && delay >= l.policy.MaxDelay {
+			return l.policy.MaxDelay
+		}
+	}
+
+	return delay
+}
+
+func (l LoginAttemptService) check(key string, now time.Time) (time.Duration, error) {
+	attempt, err := l.LoginAttemptRepository.GetByKey(key)
+	if errors.Is(err, repository_errors.DoesNotExist) {
+		return 0, nil
+	} else if err != nil {
+		l.logger.Error("SERVICE: GetByKey method failed", "key", key, "error", err)
+		return 0, err
+	}
+
+	if attempt.LockedUntil.After(now) {
+		return attempt.LockedUntil.Sub(now), service_errors.LoginLocked
+	}
+
+	if l.policy.Window > 0 && attempt.LastFailure.Before(now.Add(-l.policy.Window)) {
+		return 0, nil
+	}
+
+	wait := attempt.LastFailure.Add(l.delay(attempt.Failures)).Sub(now)
+	if wait > 0 {
+		return wait, service_errors.LoginThrottled
+	}
+
+	return 0, nil
+}
+
+func (l LoginAttemptService) Check(scope string, email string, ip string) (time.Duration, error) {
+	now := time.Now()
+
+	wait, err := l.check(accountKey(scope, email), now)
+	if err != nil {
+		return wait, err
+	}
+
+	return l.check(ipKey(ip), now)
+}
+
+func (l LoginAttemptService) registerFailure(key string, limit int, now time.Time) error {
+	var resetBefore time.Time
+	if l.policy.Window > 0 {
+		resetBefore = now.Add(-l.policy.Window)
+	}
+
+	attempt, err := l.LoginAttemptRepository.RegisterFailure(key, now, resetBefore)
+	if err != nil {
+		l.logger.Error("SERVICE: RegisterFailure method failed", "key", key, "error", err)
+		return err
+	}
+
+	if limit > 0 && attempt.Failures >= limit {
+		err = l.LoginAttemptRepository.Lock(key, now.Add(l.policy.LockoutDuration))
+		if err != nil {
+			l.logger.Error("SERVICE: Lock method failed", "key", key, "error", err)
+			return err
+		}
+		l.logger.Warn("SERVICE: Sign in locked", "key", key, "failures", attempt.Failures)
+	}
+
+	return nil
+}
+
+func (l LoginAttemptService) RegisterFailure(scope string, email string, ip string) error {
+	now := time.Now()
+
+	err := l.registerFailure(accountKey(scope, email), l.policy.MaxAttempts, now)
+	if err != nil {
+		return err
+	}
+
+	return l.registerFailure(ipKey(ip), l.policy.IPMaxAttempts, now)
+}
+
+// RegisterSuccess resets the account counter. The IP counter is kept, so that signing in to
+// one's own account does not reset the limit for guessing others.
+func (l LoginAttemptService) RegisterSuccess(scope string, email string) error {
+	err := l.LoginAttemptRepository.Delete(accountKey(scope, email))
+	if err != nil {
+		l.logger.Error("SERVICE: Delete method failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (l LoginAttemptService) GetLocked() ([]models.LoginAttempt, error) {
+	attempts, err := l.LoginAttemptRepository.GetLocked(time.Now())
+	if err != nil {
+		l.logger.Error("SERVICE: GetLocked method failed", "error", err)
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+func (l LoginAttemptService) Unlock(key string) error {
+	err := l.LoginAttemptRepository.Delete(key)
+	if err != nil {
+		l.logger.Error("SERVICE: Delete method failed", "key", key, "error", err)
+		return err
+	}
+
+	l.logger.Info("SERVICE: Sign in unlocked", "key", key)
+	return nil
+}
@@ -0,0 +1,63 @@
+package interfaces
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/google/uuid"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_interfaces"
+	"time"
+)
+
+// newOneTimeToken generates a random token to be sent to its holder and the hash to be stored instead of it.
+func newOneTimeToken() (string, string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashOneTimeToken(token), nil
+}
+
+func hashOneTimeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// issueOneTimeToken stores a new token for the owner and invalidates the owner's previous tokens with the same purpose.
+// It returns the token to be sent and its expiration time.
+func issueOneTimeToken(repository repository_interfaces.IOneTimeTokenRepository, purpose string, userID uuid.UUID, workerID uuid.UUID, ttl time.Duration) (string, time.Time, error) {
+	token, hash, err := newOneTimeToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if userID != uuid.Nil {
+		err = repository.DeleteByUserID(purpose, userID)
+	} else {
+		err = repository.DeleteByWorkerID(purpose, workerID)
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	err = repository.Create(&models.OneTimeToken{
+		Hash:      hash,
+		Purpose:   purpose,
+		UserID:    userID,
+		WorkerID:  workerID,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
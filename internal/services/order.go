@@ -8,26 +8,36 @@ import (
 	"lab3/internal/models"
 	"lab3/internal/repository/repository_errors"
 	"lab3/internal/repository/repository_interfaces"
+	"lab3/internal/services/service_errors"
 	"lab3/internal/services/service_interfaces"
 	"lab3/internal/validators"
 	"time"
 )
 
+// maxIdempotencyKeyLength bounds the keys sent by clients in the Idempotency-Key header.
+const maxIdempotencyKeyLength = 255
+
 type OrderService struct {
-	OrderRepository  repository_interfaces.IOrderRepository
-	TaskRepository   repository_interfaces.ITaskRepository
-	WorkerRepository repository_interfaces.IWorkerRepository
-	UserRepository   repository_interfaces.IUserRepository
-	logger           *log.Logger
+	OrderRepository    repository_interfaces.IOrderRepository
+	TaskRepository     repository_interfaces.ITaskRepository
+	WorkerRepository   repository_interfaces.IWorkerRepository
+	UserRepository     repository_interfaces.IUserRepository
+	CategoryRepository repository_interfaces.ICategoryRepository
+	tax                TaxPolicy
+	verifyEmail        bool
+	logger             *log.Logger
 }
 
-func NewOrderService(orderRepository repository_interfaces.IOrderRepository, workerRepository repository_interfaces.IWorkerRepository, taskRepository repository_interfaces.ITaskRepository, userRepository repository_interfaces.IUserRepository, logger *log.Logger) service_interfaces.IOrderService {
+func NewOrderService(orderRepository repository_interfaces.IOrderRepository, workerRepository repository_interfaces.IWorkerRepository, taskRepository repository_interfaces.ITaskRepository, userRepository repository_interfaces.IUserRepository, categoryRepository repository_interfaces.ICategoryRepository, tax TaxPolicy, verifyEmail bool, logger *log.Logger) service_interfaces.IOrderService {
 	return &OrderService{
-		OrderRepository:  orderRepository,
-		TaskRepository:   taskRepository,
-		WorkerRepository: workerRepository,
-		UserRepository:   userRepository,
-		logger:           logger,
+		OrderRepository:    orderRepository,
+		TaskRepository:     taskRepository,
+		WorkerRepository:   workerRepository,
+		UserRepository:     userRepository,
+		CategoryRepository: categoryRepository,
+		tax:                tax,
+		verifyEmail:        verifyEmail,
+		logger:             logger,
 	}
 }
 
@@ -35,6 +45,21 @@ func orderIsCompleted(orderStatus int) bool {
 	return orderStatus == models.CompletedOrderStatus || orderStatus == models.CancelledOrderStatus
 }
 
+// getWritableOrder returns the order unless it is archived.
+func (o OrderService) getWritableOrder(id uuid.UUID) (*models.Order, error) {
+	order, err := o.OrderRepository.GetOrderByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Archived {
+		o.logger.Error("SERVICE: Order is archived", "id", id)
+		return nil, service_errors.OrderArchived
+	}
+
+	return order, nil
+}
+
 func (o OrderService) checkTasksExistence(tasks []models.OrderedTask) (bool, error) {
 	for _, task := range tasks {
 		if task.Quantity <= 0 {
@@ -42,7 +67,7 @@ func (o OrderService) checkTasksExistence(tasks []models.OrderedTask) (bool, err
 			return false, fmt.Errorf("SERVICE: Quantity is negative")
 		}
 
-		_, err := o.TaskRepository.GetTaskByID(task.Task.ID)
+		existingTask, err := o.TaskRepository.GetTaskByID(task.Task.ID)
 		if errors.Is(err, repository_errors.DoesNotExist) {
 			o.logger.Error("SERVICE: Task does not exist", "id", task.Task.ID)
 			return false, fmt.Errorf("SERVICE: Task does not exist")
@@ -50,12 +75,34 @@ func (o OrderService) checkTasksExistence(tasks []models.OrderedTask) (bool, err
 			o.logger.Error("SERVICE: GetTaskByID method failed", "id", task.Task.ID, "error", err)
 			return false, err
 		}
+
+		if existingTask.IsDeleted() {
+			o.logger.Error("SERVICE: Task is archived", "id", task.Task.ID)
+			return false, service_errors.Archived
+		}
 	}
 
 	return true, nil
 }
 
-func (o OrderService) CreateOrder(userID uuid.UUID, address string, deadline time.Time, orderedTasks []models.OrderedTask) (*models.Order, error) {
+func (o OrderService) CreateOrder(userID uuid.UUID, address string, deadline time.Time, orderedTasks []models.OrderedTask, idempotencyKey string) (*models.Order, error) {
+	// a repeated submission returns the order created by the first one
+	if idempotencyKey != "" {
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			o.logger.Error("SERVICE: Invalid idempotency key", "length", len(idempotencyKey))
+			return nil, service_errors.InvalidIdempotencyKey
+		}
+
+		order, err := o.OrderRepository.GetOrderByIdempotencyKey(userID, idempotencyKey)
+		if err == nil {
+			o.logger.Info("SERVICE: Order was already created with this idempotency key", "order", order)
+			return order, nil
+		} else if !errors.Is(err, repository_errors.DoesNotExist) {
+			o.logger.Error("SERVICE: GetOrderByIdempotencyKey method failed", "user_id", userID, "error", err)
+			return nil, err
+		}
+	}
+
 	// checking if order is valid
 	if !validators.ValidAddress(address) || !validators.ValidDeadline(deadline) || !validators.ValidTasksNumber(orderedTasks) {
 		o.logger.Error("SERVICE: Invalid input")
@@ -68,7 +115,7 @@ func (o OrderService) CreateOrder(userID uuid.UUID, address string, deadline tim
 	}
 
 	// checking if user exists
-	_, err := o.UserRepository.GetUserByID(userID)
+	user, err := o.UserRepository.GetUserByID(userID)
 	if errors.Is(err, repository_errors.DoesNotExist) {
 		o.logger.Error("SERVICE: User does not exist", "id", userID)
 		return nil, fmt.Errorf("SERVICE: User does not exist")
@@ -77,16 +124,38 @@ func (o OrderService) CreateOrder(userID uuid.UUID, address string, deadline tim
 		return nil, err
 	}
 
+	if user.IsDeleted() {
+		o.logger.Error("SERVICE: User is archived", "id", userID)
+		return nil, service_errors.Archived
+	}
+
+	// when verification is required only clients who confirmed their email order, whichever way the order comes
+	if o.verifyEmail && !user.EmailVerified {
+		o.logger.Error("SERVICE: User email is not verified", "id", userID)
+		return nil, service_errors.EmailNotVerified
+	}
+
 	// creating order
 	var order = &models.Order{
-		UserID:   userID,
-		Status:   models.NewOrderStatus,
-		Address:  address,
-		Deadline: deadline,
+		UserID:         userID,
+		Status:         models.NewOrderStatus,
+		Address:        address,
+		Deadline:       deadline,
+		IdempotencyKey: idempotencyKey,
 	}
 
 	order, err = o.OrderRepository.Create(order, orderedTasks)
-	if err != nil {
+	if errors.Is(err, repository_errors.AlreadyExists) && idempotencyKey != "" {
+		// a concurrent submission with the same key has created the order first
+		order, err = o.OrderRepository.GetOrderByIdempotencyKey(userID, idempotencyKey)
+		if err != nil {
+			o.logger.Error("SERVICE: GetOrderByIdempotencyKey method failed", "user_id", userID, "error", err)
+			return nil, err
+		}
+
+		o.logger.Info("SERVICE: Order was already created with this idempotency key", "order", order)
+		return order, nil
+	} else if err != nil {
 		o.logger.Error("SERVICE: Create method failed", "order", order, "error", err)
 		return nil, err
 	}
@@ -96,7 +165,7 @@ func (o OrderService) CreateOrder(userID uuid.UUID, address string, deadline tim
 }
 
 func (o OrderService) DeleteOrder(id uuid.UUID) error {
-	order, err := o.OrderRepository.GetOrderByID(id)
+	order, err := o.getWritableOrder(id)
 	if err != nil {
 		o.logger.Error("SERVICE: GetOrderByID method failed", "id", id, "error", err)
 		return err
@@ -127,8 +196,9 @@ func (o OrderService) DeleteOrder(id uuid.UUID) error {
 	return nil
 }
 
+// GetTasksInOrder returns the tasks with the prices in force when the order was created.
 func (o OrderService) GetTasksInOrder(orderID uuid.UUID) ([]models.Task, error) {
-	_, err := o.OrderRepository.GetOrderByID(orderID)
+	order, err := o.OrderRepository.GetOrderByID(orderID)
 	if err != nil {
 		o.logger.Error("SERVICE: GetOrderByID method failed", "id", orderID, "error", err)
 		return nil, err
@@ -140,6 +210,14 @@ func (o OrderService) GetTasksInOrder(orderID uuid.UUID) ([]models.Task, error)
 		return nil, err
 	}
 
+	for i := range tasks {
+		tasks[i].PricePerSingle, err = priceAt(o.TaskRepository, &tasks[i], order.CreationDate)
+		if err != nil {
+			o.logger.Error("SERVICE: GetPrices method failed", "task_id", tasks[i].ID, "error", err)
+			return nil, err
+		}
+	}
+
 	o.logger.Info("SERVICE: Successfully got tasks in order", "order_id", orderID)
 	return tasks, nil
 }
@@ -189,20 +267,59 @@ func (o OrderService) Filter(params map[string]string) ([]models.Order, error) {
 	return orders, nil
 }
 
-func (o OrderService) Update(orderID uuid.UUID, status int, rate int, workerID uuid.UUID) (*models.Order, error) {
-	order, err := o.OrderRepository.GetOrderByID(orderID)
+func (o OrderService) History(params map[string]string) ([]models.Order, error) {
+	orders, err := o.OrderRepository.History(params)
+	if err != nil {
+		o.logger.Error("SERVICE: History method failed", "params", params, "error", err)
+		return nil, err
+	}
+
+	o.logger.Info("SERVICE: Successfully got orders history", "params", params)
+	return orders, nil
+}
+
+func (o OrderService) Archive(months int) (int, error) {
+	if months <= 0 {
+		o.logger.Error("SERVICE: Invalid archive period", "months", months)
+		return 0, service_errors.InvalidArchivePeriod
+	}
+
+	closedBefore := time.Now().AddDate(0, -months, 0)
+	count, err := o.OrderRepository.Archive(closedBefore)
+	if err != nil {
+		o.logger.Error("SERVICE: Archive method failed", "closed_before", closedBefore, "error", err)
+		return 0, err
+	}
+
+	o.logger.Info("SERVICE: Successfully archived orders", "closed_before", closedBefore, "count", count)
+	return count, nil
+}
+
+func (o OrderService) Update(orderID uuid.UUID, status int, rate int, workerID uuid.UUID, version int) (*models.Order, error) {
+	order, err := o.getWritableOrder(orderID)
 	if err != nil {
 		o.logger.Error("SERVICE: GetOrderByID method failed", "id", orderID, "error", err)
 		return nil, err
 	}
 
+	if order.Version != version {
+		o.logger.Error("SERVICE: Order was changed concurrently", "id", orderID, "version", version, "current_version", order.Version)
+		return nil, service_errors.OrderVersionConflict
+	}
+
 	if workerID != uuid.Nil {
-		_, err = o.WorkerRepository.GetWorkerByID(workerID)
+		worker, err := o.WorkerRepository.GetWorkerByID(workerID)
 		if err != nil {
 			o.logger.Error("SERVICE: GetWorkerByID method failed", "id", workerID, "error", err)
 			return nil, err
 		}
 
+		// an archived worker keeps the orders assigned before, but gets no new ones
+		if worker.IsDeleted() && workerID != order.WorkerID {
+			o.logger.Error("SERVICE: Worker is archived", "id", workerID)
+			return nil, service_errors.Archived
+		}
+
 		order.WorkerID = workerID
 	} else {
 		order.WorkerID = uuid.Nil
@@ -217,10 +334,16 @@ func (o OrderService) Update(orderID uuid.UUID, status int, rate int, workerID u
 	if !validators.ValidStatus(status) {
 		o.logger.Error("SERVICE: Invalid status", "status", status)
 		return nil, fmt.Errorf("SERVICE: Invalid status")
-	} else {
-		order.Status = status
 	}
 
+	// the time of closing starts the period after which the order is archived
+	if !orderIsCompleted(status) {
+		order.ClosedAt = time.Time{}
+	} else if !orderIsCompleted(order.Status) || order.ClosedAt.IsZero() {
+		order.ClosedAt = time.Now()
+	}
+	order.Status = status
+
 	//for testing adding rate to an uncompleted order -> 0 = no status
 	if !orderIsCompleted(status) && rate != 0 {
 		o.logger.Error("SERVICE: Order is not completed", "order", order)
@@ -235,7 +358,10 @@ func (o OrderService) Update(orderID uuid.UUID, status int, rate int, workerID u
 	}
 
 	order, err = o.OrderRepository.Update(order)
-	if err != nil {
+	if errors.Is(err, repository_errors.VersionConflict) {
+		o.logger.Error("SERVICE: Order was changed concurrently", "id", orderID, "version", version)
+		return nil, service_errors.OrderVersionConflict
+	} else if err != nil {
 		o.logger.Error("SERVICE: Update method failed", "order", order, "error", err)
 		return nil, err
 	}
@@ -245,7 +371,7 @@ func (o OrderService) Update(orderID uuid.UUID, status int, rate int, workerID u
 }
 
 func (o OrderService) AddTask(orderID uuid.UUID, taskID uuid.UUID) error {
-	order, err := o.OrderRepository.GetOrderByID(orderID)
+	order, err := o.getWritableOrder(orderID)
 	if err != nil {
 		o.logger.Error("SERVICE: GetOrderByID method failed", "id", orderID, "error", err)
 		return err
@@ -253,12 +379,17 @@ func (o OrderService) AddTask(orderID uuid.UUID, taskID uuid.UUID) error {
 
 	attachedTasks, err := o.OrderRepository.GetTasksInOrder(order.ID)
 
-	_, err = o.TaskRepository.GetTaskByID(taskID)
+	task, err := o.TaskRepository.GetTaskByID(taskID)
 	if err != nil {
 		o.logger.Error("SERVICE: GetTaskByID method failed", "id", taskID, "error", err)
 		return err
 	}
 
+	if task.IsDeleted() {
+		o.logger.Error("SERVICE: Task is archived", "id", taskID)
+		return service_errors.Archived
+	}
+
 	if validators.TaskIsAttachedToOrder(taskID, attachedTasks) {
 		o.logger.Error("SERVICE: Task is already attached to order", "order_id", orderID, "task_id", taskID)
 		return fmt.Errorf("SERVICE: Task is already attached to order")
@@ -275,7 +406,7 @@ func (o OrderService) AddTask(orderID uuid.UUID, taskID uuid.UUID) error {
 }
 
 func (o OrderService) RemoveTask(orderID uuid.UUID, taskID uuid.UUID) error {
-	order, err := o.OrderRepository.GetOrderByID(orderID)
+	order, err := o.getWritableOrder(orderID)
 	if err != nil {
 		o.logger.Error("SERVICE: GetOrderByID method failed", "id", orderID, "error", err)
 		return err
@@ -321,7 +452,7 @@ func (o OrderService) GetOrderByID(id uuid.UUID) (*models.Order, error) {
 }
 
 func (o OrderService) IncrementTaskQuantity(id uuid.UUID, taskID uuid.UUID) (int, error) {
-	_, err := o.OrderRepository.GetOrderByID(id)
+	_, err := o.getWritableOrder(id)
 	if err != nil {
 		o.logger.Error("SERVICE: GetOrderByID method failed", "id", id, "error", err)
 		return 0, err
@@ -351,7 +482,7 @@ func (o OrderService) IncrementTaskQuantity(id uuid.UUID, taskID uuid.UUID) (int
 }
 
 func (o OrderService) DecrementTaskQuantity(id uuid.UUID, taskID uuid.UUID) (int, error) {
-	_, err := o.OrderRepository.GetOrderByID(id)
+	_, err := o.getWritableOrder(id)
 	if err != nil {
 		o.logger.Error("SERVICE: GetOrderByID method failed", "id", id, "error", err)
 		return 0, err
@@ -391,7 +522,7 @@ func (o OrderService) SetTaskQuantity(id uuid.UUID, taskID uuid.UUID, quantity i
 		return fmt.Errorf("SERVICE: Quantity is negative")
 	}
 
-	_, err := o.OrderRepository.GetOrderByID(id)
+	_, err := o.getWritableOrder(id)
 	if err != nil {
 		o.logger.Error("SERVICE: GetOrderByID method failed", "id", id, "error", err)
 		return err
@@ -437,23 +568,96 @@ func (o OrderService) GetTaskQuantity(orderID uuid.UUID, taskID uuid.UUID) (int,
 }
 
 func (o OrderService) GetTotalPrice(orderID uuid.UUID) (float64, error) {
-	orders, err := o.OrderRepository.GetTasksInOrder(orderID)
+	totals, err := o.GetTotals(orderID)
 	if err != nil {
-		o.logger.Error("SERVICE: GetTasksInOrder method failed", "order_id", orderID, "error", err)
 		return 0, err
 	}
 
-	var sum float64 = 0
-	for _, task := range orders {
-		var quantity int
-		quantity, err = o.OrderRepository.GetTaskQuantity(orderID, task.ID)
+	o.logger.Info("SERVICE: Successfully got total price", "order_id", orderID, "total_price", totals.Gross)
+	return totals.Gross, nil
+}
+
+// taxRate returns the rate of the task, of its category or of the nearest parent category that has one,
+// at last the default rate.
+func (o OrderService) taxRate(task *models.Task) (models.TaxRate, error) {
+	if len(o.tax.Rates) == 0 {
+		return models.TaxRate{}, nil
+	}
+
+	code := task.TaxRate
+	for id := task.Category; code == "" && id != 0; {
+		category, err := o.CategoryRepository.GetByID(id)
 		if err != nil {
-			o.logger.Error("SERVICE: GetTaskQuantity method failed", "order_id", orderID, "task_id", task.ID, "error", err)
-			return 0, err
+			o.logger.Error("SERVICE: GetByID method failed", "category_id", id, "error", err)
+			return models.TaxRate{}, err
 		}
-		sum += task.PricePerSingle * float64(quantity)
+		code, id = category.TaxRate, category.ParentID
 	}
+	if code == "" {
+		code = o.tax.DefaultRate
+	}
+
+	rate, found := o.tax.find(code)
+	if !found {
+		// a rate removed from the configuration does not stop the orders of the tasks that still refer to it
+		o.logger.Warn("SERVICE: Unknown tax rate, the default rate is used", "task_id", task.ID, "tax_rate", code)
+		rate, _ = o.tax.find(o.tax.DefaultRate)
+	}
+	return rate, nil
+}
+
+func (o OrderService) CalculateTotals(orderedTasks []models.OrderedTask) (*models.OrderTotals, error) {
+	totals := &models.OrderTotals{
+		Lines:            make([]models.OrderLine, 0, len(orderedTasks)),
+		PricesIncludeTax: o.tax.PricesIncludeTax,
+	}
+
+	for _, ordered := range orderedTasks {
+		rate, err := o.taxRate(ordered.Task)
+		if err != nil {
+			return nil, err
+		}
+
+		net, tax, gross := o.tax.split(ordered.Task.PricePerSingle*float64(ordered.Quantity), rate)
+		totals.Lines = append(totals.Lines, models.OrderLine{
+			Task:     *ordered.Task,
+			Price:    ordered.Task.PricePerSingle,
+			Quantity: ordered.Quantity,
+			TaxRate:  rate,
+			Net:      net,
+			Tax:      tax,
+			Gross:    gross,
+		})
+		totals.Net += net
+		totals.Tax += tax
+		totals.Gross += gross
+	}
+
+	totals.Net = roundMoney(totals.Net)
+	totals.Tax = roundMoney(totals.Tax)
+	totals.Gross = roundMoney(totals.Gross)
+	return totals, nil
+}
+
+func (o OrderService) GetTotals(orderID uuid.UUID) (*models.OrderTotals, error) {
+	tasks, err := o.GetTasksInOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	orderedTasks := make([]models.OrderedTask, 0, len(tasks))
+	for i := range tasks {
+		quantity, err := o.OrderRepository.GetTaskQuantity(orderID, tasks[i].ID)
+		if err != nil {
+			o.logger.Error("SERVICE: GetTaskQuantity method failed", "order_id", orderID, "task_id", tasks[i].ID, "error", err)
+			return nil, err
+		}
+		orderedTasks = append(orderedTasks, models.OrderedTask{Task: &tasks[i], Quantity: quantity})
+	}
+
+	return o.CalculateTotals(orderedTasks)
+}
 
-	o.logger.Info("SERVICE: Successfully got total price", "order_id", orderID, "total_price", sum)
-	return sum, nil
+func (o OrderService) TaxRates() []models.TaxRate {
+	return o.tax.Rates
 }
@@ -0,0 +1,194 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"lab3/internal/services/service_errors"
+	"lab3/internal/services/service_interfaces"
+	"lab3/internal/validators"
+	"lab3/mail_sender"
+	"lab3/password_hash"
+	"net/url"
+	"time"
+)
+
+const passwordResetSubject = "Восстановление пароля"
+
+type PasswordResetService struct {
+	UserRepository         repository_interfaces.IUserRepository
+	WorkerRepository       repository_interfaces.IWorkerRepository
+	OneTimeTokenRepository repository_interfaces.IOneTimeTokenRepository
+	SessionRepository      repository_interfaces.ISessionRepository
+	hash                   password_hash.PasswordHash
+	mailSender             mail_sender.MailSender
+	baseURL                string
+	tokenTTL               time.Duration
+	logger                 *log.Logger
+}
+
+// NewPasswordResetService creates a service that mails reset links pointing to baseURL. Links expire after tokenTTL.
+func NewPasswordResetService(UserRepository repository_interfaces.IUserRepository, WorkerRepository repository_interfaces.IWorkerRepository, OneTimeTokenRepository repository_interfaces.IOneTimeTokenRepository, SessionRepository repository_interfaces.ISessionRepository, hash password_hash.PasswordHash, mailSender mail_sender.MailSender, baseURL string, tokenTTL time.Duration, logger *log.Logger) service_interfaces.IPasswordResetService {
+	return &PasswordResetService{
+		UserRepository:         UserRepository,
+		WorkerRepository:       WorkerRepository,
+		OneTimeTokenRepository: OneTimeTokenRepository,
+		SessionRepository:      SessionRepository,
+		hash:                   hash,
+		mailSender:             mailSender,
+		baseURL:                baseURL,
+		tokenTTL:               tokenTTL,
+		logger:                 logger,
+	}
+}
+
+func (p PasswordResetService) sendResetLink(email string, name string, path string, token string, expiresAt time.Time) error {
+	link := p.baseURL + path + "?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Здравствуйте, %s!\n\n"+
+		"Для восстановления пароля перейдите по ссылке:\n%s\n\n"+
+		"Ссылка действительна до %s. Если вы не запрашивали восстановление пароля, просто проигнорируйте это письмо.\n",
+		name, link, expiresAt.Format("02-01-2006 15:04"))
+
+	err := p.mailSender.Send(email, passwordResetSubject, body)
+	if err != nil {
+		p.logger.Error("SERVICE: Error occurred during sending password reset mail", "email", email, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (p PasswordResetService) RequestUserReset(email string) error {
+	user, err := p.UserRepository.GetUserByEmail(email)
+	if errors.Is(err, repository_errors.DoesNotExist) {
+		p.logger.Info("SERVICE: Password reset requested for unknown user", "email", email)
+		return nil
+	} else if err != nil {
+		p.logger.Error("SERVICE: GetUserByEmail method failed", "email", email, "error", err)
+		return err
+	}
+
+	token, expiresAt, err := issueOneTimeToken(p.OneTimeTokenRepository, models.UserPasswordResetPurpose, user.ID, uuid.Nil, p.tokenTTL)
+	if err != nil {
+		p.logger.Error("SERVICE: Error occurred during password reset token issue", "error", err)
+		return err
+	}
+
+	p.logger.Info("SERVICE: Password reset requested", "user_id", user.ID)
+	return p.sendResetLink(user.Email, user.Name, "/auth/reset-password", token, expiresAt)
+}
+
+func (p PasswordResetService) RequestWorkerReset(email string) error {
+	worker, err := p.WorkerRepository.GetWorkerByEmail(email)
+	if errors.Is(err, repository_errors.DoesNotExist) {
+		p.logger.Info("SERVICE: Password reset requested for unknown worker", "email", email)
+		return nil
+	} else if err != nil {
+		p.logger.Error("SERVICE: GetWorkerByEmail method failed", "email", email, "error", err)
+		return err
+	}
+
+	token, expiresAt, err := issueOneTimeToken(p.OneTimeTokenRepository, models.WorkerPasswordResetPurpose, uuid.Nil, worker.ID, p.tokenTTL)
+	if err != nil {
+		p.logger.Error("SERVICE: Error occurred during password reset token issue", "error", err)
+		return err
+	}
+
+	p.logger.Info("SERVICE: Password reset requested", "worker_id", worker.ID)
+	return p.sendResetLink(worker.Email, worker.Name, "/worker-auth/reset-password", token, expiresAt)
+}
+
+func (p PasswordResetService) consumeToken(token string, purpose string) (*models.OneTimeToken, error) {
+	stored, err := p.OneTimeTokenRepository.Consume(hashOneTimeToken(token), purpose, time.Now())
+	if errors.Is(err, repository_errors.DoesNotExist) {
+		p.logger.Info("SERVICE: Invalid or expired password reset token")
+		return nil, service_errors.InvalidToken
+	} else if err != nil {
+		p.logger.Error("SERVICE: Consume method failed", "error", err)
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+func (p PasswordResetService) ResetUserPassword(token string, password string) error {
+	if !validators.ValidPassword(password) {
+		p.logger.Error("SERVICE: Invalid password")
+		return service_errors.InvalidPassword
+	}
+
+	stored, err := p.consumeToken(token, models.UserPasswordResetPurpose)
+	if err != nil {
+		return err
+	}
+
+	user, err := p.UserRepository.GetUserByID(stored.UserID)
+	if err != nil {
+		p.logger.Error("SERVICE: GetUserByID method failed", "id", stored.UserID, "error", err)
+		return err
+	}
+
+	user.Password, err = p.hash.GetHash(password)
+	if err != nil {
+		p.logger.Error("SERVICE: Error occurred during password hashing")
+		return err
+	}
+
+	_, err = p.UserRepository.Update(user)
+	if err != nil {
+		p.logger.Error("SERVICE: Update method failed", "error", err)
+		return err
+	}
+
+	err = p.SessionRepository.DeleteByUserID(user.ID)
+	if err != nil {
+		p.logger.Error("SERVICE: DeleteByUserID method failed", "error", err)
+		return err
+	}
+
+	p.logger.Info("SERVICE: Successfully reset user password", "user_id", user.ID)
+	return nil
+}
+
+func (p PasswordResetService) ResetWorkerPassword(token string, password string) error {
+	if !validators.ValidPassword(password) {
+		p.logger.Error("SERVICE: Invalid password")
+		return service_errors.InvalidPassword
+	}
+
+	stored, err := p.consumeToken(token, models.WorkerPasswordResetPurpose)
+	if err != nil {
+		return err
+	}
+
+	worker, err := p.WorkerRepository.GetWorkerByID(stored.WorkerID)
+	if err != nil {
+		p.logger.Error("SERVICE: GetWorkerByID method failed", "id", stored.WorkerID, "error", err)
+		return err
+	}
+
+	worker.Password, err = p.hash.GetHash(password)
+	if err != nil {
+		p.logger.Error("SERVICE: Error occurred during password hashing")
+		return err
+	}
+
+	_, err = p.WorkerRepository.Update(worker)
+	if err != nil {
+		p.logger.Error("SERVICE: Update method failed", "error", err)
+		return err
+	}
+
+	err = p.SessionRepository.DeleteByWorkerID(worker.ID)
+	if err != nil {
+		p.logger.Error("SERVICE: DeleteByWorkerID method failed", "error", err)
+		return err
+	}
+
+	p.logger.Info("SERVICE: Successfully reset worker password", "worker_id", worker.ID)
+	return nil
+}
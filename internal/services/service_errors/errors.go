@@ -24,4 +24,21 @@ var (
 	TaskIsNotAttachedToOrder     = errors.New("task is not attached to the order")
 	TaskIsAlreadyAttachedToOrder = errors.New("task is already attached to the order")
 	NegativeQuantity             = errors.New("quantity is negative")
+	SessionExpired               = errors.New("session expired")
+	LoginLocked                  = errors.New("sign in is temporarily locked")
+	LoginThrottled               = errors.New("too many sign in attempts")
+	InvalidToken                 = errors.New("invalid or expired token")
+	EmailAlreadyVerified         = errors.New("email is already verified")
+	InvalidTwoFactorCode         = errors.New("invalid two-factor code")
+	TwoFactorAlreadyEnabled      = errors.New("two-factor authentication is already enabled")
+	TwoFactorNotEnabled          = errors.New("two-factor authentication is not enabled")
+	InvalidIdempotencyKey        = errors.New("invalid idempotency key")
+	Archived                     = errors.New("the record is archived")
+	OrderVersionConflict         = errors.New("the order was changed by another user")
+	OrderArchived                = errors.New("the order is archived and can only be read")
+	InvalidArchivePeriod         = errors.New("invalid archive period")
+	CategoryIsNotEmpty           = errors.New("the category has tasks")
+	CategoryHasSubcategories     = errors.New("the category has subcategories")
+	InvalidEffectiveDate         = errors.New("a price change can only be scheduled for the future")
+	EmailNotVerified             = errors.New("email is not verified")
 )
@@ -0,0 +1,10 @@
+package service_interfaces
+
+import "lab3/internal/models"
+
+type IAuditService interface {
+	// Record stores an entry for the action of the actor. Before and after are snapshots of the
+	// target, nil when the target did not exist before or after the action.
+	Record(actor *models.Worker, ip string, action string, targetType string, targetID string, before interface{}, after interface{}) error
+	Filter(filter models.AuditFilter) ([]models.AuditEntry, error)
+}
@@ -0,0 +1,10 @@
+package service_interfaces
+
+import "lab3/internal/models"
+
+type ICacheService interface {
+	// Stats returns the statistics of every cache, empty when caching is disabled
+	Stats() []models.CacheStats
+	// Invalidate drops the results of every cache
+	Invalidate()
+}
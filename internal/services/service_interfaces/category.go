@@ -6,7 +6,20 @@ type ICategoryService interface {
 	GetAll() ([]models.Category, error)
 	GetTasksInCategory(id int) ([]models.Task, error)
 	GetByID(id int) (*models.Category, error)
-	Create(name string) (*models.Category, error)
+	// Create places the category after all the others. parentID is 0 for a top level category, an empty taxRate
+	// gives the tasks the rate of the parent category
+	Create(name string, parentID int, hidden bool, taxRate string) (*models.Category, error)
+	// Update fails with InvalidCategory when the parent is missing, archived or nested in the category
 	Update(category *models.Category) (*models.Category, error)
+	// Delete archives the category. It fails with CategoryIsNotEmpty while the category has tasks
+	// and with CategoryHasSubcategories while other categories are nested in it
 	Delete(id int) error
+	// Merge moves the tasks of the category, the archived ones too, and its subcategories to category into and archives the category
+	Merge(id int, into int) error
+	// Catalog returns the active categories in the order of the catalog, without the hidden ones unless includeHidden
+	Catalog(includeHidden bool) ([]models.CategoryNode, error)
+	// Move moves the category offset places among the categories nested in the same parent, negative offsets move it up
+	Move(id int, offset int) error
+	Restore(id int) error
+	GetDeleted() ([]models.Category, error)
 }
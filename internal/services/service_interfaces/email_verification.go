@@ -0,0 +1,14 @@
+package service_interfaces
+
+import (
+	"github.com/google/uuid"
+	"lab3/internal/models"
+)
+
+type IEmailVerificationService interface {
+	// Required reports whether clients must confirm their email before ordering.
+	Required() bool
+	// SendUserVerification mails a new verification link to the user. Previously sent links stop working.
+	SendUserVerification(userID uuid.UUID) error
+	VerifyUser(token string) (*models.User, error)
+}
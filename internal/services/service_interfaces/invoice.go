@@ -0,0 +1,16 @@
+package service_interfaces
+
+import (
+	"github.com/google/uuid"
+	"lab3/internal/models"
+)
+
+type IInvoiceService interface {
+	// Issue returns the invoice of the order, it is issued on the first call. Only completed orders have invoices
+	Issue(orderID uuid.UUID) (*models.Invoice, error)
+	GetByID(id uuid.UUID) (*models.Invoice, error)
+	// GetByOrderID fails with DoesNotExist when no invoice was issued for the order
+	GetByOrderID(orderID uuid.UUID) (*models.Invoice, error)
+	// GetAll returns the invoices, the latest first
+	GetAll() ([]models.Invoice, error)
+}
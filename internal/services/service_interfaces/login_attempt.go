@@ -0,0 +1,16 @@
+package service_interfaces
+
+import (
+	"lab3/internal/models"
+	"time"
+)
+
+type ILoginAttemptService interface {
+	// Check reports whether a sign-in attempt may proceed. When it may not, it returns
+	// service_errors.LoginLocked or service_errors.LoginThrottled and the time left to wait.
+	Check(scope string, email string, ip string) (time.Duration, error)
+	RegisterFailure(scope string, email string, ip string) error
+	RegisterSuccess(scope string, email string) error
+	GetLocked() ([]models.LoginAttempt, error)
+	Unlock(key string) error
+}
@@ -7,14 +7,16 @@ import (
 )
 
 type IOrderService interface {
-	CreateOrder(userID uuid.UUID, address string, deadline time.Time, orderedTasks []models.OrderedTask) (*models.Order, error)
+	CreateOrder(userID uuid.UUID, address string, deadline time.Time, orderedTasks []models.OrderedTask, idempotencyKey string) (*models.Order, error)
 	DeleteOrder(id uuid.UUID) error
 	GetTasksInOrder(orderID uuid.UUID) ([]models.Task, error)
 	GetOrderByID(id uuid.UUID) (*models.Order, error)
 	GetCurrentOrderByUserID(userID uuid.UUID) (*models.Order, error)
 	GetAllOrdersByUserID(userID uuid.UUID) ([]models.Order, error)
 
-	Update(orderID uuid.UUID, status int, rate int, workerID uuid.UUID) (*models.Order, error)
+	// Update changes the order read at the given version, a concurrent change of the order returns OrderVersionConflict.
+	// Archived orders cannot be changed, the methods changing an order return OrderArchived for them.
+	Update(orderID uuid.UUID, status int, rate int, workerID uuid.UUID, version int) (*models.Order, error)
 
 	AddTask(orderID uuid.UUID, tasksID uuid.UUID) error
 	RemoveTask(orderID uuid.UUID, taskID uuid.UUID) error
@@ -25,5 +27,18 @@ type IOrderService interface {
 	GetTaskQuantity(orderID uuid.UUID, taskID uuid.UUID) (int, error)
 
 	Filter(params map[string]string) ([]models.Order, error)
+	// History filters the current and the archived orders, the latest created first
+	History(params map[string]string) ([]models.Order, error)
+	// Archive moves the orders completed or cancelled more than the given number of months ago to the archive,
+	// where they can only be read. It returns the number of archived orders.
+	Archive(months int) (int, error)
+	// GetTotalPrice returns what the client pays for the order, the tax included
 	GetTotalPrice(orderID uuid.UUID) (float64, error)
+	// CalculateTotals returns the lines of the ordered tasks at their current prices with the net amounts, the tax
+	// and the gross amounts, for an order that is not created yet
+	CalculateTotals(orderedTasks []models.OrderedTask) (*models.OrderTotals, error)
+	// GetTotals returns the lines of the order and its sums at the prices in force when the order was created
+	GetTotals(orderID uuid.UUID) (*models.OrderTotals, error)
+	// TaxRates returns the configured VAT rates that categories and tasks may refer to
+	TaxRates() []models.TaxRate
 }
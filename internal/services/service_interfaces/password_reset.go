@@ -0,0 +1,9 @@
+package service_interfaces
+
+type IPasswordResetService interface {
+	// RequestUserReset mails a reset link to the user. Unknown emails are ignored without an error.
+	RequestUserReset(email string) error
+	RequestWorkerReset(email string) error
+	ResetUserPassword(token string, password string) error
+	ResetWorkerPassword(token string, password string) error
+}
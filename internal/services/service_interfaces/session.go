@@ -0,0 +1,15 @@
+package service_interfaces
+
+import (
+	"github.com/google/uuid"
+	"lab3/internal/models"
+)
+
+type ISessionService interface {
+	Resume(id string) (*models.Session, error)
+	Save(session *models.Session) error
+	Delete(id string) error
+	RevokeUserSessions(userID uuid.UUID) error
+	RevokeWorkerSessions(workerID uuid.UUID) error
+	PurgeExpired() error
+}
@@ -3,14 +3,33 @@ package service_interfaces
 import (
 	"github.com/google/uuid"
 	"lab3/internal/models"
+	"time"
 )
 
 type ITaskService interface {
-	Create(name string, price float64, category int) (*models.Task, error)
-	Update(taskID uuid.UUID, category int, name string, price float64) (*models.Task, error)
+	// Create gives the task the tax rate with the code taxRate, an empty one gives it the rate of its category
+	Create(name string, price float64, category int, taxRate string) (*models.Task, error)
+	// Update changes the price right away, SchedulePrice changes it later
+	Update(taskID uuid.UUID, category int, name string, price float64, taxRate string) (*models.Task, error)
+	// Delete archives the task
 	Delete(taskID uuid.UUID) error
+	Restore(taskID uuid.UUID) error
 	GetAllTasks() ([]models.Task, error)
+	GetDeletedTasks() ([]models.Task, error)
 	GetTaskByID(id uuid.UUID) (*models.Task, error)
 	GetTasksInCategory(category int) ([]models.Task, error)
 	GetTaskByName(name string) (*models.Task, error)
+	// Search returns the not archived tasks whose names have every word of query in any of its forms,
+	// the most relevant first
+	Search(query string) ([]models.Task, error)
+	// SchedulePrice announces a new price of the task in force from effectiveFrom, which must be in the future.
+	// A change scheduled for the same moment is replaced
+	SchedulePrice(taskID uuid.UUID, price float64, effectiveFrom time.Time) (*models.TaskPrice, error)
+	// CancelPriceChange deletes a scheduled price that is not in force yet and returns it
+	CancelPriceChange(priceID uuid.UUID) (*models.TaskPrice, error)
+	// GetUpcomingPrices returns the scheduled prices of all tasks, the earliest first
+	GetUpcomingPrices() ([]models.TaskPrice, error)
+	// ApplyScheduledPrices brings the scheduled prices that came in force to the tasks and returns
+	// how many tasks changed their price
+	ApplyScheduledPrices() (int, error)
 }
@@ -0,0 +1,22 @@
+package service_interfaces
+
+import (
+	"github.com/google/uuid"
+	"lab3/internal/models"
+)
+
+type ITwoFactorService interface {
+	IsEnabled(workerID uuid.UUID) (bool, error)
+	// IsRequired reports whether the worker may not use the panel until two-factor authentication is enabled.
+	IsRequired(worker *models.Worker) bool
+	// BeginEnrollment returns the secret to be added to an authenticator app. The secret is not
+	// used for sign in until the enrollment is confirmed with a code generated from it.
+	BeginEnrollment(worker *models.Worker) (*models.TwoFactorEnrollment, error)
+	// ConfirmEnrollment enables two-factor authentication and returns the one-time recovery codes.
+	ConfirmEnrollment(workerID uuid.UUID, code string) ([]string, error)
+	// Verify accepts either a code from the authenticator app or an unused recovery code.
+	Verify(workerID uuid.UUID, code string) error
+	RegenerateRecoveryCodes(workerID uuid.UUID) ([]string, error)
+	RecoveryCodesLeft(workerID uuid.UUID) (int, error)
+	Disable(workerID uuid.UUID) error
+}
@@ -11,4 +11,8 @@ type IUserService interface {
 	Login(email, password string) (*models.User, error)
 	Update(id uuid.UUID, name string, surname string, email string, address string, phoneNumber string, password string) (*models.User, error)
 	GetUserByEmail(email string) (*models.User, error)
+	// Delete archives the account
+	Delete(id uuid.UUID) error
+	Restore(id uuid.UUID) error
+	GetDeletedUsers() ([]models.User, error)
 }
@@ -8,9 +8,12 @@ import (
 type IWorkerService interface {
 	Login(email, password string) (*models.Worker, error)
 	Create(worker *models.Worker, password string) (*models.Worker, error)
+	// Delete archives the worker
 	Delete(id uuid.UUID) error
+	Restore(id uuid.UUID) error
 	GetWorkerByID(id uuid.UUID) (*models.Worker, error)
 	GetAllWorkers() ([]models.Worker, error)
+	GetDeletedWorkers() ([]models.Worker, error)
 	Update(id uuid.UUID, name string, surname string, email string, address string, phoneNumber string, role int, password string) (*models.Worker, error)
 
 	GetWorkersByRole(role int) ([]models.Worker, error)
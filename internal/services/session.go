@@ -0,0 +1,140 @@
+package interfaces
+
+import (
+	"errors"
+	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"lab3/internal/services/service_errors"
+	"lab3/internal/services/service_interfaces"
+	"time"
+)
+
+type SessionService struct {
+	SessionRepository repository_interfaces.ISessionRepository
+	idleTimeout       time.Duration
+	absoluteTimeout   time.Duration
+	logger            *log.Logger
+}
+
+// NewSessionService creates a session service. A zero timeout disables the corresponding check.
+func NewSessionService(SessionRepository repository_interfaces.ISessionRepository, idleTimeout time.Duration, absoluteTimeout time.Duration, logger *log.Logger) service_interfaces.ISessionService {
+	return &SessionService{
+		SessionRepository: SessionRepository,
+		idleTimeout:       idleTimeout,
+		absoluteTimeout:   absoluteTimeout,
+		logger:            logger,
+	}
+}
+
+func (s SessionService) isExpired(session *models.Session, now time.Time) bool {
+	if s.idleTimeout > 0 && now.Sub(session.LastActivity) > s.idleTimeout {
+		return true
+	}
+
+	if s.absoluteTimeout > 0 && now.Sub(session.CreatedAt) > s.absoluteTimeout {
+		return true
+	}
+
+	return false
+}
+
+// Resume loads a session and marks it as active. Expired sessions are deleted.
+func (s SessionService) Resume(id string) (*models.Session, error) {
+	session, err := s.SessionRepository.GetSessionByID(id)
+	if err != nil {
+		if !errors.Is(err, repository_errors.DoesNotExist) {
+			s.logger.Error("SERVICE: GetSessionByID method failed", "error", err)
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if s.isExpired(session, now) {
+		s.logger.Info("SERVICE: Session expired", "user_id", session.UserID, "worker_id", session.WorkerID)
+		err = s.SessionRepository.Delete(id)
+		if err != nil {
+			s.logger.Error("SERVICE: Delete method failed", "error", err)
+		}
+		return nil, service_errors.SessionExpired
+	}
+
+	err = s.SessionRepository.UpdateLastActivity(id, now)
+	if err != nil {
+		s.logger.Error("SERVICE: UpdateLastActivity method failed", "error", err)
+		return nil, err
+	}
+	session.LastActivity = now
+
+	return session, nil
+}
+
+func (s SessionService) Save(session *models.Session) error {
+	now := time.Now()
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = now
+	}
+	session.LastActivity = now
+
+	err := s.SessionRepository.Save(session)
+	if err != nil {
+		s.logger.Error("SERVICE: Save method failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s SessionService) Delete(id string) error {
+	err := s.SessionRepository.Delete(id)
+	if err != nil {
+		s.logger.Error("SERVICE: Delete method failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s SessionService) RevokeUserSessions(userID uuid.UUID) error {
+	err := s.SessionRepository.DeleteByUserID(userID)
+	if err != nil {
+		s.logger.Error("SERVICE: DeleteByUserID method failed", "user_id", userID, "error", err)
+		return err
+	}
+
+	s.logger.Info("SERVICE: Successfully revoked user sessions", "user_id", userID)
+	return nil
+}
+
+func (s SessionService) RevokeWorkerSessions(workerID uuid.UUID) error {
+	err := s.SessionRepository.DeleteByWorkerID(workerID)
+	if err != nil {
+		s.logger.Error("SERVICE: DeleteByWorkerID method failed", "worker_id", workerID, "error", err)
+		return err
+	}
+
+	s.logger.Info("SERVICE: Successfully revoked worker sessions", "worker_id", workerID)
+	return nil
+}
+
+func (s SessionService) PurgeExpired() error {
+	now := time.Now()
+	// a disabled timeout never matches: compare against the zero time
+	var idleBefore, createdBefore time.Time
+	if s.idleTimeout > 0 {
+		idleBefore = now.Add(-s.idleTimeout)
+	}
+	if s.absoluteTimeout > 0 {
+		createdBefore = now.Add(-s.absoluteTimeout)
+	}
+
+	err := s.SessionRepository.DeleteExpired(idleBefore, createdBefore)
+	if err != nil {
+		s.logger.Error("SERVICE: DeleteExpired method failed", "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -1,37 +1,62 @@
 package interfaces
 
 import (
+	"errors"
 	"fmt"
 	"github.com/charmbracelet/log"
 	"github.com/google/uuid"
 	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
 	"lab3/internal/repository/repository_interfaces"
+	"lab3/internal/services/service_errors"
 	"lab3/internal/services/service_interfaces"
 	"lab3/internal/validators"
+	"time"
 )
 
 type TaskService struct {
-	TaskRepository repository_interfaces.ITaskRepository
-	logger         *log.Logger
+	TaskRepository     repository_interfaces.ITaskRepository
+	CategoryRepository repository_interfaces.ICategoryRepository
+	logger             *log.Logger
 }
 
-func NewTaskService(TaskRepository repository_interfaces.ITaskRepository, logger *log.Logger) service_interfaces.ITaskService {
+func NewTaskService(TaskRepository repository_interfaces.ITaskRepository, CategoryRepository repository_interfaces.ICategoryRepository, logger *log.Logger) service_interfaces.ITaskService {
 	return &TaskService{
-		TaskRepository: TaskRepository,
-		logger:         logger,
+		TaskRepository:     TaskRepository,
+		CategoryRepository: CategoryRepository,
+		logger:             logger,
 	}
 }
 
-func (t TaskService) Create(name string, price float64, category int) (*models.Task, error) {
-	if !validators.ValidName(name) || !validators.ValidPrice(price) || !validators.ValidCategory(category) {
+// checkCategory returns InvalidCategory unless the category exists and is not archived.
+func (t TaskService) checkCategory(category int) error {
+	found, err := t.CategoryRepository.GetByID(category)
+	if errors.Is(err, repository_errors.DoesNotExist) || (err == nil && found.IsDeleted()) {
+		t.logger.Error("SERVICE: Invalid category", "category", category)
+		return service_errors.InvalidCategory
+	} else if err != nil {
+		t.logger.Error("SERVICE: GetByID method failed", "category", category, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (t TaskService) Create(name string, price float64, category int, taxRate string) (*models.Task, error) {
+	if !validators.ValidName(name) || !validators.ValidPrice(price) {
 		t.logger.Error("SERVICE: Invalid input")
 		return nil, fmt.Errorf("SERVICE: Invalid input")
 	}
 
+	if err := t.checkCategory(category); err != nil {
+		return nil, err
+	}
+
 	task := &models.Task{
 		Name:           name,
 		PricePerSingle: price,
 		Category:       category,
+		TaxRate:        taxRate,
 	}
 
 	task, err := t.TaskRepository.Create(task)
@@ -40,26 +65,50 @@ func (t TaskService) Create(name string, price float64, category int) (*models.T
 		return nil, err
 	}
 
+	// the first price starts the price history of the task
+	_, err = t.TaskRepository.SchedulePrice(&models.TaskPrice{TaskID: task.ID, Price: price, EffectiveFrom: time.Now()})
+	if err != nil {
+		t.logger.Error("SERVICE: SchedulePrice method failed", "task", task.ID, "error", err)
+		return nil, err
+	}
+
 	t.logger.Info("SERVICE: Successfully created new task", "task", task)
 	return task, nil
 }
 
-func (t TaskService) Update(taskID uuid.UUID, category int, name string, price float64) (*models.Task, error) {
+func (t TaskService) Update(taskID uuid.UUID, category int, name string, price float64, taxRate string) (*models.Task, error) {
 	task, err := t.GetTaskByID(taskID)
 	if err != nil {
 		t.logger.Error("SERVICE: GetTaskByID method failed", "id", taskID, "error", err)
 		return nil, err
 	}
 
-	if !validators.ValidCategory(category) || !validators.ValidName(name) || !validators.ValidPrice(price) {
+	if !validators.ValidName(name) || !validators.ValidPrice(price) {
 		t.logger.Error("SERVICE: Invalid input")
 		return nil, fmt.Errorf("SERVICE: Invalid input")
-	} else {
-		task.Category = category
-		task.Name = name
-		task.PricePerSingle = price
 	}
 
+	// a task may stay in its category after the category was archived
+	if category != task.Category {
+		if err = t.checkCategory(category); err != nil {
+			return nil, err
+		}
+	}
+
+	// a price changed right away is kept in the history too, so that older orders keep their price
+	if price != task.PricePerSingle {
+		_, err = t.TaskRepository.SchedulePrice(&models.TaskPrice{TaskID: taskID, Price: price, EffectiveFrom: time.Now()})
+		if err != nil {
+			t.logger.Error("SERVICE: SchedulePrice method failed", "task", taskID, "error", err)
+			return nil, err
+		}
+	}
+
+	task.Category = category
+	task.Name = name
+	task.PricePerSingle = price
+	task.TaxRate = taxRate
+
 	updatedTask, err := t.TaskRepository.Update(task)
 	if err != nil {
 		t.logger.Error("SERVICE: UpdateTask method failed", "error", err)
@@ -83,10 +132,32 @@ func (t TaskService) Delete(taskID uuid.UUID) error {
 		return err
 	}
 
-	t.logger.Info("SERVICE: Successfully deleted task", "task", taskID)
+	t.logger.Info("SERVICE: Successfully archived task", "task", taskID)
 	return nil
 }
 
+func (t TaskService) Restore(taskID uuid.UUID) error {
+	err := t.TaskRepository.Restore(taskID)
+	if err != nil {
+		t.logger.Error("SERVICE: Restore method failed", "id", taskID, "error", err)
+		return err
+	}
+
+	t.logger.Info("SERVICE: Successfully restored task", "task", taskID)
+	return nil
+}
+
+func (t TaskService) GetDeletedTasks() ([]models.Task, error) {
+	tasks, err := t.TaskRepository.GetDeletedTasks()
+	if err != nil {
+		t.logger.Error("SERVICE: GetDeletedTasks method failed", "error", err)
+		return nil, err
+	}
+
+	t.logger.Info("SERVICE: Successfully got archived tasks", "tasks", tasks)
+	return tasks, nil
+}
+
 func (t TaskService) GetAllTasks() ([]models.Task, error) {
 	tasks, err := t.TaskRepository.GetAllTasks()
 	if err != nil {
@@ -111,10 +182,13 @@ func (t TaskService) GetTaskByID(id uuid.UUID) (*models.Task, error) {
 }
 
 func (t TaskService) GetTasksInCategory(category int) ([]models.Task, error) {
-	print(category)
-	if !validators.ValidCategory(category) {
+	_, err := t.CategoryRepository.GetByID(category)
+	if errors.Is(err, repository_errors.DoesNotExist) {
 		t.logger.Error("SERVICE: Invalid category", "category", category)
-		return nil, fmt.Errorf("SERVICE: Invalid category")
+		return nil, service_errors.InvalidCategory
+	} else if err != nil {
+		t.logger.Error("SERVICE: GetByID method failed", "category", category, "error", err)
+		return nil, err
 	}
 
 	tasks, err := t.TaskRepository.GetTasksInCategory(category)
@@ -138,3 +212,110 @@ func (t TaskService) GetTaskByName(name string) (*models.Task, error) {
 	t.logger.Info("SERVICE: Successfully got task with GetTaskByName", "name", name)
 	return task, nil
 }
+
+func (t TaskService) Search(query string) ([]models.Task, error) {
+	tasks, err := t.TaskRepository.Search(query)
+	if err != nil {
+		t.logger.Error("SERVICE: Search method failed", "query", query, "error", err)
+		return nil, err
+	}
+
+	t.logger.Info("SERVICE: Successfully searched tasks", "query", query, "found", len(tasks))
+	return tasks, nil
+}
+
+func (t TaskService) SchedulePrice(taskID uuid.UUID, price float64, effectiveFrom time.Time) (*models.TaskPrice, error) {
+	if !validators.ValidPrice(price) {
+		t.logger.Error("SERVICE: Invalid price", "price", price)
+		return nil, service_errors.InvalidPrice
+	}
+	if !effectiveFrom.After(time.Now()) {
+		t.logger.Error("SERVICE: Invalid effective date", "effective_from", effectiveFrom)
+		return nil, service_errors.InvalidEffectiveDate
+	}
+
+	task, err := t.GetTaskByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.IsDeleted() {
+		t.logger.Error("SERVICE: Task is archived", "id", taskID)
+		return nil, service_errors.Archived
+	}
+
+	scheduled, err := t.TaskRepository.SchedulePrice(&models.TaskPrice{TaskID: taskID, Price: price, EffectiveFrom: effectiveFrom})
+	if err != nil {
+		t.logger.Error("SERVICE: SchedulePrice method failed", "task", taskID, "error", err)
+		return nil, err
+	}
+
+	t.logger.Info("SERVICE: Successfully scheduled price", "task", taskID, "price", price, "effective_from", effectiveFrom)
+	return scheduled, nil
+}
+
+func (t TaskService) CancelPriceChange(priceID uuid.UUID) (*models.TaskPrice, error) {
+	upcoming, err := t.GetUpcomingPrices()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, price := range upcoming {
+		if price.ID != priceID {
+			continue
+		}
+
+		err = t.TaskRepository.DeletePrice(priceID)
+		if err != nil {
+			t.logger.Error("SERVICE: DeletePrice method failed", "id", priceID, "error", err)
+			return nil, err
+		}
+
+		t.logger.Info("SERVICE: Successfully cancelled price change", "id", priceID, "task", price.TaskID)
+		return &price, nil
+	}
+
+	t.logger.Error("SERVICE: No upcoming price change", "id", priceID)
+	return nil, repository_errors.DoesNotExist
+}
+
+func (t TaskService) GetUpcomingPrices() ([]models.TaskPrice, error) {
+	prices, err := t.TaskRepository.GetUpcomingPrices(time.Now())
+	if err != nil {
+		t.logger.Error("SERVICE: GetUpcomingPrices method failed", "error", err)
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+func (t TaskService) ApplyScheduledPrices() (int, error) {
+	changed, err := t.TaskRepository.ApplyPrices(time.Now())
+	if err != nil {
+		t.logger.Error("SERVICE: ApplyPrices method failed", "error", err)
+		return 0, err
+	}
+
+	if changed > 0 {
+		t.logger.Info("SERVICE: Successfully applied scheduled prices", "tasks", changed)
+	}
+	return changed, nil
+}
+
+// priceAt returns the price of the task in force at moment. A task without a price in force then,
+// like a task created before the price history was kept, has its current price.
+func priceAt(repository repository_interfaces.ITaskRepository, task *models.Task, moment time.Time) (float64, error) {
+	prices, err := repository.GetPrices(task.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	price := task.PricePerSingle
+	for _, scheduled := range prices {
+		if scheduled.EffectiveFrom.After(moment) {
+			break
+		}
+		price = scheduled.Price
+	}
+
+	return price, nil
+}
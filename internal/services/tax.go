@@ -0,0 +1,41 @@
+package interfaces
+
+import (
+	"lab3/internal/models"
+	"math"
+)
+
+// TaxPolicy configures the VAT of orders. A task takes its own rate, then the rate of its category or of the
+// nearest parent category that has one, at last DefaultRate. Without Rates orders are not taxed.
+type TaxPolicy struct {
+	// PricesIncludeTax tells whether the prices of the catalog include the tax or the tax is added to them.
+	PricesIncludeTax bool
+	DefaultRate      string
+	Rates            []models.TaxRate
+}
+
+func (p TaxPolicy) find(code string) (models.TaxRate, bool) {
+	for _, rate := range p.Rates {
+		if rate.Code == code {
+			return rate, true
+		}
+	}
+	return models.TaxRate{}, false
+}
+
+// roundMoney rounds an amount to kopecks.
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// split returns the net amount, the tax and the gross amount of a line costing amount at the prices of the catalog.
+func (p TaxPolicy) split(amount float64, rate models.TaxRate) (float64, float64, float64) {
+	amount = roundMoney(amount)
+	if p.PricesIncludeTax {
+		tax := roundMoney(amount * rate.Rate / (100 + rate.Rate))
+		return roundMoney(amount - tax), tax, amount
+	}
+
+	tax := roundMoney(amount * rate.Rate / 100)
+	return amount, tax, roundMoney(amount + tax)
+}
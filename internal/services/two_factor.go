@@ -0,0 +1,331 @@
+package interfaces
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"image/png"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_interfaces"
+	"lab3/internal/services/service_errors"
+	"lab3/internal/services/service_interfaces"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	totpPeriod         = 30
+	totpSkew           = 1
+	recoveryCodesCount = 10
+	recoveryCodeLength = 10
+	qrCodeSize         = 200
+	// recoveryCodeAlphabet leaves out characters that are easy to confuse when copied by hand.
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+type TwoFactorService struct {
+	TwoFactorRepository repository_interfaces.ITwoFactorRepository
+	issuer              string
+	logger              *log.Logger
+}
+
+func NewTwoFactorService(TwoFactorRepository repository_interfaces.ITwoFactorRepository, issuer string, logger *log.Logger) service_interfaces.ITwoFactorService {
+	return &TwoFactorService{
+		TwoFactorRepository: TwoFactorRepository,
+		issuer:              issuer,
+		logger:              logger,
+	}
+}
+
+func (t TwoFactorService) IsEnabled(workerID uuid.UUID) (bool, error) {
+	twoFactor, err := t.TwoFactorRepository.GetByWorkerID(workerID)
+	if errors.Is(err, repository_errors.DoesNotExist) {
+		return false, nil
+	} else if err != nil {
+		t.logger.Error("SERVICE: GetByWorkerID method failed", "worker_id", workerID, "error", err)
+		return false, err
+	}
+
+	return twoFactor.Enabled, nil
+}
+
+func (t TwoFactorService) IsRequired(worker *models.Worker) bool {
+	return worker.Role == models.ManagerRole
+}
+
+func (t TwoFactorService) BeginEnrollment(worker *models.Worker) (*models.TwoFactorEnrollment, error) {
+	twoFactor, err := t.TwoFactorRepository.GetByWorkerID(worker.ID)
+	if err != nil && !errors.Is(err, repository_errors.DoesNotExist) {
+		t.logger.Error("SERVICE: GetByWorkerID method failed", "worker_id", worker.ID, "error", err)
+		return nil, err
+	}
+
+	if twoFactor != nil && twoFactor.Enabled {
+		t.logger.Info("SERVICE: Two-factor authentication is already enabled", "worker_id", worker.ID)
+		return nil, service_errors.TwoFactorAlreadyEnabled
+	}
+
+	// an unfinished enrollment keeps its secret, so that reloading the page does not invalidate an already scanned code
+	if twoFactor == nil {
+		key, err := totp.Generate(totp.GenerateOpts{Issuer: t.issuer, AccountName: worker.Email, Period: totpPeriod})
+		if err != nil {
+			t.logger.Error("SERVICE: Error occurred during TOTP secret generation", "error", err)
+			return nil, err
+		}
+
+		twoFactor = &models.TwoFactor{
+			WorkerID:  worker.ID,
+			Secret:    key.Secret(),
+			CreatedAt: time.Now(),
+		}
+		err = t.TwoFactorRepository.Save(twoFactor)
+		if err != nil {
+			t.logger.Error("SERVICE: Save method failed", "worker_id", worker.ID, "error", err)
+			return nil, err
+		}
+	}
+
+	key, err := t.key(worker.Email, twoFactor.Secret)
+	if err != nil {
+		t.logger.Error("SERVICE: Error occurred during TOTP key creation", "error", err)
+		return nil, err
+	}
+
+	image, err := key.Image(qrCodeSize, qrCodeSize)
+	if err != nil {
+		t.logger.Error("SERVICE: Error occurred during QR code generation", "error", err)
+		return nil, err
+	}
+
+	var qrCode bytes.Buffer
+	err = png.Encode(&qrCode, image)
+	if err != nil {
+		t.logger.Error("SERVICE: Error occurred during QR code encoding", "error", err)
+		return nil, err
+	}
+
+	t.logger.Info("SERVICE: Two-factor enrollment started", "worker_id", worker.ID)
+	return &models.TwoFactorEnrollment{
+		Secret: twoFactor.Secret,
+		URL:    key.URL(),
+		QRCode: qrCode.Bytes(),
+	}, nil
+}
+
+func (t TwoFactorService) ConfirmEnrollment(workerID uuid.UUID, code string) ([]string, error) {
+	twoFactor, err := t.TwoFactorRepository.GetByWorkerID(workerID)
+	if errors.Is(err, repository_errors.DoesNotExist) {
+		t.logger.Info("SERVICE: Two-factor enrollment was not started", "worker_id", workerID)
+		return nil, service_errors.TwoFactorNotEnabled
+	} else if err != nil {
+		t.logger.Error("SERVICE: GetByWorkerID method failed", "worker_id", workerID, "error", err)
+		return nil, err
+	}
+
+	if twoFactor.Enabled {
+		t.logger.Info("SERVICE: Two-factor authentication is already enabled", "worker_id", workerID)
+		return nil, service_errors.TwoFactorAlreadyEnabled
+	}
+
+	step, ok := matchTOTPStep(twoFactor.Secret, code, time.Now())
+	if !ok {
+		t.logger.Info("SERVICE: Invalid two-factor code", "worker_id", workerID)
+		return nil, service_errors.InvalidTwoFactorCode
+	}
+
+	twoFactor.Enabled = true
+	twoFactor.LastUsedStep = step
+	err = t.TwoFactorRepository.Save(twoFactor)
+	if err != nil {
+		t.logger.Error("SERVICE: Save method failed", "worker_id", workerID, "error", err)
+		return nil, err
+	}
+
+	codes, err := t.replaceRecoveryCodes(workerID)
+	if err != nil {
+		return nil, err
+	}
+
+	t.logger.Info("SERVICE: Two-factor authentication enabled", "worker_id", workerID)
+	return codes, nil
+}
+
+func (t TwoFactorService) Verify(workerID uuid.UUID, code string) error {
+	twoFactor, err := t.TwoFactorRepository.GetByWorkerID(workerID)
+	if errors.Is(err, repository_errors.DoesNotExist) {
+		t.logger.Info("SERVICE: Two-factor authentication is not enabled", "worker_id", workerID)
+		return service_errors.TwoFactorNotEnabled
+	} else if err != nil {
+		t.logger.Error("SERVICE: GetByWorkerID method failed", "worker_id", workerID, "error", err)
+		return err
+	}
+
+	if !twoFactor.Enabled {
+		t.logger.Info("SERVICE: Two-factor authentication is not enabled", "worker_id", workerID)
+		return service_errors.TwoFactorNotEnabled
+	}
+
+	if step, ok := matchTOTPStep(twoFactor.Secret, code, time.Now()); ok {
+		err = t.TwoFactorRepository.MarkStepUsed(workerID, step)
+		if errors.Is(err, repository_errors.DoesNotExist) {
+			t.logger.Info("SERVICE: Two-factor code was already used", "worker_id", workerID)
+			return service_errors.InvalidTwoFactorCode
+		} else if err != nil {
+			t.logger.Error("SERVICE: MarkStepUsed method failed", "worker_id", workerID, "error", err)
+			return err
+		}
+
+		t.logger.Info("SERVICE: Two-factor code accepted", "worker_id", workerID)
+		return nil
+	}
+
+	err = t.TwoFactorRepository.ConsumeRecoveryCode(workerID, hashOneTimeToken(normalizeRecoveryCode(code)))
+	if errors.Is(err, repository_errors.DoesNotExist) {
+		t.logger.Info("SERVICE: Invalid two-factor code", "worker_id", workerID)
+		return service_errors.InvalidTwoFactorCode
+	} else if err != nil {
+		t.logger.Error("SERVICE: ConsumeRecoveryCode method failed", "worker_id", workerID, "error", err)
+		return err
+	}
+
+	t.logger.Info("SERVICE: Recovery code used", "worker_id", workerID)
+	return nil
+}
+
+func (t TwoFactorService) RegenerateRecoveryCodes(workerID uuid.UUID) ([]string, error) {
+	enabled, err := t.IsEnabled(workerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !enabled {
+		t.logger.Info("SERVICE: Two-factor authentication is not enabled", "worker_id", workerID)
+		return nil, service_errors.TwoFactorNotEnabled
+	}
+
+	codes, err := t.replaceRecoveryCodes(workerID)
+	if err != nil {
+		return nil, err
+	}
+
+	t.logger.Info("SERVICE: Recovery codes regenerated", "worker_id", workerID)
+	return codes, nil
+}
+
+func (t TwoFactorService) RecoveryCodesLeft(workerID uuid.UUID) (int, error) {
+	count, err := t.TwoFactorRepository.CountRecoveryCodes(workerID)
+	if err != nil {
+		t.logger.Error("SERVICE: CountRecoveryCodes method failed", "worker_id", workerID, "error", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (t TwoFactorService) Disable(workerID uuid.UUID) error {
+	err := t.TwoFactorRepository.Delete(workerID)
+	if err != nil {
+		t.logger.Error("SERVICE: Delete method failed", "worker_id", workerID, "error", err)
+		return err
+	}
+
+	t.logger.Info("SERVICE: Two-factor authentication disabled", "worker_id", workerID)
+	return nil
+}
+
+func (t TwoFactorService) key(accountName string, secret string) (*otp.Key, error) {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", t.issuer)
+	query.Set("period", fmt.Sprint(totpPeriod))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + t.issuer + ":" + accountName,
+		RawQuery: query.Encode(),
+	}
+
+	return otp.NewKeyFromURL(u.String())
+}
+
+func (t TwoFactorService) replaceRecoveryCodes(workerID uuid.UUID) ([]string, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([]string, 0, recoveryCodesCount)
+	for i := 0; i < recoveryCodesCount; i++ {
+		code, err := newRecoveryCode()
+		if err != nil {
+			t.logger.Error("SERVICE: Error occurred during recovery code generation", "error", err)
+			return nil, err
+		}
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashOneTimeToken(normalizeRecoveryCode(code)))
+	}
+
+	err := t.TwoFactorRepository.ReplaceRecoveryCodes(workerID, hashes)
+	if err != nil {
+		t.logger.Error("SERVICE: ReplaceRecoveryCodes method failed", "worker_id", workerID, "error", err)
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// matchTOTPStep checks the code against the current time step and its neighbours to tolerate clock drift,
+// and returns the step the code belongs to.
+func matchTOTPStep(secret string, code string, now time.Time) (int64, bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != int(otp.DigitsSix) {
+		return 0, false
+	}
+
+	for skew := -totpSkew; skew <= totpSkew; skew++ {
+		at := now.Add(time.Duration(skew*totpPeriod) * time.Second)
+		expected, err := totp.GenerateCodeCustom(secret, at, totp.ValidateOpts{
+			Period:    totpPeriod,
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(code), []byte(expected)) == 1 {
+			return at.Unix() / totpPeriod, true
+		}
+	}
+
+	return 0, false
+}
+
+// newRecoveryCode returns a code formatted as two groups of five characters, e.g. "k3m9p-x2qrt".
+func newRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeLength)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	code := make([]byte, 0, recoveryCodeLength+1)
+	for i, v := range b {
+		if i == recoveryCodeLength/2 {
+			code = append(code, '-')
+		}
+		code = append(code, recoveryCodeAlphabet[int(v)%len(recoveryCodeAlphabet)])
+	}
+
+	return string(code), nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+}
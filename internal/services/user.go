@@ -147,10 +147,35 @@ func (u UserService) Login(email, password string) (*models.User, error) {
 		return nil, fmt.Errorf("SERVICE: Password is incorrect for user with email")
 	}
 
+	u.rehashPassword(tempUser, password)
+
 	u.logger.Info("SERVICE: Successfully logged in user with email", "email", email)
 	return tempUser, nil
 }
 
+// rehashPassword upgrades a hash made with an outdated algorithm or parameters. A failure does not prevent the login.
+func (u UserService) rehashPassword(user *models.User, password string) {
+	rehasher, ok := u.hash.(password_hash.Rehasher)
+	if !ok || !rehasher.NeedsRehash(user.Password) {
+		return
+	}
+
+	hashedPassword, err := u.hash.GetHash(password)
+	if err != nil {
+		u.logger.Error("SERVICE: Error occurred during password rehash", "error", err)
+		return
+	}
+
+	user.Password = hashedPassword
+	_, err = u.UserRepository.Update(user)
+	if err != nil {
+		u.logger.Error("SERVICE: Update method failed during password rehash", "id", user.ID, "error", err)
+		return
+	}
+
+	u.logger.Info("SERVICE: Password hash upgraded", "id", user.ID)
+}
+
 func (u UserService) Update(id uuid.UUID, name string, surname string, email string, address string, phoneNumber string, password string) (*models.User, error) {
 	user, err := u.UserRepository.GetUserByID(id)
 	if err != nil {
@@ -163,6 +188,10 @@ func (u UserService) Update(id uuid.UUID, name string, surname string, email str
 		return nil, fmt.Errorf("SERVICE: Invalid input")
 	}
 
+	if user.Email != email {
+		user.EmailVerified = false
+	}
+
 	user.Name = name
 	user.Surname = surname
 	user.Email = email
@@ -188,3 +217,37 @@ func (u UserService) Update(id uuid.UUID, name string, surname string, email str
 	u.logger.Info("SERVICE: Successfully updated user personal information", "user", user)
 	return user, nil
 }
+
+// Delete archives the account. The orders of the user are kept.
+func (u UserService) Delete(id uuid.UUID) error {
+	err := u.UserRepository.Delete(id)
+	if err != nil {
+		u.logger.Error("SERVICE: Delete method failed", "id", id, "error", err)
+		return err
+	}
+
+	u.logger.Info("SERVICE: Successfully archived user", "id", id)
+	return nil
+}
+
+func (u UserService) Restore(id uuid.UUID) error {
+	err := u.UserRepository.Restore(id)
+	if err != nil {
+		u.logger.Error("SERVICE: Restore method failed", "id", id, "error", err)
+		return err
+	}
+
+	u.logger.Info("SERVICE: Successfully restored user", "id", id)
+	return nil
+}
+
+func (u UserService) GetDeletedUsers() ([]models.User, error) {
+	users, err := u.UserRepository.GetDeletedUsers()
+	if err != nil {
+		u.logger.Error("SERVICE: GetDeletedUsers method failed", "error", err)
+		return nil, err
+	}
+
+	u.logger.Info("SERVICE: Successfully got archived users")
+	return users, nil
+}
@@ -59,10 +59,35 @@ func (w WorkerService) Login(email, password string) (*models.Worker, error) {
 		return nil, fmt.Errorf("SERVICE: Password is incorrect for worker with email")
 	}
 
+	w.rehashPassword(tempWorker, password)
+
 	w.logger.Info("SERVICE: Successfully logged in worker with email", "email", email)
 	return tempWorker, nil
 }
 
+// rehashPassword upgrades a hash made with an outdated algorithm or parameters. A failure does not prevent the login.
+func (w WorkerService) rehashPassword(worker *models.Worker, password string) {
+	rehasher, ok := w.hash.(password_hash.Rehasher)
+	if !ok || !rehasher.NeedsRehash(worker.Password) {
+		return
+	}
+
+	hashedPassword, err := w.hash.GetHash(password)
+	if err != nil {
+		w.logger.Error("SERVICE: Error occurred during password rehash", "error", err)
+		return
+	}
+
+	worker.Password = hashedPassword
+	_, err = w.WorkerRepository.Update(worker)
+	if err != nil {
+		w.logger.Error("SERVICE: Update method failed during password rehash", "id", worker.ID, "error", err)
+		return
+	}
+
+	w.logger.Info("SERVICE: Password hash upgraded", "id", worker.ID)
+}
+
 func (w WorkerService) Create(worker *models.Worker, password string) (*models.Worker, error) {
 	w.logger.Info("SERVICE: Validating data")
 	if !validators.ValidName(worker.Name) || !validators.ValidName(worker.Surname) || !validators.ValidEmail(worker.Email) || !validators.ValidAddress(worker.Address) || !validators.ValidPhoneNumber(worker.PhoneNumber) || !validators.ValidRole(worker.Role) || !validators.ValidPassword(password) {
@@ -110,12 +135,36 @@ func (w WorkerService) Delete(id uuid.UUID) error {
 	err = w.WorkerRepository.Delete(id)
 	if err != nil {
 		w.logger.Error("SERVICE: Delete method failed", "error", err)
+		return err
+	}
+
+	w.logger.Info("SERVICE: Successfully archived worker", "id", id)
+	return nil
+}
+
+func (w WorkerService) Restore(id uuid.UUID) error {
+	err := w.WorkerRepository.Restore(id)
+	if err != nil {
+		w.logger.Error("SERVICE: Restore method failed", "id", id, "error", err)
+		return err
 	}
 
-	w.logger.Info("SERVICE: Successfully deleted worker", "id", id)
+	w.logger.Info("SERVICE: Successfully restored worker", "id", id)
 	return nil
 }
 
+func (w WorkerService) GetDeletedWorkers() ([]models.Worker, error) {
+	workers, err := w.WorkerRepository.GetDeletedWorkers()
+
+	if err != nil {
+		w.logger.Error("SERVICE: GetDeletedWorkers method failed", "error", err)
+		return nil, err
+	}
+
+	w.logger.Info("SERVICE: Successfully got archived workers")
+	return workers, nil
+}
+
 func (w WorkerService) GetWorkerByID(id uuid.UUID) (*models.Worker, error) {
 	worker, err := w.WorkerRepository.GetWorkerByID(id)
 
@@ -16,10 +16,6 @@ func ValidPrice(price float64) bool {
 	return price > 0
 }
 
-func ValidCategory(category int) bool {
-	return category > 0 && category < 9
-}
-
 func ValidEmail(email string) bool {
 	_, err := mail.ParseAddress(email)
 	return err == nil
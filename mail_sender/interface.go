@@ -0,0 +1,5 @@
+package mail_sender
+
+type MailSender interface {
+	Send(to string, subject string, body string) error
+}
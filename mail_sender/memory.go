@@ -0,0 +1,38 @@
+package mail_sender
+
+import "sync"
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// MemorySender keeps sent messages in memory instead of delivering them. It is meant for tests
+// and local development.
+type MemorySender struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemorySender() *MemorySender {
+	return &MemorySender{}
+}
+
+func (m *MemorySender) Send(to string, subject string, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, Message{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+// Messages returns a copy of all messages sent so far.
+func (m *MemorySender) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	messages := make([]Message, len(m.messages))
+	copy(messages, m.messages)
+	return messages
+}
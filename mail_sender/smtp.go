@@ -0,0 +1,45 @@
+package mail_sender
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+type smtpSender struct {
+	address string
+	auth    smtp.Auth
+	from    string
+}
+
+// NewSMTPSender creates a sender that delivers mail through an SMTP server.
+// Authentication is skipped when username is empty.
+func NewSMTPSender(host string, port string, username string, password string, from string) MailSender {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &smtpSender{
+		address: net.JoinHostPort(host, port),
+		auth:    auth,
+		from:    from,
+	}
+}
+
+func (s *smtpSender) Send(to string, subject string, body string) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	// header values must be ASCII, the Cyrillic subject is sent as an encoded word
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", subject))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(body)
+
+	return smtp.SendMail(s.address, s.auth, s.from, []string{to}, []byte(msg.String()))
+}
@@ -6,6 +6,7 @@ import (
 	"lab3/internal/models"
 	"lab3/internal/registry"
 	"lab3/server"
+	"os"
 
 	"github.com/charmbracelet/log"
 )
@@ -18,6 +19,15 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// "migrate up|down|status" manages the schema and exits, so migrations are not applied on start
+	migrateCommand := len(os.Args) > 1 && os.Args[1] == "migrate"
+	if migrateCommand {
+		app.Config.MigrateOnStart = false
+	}
+
+	// "archive [months]" moves old closed orders to the archive and exits
+	archiveCommand := len(os.Args) > 1 && os.Args[1] == "archive"
+
 	err = app.Run()
 
 	if err != nil {
@@ -25,6 +35,22 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if migrateCommand {
+		err = cmd.RunMigrate(app.Migrator, os.Args[2:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if archiveCommand {
+		err = cmd.RunArchive(app.Services.OrderService, app.Config.Archive.AfterMonths, os.Args[2:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	err = initAdmin(app.Services)
 	if err != nil {
 		log.Fatal(err)
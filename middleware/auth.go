@@ -38,7 +38,7 @@ func (m *Middleware) AuthMiddleware() gin.HandlerFunc {
 		// Check if the user exists
 		userId, err := uuid.Parse(strUserId)
 		user, err := m.Services.UserService.GetUserByID(userId)
-		if err != nil || user.ID == uuid.Nil {
+		if err != nil || user.ID == uuid.Nil || user.IsDeleted() {
 			c.Redirect(http.StatusMovedPermanently, "/auth/signin")
 			c.Abort()
 			return
@@ -69,7 +69,7 @@ func (m *Middleware) WorkerMiddleware() gin.HandlerFunc {
 		// Check if the user exists
 		workerID, err := uuid.Parse(strWorkerId)
 		worker, err := m.Services.WorkerService.GetWorkerByID(workerID)
-		if err != nil || worker.ID == uuid.Nil {
+		if err != nil || worker.ID == uuid.Nil || worker.IsDeleted() {
 			c.Redirect(http.StatusMovedPermanently, "/worker-auth/signin")
 			c.Abort()
 			return
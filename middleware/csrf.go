@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/contrib/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// CSRFTokenKey is the session and context key holding the token of the current session.
+	CSRFTokenKey = "csrfToken"
+	// CSRFFormField is the name of the hidden input carrying the token in HTML forms.
+	CSRFFormField = "csrf_token"
+	// CSRFHeader carries the token for JSON requests.
+	CSRFHeader = "X-CSRF-Token"
+)
+
+// CSRFMiddleware issues a token per session and rejects state-changing requests that do not echo it back.
+// Forms send the token in the CSRFFormField field, JSON requests in the CSRFHeader header.
+func (m *Middleware) CSRFMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session := sessions.Default(c)
+		token, _ := session.Get(CSRFTokenKey).(string)
+		if token == "" {
+			var err error
+			token, err = generateCSRFToken()
+			if err != nil {
+				c.AbortWithStatus(http.StatusInternalServerError)
+				return
+			}
+			session.Set(CSRFTokenKey, token)
+			session.Save()
+		}
+		c.Set(CSRFTokenKey, token)
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		submitted := c.GetHeader(CSRFHeader)
+		if submitted == "" && !isJSONRequest(c) {
+			submitted = c.PostForm(CSRFFormField)
+		}
+
+		if subtle.ConstantTimeCompare([]byte(submitted), []byte(token)) != 1 {
+			if isJSONRequest(c) || c.GetHeader(CSRFHeader) != "" {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+					"error": "Invalid CSRF token",
+				})
+				return
+			}
+			c.String(http.StatusForbidden, "Недействительный CSRF-токен. Обновите страницу и попробуйте снова.")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func isJSONRequest(c *gin.Context) bool {
+	return strings.HasPrefix(c.ContentType(), "application/json")
+}
+
+func generateCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"encoding/base32"
+	"lab3/internal/models"
+	"lab3/internal/services/service_interfaces"
+	"net/http"
+
+	"github.com/gin-gonic/contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/securecookie"
+	gsessions "github.com/gorilla/sessions"
+)
+
+var base32RawStdEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// renewSessionKey marks a session that is saved under a new ID, see RenewSession.
+const renewSessionKey = "renewSessionID"
+
+// RenewSession makes the next save of the session delete it under its current ID and store it under a new one,
+// with a new CSRF token. It is called on sign-in, so that an ID or a token known before the sign-in is worth nothing after it.
+func RenewSession(c *gin.Context) error {
+	token, err := generateCSRFToken()
+	if err != nil {
+		return err
+	}
+
+	session := sessions.Default(c)
+	session.Set(renewSessionKey, true)
+	session.Set(CSRFTokenKey, token)
+	c.Set(CSRFTokenKey, token)
+	return nil
+}
+
+// SessionStore keeps session values in the database and only the signed session ID in the cookie,
+// so sessions can expire and be revoked on the server side.
+type SessionStore struct {
+	Codecs  []securecookie.Codec
+	options *gsessions.Options
+	service service_interfaces.ISessionService
+}
+
+func NewSessionStore(service service_interfaces.ISessionService, maxAge int, keyPairs ...[]byte) *SessionStore {
+	store := &SessionStore{
+		Codecs: securecookie.CodecsFromPairs(keyPairs...),
+		options: &gsessions.Options{
+			Path:     "/",
+			MaxAge:   maxAge,
+			HttpOnly: true,
+		},
+		service: service,
+	}
+
+	for _, codec := range store.Codecs {
+		if sc, ok := codec.(*securecookie.SecureCookie); ok {
+			sc.MaxAge(maxAge)
+		}
+	}
+
+	return store
+}
+
+func (s *SessionStore) Options(options sessions.Options) {
+	s.options = &gsessions.Options{
+		Path:     options.Path,
+		Domain:   options.Domain,
+		MaxAge:   options.MaxAge,
+		Secure:   options.Secure,
+		HttpOnly: options.HttpOnly,
+	}
+}
+
+func (s *SessionStore) Get(r *http.Request, name string) (*gsessions.Session, error) {
+	return gsessions.GetRegistry(r).Get(s, name)
+}
+
+// New returns the stored session for the request cookie or a fresh one if it is missing, expired or revoked.
+func (s *SessionStore) New(r *http.Request, name string) (*gsessions.Session, error) {
+	session := gsessions.NewSession(s, name)
+	opts := *s.options
+	session.Options = &opts
+	session.IsNew = true
+
+	c, errCookie := r.Cookie(name)
+	if errCookie != nil {
+		return session, nil
+	}
+
+	var id string
+	err := securecookie.DecodeMulti(name, c.Value, &id, s.Codecs...)
+	if err != nil {
+		return session, err
+	}
+
+	stored, err := s.service.Resume(id)
+	if err != nil {
+		return session, nil
+	}
+
+	err = securecookie.DecodeMulti(name, stored.Data, &session.Values, s.Codecs...)
+	if err != nil {
+		return session, err
+	}
+
+	session.ID = stored.ID
+	session.IsNew = false
+	return session, nil
+}
+
+// Save persists the session values. A negative MaxAge deletes the session, a renewed session gets a new ID.
+func (s *SessionStore) Save(r *http.Request, w http.ResponseWriter, session *gsessions.Session) error {
+	if session.Options.MaxAge < 0 {
+		if session.ID != "" {
+			if err := s.service.Delete(session.ID); err != nil {
+				return err
+			}
+		}
+		http.SetCookie(w, gsessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	if renew, _ := session.Values[renewSessionKey].(bool); renew {
+		delete(session.Values, renewSessionKey)
+		if session.ID != "" {
+			if err := s.service.Delete(session.ID); err != nil {
+				return err
+			}
+		}
+		session.ID = ""
+	}
+
+	if session.ID == "" {
+		session.ID = base32RawStdEncoding.EncodeToString(securecookie.GenerateRandomKey(32))
+	}
+
+	data, err := securecookie.EncodeMulti(session.Name(), session.Values, s.Codecs...)
+	if err != nil {
+		return err
+	}
+
+	err = s.service.Save(&models.Session{
+		ID:       session.ID,
+		UserID:   sessionOwnerID(session, "userID"),
+		WorkerID: sessionOwnerID(session, "workerID"),
+		Data:     data,
+	})
+	if err != nil {
+		return err
+	}
+
+	encoded, err := securecookie.EncodeMulti(session.Name(), session.ID, s.Codecs...)
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, gsessions.NewCookie(session.Name(), encoded, session.Options))
+	return nil
+}
+
+func sessionOwnerID(session *gsessions.Session, key string) uuid.UUID {
+	strID, ok := session.Values[key].(string)
+	if !ok {
+		return uuid.Nil
+	}
+
+	id, err := uuid.Parse(strID)
+	if err != nil {
+		return uuid.Nil
+	}
+
+	return id
+}
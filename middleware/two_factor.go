@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// twoFactorSetupPath is left open to workers who still have to enable two-factor authentication.
+const twoFactorSetupPath = "/worker/two-factor"
+
+// TwoFactorEnrollmentMiddleware sends workers for whom two-factor authentication is mandatory to the
+// enrollment page until they enable it. It must run after WorkerMiddleware.
+func (m *Middleware) TwoFactorEnrollmentMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.HasPrefix(c.Request.URL.Path, twoFactorSetupPath) {
+			c.Next()
+			return
+		}
+
+		worker, err := m.Services.WorkerService.GetWorkerByID(c.MustGet("workerID").(uuid.UUID))
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		if !m.Services.TwoFactorService.IsRequired(worker) {
+			c.Next()
+			return
+		}
+
+		enabled, err := m.Services.TwoFactorService.IsEnabled(worker.ID)
+		if err != nil {
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		if !enabled {
+			c.Redirect(http.StatusFound, twoFactorSetupPath)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
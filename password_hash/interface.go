@@ -4,3 +4,16 @@ type PasswordHash interface {
 	GetHash(stringToHash string) (string, error)
 	CompareHashAndPassword(hashedPassword, plainPassword string) bool
 }
+
+// Hasher is a password hashing algorithm that can be registered in a Registry.
+type Hasher interface {
+	PasswordHash
+	// Recognizes reports whether the stored hash has the format produced by this hasher.
+	Recognizes(hashedPassword string) bool
+}
+
+// Rehasher is implemented by hashes that can tell when a stored hash is outdated,
+// i.e. was produced by another algorithm or with weaker parameters.
+type Rehasher interface {
+	NeedsRehash(hashedPassword string) bool
+}
@@ -1,18 +1,29 @@
 package password_hash
 
 import (
+	"strings"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
 type bcryptHash struct {
+	cost int
 }
 
 func NewPasswordHash() PasswordHash {
-	return &bcryptHash{}
+	return NewBcryptHash(bcrypt.DefaultCost)
+}
+
+func NewBcryptHash(cost int) Hasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+
+	return &bcryptHash{cost: cost}
 }
 
 func (b *bcryptHash) GetHash(stringToHash string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(stringToHash), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(stringToHash), b.cost)
 	return string(hashedPassword), err
 }
 
@@ -20,3 +31,12 @@ func (b *bcryptHash) CompareHashAndPassword(hashedPassword, plainPassword string
 	res := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(plainPassword))
 	return res == nil
 }
+
+func (b *bcryptHash) Recognizes(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, "$2a$") || strings.HasPrefix(hashedPassword, "$2b$") || strings.HasPrefix(hashedPassword, "$2y$")
+}
+
+func (b *bcryptHash) NeedsRehash(hashedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	return err != nil || cost < b.cost
+}
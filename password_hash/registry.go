@@ -0,0 +1,51 @@
+package password_hash
+
+// Registry hashes new passwords with the preferred hasher and checks stored hashes with
+// whichever registered hasher recognizes their format.
+type Registry struct {
+	preferred Hasher
+	hashers   []Hasher
+}
+
+func NewRegistry(preferred Hasher, others ...Hasher) *Registry {
+	return &Registry{
+		preferred: preferred,
+		hashers:   append([]Hasher{preferred}, others...),
+	}
+}
+
+func (r *Registry) GetHash(stringToHash string) (string, error) {
+	return r.preferred.GetHash(stringToHash)
+}
+
+func (r *Registry) CompareHashAndPassword(hashedPassword, plainPassword string) bool {
+	hasher := r.hasherFor(hashedPassword)
+	if hasher == nil {
+		return false
+	}
+
+	return hasher.CompareHashAndPassword(hashedPassword, plainPassword)
+}
+
+// NeedsRehash reports whether the hash was not produced by the preferred hasher with its current parameters.
+func (r *Registry) NeedsRehash(hashedPassword string) bool {
+	if !r.preferred.Recognizes(hashedPassword) {
+		return true
+	}
+
+	if rehasher, ok := r.preferred.(Rehasher); ok {
+		return rehasher.NeedsRehash(hashedPassword)
+	}
+
+	return false
+}
+
+func (r *Registry) hasherFor(hashedPassword string) Hasher {
+	for _, hasher := range r.hashers {
+		if hasher.Recognizes(hashedPassword) {
+			return hasher
+		}
+	}
+
+	return nil
+}
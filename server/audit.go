@@ -0,0 +1,129 @@
+package server
+
+import (
+	"lab3/internal/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const auditPageLimit = 200
+
+// audit records an administrative action. Only actions of managers are recorded, and a failure to
+// record does not undo the action: the service logs it. The recorded IP is the peer address unless the
+// request came through one of the trusted proxies of the config, so a client cannot forge it with a header.
+func (s *Services) audit(c *gin.Context, actor *models.Worker, action string, targetType string, targetID string, before interface{}, after interface{}) {
+	if actor == nil || actor.Role != models.ManagerRole {
+		return
+	}
+
+	_ = s.Services.AuditService.Record(actor, c.ClientIP(), action, targetType, targetID, before, after)
+}
+
+type auditFilterData struct {
+	Actor      string `form:"actor"`
+	Action     string `form:"action"`
+	TargetType string `form:"target_type"`
+	TargetID   string `form:"target_id"`
+	From       string `form:"from"`
+	To         string `form:"to"`
+}
+
+type auditEntryData struct {
+	CreatedAt  string
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+	Before     string
+	After      string
+	IP         string
+}
+
+var auditTargetNames = map[string]string{
+	models.AuditTargetWorker:   "Исполнитель",
+	models.AuditTargetUser:     "Клиент",
+	models.AuditTargetTask:     "Услуга",
+	models.AuditTargetCategory: "Категория",
+	models.AuditTargetOrder:    "Заказ",
+}
+
+func (s *Services) auditLog(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "auditLog", gin.H{"title": "Журнал действий", "error": "Доступ запрещен!"})
+		return
+	}
+
+	var data auditFilterData
+	_ = c.BindQuery(&data)
+
+	filter := models.AuditFilter{
+		Action:     data.Action,
+		TargetType: data.TargetType,
+		TargetID:   data.TargetID,
+		Limit:      auditPageLimit,
+	}
+	if actorID, err := uuid.Parse(data.Actor); err == nil {
+		filter.ActorID = actorID
+	}
+	if from, err := time.ParseInLocation("2006-01-02", data.From, time.Local); err == nil {
+		filter.From = from
+	}
+	if to, err := time.ParseInLocation("2006-01-02", data.To, time.Local); err == nil {
+		// the end date is inclusive
+		filter.To = to.AddDate(0, 0, 1)
+	}
+
+	managers, _ := s.Services.WorkerService.GetWorkersByRole(models.ManagerRole)
+	actorNames := make(map[uuid.UUID]string, len(managers))
+	for _, manager := range managers {
+		actorNames[manager.ID] = manager.FullName()
+	}
+
+	entries, err := s.Services.AuditService.Filter(filter)
+	if err != nil {
+		html(c, http.StatusInternalServerError, "auditLog", gin.H{
+			"title":    "Журнал действий",
+			"worker":   worker,
+			"error":    "Не удалось загрузить журнал действий",
+			"filter":   data,
+			"managers": managers,
+			"actions":  models.AuditActions,
+			"targets":  auditTargetNames,
+		})
+		return
+	}
+
+	entriesData := make([]auditEntryData, len(entries))
+	for i, entry := range entries {
+		actor, ok := actorNames[entry.ActorID]
+		if !ok {
+			actor = entry.ActorID.String()
+		}
+
+		entriesData[i] = auditEntryData{
+			CreatedAt:  entry.CreatedAt.Format("2006-01-02 15:04:05"),
+			Actor:      actor,
+			Action:     models.AuditActions[entry.Action],
+			TargetType: auditTargetNames[entry.TargetType],
+			TargetID:   entry.TargetID,
+			Before:     entry.Before,
+			After:      entry.After,
+			IP:         entry.IP,
+		}
+	}
+
+	html(c, 200, "auditLog", gin.H{
+		"title":    "Журнал действий",
+		"worker":   worker,
+		"entries":  entriesData,
+		"filter":   data,
+		"managers": managers,
+		"actions":  models.AuditActions,
+		"targets":  auditTargetNames,
+	})
+}
@@ -1,11 +1,17 @@
 package server
 
 import (
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/contrib/sessions"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"lab3/internal/models"
+	"lab3/internal/services/service_errors"
+	"lab3/middleware"
+	"math"
 	"net/http"
+	"time"
 )
 
 type fullFormData struct {
@@ -28,7 +34,7 @@ func (s *Services) authenticatedUser(c *gin.Context) *models.User {
 			userId, err := uuid.Parse(strUserID)
 			if err == nil {
 				user, err := s.Services.UserService.GetUserByID(userId)
-				if err == nil {
+				if err == nil && !user.IsDeleted() {
 					return user
 				}
 			}
@@ -38,7 +44,7 @@ func (s *Services) authenticatedUser(c *gin.Context) *models.User {
 }
 
 func (s *Services) signupGet(c *gin.Context) {
-	c.HTML(200, "signup", gin.H{
+	html(c, 200, "signup", gin.H{
 		"title": "Регистрация",
 	})
 }
@@ -46,7 +52,7 @@ func (s *Services) signupGet(c *gin.Context) {
 func (s *Services) signupPost(c *gin.Context) {
 	var data fullFormData
 	if err := c.Bind(&data); err != nil {
-		c.HTML(http.StatusBadRequest, "signup", gin.H{
+		html(c, http.StatusBadRequest, "signup", gin.H{
 			"title":    "Регистрация",
 			"error":    err.Error(),
 			"formData": data,
@@ -55,7 +61,7 @@ func (s *Services) signupPost(c *gin.Context) {
 	}
 
 	if data.Password != data.PasswordRepeat {
-		c.HTML(http.StatusBadRequest, "signup", gin.H{
+		html(c, http.StatusBadRequest, "signup", gin.H{
 			"title":    "Регистрация",
 			"error":    "Пароли не совпадают",
 			"formData": data,
@@ -66,7 +72,7 @@ func (s *Services) signupPost(c *gin.Context) {
 	// Check if the user exists already
 	_, err := s.Services.UserService.GetUserByEmail(data.Email)
 	if err == nil {
-		c.HTML(http.StatusBadRequest, "signup", gin.H{
+		html(c, http.StatusBadRequest, "signup", gin.H{
 			"title":    "Регистрация",
 			"error":    "Пользователь с таким email уже существует",
 			"formData": data,
@@ -84,7 +90,7 @@ func (s *Services) signupPost(c *gin.Context) {
 	}, data.Password)
 
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "signup", gin.H{
+		html(c, http.StatusBadRequest, "signup", gin.H{
 			"title":    "Регистрация",
 			"error":    err.Error(),
 			"formData": data,
@@ -92,12 +98,21 @@ func (s *Services) signupPost(c *gin.Context) {
 		return
 	}
 
+	// the user signs in with the new account, the session is renewed like on sign-in
+	if err = middleware.RenewSession(c); err != nil {
+		c.Redirect(http.StatusFound, "/auth/signin")
+		return
+	}
+
 	// Set the session.
 	session := sessions.Default(c)
 	session.Set("userID", user.ID.String())
 	session.Save()
 
-	c.Redirect(http.StatusFound, "/")
+	// a failed mail is not fatal: the link can be sent again from the profile
+	_ = s.Services.EmailVerificationService.SendUserVerification(user.ID)
+
+	c.Redirect(http.StatusFound, "/users/profile")
 }
 
 type loginFormData struct {
@@ -106,7 +121,7 @@ type loginFormData struct {
 }
 
 func (s *Services) signinGet(c *gin.Context) {
-	c.HTML(200, "signin", gin.H{
+	html(c, 200, "signin", gin.H{
 		"title": "Вход",
 	})
 }
@@ -114,17 +129,29 @@ func (s *Services) signinGet(c *gin.Context) {
 func (s *Services) signinPost(c *gin.Context) {
 	var data loginFormData
 	if err := c.Bind(&data); err != nil {
-		c.HTML(http.StatusBadRequest, "signin", gin.H{
+		html(c, http.StatusBadRequest, "signin", gin.H{
 			"title": "Вход",
 			"error": err.Error(),
 		})
 		return
 	}
 
+	wait, err := s.Services.LoginAttemptService.Check(models.UserLoginScope, data.Email, c.ClientIP())
+	if err != nil {
+		code, message := signinBlockedMessage(wait, err)
+		html(c, code, "signin", gin.H{
+			"title":    "Вход",
+			"error":    message,
+			"formData": data,
+		})
+		return
+	}
+
 	// try to login
 	user, err := s.Services.UserService.Login(data.Email, data.Password)
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "signin", gin.H{
+		_ = s.Services.LoginAttemptService.RegisterFailure(models.UserLoginScope, data.Email, c.ClientIP())
+		html(c, http.StatusBadRequest, "signin", gin.H{
 			"title":    "Вход",
 			"error":    "Неверный пароль или пользователь с таким email не существует",
 			"formData": data,
@@ -132,12 +159,19 @@ func (s *Services) signinPost(c *gin.Context) {
 		return
 	}
 
+	_ = s.Services.LoginAttemptService.RegisterSuccess(models.UserLoginScope, data.Email)
+
+	// a session fixed before the sign-in must not become the session of the user
+	err = middleware.RenewSession(c)
+
 	// Set the session.
 	session := sessions.Default(c)
 	session.Set("userID", user.ID.String())
-	ok := session.Save()
-	if ok != nil {
-		c.HTML(http.StatusBadRequest, "signin", gin.H{
+	if err == nil {
+		err = session.Save()
+	}
+	if err != nil {
+		html(c, http.StatusBadRequest, "signin", gin.H{
 			"title":    "Вход",
 			"error":    "Не удалось сохранить сессию",
 			"formData": data,
@@ -149,11 +183,66 @@ func (s *Services) signinPost(c *gin.Context) {
 
 }
 
+// signinBlockedMessage explains to the user why a sign-in attempt was rejected before checking the password.
+func signinBlockedMessage(wait time.Duration, err error) (int, string) {
+	switch {
+	case errors.Is(err, service_errors.LoginLocked):
+		minutes := int(math.Ceil(wait.Minutes()))
+		return http.StatusTooManyRequests, fmt.Sprintf("Вход временно заблокирован из-за большого количества неудачных попыток. Повторите через %d мин.", minutes)
+	case errors.Is(err, service_errors.LoginThrottled):
+		seconds := int(math.Ceil(wait.Seconds()))
+		return http.StatusTooManyRequests, fmt.Sprintf("Слишком много попыток входа. Повторите через %d сек.", seconds)
+	default:
+		return http.StatusInternalServerError, "Не удалось выполнить вход. Попробуйте позже"
+	}
+}
+
 func (s *Services) logout(c *gin.Context) {
 	// Delete the session
 	session := sessions.Default(c)
 	session.Clear()
+	session.Options(sessions.Options{Path: "/", MaxAge: -1})
 	session.Save()
 	c.Status(http.StatusAccepted)
 	c.Redirect(http.StatusFound, "/")
 }
+
+func (s *Services) logoutEverywhere(c *gin.Context) {
+	authUser := s.authenticatedUser(c)
+
+	err := s.Services.SessionService.RevokeUserSessions(authUser.ID)
+	if err != nil {
+		html(c, http.StatusInternalServerError, "profile", gin.H{
+			"title": "Ваш профиль",
+			"auth":  authUser,
+			"error": "Не удалось завершить сессии",
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/auth/signin")
+}
+
+// deleteAccount archives the account of the client. The orders stay visible to workers,
+// and a manager can restore the account.
+func (s *Services) deleteAccount(c *gin.Context) {
+	authUser := s.authenticatedUser(c)
+
+	err := s.Services.UserService.Delete(authUser.ID)
+	if err != nil {
+		html(c, http.StatusInternalServerError, "profile", gin.H{
+			"title": "Ваш профиль",
+			"auth":  authUser,
+			"error": "Не удалось удалить учетную запись",
+		})
+		return
+	}
+
+	_ = s.Services.SessionService.RevokeUserSessions(authUser.ID)
+
+	session := sessions.Default(c)
+	session.Clear()
+	session.Options(sessions.Options{Path: "/", MaxAge: -1})
+	session.Save()
+	c.Redirect(http.StatusFound, "/")
+}
@@ -1,7 +1,10 @@
 package server
 
 import (
+	"errors"
 	"lab3/internal/models"
+	"lab3/internal/services/service_errors"
+	"log"
 	"net/http"
 	"strconv"
 
@@ -11,14 +14,28 @@ import (
 func (s *Services) createCategoryGet(c *gin.Context) {
 	worker := s.authenticatedWorker(c)
 
-	c.HTML(http.StatusOK, "createCategory", gin.H{
-		"title":  "Создать категорию",
-		"worker": worker,
+	html(c, http.StatusOK, "createCategory", gin.H{
+		"title":      "Создать категорию",
+		"worker":     worker,
+		"categories": s.parentCategories(),
+		"taxRates":   s.Services.OrderService.TaxRates(),
 	})
 }
 
 type CategoryFormData struct {
-	Name string `form:"name"`
+	Name     string `form:"name"`
+	ParentID int    `form:"parent_id"`
+	Hidden   bool   `form:"hidden"`
+	TaxRate  string `form:"tax_rate"`
+}
+
+// parentCategories lists the categories a category can be nested in, in the order of the catalog.
+func (s *Services) parentCategories() []models.CategoryNode {
+	categories, err := s.Services.CategoryService.Catalog(true)
+	if err != nil {
+		log.Printf("Error getting categories: %v", err)
+	}
+	return categories
 }
 
 func (s *Services) createCategoryPost(c *gin.Context) {
@@ -26,7 +43,7 @@ func (s *Services) createCategoryPost(c *gin.Context) {
 
 	var data CategoryFormData
 	if err := c.Bind(&data); err != nil {
-		c.HTML(http.StatusBadRequest, "createCategory", gin.H{
+		html(c, http.StatusBadRequest, "createCategory", gin.H{
 			"worker":   worker,
 			"title":    "Создать категорию",
 			"error":    err.Error(),
@@ -35,17 +52,21 @@ func (s *Services) createCategoryPost(c *gin.Context) {
 		return
 	}
 
-	_, err := s.Services.CategoryService.Create(data.Name)
+	category, err := s.Services.CategoryService.Create(data.Name, data.ParentID, data.Hidden, data.TaxRate)
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "createCategory", gin.H{
-			"worker":   worker,
-			"title":    "Создать категорию",
-			"error":    err.Error(),
-			"formData": data,
+		html(c, http.StatusBadRequest, "createCategory", gin.H{
+			"worker":     worker,
+			"title":      "Создать категорию",
+			"error":      err.Error(),
+			"formData":   data,
+			"categories": s.parentCategories(),
+			"taxRates":   s.Services.OrderService.TaxRates(),
 		})
 		return
 	}
 
+	s.audit(c, worker, models.AuditCategoryCreate, models.AuditTargetCategory, strconv.Itoa(category.ID), nil, category)
+
 	c.Redirect(http.StatusFound, "/services")
 }
 
@@ -53,7 +74,7 @@ func (s *Services) editCategoryGet(c *gin.Context) {
 	worker := s.authenticatedWorker(c)
 	serviceID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "createCategory", gin.H{
+		html(c, http.StatusBadRequest, "createCategory", gin.H{
 			"worker": worker,
 			"title":  "Создать категорию",
 			"error":  "Неверный идентификатор категории",
@@ -63,7 +84,7 @@ func (s *Services) editCategoryGet(c *gin.Context) {
 
 	service, err := s.Services.CategoryService.GetByID(serviceID)
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "createCategory", gin.H{
+		html(c, http.StatusBadRequest, "createCategory", gin.H{
 			"worker": worker,
 			"title":  "Создать категорию",
 			"error":  "Категория не найдена",
@@ -72,13 +93,19 @@ func (s *Services) editCategoryGet(c *gin.Context) {
 	}
 
 	formData := CategoryFormData{
-		Name: service.Name,
+		Name:     service.Name,
+		ParentID: service.ParentID,
+		Hidden:   service.Hidden,
+		TaxRate:  service.TaxRate,
 	}
 
-	c.HTML(http.StatusOK, "createCategory", gin.H{
-		"title":    "Создать категорию",
-		"worker":   worker,
-		"formData": formData,
+	html(c, http.StatusOK, "createCategory", gin.H{
+		"title":      "Создать категорию",
+		"worker":     worker,
+		"formData":   formData,
+		"categoryID": service.ID,
+		"categories": s.parentCategories(),
+		"taxRates":   s.Services.OrderService.TaxRates(),
 	})
 }
 
@@ -86,7 +113,7 @@ func (s *Services) editCategoryPost(c *gin.Context) {
 	worker := s.authenticatedWorker(c)
 	categoryID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "createCategory", gin.H{
+		html(c, http.StatusBadRequest, "createCategory", gin.H{
 			"worker": worker,
 			"title":  "Создать категорию",
 			"error":  "Неверный идентификатор категории",
@@ -96,7 +123,7 @@ func (s *Services) editCategoryPost(c *gin.Context) {
 
 	var data CategoryFormData
 	if err := c.Bind(&data); err != nil {
-		c.HTML(http.StatusBadRequest, "createCategory", gin.H{
+		html(c, http.StatusBadRequest, "createCategory", gin.H{
 			"worker":   worker,
 			"title":    "Изменить категорию",
 			"error":    err.Error(),
@@ -105,19 +132,174 @@ func (s *Services) editCategoryPost(c *gin.Context) {
 		return
 	}
 
-	_, err = s.Services.CategoryService.Update(&models.Category{
-		ID:   categoryID,
-		Name: data.Name,
+	before, _ := s.Services.CategoryService.GetByID(categoryID)
+
+	category, err := s.Services.CategoryService.Update(&models.Category{
+		ID:       categoryID,
+		Name:     data.Name,
+		ParentID: data.ParentID,
+		Hidden:   data.Hidden,
+		TaxRate:  data.TaxRate,
 	})
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "createCategory", gin.H{
-			"worker":   worker,
-			"title":    "Изменить категорию",
-			"error":    err.Error(),
-			"formData": data,
+		html(c, http.StatusBadRequest, "createCategory", gin.H{
+			"worker":     worker,
+			"title":      "Изменить категорию",
+			"error":      err.Error(),
+			"formData":   data,
+			"categoryID": categoryID,
+			"categories": s.parentCategories(),
+			"taxRates":   s.Services.OrderService.TaxRates(),
 		})
 		return
 	}
 
+	s.audit(c, worker, models.AuditCategoryUpdate, models.AuditTargetCategory, strconv.Itoa(categoryID), before, category)
+
 	c.Redirect(http.StatusFound, "/services")
 }
+
+func (s *Services) archiveCategory(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "servicesList", gin.H{"title": "Доступные услуги", "worker": worker, "error": "Доступ запрещен!"})
+		return
+	}
+
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		html(c, http.StatusBadRequest, "servicesList", gin.H{
+			"title":  "Доступные услуги",
+			"worker": worker,
+			"error":  "Неверный идентификатор категории",
+		})
+		return
+	}
+
+	// a category with tasks is archived only together with moving its tasks to another category
+	mergeInto, mergeErr := strconv.Atoi(c.PostForm("merge_into"))
+	merge := c.PostForm("merge_into") != ""
+	if merge && mergeErr != nil {
+		html(c, http.StatusBadRequest, "servicesList", gin.H{
+			"title":  "Доступные услуги",
+			"worker": worker,
+			"error":  "Неверный идентификатор категории",
+		})
+		return
+	}
+
+	before, _ := s.Services.CategoryService.GetByID(categoryID)
+
+	if merge {
+		err = s.Services.CategoryService.Merge(categoryID, mergeInto)
+	} else {
+		err = s.Services.CategoryService.Delete(categoryID)
+	}
+	if err != nil {
+		message := "Не удалось отправить категорию в архив"
+		if errors.Is(err, service_errors.CategoryIsNotEmpty) {
+			message = "В категории есть услуги. Выберите категорию, в которую их перенести"
+		} else if errors.Is(err, service_errors.CategoryHasSubcategories) {
+			message = "В категории есть подкатегории. Выберите категорию, в которую их перенести"
+		} else if errors.Is(err, service_errors.InvalidCategory) {
+			message = "Нельзя перенести услуги в выбранную категорию"
+		}
+		html(c, http.StatusBadRequest, "servicesList", gin.H{
+			"title":  "Доступные услуги",
+			"worker": worker,
+			"error":  message,
+		})
+		return
+	}
+
+	after, _ := s.Services.CategoryService.GetByID(categoryID)
+	if merge {
+		s.audit(c, worker, models.AuditCategoryMerge, models.AuditTargetCategory, strconv.Itoa(categoryID), before, gin.H{
+			"category":   after,
+			"merge_into": mergeInto,
+		})
+	} else {
+		s.audit(c, worker, models.AuditCategoryDelete, models.AuditTargetCategory, strconv.Itoa(categoryID), before, after)
+	}
+
+	c.Redirect(http.StatusFound, "/services")
+}
+
+// moveCategory moves the category one place up or down among the categories nested in the same parent.
+func (s *Services) moveCategory(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "servicesList", gin.H{"title": "Доступные услуги", "worker": worker, "error": "Доступ запрещен!"})
+		return
+	}
+
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		html(c, http.StatusBadRequest, "servicesList", gin.H{
+			"title":  "Доступные услуги",
+			"worker": worker,
+			"error":  "Неверный идентификатор категории",
+		})
+		return
+	}
+
+	offset := 1
+	if c.PostForm("direction") == "up" {
+		offset = -1
+	}
+
+	before, _ := s.Services.CategoryService.GetByID(categoryID)
+
+	err = s.Services.CategoryService.Move(categoryID, offset)
+	if err != nil {
+		html(c, http.StatusBadRequest, "servicesList", gin.H{
+			"title":  "Доступные услуги",
+			"worker": worker,
+			"error":  "Не удалось переместить категорию",
+		})
+		return
+	}
+
+	after, _ := s.Services.CategoryService.GetByID(categoryID)
+	s.audit(c, worker, models.AuditCategoryMove, models.AuditTargetCategory, strconv.Itoa(categoryID), before, after)
+
+	c.Redirect(http.StatusFound, "/services")
+}
+
+func (s *Services) restoreCategory(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "servicesList", gin.H{"title": "Доступные услуги", "worker": worker, "error": "Доступ запрещен!"})
+		return
+	}
+
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		html(c, http.StatusBadRequest, "servicesList", gin.H{
+			"title":  "Доступные услуги",
+			"worker": worker,
+			"error":  "Неверный идентификатор категории",
+		})
+		return
+	}
+
+	before, _ := s.Services.CategoryService.GetByID(categoryID)
+
+	err = s.Services.CategoryService.Restore(categoryID)
+	if err != nil {
+		html(c, http.StatusBadRequest, "servicesList", gin.H{
+			"title":  "Доступные услуги",
+			"worker": worker,
+			"error":  "Категория не найдена в архиве",
+		})
+		return
+	}
+
+	after, _ := s.Services.CategoryService.GetByID(categoryID)
+	s.audit(c, worker, models.AuditCategoryRestore, models.AuditTargetCategory, strconv.Itoa(categoryID), before, after)
+
+	c.Redirect(http.StatusFound, "/services/?archived=1")
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"errors"
+	"lab3/internal/models"
+	"lab3/internal/services/service_errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const emailNotVerifiedMessage = "Подтвердите адрес электронной почты, чтобы оформлять заказы. Ссылка для подтверждения отправлена на ваш email"
+
+// mustVerifyEmail reports whether the client has to confirm the email before ordering.
+func (s *Services) mustVerifyEmail(user *models.User) bool {
+	return s.Services.EmailVerificationService.Required() && !user.EmailVerified
+}
+
+func (s *Services) verifyEmail(c *gin.Context) {
+	_, err := s.Services.EmailVerificationService.VerifyUser(c.Query("token"))
+	if err != nil {
+		message := "Не удалось подтвердить адрес. Попробуйте позже"
+		if errors.Is(err, service_errors.InvalidToken) {
+			message = "Ссылка недействительна или устарела. Запросите новую ссылку в профиле"
+		}
+
+		html(c, http.StatusBadRequest, "verifyEmail", gin.H{
+			"title": "Подтверждение email",
+			"auth":  s.authenticatedUser(c),
+			"error": message,
+		})
+		return
+	}
+
+	html(c, 200, "verifyEmail", gin.H{
+		"title":   "Подтверждение email",
+		"auth":    s.authenticatedUser(c),
+		"message": "Адрес электронной почты подтвержден. Теперь вы можете оформлять заказы",
+	})
+}
+
+func (s *Services) resendEmailVerification(c *gin.Context) {
+	authUser := s.authenticatedUser(c)
+
+	err := s.Services.EmailVerificationService.SendUserVerification(authUser.ID)
+	if errors.Is(err, service_errors.EmailAlreadyVerified) {
+		html(c, 200, "profile", gin.H{
+			"title":   "Ваш профиль",
+			"auth":    authUser,
+			"message": "Адрес электронной почты уже подтвержден",
+		})
+		return
+	} else if err != nil {
+		html(c, http.StatusInternalServerError, "profile", gin.H{
+			"title": "Ваш профиль",
+			"auth":  authUser,
+			"error": "Не удалось отправить письмо. Попробуйте позже",
+		})
+		return
+	}
+
+	html(c, 200, "profile", gin.H{
+		"title":   "Ваш профиль",
+		"auth":    authUser,
+		"message": "Ссылка для подтверждения отправлена на " + authUser.Email,
+	})
+}
+
+func (s *Services) resendUserEmailVerification(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		c.String(http.StatusForbidden, "Доступ запрещен!")
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Неверный идентификатор клиента")
+		return
+	}
+
+	err = s.Services.EmailVerificationService.SendUserVerification(userID)
+	if err != nil && !errors.Is(err, service_errors.EmailAlreadyVerified) {
+		c.String(http.StatusInternalServerError, "Не удалось отправить письмо")
+		return
+	}
+
+	c.Redirect(http.StatusFound, localReferer(c, "/worker/"))
+}
+
+// localReferer returns the path of the referring page, or fallback when it is missing,
+// so that redirects never leave the site.
+func localReferer(c *gin.Context, fallback string) string {
+	referer, err := url.Parse(c.Request.Referer())
+	if err != nil || referer.Path == "" || referer.Path[0] != '/' {
+		return fallback
+	}
+
+	return referer.RequestURI()
+}
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"lab3/internal/models"
+	"lab3/internal/pdf"
+	"lab3/internal/services/service_errors"
+	"lab3/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type invoiceLineData struct {
+	Position int
+	models.InvoiceLine
+}
+
+// writeInvoice sends the invoice as a printable page or as a PDF file to download.
+func writeInvoice(c *gin.Context, invoice *models.Invoice, asPDF bool, pdfURL string, backURL string) {
+	if asPDF {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="invoice-%d.pdf"`, invoice.Number))
+		c.Data(http.StatusOK, "application/pdf", renderInvoicePDF(invoice))
+		return
+	}
+
+	lines := make([]invoiceLineData, len(invoice.Lines))
+	for i, line := range invoice.Lines {
+		lines[i] = invoiceLineData{Position: i + 1, InvoiceLine: line}
+	}
+
+	html(c, http.StatusOK, "invoice", gin.H{
+		"invoice": invoice,
+		"lines":   lines,
+		"pdfURL":  pdfURL,
+		"backURL": backURL,
+	})
+}
+
+// renderInvoicePDF lays the invoice out like its printable page.
+func renderInvoicePDF(invoice *models.Invoice) []byte {
+	const (
+		left       = 40.0
+		right      = pdf.PageWidth - 40
+		bottom     = pdf.PageHeight - 50
+		size       = 10.0
+		lineHeight = 14.0
+		// the right edges of the number columns, the left edge of the rate column and the width of the name column
+		priceColumn    = 300.0
+		quantityColumn = 345.0
+		rateColumn     = 355.0
+		taxColumn      = 480.0
+		nameWidth      = 190.0
+	)
+
+	document := pdf.New()
+	document.AddPage()
+	y := 50.0
+
+	text := func(bold bool, value string) {
+		for _, line := range pdf.Wrap(value, size, bold, right-left) {
+			document.Text(left, y, size, bold, line)
+			y += lineHeight
+		}
+	}
+
+	text(true, invoice.Seller.Company)
+	requisites := "ИНН " + invoice.Seller.INN
+	if invoice.Seller.KPP != "" {
+		requisites += ", КПП " + invoice.Seller.KPP
+	}
+	text(false, requisites)
+	text(false, invoice.Seller.Address)
+	text(false, fmt.Sprintf("Банк: %s, БИК %s", invoice.Seller.Bank, invoice.Seller.BIC))
+	text(false, fmt.Sprintf("Р/с %s, к/с %s", invoice.Seller.Account, invoice.Seller.CorrespondentAccount))
+
+	y += lineHeight
+	document.Text(left, y, 14, true, fmt.Sprintf("Счет № %d от %s", invoice.Number, utils.FormatDate(invoice.IssuedAt)))
+	y += 2 * lineHeight
+
+	text(false, "Покупатель: "+invoice.Buyer)
+	text(false, "Адрес оказания услуг: "+invoice.Address)
+	text(false, "Заказ: "+invoice.OrderID.String())
+	y += lineHeight
+
+	header := func() {
+		document.Line(left, y-size-2, right, y-size-2)
+		document.Text(left, y, size, true, "№")
+		document.Text(left+25, y, size, true, "Наименование")
+		document.TextRight(priceColumn, y, size, true, "Цена, р.")
+		document.TextRight(quantityColumn, y, size, true, "Кол-во")
+		document.Text(rateColumn, y, size, true, "НДС")
+		document.TextRight(taxColumn, y, size, true, "НДС, р.")
+		document.TextRight(right, y, size, true, "Сумма, р.")
+		document.Line(left, y+4, right, y+4)
+		y += lineHeight + 4
+	}
+	header()
+
+	for i, line := range invoice.Lines {
+		names := pdf.Wrap(line.Name, size, false, nameWidth)
+		if y+float64(len(names)-1)*lineHeight > bottom {
+			document.AddPage()
+			y = 50
+			header()
+		}
+
+		document.Text(left, y, size, false, strconv.Itoa(i+1))
+		document.TextRight(priceColumn, y, size, false, utils.FormatMoney(line.Price))
+		document.TextRight(quantityColumn, y, size, false, strconv.Itoa(line.Quantity))
+		taxName := line.TaxName
+		if taxName == "" {
+			taxName = "Без НДС"
+		}
+		document.Text(rateColumn, y, size, false, taxName)
+		document.TextRight(taxColumn, y, size, false, utils.FormatMoney(line.Tax))
+		document.TextRight(right, y, size, false, utils.FormatMoney(line.Amount))
+		for _, name := range names {
+			document.Text(left+25, y, size, false, name)
+			y += lineHeight
+		}
+	}
+
+	document.Line(left, y-size, right, y-size)
+	y += 4
+	document.TextRight(right, y, size, false, "Сумма без НДС: "+utils.FormatMoney(invoice.Net())+" р.")
+	y += lineHeight
+	document.TextRight(right, y, size, false, "НДС: "+utils.FormatMoney(invoice.Tax())+" р.")
+	y += lineHeight + 4
+	document.TextRight(right, y, 12, true, "Итого: "+utils.FormatMoney(invoice.Total)+" р.")
+
+	return document.Bytes()
+}
+
+func (s *Services) userInvoice(c *gin.Context, asPDF bool) {
+	authUser := s.authenticatedUser(c)
+	orderID, _ := uuid.Parse(c.Param("id"))
+
+	order, err := s.Services.OrderService.GetOrderByID(orderID)
+	if err != nil || order.UserID != authUser.ID {
+		html(c, http.StatusNotFound, "orderDetails", gin.H{
+			"title": "Ошибка",
+			"auth":  authUser,
+			"error": "Такого заказа не существует или у вас нет прав на его просмотр",
+		})
+		return
+	}
+
+	invoice, err := s.Services.InvoiceService.Issue(order.ID)
+	if err != nil {
+		message := "Не удалось сформировать счет, попробуйте позже"
+		if errors.Is(err, service_errors.OrderIsNotCompleted) {
+			message = "Счет формируется только для завершенного заказа"
+		}
+		html(c, http.StatusBadRequest, "orderDetails", gin.H{
+			"title": "Заказ",
+			"auth":  authUser,
+			"order": order,
+			"error": message,
+		})
+		return
+	}
+
+	orderURL := "/users/orders/" + order.ID.String()
+	writeInvoice(c, invoice, asPDF, orderURL+"/invoice/pdf", orderURL)
+}
+
+func (s *Services) orderInvoice(c *gin.Context) {
+	s.userInvoice(c, false)
+}
+
+func (s *Services) orderInvoicePDF(c *gin.Context) {
+	s.userInvoice(c, true)
+}
+
+func (s *Services) invoicesList(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "invoicesList", gin.H{"title": "Счета", "worker": worker, "error": "Доступ запрещен!"})
+		return
+	}
+
+	invoices, err := s.Services.InvoiceService.GetAll()
+	if err != nil {
+		html(c, http.StatusInternalServerError, "invoicesList", gin.H{"title": "Счета", "worker": worker, "error": "Не удалось загрузить счета"})
+		return
+	}
+
+	html(c, 200, "invoicesList", gin.H{
+		"title":    "Счета",
+		"worker":   worker,
+		"invoices": invoices,
+	})
+}
+
+func (s *Services) workerInvoice(c *gin.Context, asPDF bool) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "invoicesList", gin.H{"title": "Счета", "worker": worker, "error": "Доступ запрещен!"})
+		return
+	}
+
+	invoiceID, _ := uuid.Parse(c.Param("id"))
+	invoice, err := s.Services.InvoiceService.GetByID(invoiceID)
+	if err != nil {
+		html(c, http.StatusNotFound, "invoicesList", gin.H{"title": "Счета", "worker": worker, "error": "Счет не найден"})
+		return
+	}
+
+	invoiceURL := "/worker/invoices/" + invoice.ID.String()
+	writeInvoice(c, invoice, asPDF, invoiceURL+"/pdf", "/worker/invoices")
+}
+
+func (s *Services) invoiceGet(c *gin.Context) {
+	s.workerInvoice(c, false)
+}
+
+func (s *Services) invoicePDF(c *gin.Context) {
+	s.workerInvoice(c, true)
+}
+
+// issueInvoice opens the invoice of a completed order, issuing it the first time.
+func (s *Services) issueInvoice(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "invoicesList", gin.H{"title": "Счета", "worker": worker, "error": "Доступ запрещен!"})
+		return
+	}
+
+	orderID, _ := uuid.Parse(c.Param("id"))
+	invoice, err := s.Services.InvoiceService.Issue(orderID)
+	if err != nil {
+		message := "Не удалось сформировать счет, попробуйте позже"
+		if errors.Is(err, service_errors.OrderIsNotCompleted) {
+			message = "Счет формируется только для завершенного заказа"
+		}
+		html(c, http.StatusBadRequest, "invoicesList", gin.H{"title": "Счета", "worker": worker, "error": message})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/worker/invoices/"+invoice.ID.String())
+}
@@ -2,13 +2,85 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"lab3/internal/models"
+	"lab3/internal/services/service_errors"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// idempotencyKeyHeader lets API clients retry order creation without creating the order twice.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+type orderedTaskData struct {
+	TaskID   string `json:"task_id"`
+	Quantity int    `json:"quantity"`
+}
+
+type createOrderApiData struct {
+	Address  string            `json:"address"`
+	Deadline string            `json:"deadline"`
+	Tasks    []orderedTaskData `json:"tasks"`
+}
+
+func (s *Services) createOrderApiPost(c *gin.Context) {
+	authUser := s.authenticatedUser(c)
+
+	if s.mustVerifyEmail(authUser) {
+		c.JSON(403, gin.H{
+			"error": emailNotVerifiedMessage,
+		})
+		return
+	}
+
+	data := createOrderApiData{}
+	err := json.NewDecoder(c.Request.Body).Decode(&data)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"error": "Invalid order",
+		})
+		return
+	}
+
+	deadline, err := time.Parse("2006-01-02", data.Deadline)
+	if err != nil {
+		c.JSON(400, gin.H{
+			"error": "Invalid deadline",
+		})
+		return
+	}
+
+	orderedTasks := make([]models.OrderedTask, 0, len(data.Tasks))
+	for _, orderedTask := range data.Tasks {
+		taskID, err := uuid.Parse(orderedTask.TaskID)
+		if err != nil {
+			c.JSON(400, gin.H{
+				"error": "Invalid task ID",
+			})
+			return
+		}
+
+		orderedTasks = append(orderedTasks, models.OrderedTask{
+			Task:     &models.Task{ID: taskID},
+			Quantity: orderedTask.Quantity,
+		})
+	}
+
+	order, err := s.Services.OrderService.CreateOrder(authUser.ID, data.Address, deadline, orderedTasks, c.GetHeader(idempotencyKeyHeader))
+	if err != nil {
+		c.JSON(400, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// a retried request gets the same response as the first one
+	c.JSON(201, order)
+}
+
 type Rating struct {
 	Rating string `json:"rating"`
 }
@@ -64,7 +136,11 @@ func (s *Services) rateOrderApiPost(c *gin.Context) {
 		return
 	}
 
-	_, err = s.Services.OrderService.Update(order.ID, order.Status, ratingInt, order.WorkerID)
+	_, err = s.Services.OrderService.Update(order.ID, order.Status, ratingInt, order.WorkerID, order.Version)
+	if err != nil {
+		orderUpdateError(c, err)
+		return
+	}
 
 	c.JSON(200, gin.H{
 		"message": "Rating updated",
@@ -104,7 +180,11 @@ func (s *Services) cancelOrderApiPost(c *gin.Context) {
 		return
 	}
 
-	_, err = s.Services.OrderService.Update(order.ID, models.CancelledOrderStatus, models.NoStatus, order.WorkerID)
+	_, err = s.Services.OrderService.Update(order.ID, models.CancelledOrderStatus, models.NoStatus, order.WorkerID, order.Version)
+	if err != nil {
+		orderUpdateError(c, err)
+		return
+	}
 
 	c.JSON(200, gin.H{
 		"message": "Order cancelled",
@@ -113,6 +193,30 @@ func (s *Services) cancelOrderApiPost(c *gin.Context) {
 
 type statusData struct {
 	Status string `json:"status"`
+	// Version is the version of the order shown to the worker, 0 means the current one
+	Version int `json:"version"`
+}
+
+// expectedVersion returns the order version a change is based on. Clients that do not send it change the current version.
+func expectedVersion(order *models.Order, version int) int {
+	if version == 0 {
+		return order.Version
+	}
+	return version
+}
+
+// orderUpdateError answers a failed order update, a concurrent change is reported as a conflict so that the page is reloaded.
+func orderUpdateError(c *gin.Context, err error) {
+	if errors.Is(err, service_errors.OrderVersionConflict) {
+		c.JSON(409, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(400, gin.H{
+		"error": err.Error(),
+	})
 }
 
 func (s *Services) changeStatusOrderApiPost(c *gin.Context) {
@@ -166,14 +270,15 @@ func (s *Services) changeStatusOrderApiPost(c *gin.Context) {
 		return
 	}
 
-	_, err = s.Services.OrderService.Update(order.ID, statusInt, order.Rate, order.WorkerID)
+	before := *order
+	updatedOrder, err := s.Services.OrderService.Update(order.ID, statusInt, order.Rate, order.WorkerID, expectedVersion(order, status.Version))
 	if err != nil {
-		c.JSON(400, gin.H{
-			"error": err.Error(),
-		})
+		orderUpdateError(c, err)
 		return
 	}
 
+	s.audit(c, s.authenticatedWorker(c), models.AuditOrderStatus, models.AuditTargetOrder, order.ID.String(), &before, updatedOrder)
+
 	c.JSON(200, gin.H{
 		"message": "Status updated",
 	})
@@ -181,6 +286,8 @@ func (s *Services) changeStatusOrderApiPost(c *gin.Context) {
 
 type changeWorkerData struct {
 	WorkerID string `json:"workerId"`
+	// Version is the version of the order shown to the manager, 0 means the current one
+	Version int `json:"version"`
 }
 
 func (s *Services) changeWorkerApiPost(c *gin.Context) {
@@ -241,14 +348,15 @@ func (s *Services) changeWorkerApiPost(c *gin.Context) {
 		return
 	}
 
-	_, err = s.Services.OrderService.Update(order.ID, order.Status, order.Rate, workerID)
+	before := *order
+	updatedOrder, err := s.Services.OrderService.Update(order.ID, order.Status, order.Rate, workerID, expectedVersion(order, data.Version))
 	if err != nil {
-		c.JSON(400, gin.H{
-			"error": err.Error(),
-		})
+		orderUpdateError(c, err)
 		return
 	}
 
+	s.audit(c, s.authenticatedWorker(c), models.AuditOrderReassign, models.AuditTargetOrder, order.ID.String(), &before, updatedOrder)
+
 	c.JSON(200, gin.H{
 		"message": "Worker assigned",
 	})
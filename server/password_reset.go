@@ -0,0 +1,133 @@
+package server
+
+import (
+	"errors"
+	"lab3/internal/services/service_errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type forgotPasswordFormData struct {
+	Email string `form:"InputEmail"`
+}
+
+type resetPasswordFormData struct {
+	Token          string `form:"token"`
+	Password       string `form:"InputPassword"`
+	PasswordRepeat string `form:"InputPassword2"`
+}
+
+const forgotPasswordMessage = "Если учетная запись с таким email существует, мы отправили на него ссылку для восстановления пароля"
+
+func (s *Services) forgotPassword(c *gin.Context, title string, request func(email string) error) {
+	var data forgotPasswordFormData
+	if err := c.Bind(&data); err != nil {
+		html(c, http.StatusBadRequest, "forgotPassword", gin.H{
+			"title": title,
+			"error": err.Error(),
+		})
+		return
+	}
+
+	err := request(data.Email)
+	if err != nil {
+		html(c, http.StatusInternalServerError, "forgotPassword", gin.H{
+			"title":    title,
+			"error":    "Не удалось отправить письмо. Попробуйте позже",
+			"formData": data,
+		})
+		return
+	}
+
+	// the same answer for known and unknown emails, so that accounts cannot be enumerated
+	html(c, 200, "forgotPassword", gin.H{
+		"title":   title,
+		"message": forgotPasswordMessage,
+	})
+}
+
+func (s *Services) resetPassword(c *gin.Context, title string, signinPath string, reset func(token string, password string) error) {
+	var data resetPasswordFormData
+	if err := c.Bind(&data); err != nil {
+		html(c, http.StatusBadRequest, "resetPassword", gin.H{
+			"title": title,
+			"error": err.Error(),
+			"token": data.Token,
+		})
+		return
+	}
+
+	if data.Password != data.PasswordRepeat {
+		html(c, http.StatusBadRequest, "resetPassword", gin.H{
+			"title": title,
+			"error": "Пароли не совпадают",
+			"token": data.Token,
+		})
+		return
+	}
+
+	err := reset(data.Token, data.Password)
+	if err != nil {
+		var message string
+		switch {
+		case errors.Is(err, service_errors.InvalidPassword):
+			message = "Пароль должен быть не короче 8 символов и содержать буквы и цифры"
+		case errors.Is(err, service_errors.InvalidToken):
+			message = "Ссылка недействительна или устарела. Запросите восстановление пароля еще раз"
+		default:
+			message = "Не удалось изменить пароль. Попробуйте позже"
+		}
+
+		html(c, http.StatusBadRequest, "resetPassword", gin.H{
+			"title": title,
+			"error": message,
+			"token": data.Token,
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, signinPath)
+}
+
+func (s *Services) forgotPasswordGet(c *gin.Context) {
+	html(c, 200, "forgotPassword", gin.H{
+		"title": "Восстановление пароля",
+	})
+}
+
+func (s *Services) forgotPasswordPost(c *gin.Context) {
+	s.forgotPassword(c, "Восстановление пароля", s.Services.PasswordResetService.RequestUserReset)
+}
+
+func (s *Services) resetPasswordGet(c *gin.Context) {
+	html(c, 200, "resetPassword", gin.H{
+		"title": "Новый пароль",
+		"token": c.Query("token"),
+	})
+}
+
+func (s *Services) resetPasswordPost(c *gin.Context) {
+	s.resetPassword(c, "Новый пароль", "/auth/signin", s.Services.PasswordResetService.ResetUserPassword)
+}
+
+func (s *Services) workerForgotPasswordGet(c *gin.Context) {
+	html(c, 200, "forgotPassword", gin.H{
+		"title": "Восстановление пароля исполнителя",
+	})
+}
+
+func (s *Services) workerForgotPasswordPost(c *gin.Context) {
+	s.forgotPassword(c, "Восстановление пароля исполнителя", s.Services.PasswordResetService.RequestWorkerReset)
+}
+
+func (s *Services) workerResetPasswordGet(c *gin.Context) {
+	html(c, 200, "resetPassword", gin.H{
+		"title": "Новый пароль исполнителя",
+		"token": c.Query("token"),
+	})
+}
+
+func (s *Services) workerResetPasswordPost(c *gin.Context) {
+	s.resetPassword(c, "Новый пароль исполнителя", "/worker-auth/signin", s.Services.PasswordResetService.ResetWorkerPassword)
+}
@@ -2,35 +2,92 @@ package server
 
 import (
 	"lab3/internal/models"
+	"strings"
 
 	"github.com/charmbracelet/log"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func (s *Services) priceList(c *gin.Context) {
 	authUser := s.authenticatedUser(c)
 	worker := s.authenticatedWorker(c)
 
-	categories, err := s.Services.CategoryService.GetAll()
+	query, found := s.catalogSearch(c)
+
+	html(c, 200, "prices", gin.H{
+		"auth":     authUser,
+		"worker":   worker,
+		"title":    "Услуги",
+		"prices":   s.catalogSections(false, query, found),
+		"upcoming": s.upcomingPrices(),
+		"query":    query,
+	})
+}
+
+// catalogSection is a category of a catalog page with its tasks.
+type catalogSection struct {
+	Category models.CategoryNode
+	Tasks    []models.Task
+}
+
+// catalogSections lists the categories in the order of the catalog, each with its tasks.
+// When a query is searched, only the found tasks and the categories with them are listed.
+func (s *Services) catalogSections(includeHidden bool, query string, found []models.Task) []catalogSection {
+	categories, err := s.Services.CategoryService.Catalog(includeHidden)
 	if err != nil {
 		log.Printf("Error getting categories: %v", err)
-		categories = []models.Category{}
+		return nil
 	}
 
-	prices := make(map[string][]models.Task)
-	for i, category := range categories {
-		tasks, err := s.Services.TaskService.GetTasksInCategory(i)
+	var sections []catalogSection
+	for _, category := range categories {
+		tasks, err := s.Services.CategoryService.GetTasksInCategory(category.ID)
 		if err != nil {
 			log.Printf("Error getting tasks in category %s: %v", category.Name, err)
 			continue
 		}
-		prices[category.Name] = tasks
+		if query != "" {
+			tasks = onlyFound(tasks, found)
+			if len(tasks) == 0 {
+				continue
+			}
+		}
+		sections = append(sections, catalogSection{Category: category, Tasks: tasks})
 	}
 
-	c.HTML(200, "prices", gin.H{
-		"auth":   authUser,
-		"worker": worker,
-		"title":  "Услуги",
-		"prices": prices,
-	})
+	return sections
+}
+
+// catalogSearch returns the query of the search box of a catalog page and the tasks it found,
+// the most relevant first. The query is empty when nothing is searched.
+func (s *Services) catalogSearch(c *gin.Context) (string, []models.Task) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		return "", nil
+	}
+
+	found, err := s.Services.TaskService.Search(query)
+	if err != nil {
+		log.Printf("Error searching tasks %q: %v", query, err)
+	}
+
+	return query, found
+}
+
+// onlyFound keeps the tasks found by the search, in the order of their relevance.
+func onlyFound(tasks []models.Task, found []models.Task) []models.Task {
+	listed := make(map[uuid.UUID]bool, len(tasks))
+	for _, task := range tasks {
+		listed[task.ID] = true
+	}
+
+	var result []models.Task
+	for _, task := range found {
+		if listed[task.ID] {
+			result = append(result, task)
+		}
+	}
+
+	return result
 }
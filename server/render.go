@@ -0,0 +1,17 @@
+package server
+
+import (
+	"lab3/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// html renders a template with the CSRF token of the current session added to its data,
+// so every form can include it.
+func html(c *gin.Context, code int, name string, obj gin.H) {
+	if obj == nil {
+		obj = gin.H{}
+	}
+	obj[middleware.CSRFTokenKey] = c.GetString(middleware.CSRFTokenKey)
+	c.HTML(code, name, obj)
+}
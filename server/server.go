@@ -1,46 +1,95 @@
 package server
 
 import (
+	"errors"
 	"html/template"
 	"lab3/internal/registry"
 	"lab3/middleware"
 	"lab3/utils"
+	"time"
 
 	"github.com/gin-gonic/contrib/sessions"
 	"github.com/gin-gonic/gin"
 )
 
+// defaultSessionMaxAge is used for the session cookie when no absolute timeout is configured.
+const defaultSessionMaxAge = 30 * 24 * time.Hour
+
+const expiredSessionsPurgeInterval = time.Hour
+
+// scheduledPricesInterval is how late a scheduled price may come in force on the pages.
+// Order totals do not depend on it, they use the price history.
+const scheduledPricesInterval = time.Minute
+
+// defaultArchiveInterval is used for the archiving of closed orders when no interval is configured.
+const defaultArchiveInterval = 24 * time.Hour
+
 type Services struct {
 	Services *registry.Services
 }
 
 func RunServer(app *registry.App) error {
+	if app.Config.Session.Key == "" {
+		return errors.New("session key is not configured")
+	}
+
 	s := Services{
 		app.Services,
 	}
 
-	router := s.setupRouter(app)
+	router, err := s.setupRouter(app)
+	if err != nil {
+		return err
+	}
+
+	go s.purgeExpiredSessions(app)
+	go s.applyScheduledPrices(app)
+	if app.Config.Archive.AfterMonths > 0 {
+		go s.archiveOrders(app)
+	}
 
 	gin.SetMode(gin.DebugMode)
 
 	port := app.Config.Port
 	address := app.Config.Address
-	err := router.Run(address + port)
+	err = router.Run(address + port)
 	return err
 }
 
-func (s *Services) setupRouter(app *registry.App) *gin.Engine {
+// NewEngine creates the gin engine. The client IP is taken from X-Forwarded-For and X-Real-IP only
+// when the request comes from one of trustedProxies, without them it is the address of the peer.
+func NewEngine(trustedProxies []string) (*gin.Engine, error) {
+	router := gin.Default()
+
+	err := router.SetTrustedProxies(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	return router, nil
+}
+
+func (s *Services) setupRouter(app *registry.App) (*gin.Engine, error) {
 	authMiddleware := middleware.NewMiddleware(*app)
 
-	router := gin.Default()
+	router, err := NewEngine(app.Config.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
 
 	router.SetFuncMap(template.FuncMap{
 		"formatDate":    utils.FormatDate,
 		"displayStatus": utils.DisplayStatus,
+		"formatMoney":   utils.FormatMoney,
 	})
 
-	store := sessions.NewCookieStore([]byte("secret"))
+	maxAge := app.Config.Session.AbsoluteTimeout
+	if maxAge <= 0 {
+		maxAge = defaultSessionMaxAge
+	}
+	store := middleware.NewSessionStore(s.Services.SessionService, int(maxAge.Seconds()), []byte(app.Config.Session.Key))
 	router.Use(sessions.Sessions("mysession", store))
+	router.Use(authMiddleware.CSRFMiddleware())
 
 	router.LoadHTMLGlob("templates/**/*")
 
@@ -56,12 +105,26 @@ func (s *Services) setupRouter(app *registry.App) *gin.Engine {
 		authGroup.POST("/signin", s.signinPost)
 
 		authGroup.GET("/logout", s.logout)
+
+		authGroup.GET("/verify-email", s.verifyEmail)
+
+		authGroup.GET("/forgot-password", s.forgotPasswordGet)
+		authGroup.POST("/forgot-password", s.forgotPasswordPost)
+		authGroup.GET("/reset-password", s.resetPasswordGet)
+		authGroup.POST("/reset-password", s.resetPasswordPost)
 	}
 
 	workerAuthGroup := router.Group("/worker-auth")
 	{
 		workerAuthGroup.GET("/signin", s.workerSigninGet)
 		workerAuthGroup.POST("/signin", s.workerSigninPost)
+		workerAuthGroup.GET("/two-factor", s.workerTwoFactorGet)
+		workerAuthGroup.POST("/two-factor", s.workerTwoFactorPost)
+
+		workerAuthGroup.GET("/forgot-password", s.workerForgotPasswordGet)
+		workerAuthGroup.POST("/forgot-password", s.workerForgotPasswordPost)
+		workerAuthGroup.GET("/reset-password", s.workerResetPasswordGet)
+		workerAuthGroup.POST("/reset-password", s.workerResetPasswordPost)
 	}
 
 	usersGroup := router.Group("/users")
@@ -73,6 +136,10 @@ func (s *Services) setupRouter(app *registry.App) *gin.Engine {
 
 		usersGroup.GET("/edit-profile", s.editProfileGet)
 		usersGroup.POST("/edit-profile", s.editProfilePost)
+
+		usersGroup.POST("/logout-everywhere", s.logoutEverywhere)
+		usersGroup.POST("/delete-account", s.deleteAccount)
+		usersGroup.POST("/verify-email/resend", s.resendEmailVerification)
 	}
 
 	userOrderGroup := usersGroup.Group("/orders")
@@ -80,31 +147,54 @@ func (s *Services) setupRouter(app *registry.App) *gin.Engine {
 	{
 		userOrderGroup.GET("/create", s.createOrderGet)
 		userOrderGroup.POST("/create", s.createOrderPost)
+		userOrderGroup.POST("", s.createOrderApiPost)
 
 		userOrderGroup.GET("/in-progress", s.inProgressOrders)
 		userOrderGroup.GET("/completed", s.completedOrders)
 		userOrderGroup.GET("/:id", s.orderGet)
+		userOrderGroup.GET("/:id/invoice", s.orderInvoice)
+		userOrderGroup.GET("/:id/invoice/pdf", s.orderInvoicePDF)
 		userOrderGroup.POST("/:id/rate", s.rateOrderApiPost)
 		userOrderGroup.POST("/:id/cancel", s.cancelOrderApiPost)
 	}
 
 	workerGroup := router.Group("/worker")
-	workerGroup.Use(authMiddleware.WorkerMiddleware())
+	workerGroup.Use(authMiddleware.WorkerMiddleware(), authMiddleware.TwoFactorEnrollmentMiddleware())
 	{
 		workerGroup.GET("/", s.dashboard)
 		workerGroup.GET("/profile", s.workerProfile)
 		workerGroup.GET("/directory", s.workersDirectory)
 		workerGroup.GET("/create", s.createWorkerGet)
 		workerGroup.POST("/create", s.createWorkerPost)
+		workerGroup.GET("/lockouts", s.loginLockouts)
+		workerGroup.POST("/lockouts/unlock", s.unlockLogin)
+		workerGroup.GET("/cache", s.cacheStats)
+		workerGroup.POST("/cache/invalidate", s.invalidateCache)
+		workerGroup.GET("/audit", s.auditLog)
+		workerGroup.GET("/invoices", s.invoicesList)
+		workerGroup.GET("/invoices/:id", s.invoiceGet)
+		workerGroup.GET("/invoices/:id/pdf", s.invoicePDF)
+		workerGroup.POST("/users/:id/verify-email/resend", s.resendUserEmailVerification)
+		workerGroup.POST("/users/:id/restore", s.restoreUser)
 		workerGroup.GET("/:id", s.workerDetails)
 		workerGroup.GET("/orders/history", s.ordersHistory)
 		workerGroup.GET("/orders/:id", s.orderDetails)
 		workerGroup.POST("/orders/:id/status", s.changeStatusOrderApiPost)
 		workerGroup.POST("/orders/:id/worker", s.changeWorkerApiPost)
+		workerGroup.GET("/orders/:id/invoice", s.issueInvoice)
 		workerGroup.GET("/:id/edit", s.editWorkerGet)
 		workerGroup.POST("/:id/edit", s.editWorkerPost)
 		workerGroup.GET("/change-password", s.changeWorkerPasswordGet)
 		workerGroup.POST("/change-password", s.changeWorkerPasswordPost)
+		workerGroup.POST("/logout-everywhere", s.workerLogoutEverywhere)
+		workerGroup.POST("/:id/sessions/revoke", s.revokeWorkerSessions)
+		workerGroup.POST("/:id/delete", s.archiveWorker)
+		workerGroup.POST("/:id/restore", s.restoreWorker)
+		workerGroup.GET("/two-factor", s.twoFactorSetupGet)
+		workerGroup.POST("/two-factor/confirm", s.twoFactorConfirm)
+		workerGroup.POST("/two-factor/recovery-codes", s.regenerateRecoveryCodes)
+		workerGroup.POST("/two-factor/disable", s.disableTwoFactor)
+		workerGroup.POST("/:id/two-factor/reset", s.resetWorkerTwoFactor)
 
 		workerGroup.GET("/category/create", s.createCategoryGet)
 		workerGroup.POST("/category/create", s.createCategoryPost)
@@ -113,23 +203,72 @@ func (s *Services) setupRouter(app *registry.App) *gin.Engine {
 	}
 
 	servicesGroup := router.Group("/services")
-	servicesGroup.Use(authMiddleware.WorkerMiddleware())
+	servicesGroup.Use(authMiddleware.WorkerMiddleware(), authMiddleware.TwoFactorEnrollmentMiddleware())
 	{
 		servicesGroup.GET("/", s.services)
 		servicesGroup.GET("/create", s.createServiceGet)
 		servicesGroup.POST("/create", s.createServicePost)
 		servicesGroup.GET("/:id", s.editServiceGet)
 		servicesGroup.POST("/:id", s.editServicePost)
+		servicesGroup.POST("/:id/delete", s.archiveService)
+		servicesGroup.POST("/:id/restore", s.restoreService)
+		servicesGroup.POST("/:id/prices", s.schedulePrice)
+		servicesGroup.POST("/:id/prices/:price/delete", s.cancelPrice)
 	}
 
 	categoriesGroup := router.Group("/categories")
-	categoriesGroup.Use(authMiddleware.WorkerMiddleware())
+	categoriesGroup.Use(authMiddleware.WorkerMiddleware(), authMiddleware.TwoFactorEnrollmentMiddleware())
 	{
 		categoriesGroup.GET("/create", s.createCategoryGet)
 		categoriesGroup.POST("/create", s.createCategoryPost)
 		categoriesGroup.GET("/:id", s.editCategoryGet)
 		categoriesGroup.POST("/:id", s.editCategoryPost)
+		categoriesGroup.POST("/:id/delete", s.archiveCategory)
+		categoriesGroup.POST("/:id/move", s.moveCategory)
+		categoriesGroup.POST("/:id/restore", s.restoreCategory)
+	}
+
+	return router, nil
+}
+
+func (s *Services) purgeExpiredSessions(app *registry.App) {
+	ticker := time.NewTicker(expiredSessionsPurgeInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		err := s.Services.SessionService.PurgeExpired()
+		if err != nil {
+			app.Logger.Error("Error purging expired sessions", "err", err)
+		}
 	}
+}
+
+func (s *Services) applyScheduledPrices(app *registry.App) {
+	ticker := time.NewTicker(scheduledPricesInterval)
+	defer ticker.Stop()
+
+	// the prices that came in force while the server was stopped are applied at once
+	for ; true; <-ticker.C {
+		_, err := s.Services.TaskService.ApplyScheduledPrices()
+		if err != nil {
+			app.Logger.Error("Error applying scheduled prices", "err", err)
+		}
+	}
+}
 
-	return router
+func (s *Services) archiveOrders(app *registry.App) {
+	interval := app.Config.Archive.Interval
+	if interval <= 0 {
+		interval = defaultArchiveInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		_, err := s.Services.OrderService.Archive(app.Config.Archive.AfterMonths)
+		if err != nil {
+			app.Logger.Error("Error archiving orders", "err", err)
+		}
+	}
 }
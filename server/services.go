@@ -1,9 +1,12 @@
 package server
 
 import (
+	"errors"
 	"lab3/internal/models"
+	"lab3/internal/services/service_errors"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -12,27 +15,57 @@ import (
 func (s *Services) services(c *gin.Context) {
 	worker := s.authenticatedWorker(c)
 
-	categories, err := s.Services.CategoryService.GetAll()
+	categories, err := s.Services.CategoryService.Catalog(true)
 	if err != nil {
 		log.Printf("Error getting categories: %v", err)
-		categories = []models.Category{}
 	}
 
-	prices := make(map[models.Category][]models.Task)
+	query, found := s.catalogSearch(c)
 
-	for _, category := range categories {
-		tasks, err := s.Services.CategoryService.GetTasksInCategory(category.ID)
-		if err != nil {
-			log.Printf("Error getting tasks in category %s: %v", category.Name, err)
-			continue
+	data := gin.H{
+		"title":      "Доступные услуги",
+		"worker":     worker,
+		"prices":     s.catalogSections(true, query, found),
+		"categories": categories,
+		"upcoming":   s.upcomingPrices(),
+		"query":      query,
+	}
+
+	if c.Query("archived") == "1" {
+		archivedCategories, _ := s.Services.CategoryService.GetDeleted()
+		archivedTasks, _ := s.Services.TaskService.GetDeletedTasks()
+
+		archivedTasksData := make([]archivedTaskData, len(archivedTasks))
+		for i, task := range archivedTasks {
+			categoryName := ""
+			category, err := s.Services.CategoryService.GetByID(task.Category)
+			if err == nil {
+				categoryName = category.Name
+			}
+
+			archivedTasksData[i] = archivedTaskData{
+				ID:             task.ID,
+				Name:           task.Name,
+				PricePerSingle: task.PricePerSingle,
+				Category:       categoryName,
+				DeletedAt:      task.DeletedAt.Format("2006-01-02 15:04:05"),
+			}
 		}
-		prices[category] = tasks
+
+		data["archived"] = true
+		data["archivedCategories"] = archivedCategories
+		data["archivedTasks"] = archivedTasksData
 	}
-	c.HTML(http.StatusOK, "servicesList", gin.H{
-		"title":  "Доступные услуги",
-		"worker": worker,
-		"prices": prices,
-	})
+
+	html(c, http.StatusOK, "servicesList", data)
+}
+
+type archivedTaskData struct {
+	ID             uuid.UUID
+	Name           string
+	PricePerSingle float64
+	Category       string
+	DeletedAt      string
 }
 
 func (s *Services) createServiceGet(c *gin.Context) {
@@ -43,10 +76,11 @@ func (s *Services) createServiceGet(c *gin.Context) {
 		categories = []models.Category{}
 	}
 
-	c.HTML(http.StatusOK, "createService", gin.H{
+	html(c, http.StatusOK, "createService", gin.H{
 		"title":      "Создать услугу",
 		"worker":     worker,
 		"categories": categories,
+		"taxRates":   s.Services.OrderService.TaxRates(),
 	})
 }
 
@@ -54,6 +88,7 @@ type ServiceFormData struct {
 	Name           string  `form:"name"`
 	PricePerSingle float64 `form:"pricePerSingle"`
 	Category       int     `form:"category"`
+	TaxRate        string  `form:"tax_rate"`
 }
 
 func (s *Services) createServicePost(c *gin.Context) {
@@ -61,7 +96,7 @@ func (s *Services) createServicePost(c *gin.Context) {
 
 	var data ServiceFormData
 	if err := c.Bind(&data); err != nil {
-		c.HTML(http.StatusBadRequest, "createService", gin.H{
+		html(c, http.StatusBadRequest, "createService", gin.H{
 			"worker":   worker,
 			"title":    "Создать услугу",
 			"error":    err.Error(),
@@ -70,9 +105,9 @@ func (s *Services) createServicePost(c *gin.Context) {
 		return
 	}
 
-	_, err := s.Services.TaskService.Create(data.Name, data.PricePerSingle, data.Category)
+	task, err := s.Services.TaskService.Create(data.Name, data.PricePerSingle, data.Category, data.TaxRate)
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "createService", gin.H{
+		html(c, http.StatusBadRequest, "createService", gin.H{
 			"worker":   worker,
 			"title":    "Создать услугу",
 			"error":    err.Error(),
@@ -81,6 +116,8 @@ func (s *Services) createServicePost(c *gin.Context) {
 		return
 	}
 
+	s.audit(c, worker, models.AuditTaskCreate, models.AuditTargetTask, task.ID.String(), nil, task)
+
 	c.Redirect(http.StatusFound, "/services")
 }
 
@@ -88,7 +125,7 @@ func (s *Services) editServiceGet(c *gin.Context) {
 	worker := s.authenticatedWorker(c)
 	serviceID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "createService", gin.H{
+		html(c, http.StatusBadRequest, "createService", gin.H{
 			"worker": worker,
 			"title":  "Создать услугу",
 			"error":  "Неверный идентификатор услуги",
@@ -96,9 +133,14 @@ func (s *Services) editServiceGet(c *gin.Context) {
 		return
 	}
 
+	s.editServicePage(c, http.StatusOK, worker, serviceID, "")
+}
+
+// editServicePage shows the form of the service together with its scheduled prices.
+func (s *Services) editServicePage(c *gin.Context, code int, worker *models.Worker, serviceID uuid.UUID, message string) {
 	service, err := s.Services.TaskService.GetTaskByID(serviceID)
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "createService", gin.H{
+		html(c, http.StatusBadRequest, "createService", gin.H{
 			"worker": worker,
 			"title":  "Создать услугу",
 			"error":  "Услуга не найдена",
@@ -106,17 +148,126 @@ func (s *Services) editServiceGet(c *gin.Context) {
 		return
 	}
 
+	categories, err := s.Services.CategoryService.GetAll()
+	if err != nil {
+		log.Printf("Error getting categories: %v", err)
+	}
+
 	formData := ServiceFormData{
 		Name:           service.Name,
 		PricePerSingle: service.PricePerSingle,
 		Category:       service.Category,
+		TaxRate:        service.TaxRate,
 	}
 
-	c.HTML(http.StatusOK, "createService", gin.H{
-		"title":    "Создать услугу",
-		"worker":   worker,
-		"formData": formData,
-	})
+	data := gin.H{
+		"title":      "Создать услугу",
+		"worker":     worker,
+		"formData":   formData,
+		"categories": categories,
+		"taxRates":   s.Services.OrderService.TaxRates(),
+		"serviceID":  service.ID,
+		"upcoming":   s.upcomingPrices()[service.ID],
+	}
+	if message != "" {
+		data["error"] = message
+	}
+
+	html(c, code, "createService", data)
+}
+
+// upcomingPrices groups the scheduled prices by their tasks, the earliest first.
+func (s *Services) upcomingPrices() map[uuid.UUID][]models.TaskPrice {
+	prices, err := s.Services.TaskService.GetUpcomingPrices()
+	if err != nil {
+		log.Printf("Error getting upcoming prices: %v", err)
+	}
+
+	upcoming := make(map[uuid.UUID][]models.TaskPrice)
+	for _, price := range prices {
+		upcoming[price.TaskID] = append(upcoming[price.TaskID], price)
+	}
+
+	return upcoming
+}
+
+type PriceFormData struct {
+	Price         float64 `form:"price"`
+	EffectiveFrom string  `form:"effective_from"`
+}
+
+// schedulePrice announces a new price of the service. The price comes in force at the start of the day in UTC,
+// the zone every backend keeps its times in.
+func (s *Services) schedulePrice(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+	serviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		html(c, http.StatusBadRequest, "createService", gin.H{
+			"worker": worker,
+			"title":  "Создать услугу",
+			"error":  "Неверный идентификатор услуги",
+		})
+		return
+	}
+
+	var data PriceFormData
+	if err = c.Bind(&data); err != nil {
+		s.editServicePage(c, http.StatusBadRequest, worker, serviceID, "Неверная цена")
+		return
+	}
+
+	effectiveFrom, err := time.Parse("2006-01-02", data.EffectiveFrom)
+	if err != nil {
+		s.editServicePage(c, http.StatusBadRequest, worker, serviceID, "Неверная дата новой цены")
+		return
+	}
+
+	price, err := s.Services.TaskService.SchedulePrice(serviceID, data.Price, effectiveFrom)
+	if err != nil {
+		message := "Не удалось запланировать новую цену"
+		if errors.Is(err, service_errors.InvalidPrice) {
+			message = "Неверная цена"
+		} else if errors.Is(err, service_errors.InvalidEffectiveDate) {
+			message = "Новую цену можно запланировать только с завтрашнего дня или позже"
+		} else if errors.Is(err, service_errors.Archived) {
+			message = "Услуга в архиве"
+		}
+		s.editServicePage(c, http.StatusBadRequest, worker, serviceID, message)
+		return
+	}
+
+	s.audit(c, worker, models.AuditTaskPrice, models.AuditTargetTask, serviceID.String(), nil, price)
+
+	c.Redirect(http.StatusFound, "/services/"+serviceID.String())
+}
+
+func (s *Services) cancelPrice(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+	serviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		html(c, http.StatusBadRequest, "createService", gin.H{
+			"worker": worker,
+			"title":  "Создать услугу",
+			"error":  "Неверный идентификатор услуги",
+		})
+		return
+	}
+
+	priceID, err := uuid.Parse(c.Param("price"))
+	if err != nil {
+		s.editServicePage(c, http.StatusBadRequest, worker, serviceID, "Неверный идентификатор цены")
+		return
+	}
+
+	price, err := s.Services.TaskService.CancelPriceChange(priceID)
+	if err != nil {
+		s.editServicePage(c, http.StatusBadRequest, worker, serviceID, "Новая цена уже действует или была отменена")
+		return
+	}
+
+	s.audit(c, worker, models.AuditTaskPriceCancel, models.AuditTargetTask, price.TaskID.String(), price, nil)
+
+	c.Redirect(http.StatusFound, "/services/"+price.TaskID.String())
 }
 
 func (s *Services) editServicePost(c *gin.Context) {
@@ -125,7 +276,7 @@ func (s *Services) editServicePost(c *gin.Context) {
 
 	var data ServiceFormData
 	if err := c.Bind(&data); err != nil {
-		c.HTML(http.StatusBadRequest, "createService", gin.H{
+		html(c, http.StatusBadRequest, "createService", gin.H{
 			"worker":   worker,
 			"title":    "Изменить услугу",
 			"error":    err.Error(),
@@ -134,9 +285,11 @@ func (s *Services) editServicePost(c *gin.Context) {
 		return
 	}
 
-	_, err = s.Services.TaskService.Update(serviceID, data.Category, data.Name, data.PricePerSingle)
+	before, _ := s.Services.TaskService.GetTaskByID(serviceID)
+
+	task, err := s.Services.TaskService.Update(serviceID, data.Category, data.Name, data.PricePerSingle, data.TaxRate)
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "createService", gin.H{
+		html(c, http.StatusBadRequest, "createService", gin.H{
 			"worker":   worker,
 			"title":    "Изменить услугу",
 			"error":    err.Error(),
@@ -145,5 +298,79 @@ func (s *Services) editServicePost(c *gin.Context) {
 		return
 	}
 
+	s.audit(c, worker, models.AuditTaskUpdate, models.AuditTargetTask, serviceID.String(), before, task)
+
+	c.Redirect(http.StatusFound, "/services")
+}
+
+func (s *Services) archiveService(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "servicesList", gin.H{"title": "Доступные услуги", "worker": worker, "error": "Доступ запрещен!"})
+		return
+	}
+
+	serviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		html(c, http.StatusBadRequest, "servicesList", gin.H{
+			"title":  "Доступные услуги",
+			"worker": worker,
+			"error":  "Неверный идентификатор услуги",
+		})
+		return
+	}
+
+	before, _ := s.Services.TaskService.GetTaskByID(serviceID)
+
+	err = s.Services.TaskService.Delete(serviceID)
+	if err != nil {
+		html(c, http.StatusBadRequest, "servicesList", gin.H{
+			"title":  "Доступные услуги",
+			"worker": worker,
+			"error":  "Не удалось отправить услугу в архив",
+		})
+		return
+	}
+
+	after, _ := s.Services.TaskService.GetTaskByID(serviceID)
+	s.audit(c, worker, models.AuditTaskDelete, models.AuditTargetTask, serviceID.String(), before, after)
+
 	c.Redirect(http.StatusFound, "/services")
 }
+
+func (s *Services) restoreService(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "servicesList", gin.H{"title": "Доступные услуги", "worker": worker, "error": "Доступ запрещен!"})
+		return
+	}
+
+	serviceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		html(c, http.StatusBadRequest, "servicesList", gin.H{
+			"title":  "Доступные услуги",
+			"worker": worker,
+			"error":  "Неверный идентификатор услуги",
+		})
+		return
+	}
+
+	before, _ := s.Services.TaskService.GetTaskByID(serviceID)
+
+	err = s.Services.TaskService.Restore(serviceID)
+	if err != nil {
+		html(c, http.StatusBadRequest, "servicesList", gin.H{
+			"title":  "Доступные услуги",
+			"worker": worker,
+			"error":  "Услуга не найдена в архиве",
+		})
+		return
+	}
+
+	after, _ := s.Services.TaskService.GetTaskByID(serviceID)
+	s.audit(c, worker, models.AuditTaskRestore, models.AuditTargetTask, serviceID.String(), before, after)
+
+	c.Redirect(http.StatusFound, "/services/?archived=1")
+}
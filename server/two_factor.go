@@ -0,0 +1,292 @@
+package server
+
+import (
+	"encoding/base64"
+	"errors"
+	"html/template"
+	"lab3/internal/models"
+	"lab3/internal/services/service_errors"
+	"lab3/middleware"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	pendingWorkerIDKey    = "pendingWorkerID"
+	pendingWorkerSinceKey = "pendingWorkerSince"
+	// pendingWorkerTTL limits how long a correct password waits for the second step.
+	pendingWorkerTTL = 5 * time.Minute
+)
+
+type twoFactorFormData struct {
+	Code string `form:"InputCode"`
+}
+
+// pendingWorker returns the worker who has entered the right password but not the second factor yet.
+func (s *Services) pendingWorker(c *gin.Context) *models.Worker {
+	session := sessions.Default(c)
+
+	strWorkerID, ok := session.Get(pendingWorkerIDKey).(string)
+	if !ok {
+		return nil
+	}
+
+	since, ok := session.Get(pendingWorkerSinceKey).(int64)
+	if !ok || time.Since(time.Unix(since, 0)) > pendingWorkerTTL {
+		return nil
+	}
+
+	workerID, err := uuid.Parse(strWorkerID)
+	if err != nil {
+		return nil
+	}
+
+	worker, err := s.Services.WorkerService.GetWorkerByID(workerID)
+	if err != nil {
+		return nil
+	}
+
+	return worker
+}
+
+func (s *Services) workerTwoFactorGet(c *gin.Context) {
+	if s.pendingWorker(c) == nil {
+		c.Redirect(http.StatusFound, "/worker-auth/signin")
+		return
+	}
+
+	html(c, 200, "twoFactorSignin", gin.H{
+		"title": "Подтверждение входа",
+	})
+}
+
+func (s *Services) workerTwoFactorPost(c *gin.Context) {
+	worker := s.pendingWorker(c)
+	if worker == nil {
+		c.Redirect(http.StatusFound, "/worker-auth/signin")
+		return
+	}
+
+	var data twoFactorFormData
+	if err := c.Bind(&data); err != nil {
+		html(c, http.StatusBadRequest, "twoFactorSignin", gin.H{
+			"title": "Подтверждение входа",
+			"error": err.Error(),
+		})
+		return
+	}
+
+	wait, err := s.Services.LoginAttemptService.Check(models.WorkerLoginScope, worker.Email, c.ClientIP())
+	if err != nil {
+		code, message := signinBlockedMessage(wait, err)
+		html(c, code, "twoFactorSignin", gin.H{
+			"title": "Подтверждение входа",
+			"error": message,
+		})
+		return
+	}
+
+	err = s.Services.TwoFactorService.Verify(worker.ID, data.Code)
+	if err != nil {
+		_ = s.Services.LoginAttemptService.RegisterFailure(models.WorkerLoginScope, worker.Email, c.ClientIP())
+		html(c, http.StatusBadRequest, "twoFactorSignin", gin.H{
+			"title": "Подтверждение входа",
+			"error": "Неверный код подтверждения",
+		})
+		return
+	}
+
+	_ = s.Services.LoginAttemptService.RegisterSuccess(models.WorkerLoginScope, worker.Email)
+
+	// the session is renewed again, the one that waited for the code is not the one of the signed in worker
+	err = middleware.RenewSession(c)
+
+	session := sessions.Default(c)
+	session.Delete(pendingWorkerIDKey)
+	session.Delete(pendingWorkerSinceKey)
+	session.Set("workerID", worker.ID.String())
+	if err == nil {
+		err = session.Save()
+	}
+	if err != nil {
+		html(c, http.StatusBadRequest, "twoFactorSignin", gin.H{
+			"title": "Подтверждение входа",
+			"error": "Не удалось сохранить сессию",
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/worker/profile")
+}
+
+func (s *Services) twoFactorSetupGet(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	enrollment, err := s.Services.TwoFactorService.BeginEnrollment(worker)
+	if errors.Is(err, service_errors.TwoFactorAlreadyEnabled) {
+		c.Redirect(http.StatusFound, "/worker/profile")
+		return
+	} else if err != nil {
+		html(c, http.StatusInternalServerError, "twoFactorSetup", gin.H{
+			"title":  "Двухфакторная аутентификация",
+			"worker": worker,
+			"error":  "Не удалось начать подключение двухфакторной аутентификации",
+		})
+		return
+	}
+
+	html(c, 200, "twoFactorSetup", gin.H{
+		"title":      "Двухфакторная аутентификация",
+		"worker":     worker,
+		"required":   s.Services.TwoFactorService.IsRequired(worker),
+		"enrollment": enrollment,
+		"qrCode":     template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(enrollment.QRCode)),
+		"otpURL":     template.URL(enrollment.URL),
+	})
+}
+
+func (s *Services) twoFactorConfirm(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	var data twoFactorFormData
+	if err := c.Bind(&data); err != nil {
+		html(c, http.StatusBadRequest, "twoFactorSetup", gin.H{
+			"title":  "Двухфакторная аутентификация",
+			"worker": worker,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	recoveryCodes, err := s.Services.TwoFactorService.ConfirmEnrollment(worker.ID, data.Code)
+	if errors.Is(err, service_errors.InvalidTwoFactorCode) {
+		enrollment, _ := s.Services.TwoFactorService.BeginEnrollment(worker)
+		obj := gin.H{
+			"title":    "Двухфакторная аутентификация",
+			"worker":   worker,
+			"required": s.Services.TwoFactorService.IsRequired(worker),
+			"error":    "Неверный код. Проверьте время на устройстве и попробуйте снова",
+		}
+		if enrollment != nil {
+			obj["enrollment"] = enrollment
+			obj["qrCode"] = template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(enrollment.QRCode))
+			obj["otpURL"] = template.URL(enrollment.URL)
+		}
+		html(c, http.StatusBadRequest, "twoFactorSetup", obj)
+		return
+	} else if errors.Is(err, service_errors.TwoFactorAlreadyEnabled) {
+		c.Redirect(http.StatusFound, "/worker/profile")
+		return
+	} else if err != nil {
+		html(c, http.StatusInternalServerError, "twoFactorSetup", gin.H{
+			"title":  "Двухфакторная аутентификация",
+			"worker": worker,
+			"error":  "Не удалось подключить двухфакторную аутентификацию",
+		})
+		return
+	}
+
+	html(c, 200, "recoveryCodes", gin.H{
+		"title":         "Коды восстановления",
+		"worker":        worker,
+		"recoveryCodes": recoveryCodes,
+	})
+}
+
+func (s *Services) regenerateRecoveryCodes(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	recoveryCodes, err := s.Services.TwoFactorService.RegenerateRecoveryCodes(worker.ID)
+	if err != nil {
+		html(c, http.StatusBadRequest, "worker-profile", gin.H{
+			"title":  "Профиль",
+			"worker": worker,
+			"error":  "Не удалось создать новые коды восстановления",
+		})
+		return
+	}
+
+	html(c, 200, "recoveryCodes", gin.H{
+		"title":         "Коды восстановления",
+		"worker":        worker,
+		"recoveryCodes": recoveryCodes,
+	})
+}
+
+func (s *Services) disableTwoFactor(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if s.Services.TwoFactorService.IsRequired(worker) {
+		html(c, 403, "worker-profile", gin.H{
+			"title":  "Профиль",
+			"worker": worker,
+			"error":  "Менеджер не может отключить двухфакторную аутентификацию",
+		})
+		return
+	}
+
+	var data twoFactorFormData
+	if err := c.Bind(&data); err != nil {
+		html(c, http.StatusBadRequest, "worker-profile", gin.H{
+			"title":  "Профиль",
+			"worker": worker,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	err := s.Services.TwoFactorService.Verify(worker.ID, data.Code)
+	if err != nil {
+		html(c, http.StatusBadRequest, "worker-profile", gin.H{
+			"title":  "Профиль",
+			"worker": worker,
+			"error":  "Неверный код подтверждения",
+		})
+		return
+	}
+
+	err = s.Services.TwoFactorService.Disable(worker.ID)
+	if err != nil {
+		html(c, http.StatusInternalServerError, "worker-profile", gin.H{
+			"title":  "Профиль",
+			"worker": worker,
+			"error":  "Не удалось отключить двухфакторную аутентификацию",
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/worker/profile")
+}
+
+func (s *Services) resetWorkerTwoFactor(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "workerDetails", gin.H{"title": "Информация об исполнителе", "error": "Доступ запрещен!"})
+		return
+	}
+
+	workerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		html(c, http.StatusBadRequest, "workerDetails", gin.H{
+			"title": "Информация об исполнителе",
+			"error": "Неверный идентификатор исполнителя",
+		})
+		return
+	}
+
+	err = s.Services.TwoFactorService.Disable(workerID)
+	if err != nil {
+		html(c, http.StatusInternalServerError, "workerDetails", gin.H{
+			"title": "Информация об исполнителе",
+			"error": "Не удалось сбросить двухфакторную аутентификацию",
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/worker/"+workerID.String())
+}
@@ -12,7 +12,7 @@ import (
 )
 
 func (s *Services) profile(c *gin.Context) {
-	c.HTML(200, "profile", gin.H{
+	html(c, 200, "profile", gin.H{
 		"title": "Ваш профиль",
 		"auth":  s.authenticatedUser(c),
 	})
@@ -25,7 +25,7 @@ type changePasswordData struct {
 }
 
 func (s *Services) changePasswordGet(c *gin.Context) {
-	c.HTML(200, "changePassword", gin.H{
+	html(c, 200, "changePassword", gin.H{
 		"title": "Изменить пароль",
 		"auth":  s.authenticatedUser(c),
 	})
@@ -34,7 +34,7 @@ func (s *Services) changePasswordGet(c *gin.Context) {
 func (s *Services) changePasswordPost(c *gin.Context) {
 	var data changePasswordData
 	if err := c.Bind(&data); err != nil {
-		c.HTML(400, "changePassword", gin.H{
+		html(c, 400, "changePassword", gin.H{
 			"title": "Изменить пароль",
 			"auth":  s.authenticatedUser(c),
 			"error": err.Error(),
@@ -43,7 +43,7 @@ func (s *Services) changePasswordPost(c *gin.Context) {
 	}
 
 	if data.NewPassword != data.NewPassword2 {
-		c.HTML(400, "changePassword", gin.H{
+		html(c, 400, "changePassword", gin.H{
 			"title": "Изменить пароль",
 			"auth":  s.authenticatedUser(c),
 			"error": "Новые пароли не совпадают",
@@ -56,7 +56,7 @@ func (s *Services) changePasswordPost(c *gin.Context) {
 	user, err := s.Services.UserService.Login(authUser.Email, data.OldPassword)
 
 	if err != nil {
-		c.HTML(400, "changePassword", gin.H{
+		html(c, 400, "changePassword", gin.H{
 			"title": "Изменить пароль",
 			"auth":  s.authenticatedUser(c),
 			"error": "Старый пароль неверен",
@@ -75,7 +75,7 @@ func (s *Services) changePasswordPost(c *gin.Context) {
 	)
 
 	if updateErr != nil {
-		c.HTML(400, "changePassword", gin.H{
+		html(c, 400, "changePassword", gin.H{
 			"title": "Изменить пароль",
 			"auth":  updatedUser,
 			"error": updateErr.Error(),
@@ -96,7 +96,7 @@ type editProfileData struct {
 
 func (s *Services) editProfileGet(c *gin.Context) {
 	authUser := s.authenticatedUser(c)
-	c.HTML(200, "editProfile", gin.H{
+	html(c, 200, "editProfile", gin.H{
 		"title": "Редактировать профиль",
 		"auth":  authUser,
 		"formData": editProfileData{
@@ -112,7 +112,7 @@ func (s *Services) editProfileGet(c *gin.Context) {
 func (s *Services) editProfilePost(c *gin.Context) {
 	var data editProfileData
 	if err := c.Bind(&data); err != nil {
-		c.HTML(400, "editProfile", gin.H{
+		html(c, 400, "editProfile", gin.H{
 			"title":    "Редактировать профиль",
 			"auth":     s.authenticatedUser(c),
 			"error":    err.Error(),
@@ -134,7 +134,7 @@ func (s *Services) editProfilePost(c *gin.Context) {
 	)
 
 	if updateErr != nil {
-		c.HTML(400, "editProfile", gin.H{
+		html(c, 400, "editProfile", gin.H{
 			"title":    "Редактировать профиль",
 			"auth":     updatedUser,
 			"error":    updateErr.Error(),
@@ -143,30 +143,29 @@ func (s *Services) editProfilePost(c *gin.Context) {
 		return
 	}
 
+	if updatedUser.Email != authUser.Email {
+		_ = s.Services.EmailVerificationService.SendUserVerification(updatedUser.ID)
+	}
+
 	c.Redirect(302, "/users/profile")
 }
 
 func (s *Services) createOrderGet(c *gin.Context) {
-	prices := make(map[models.Category][]models.Task)
-	categories, err := s.Services.CategoryService.GetAll()
-	if err != nil {
-		log.Printf("Error getting categories: %v", err)
-		categories = []models.Category{}
-	}
+	query, found := s.catalogSearch(c)
+	prices := s.catalogSections(false, query, found)
 
-	for i, category := range categories {
-		tasks, err := s.Services.CategoryService.GetTasksInCategory(category.ID)
-		if err != nil {
-			log.Printf("Error getting tasks in category %d: %v", i, err)
-			continue
-		}
-		prices[category] = tasks
+	authUser := s.authenticatedUser(c)
+	var verificationError string
+	if s.mustVerifyEmail(authUser) {
+		verificationError = emailNotVerifiedMessage
 	}
-	c.HTML(200, "createOrder", gin.H{
-		"title":    "Создать заказ",
-		"auth":     s.authenticatedUser(c),
-		"prices":   prices,
-		"category": categories,
+
+	html(c, 200, "createOrder", gin.H{
+		"title":  "Создать заказ",
+		"auth":   authUser,
+		"prices": prices,
+		"error":  verificationError,
+		"query":  query,
 	})
 }
 
@@ -176,15 +175,18 @@ type createOrderData struct {
 	Address     string `form:"addressInput"`
 	Tasks       map[string]string
 	Confirmed   bool `form:"confirmed"`
+	// IdempotencyKey is generated when the confirmation form is rendered
+	IdempotencyKey string `form:"idempotency_key"`
 }
 
 func (s *Services) createOrderPost(c *gin.Context) {
 	data := createOrderData{
-		SameAddress: utils.ParseHtmlToggle(c.DefaultPostForm("sameAddress", "off")),
-		Deadline:    c.PostForm("deadlineInput"),
-		Address:     c.PostForm("addressInput"),
-		Tasks:       c.PostFormMap("tasks"),
-		Confirmed:   c.DefaultPostForm("confirmed", "false") == "true",
+		SameAddress:    utils.ParseHtmlToggle(c.DefaultPostForm("sameAddress", "off")),
+		Deadline:       c.PostForm("deadlineInput"),
+		Address:        c.PostForm("addressInput"),
+		Tasks:          c.PostFormMap("tasks"),
+		Confirmed:      c.DefaultPostForm("confirmed", "false") == "true",
+		IdempotencyKey: c.DefaultPostForm("idempotency_key", c.GetHeader(idempotencyKeyHeader)),
 	}
 
 	if data.SameAddress {
@@ -193,15 +195,26 @@ func (s *Services) createOrderPost(c *gin.Context) {
 
 	authUser := s.authenticatedUser(c)
 
+	if s.mustVerifyEmail(authUser) {
+		html(c, 403, "createOrder", gin.H{
+			"title": "Создать заказ",
+			"auth":  authUser,
+			"error": emailNotVerifiedMessage,
+		})
+		return
+	}
+
 	var orderedTasks []models.OrderedTask
-	var totalPrice float64 = 0
 	for taskID, taskAmount := range data.Tasks {
 		parsedID, _ := uuid.Parse(taskID)
 		taskObj, err := s.Services.TaskService.GetTaskByID(parsedID)
+		if err != nil {
+			log.Printf("Error getting task by ID %s: %v", taskID, err)
+			continue
+		}
 
 		quantity, err := strconv.Atoi(taskAmount)
 		if err == nil && quantity > 0 {
-			totalPrice += taskObj.PricePerSingle * float64(quantity)
 			orderedTasks = append(orderedTasks, models.OrderedTask{
 				Task:     taskObj,
 				Quantity: quantity,
@@ -212,31 +225,39 @@ func (s *Services) createOrderPost(c *gin.Context) {
 	}
 
 	if !data.Confirmed {
-		sum := float64(0)
-		for _, task := range orderedTasks {
-			sum += task.Task.PricePerSingle * float64(task.Quantity)
+		totals, err := s.Services.OrderService.CalculateTotals(orderedTasks)
+		if err != nil {
+			html(c, 500, "createOrder", gin.H{
+				"title": "Создать заказ",
+				"auth":  authUser,
+				"error": "Не удалось рассчитать стоимость заказа, попробуйте позже",
+			})
+			return
 		}
-		c.HTML(200, "confirmOrder", gin.H{
-			"title":      "Подтвердить заказ",
-			"auth":       authUser,
-			"address":    data.Address,
-			"deadline":   data.Deadline,
-			"tasks":      orderedTasks,
-			"sum":        sum,
-			"totalPrice": totalPrice,
+
+		html(c, 200, "confirmOrder", gin.H{
+			"title":          "Подтвердить заказ",
+			"auth":           authUser,
+			"address":        data.Address,
+			"deadline":       data.Deadline,
+			"tasks":          orderedTasks,
+			"totals":         totals,
+			"idempotencyKey": uuid.NewString(),
 		})
 		return
 	}
 
+	// a double click on the confirmation button sends the same key twice and gets the order created by the first click
 	_, err := s.Services.OrderService.CreateOrder(
 		authUser.ID,
 		data.Address,
 		utils.ConvertStringToTime(data.Deadline),
 		orderedTasks,
+		data.IdempotencyKey,
 	)
 
 	if err != nil {
-		c.HTML(400, "createOrder", gin.H{
+		html(c, 400, "createOrder", gin.H{
 			"title": "Создать заказ",
 			"auth":  authUser,
 			"error": err.Error(),
@@ -257,10 +278,12 @@ type OrderItem struct {
 	CreationDate time.Time
 	Deadline     time.Time
 	Rate         int
+	Archived     bool
 }
 
-func (s *Services) getOrdersList(params map[string]string) ([]OrderItem, error) {
-	orders, err := s.Services.OrderService.Filter(params)
+// getOrdersList finds the orders with find, OrderService.Filter or OrderService.History, and adds their details.
+func (s *Services) getOrdersList(find func(params map[string]string) ([]models.Order, error), params map[string]string) ([]OrderItem, error) {
+	orders, err := find(params)
 	if err != nil {
 		return nil, err
 	}
@@ -280,6 +303,7 @@ func (s *Services) getOrdersList(params map[string]string) ([]OrderItem, error)
 			CreationDate: order.CreationDate,
 			Deadline:     order.Deadline,
 			Rate:         order.Rate,
+			Archived:     order.Archived,
 		})
 	}
 
@@ -294,10 +318,10 @@ func (s *Services) inProgressOrders(c *gin.Context) {
 		"user_id": authUser.ID.String(),
 	}
 
-	orders, err := s.getOrdersList(params)
+	orders, err := s.getOrdersList(s.Services.OrderService.Filter, params)
 
 	if err != nil {
-		c.HTML(500, "error", gin.H{
+		html(c, 500, "error", gin.H{
 			"title": "Ошибка",
 			"auth":  authUser,
 			"error": err.Error(),
@@ -305,7 +329,7 @@ func (s *Services) inProgressOrders(c *gin.Context) {
 		return
 	}
 
-	c.HTML(200, "ordersList", gin.H{
+	html(c, 200, "ordersList", gin.H{
 		"title":  "Активные заказы",
 		"auth":   authUser,
 		"orders": orders,
@@ -320,10 +344,11 @@ func (s *Services) completedOrders(c *gin.Context) {
 		"user_id": authUser.ID.String(),
 	}
 
-	orders, err := s.getOrdersList(params)
+	// the history also lists the archived orders
+	orders, err := s.getOrdersList(s.Services.OrderService.History, params)
 
 	if err != nil {
-		c.HTML(500, "error", gin.H{
+		html(c, 500, "error", gin.H{
 			"title": "Ошибка",
 			"auth":  authUser,
 			"error": err.Error(),
@@ -331,7 +356,7 @@ func (s *Services) completedOrders(c *gin.Context) {
 		return
 	}
 
-	c.HTML(200, "ordersList", gin.H{
+	html(c, 200, "ordersList", gin.H{
 		"title":  "Завершенные заказы",
 		"auth":   authUser,
 		"orders": orders,
@@ -344,7 +369,7 @@ func (s *Services) orderGet(c *gin.Context) {
 
 	order, err := s.Services.OrderService.GetOrderByID(orderID)
 	if err != nil || order.UserID != authUser.ID {
-		c.HTML(500, "orderDetails", gin.H{
+		html(c, 500, "orderDetails", gin.H{
 			"title": "Ошибка",
 			"auth":  authUser,
 			"error": "Такого заказа не существует или у вас нет прав на его просмотр",
@@ -353,25 +378,13 @@ func (s *Services) orderGet(c *gin.Context) {
 	}
 
 	worker, _ := s.Services.WorkerService.GetWorkerByID(order.WorkerID)
-	totalPrice, _ := s.Services.OrderService.GetTotalPrice(order.ID)
-	tasks, _ := s.Services.OrderService.GetTasksInOrder(order.ID)
-
-	orderedTasks := make([]models.OrderedTask, 0)
-	for _, task := range tasks {
-		quantity, _ := s.Services.OrderService.GetTaskQuantity(order.ID, task.ID)
-		orderedTasks = append(orderedTasks, models.OrderedTask{
-			Task:     &task,
-			Quantity: quantity,
-		})
+	totals, _ := s.Services.OrderService.GetTotals(order.ID)
 
-	}
-
-	c.HTML(200, "orderDetails", gin.H{
-		"title":      "Заказ",
-		"auth":       authUser,
-		"order":      order,
-		"worker":     worker,
-		"tasks":      orderedTasks,
-		"totalPrice": totalPrice,
+	html(c, 200, "orderDetails", gin.H{
+		"title":  "Заказ",
+		"auth":   authUser,
+		"order":  order,
+		"worker": worker,
+		"totals": totals,
 	})
 }
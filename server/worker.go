@@ -1,9 +1,13 @@
 package server
 
 import (
+	"fmt"
 	"lab3/internal/models"
+	"lab3/middleware"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/contrib/sessions"
 	"github.com/gin-gonic/gin"
@@ -20,7 +24,7 @@ func (s *Services) authenticatedWorker(c *gin.Context) *models.Worker {
 			userId, err := uuid.Parse(strWorkerID)
 			if err == nil {
 				worker, err := s.Services.WorkerService.GetWorkerByID(userId)
-				if err == nil {
+				if err == nil && !worker.IsDeleted() {
 					return worker
 				}
 			}
@@ -30,7 +34,7 @@ func (s *Services) authenticatedWorker(c *gin.Context) *models.Worker {
 }
 
 func (s *Services) workerSigninGet(c *gin.Context) {
-	c.HTML(200, "signin", gin.H{
+	html(c, 200, "signin", gin.H{
 		"title": "Вход для исполнителя",
 	})
 }
@@ -38,17 +42,29 @@ func (s *Services) workerSigninGet(c *gin.Context) {
 func (s *Services) workerSigninPost(c *gin.Context) {
 	var data loginFormData
 	if err := c.Bind(&data); err != nil {
-		c.HTML(http.StatusBadRequest, "signin", gin.H{
+		html(c, http.StatusBadRequest, "signin", gin.H{
 			"title": "Вход для исполнителя",
 			"error": err.Error(),
 		})
 		return
 	}
 
+	wait, err := s.Services.LoginAttemptService.Check(models.WorkerLoginScope, data.Email, c.ClientIP())
+	if err != nil {
+		code, message := signinBlockedMessage(wait, err)
+		html(c, code, "signin", gin.H{
+			"title":    "Вход для исполнителя",
+			"error":    message,
+			"formData": data,
+		})
+		return
+	}
+
 	// try to login
 	worker, err := s.Services.WorkerService.Login(data.Email, data.Password)
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "signin", gin.H{
+		_ = s.Services.LoginAttemptService.RegisterFailure(models.WorkerLoginScope, data.Email, c.ClientIP())
+		html(c, http.StatusBadRequest, "signin", gin.H{
 			"title":    "Вход для исполнителя",
 			"error":    "Неверный пароль или исполнитель с таким email не существует",
 			"formData": data,
@@ -56,12 +72,38 @@ func (s *Services) workerSigninPost(c *gin.Context) {
 		return
 	}
 
-	// Set the session.
 	session := sessions.Default(c)
+
+	twoFactorEnabled, err := s.Services.TwoFactorService.IsEnabled(worker.ID)
+	if err == nil {
+		// a session fixed before the sign-in must not become the session of the worker
+		err = middleware.RenewSession(c)
+	}
+	if err != nil {
+		html(c, http.StatusInternalServerError, "signin", gin.H{
+			"title":    "Вход для исполнителя",
+			"error":    "Не удалось выполнить вход. Попробуйте позже",
+			"formData": data,
+		})
+		return
+	}
+
+	// the password alone is not enough: the worker is signed in after the second step
+	if twoFactorEnabled {
+		session.Set(pendingWorkerIDKey, worker.ID.String())
+		session.Set(pendingWorkerSinceKey, time.Now().Unix())
+		session.Save()
+		c.Redirect(http.StatusFound, "/worker-auth/two-factor")
+		return
+	}
+
+	_ = s.Services.LoginAttemptService.RegisterSuccess(models.WorkerLoginScope, data.Email)
+
+	// Set the session.
 	session.Set("workerID", worker.ID.String())
 	ok := session.Save()
 	if ok != nil {
-		c.HTML(http.StatusBadRequest, "signin", gin.H{
+		html(c, http.StatusBadRequest, "signin", gin.H{
 			"title":    "Вход для исполнителя",
 			"error":    "Не удалось сохранить сессию",
 			"formData": data,
@@ -75,20 +117,29 @@ func (s *Services) workerSigninPost(c *gin.Context) {
 func (s *Services) workerProfile(c *gin.Context) {
 	worker := s.authenticatedWorker(c)
 
+	twoFactorEnabled, _ := s.Services.TwoFactorService.IsEnabled(worker.ID)
+	recoveryCodesLeft, _ := s.Services.TwoFactorService.RecoveryCodesLeft(worker.ID)
+
 	if worker.Role == models.ManagerRole {
-		c.HTML(200, "worker-profile", gin.H{
-			"title":  "Профиль менеджера",
-			"worker": worker,
+		html(c, 200, "worker-profile", gin.H{
+			"title":             "Профиль менеджера",
+			"worker":            worker,
+			"twoFactorEnabled":  twoFactorEnabled,
+			"twoFactorRequired": s.Services.TwoFactorService.IsRequired(worker),
+			"recoveryCodesLeft": recoveryCodesLeft,
 		})
 		return
 	}
 
 	avgRate, _ := s.Services.WorkerService.GetAverageOrderRate(worker)
 
-	c.HTML(200, "worker-profile", gin.H{
-		"title":   "Профиль исполнителя",
-		"worker":  worker,
-		"avgRate": avgRate,
+	html(c, 200, "worker-profile", gin.H{
+		"title":             "Профиль исполнителя",
+		"worker":            worker,
+		"avgRate":           avgRate,
+		"twoFactorEnabled":  twoFactorEnabled,
+		"twoFactorRequired": s.Services.TwoFactorService.IsRequired(worker),
+		"recoveryCodesLeft": recoveryCodesLeft,
 	})
 }
 
@@ -169,11 +220,11 @@ func (s *Services) dashboard(c *gin.Context) {
 	worker := s.authenticatedWorker(c)
 
 	if worker.Role == models.ManagerRole {
-		c.HTML(200, "adminDashboard", s.adminDashboard(worker))
+		html(c, 200, "adminDashboard", s.adminDashboard(worker))
 		return
 	}
 
-	c.HTML(200, "masterDashboard", s.masterDashboard(worker))
+	html(c, 200, "masterDashboard", s.masterDashboard(worker))
 }
 
 type workerData struct {
@@ -207,30 +258,41 @@ func (s *Services) workersDirectory(c *gin.Context) {
 	}
 
 	if worker.Role == models.ManagerRole {
-		c.HTML(200, "workersDirectory", gin.H{
+		data := gin.H{
 			"title":    "Список исполнителей",
 			"worker":   worker,
 			"managers": managers,
 			"workers":  workersData,
-		})
+		}
+
+		if c.Query("archived") == "1" {
+			archivedWorkers, _ := s.Services.WorkerService.GetDeletedWorkers()
+			archivedUsers, _ := s.Services.UserService.GetDeletedUsers()
+
+			data["archived"] = true
+			data["archivedWorkers"] = archivedWorkers
+			data["archivedUsers"] = archivedUsers
+		}
+
+		html(c, 200, "workersDirectory", data)
 		return
 	}
 
-	c.HTML(403, "workersDirectory", gin.H{"title": "Список исполнителей", "error": "Доступ запрещен!"})
+	html(c, 403, "workersDirectory", gin.H{"title": "Список исполнителей", "error": "Доступ запрещен!"})
 }
 
 func (s *Services) createWorkerGet(c *gin.Context) {
 	worker := s.authenticatedWorker(c)
 
 	if worker.Role == models.ManagerRole {
-		c.HTML(200, "createWorker", gin.H{
+		html(c, 200, "createWorker", gin.H{
 			"title":  "Добавление исполнителя",
 			"worker": worker,
 		})
 		return
 	}
 
-	c.HTML(403, "createWorker", gin.H{"title": "Добавление исполнителя", "error": "Доступ запрещен!"})
+	html(c, 403, "createWorker", gin.H{"title": "Добавление исполнителя", "error": "Доступ запрещен!"})
 }
 
 type createWorkerFormData struct {
@@ -247,13 +309,13 @@ func (s *Services) createWorkerPost(c *gin.Context) {
 	worker := s.authenticatedWorker(c)
 
 	if worker.Role != models.ManagerRole {
-		c.HTML(403, "createWorker", gin.H{"title": "Добавление исполнителя", "error": "Доступ запрещен!"})
+		html(c, 403, "createWorker", gin.H{"title": "Добавление исполнителя", "error": "Доступ запрещен!"})
 		return
 	}
 
 	var data createWorkerFormData
 	if err := c.Bind(&data); err != nil {
-		c.HTML(http.StatusBadRequest, "createWorker", gin.H{
+		html(c, http.StatusBadRequest, "createWorker", gin.H{
 			"title": "Добавление исполнителя",
 			"error": err.Error(),
 		})
@@ -272,15 +334,17 @@ func (s *Services) createWorkerPost(c *gin.Context) {
 		Password:    data.Password,
 	}
 
-	_, err := s.Services.WorkerService.Create(&newWorker, newWorker.Password)
+	createdWorker, err := s.Services.WorkerService.Create(&newWorker, newWorker.Password)
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "createWorker", gin.H{
+		html(c, http.StatusBadRequest, "createWorker", gin.H{
 			"title": "Добавление исполнителя",
 			"error": err.Error(),
 		})
 		return
 	}
 
+	s.audit(c, worker, models.AuditWorkerCreate, models.AuditTargetWorker, createdWorker.ID.String(), nil, createdWorker)
+
 	c.Redirect(http.StatusFound, "/worker/directory")
 }
 
@@ -292,19 +356,20 @@ type orderData struct {
 	CreationDate string
 	Deadline     string
 	Rate         int
+	Archived     bool
 }
 
 func (s *Services) workerDetails(c *gin.Context) {
 	worker := s.authenticatedWorker(c)
 
 	if worker.Role != models.ManagerRole {
-		c.HTML(403, "workerDetails", gin.H{"title": "Информация об исполнителе", "error": "Доступ запрещен!"})
+		html(c, 403, "workerDetails", gin.H{"title": "Информация об исполнителе", "error": "Доступ запрещен!"})
 		return
 	}
 
 	workerID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "workerDetails", gin.H{
+		html(c, http.StatusBadRequest, "workerDetails", gin.H{
 			"title": "Информация об исполнителе",
 			"error": "Неверный идентификатор исполнителя",
 		})
@@ -313,7 +378,7 @@ func (s *Services) workerDetails(c *gin.Context) {
 
 	workerDetails, err := s.Services.WorkerService.GetWorkerByID(workerID)
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "workerDetails", gin.H{
+		html(c, http.StatusBadRequest, "workerDetails", gin.H{
 			"title": "Информация об исполнителе",
 			"error": "Исполнитель не найден",
 		})
@@ -341,17 +406,20 @@ func (s *Services) workerDetails(c *gin.Context) {
 	}
 
 	params["status"] = "3"
-	completedOrders, _ := s.Services.OrderService.Filter(params)
+	completedOrders, _ := s.Services.OrderService.History(params)
 
 	avgRate, _ := s.Services.WorkerService.GetAverageOrderRate(workerDetails)
+	twoFactorEnabled, _ := s.Services.TwoFactorService.IsEnabled(workerDetails.ID)
 
-	c.HTML(200, "workerDetails", gin.H{
+	html(c, 200, "workerDetails", gin.H{
 		"worker":           worker,
 		"title":            "Информация об исполнителе",
 		"workerDetails":    workerDetails,
 		"inProgressOrders": inProgressOrdersData,
 		"completedOrders":  completedOrders,
 		"avgRate":          avgRate,
+		"twoFactorEnabled": twoFactorEnabled,
+		"isSelf":           workerDetails.ID == worker.ID,
 	})
 }
 
@@ -366,7 +434,8 @@ func (s *Services) ordersHistory(c *gin.Context) {
 		params["worker_id"] = worker.ID.String()
 	}
 
-	orders, _ := s.Services.OrderService.Filter(params)
+	// the history also lists the archived orders
+	orders, _ := s.Services.OrderService.History(params)
 
 	ordersData := make([]orderData, len(orders))
 	for i, o := range orders {
@@ -379,10 +448,11 @@ func (s *Services) ordersHistory(c *gin.Context) {
 			CreationDate: o.CreationDate.Format("2006-01-02 15:04:05"),
 			Deadline:     o.Deadline.Format("2006-01-02"),
 			Rate:         o.Rate,
+			Archived:     o.Archived,
 		}
 	}
 
-	c.HTML(200, "ordersHistory", gin.H{
+	html(c, 200, "ordersHistory", gin.H{
 		"title":  "История заказов",
 		"worker": worker,
 		"orders": ordersData,
@@ -394,7 +464,7 @@ func (s *Services) orderDetails(c *gin.Context) {
 
 	orderID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "changeStatus", gin.H{
+		html(c, http.StatusBadRequest, "changeStatus", gin.H{
 			"title":  "Информация о заказе",
 			"error":  "Неверный идентификатор заказа",
 			"worker": worker,
@@ -404,7 +474,7 @@ func (s *Services) orderDetails(c *gin.Context) {
 
 	order, err := s.Services.OrderService.GetOrderByID(orderID)
 	if err != nil {
-		c.HTML(http.StatusBadRequest, "changeStatus", gin.H{
+		html(c, http.StatusBadRequest, "changeStatus", gin.H{
 			"title":  "Информация о заказе",
 			"error":  "Заказ не найден",
 			"worker": worker,
@@ -413,45 +483,36 @@ func (s *Services) orderDetails(c *gin.Context) {
 	}
 
 	if worker.Role != models.ManagerRole && order.WorkerID != worker.ID {
-		c.HTML(403, "changeStatus", gin.H{"title": "Информация о заказе", "error": "Доступ запрещен!", "worker": worker})
+		html(c, 403, "changeStatus", gin.H{"title": "Информация о заказе", "error": "Доступ запрещен!", "worker": worker})
 		return
 	}
 
 	user, _ := s.Services.UserService.GetUserByID(order.UserID)
-	tasks, _ := s.Services.OrderService.GetTasksInOrder(orderID)
-	orderedTasks := make([]models.OrderedTask, 0)
-	for _, task := range tasks {
-		quantity, _ := s.Services.OrderService.GetTaskQuantity(order.ID, task.ID)
-		orderedTasks = append(orderedTasks, models.OrderedTask{
-			Task:     &task,
-			Quantity: quantity,
-		})
-
-	}
+	totals, _ := s.Services.OrderService.GetTotals(orderID)
 
 	if worker.Role == models.MasterRole {
-		c.HTML(200, "changeStatus", gin.H{
+		html(c, 200, "changeStatus", gin.H{
 			"title":  "Информация о заказе",
 			"worker": worker,
 			"order":  order,
 			"user":   user,
-			"tasks":  orderedTasks,
+			"totals": totals,
 		})
 		return
 	} else if worker.Role == models.ManagerRole {
 		workers, _ := s.Services.WorkerService.GetWorkersByRole(models.MasterRole)
-		c.HTML(200, "changeStatus", gin.H{
+		html(c, 200, "changeStatus", gin.H{
 			"title":         "Информация о заказе",
 			"worker":        worker,
 			"order":         order,
 			"user":          user,
 			"workersSelect": workers,
-			"tasks":         orderedTasks,
+			"totals":        totals,
 		})
 		return
 	}
 
-	c.HTML(200, "orderDetails", gin.H{
+	html(c, 200, "orderDetails", gin.H{
 		"title":  "Информация о заказе",
 		"worker": worker,
 		"order":  order,
@@ -472,7 +533,7 @@ func (s *Services) editWorkerGet(c *gin.Context) {
 	authWorker := s.authenticatedWorker(c)
 	workerID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.HTML(400, "editWorker", gin.H{
+		html(c, 400, "editWorker", gin.H{
 			"title":  "Редактировать профиль",
 			"worker": authWorker,
 			"error":  "Неверный идентификатор исполнителя",
@@ -482,7 +543,7 @@ func (s *Services) editWorkerGet(c *gin.Context) {
 
 	editedWorker, err := s.Services.WorkerService.GetWorkerByID(workerID)
 	if err != nil {
-		c.HTML(400, "editWorker", gin.H{
+		html(c, 400, "editWorker", gin.H{
 			"title":  "Редактировать профиль",
 			"worker": authWorker,
 			"error":  "Исполнитель не найден",
@@ -490,7 +551,7 @@ func (s *Services) editWorkerGet(c *gin.Context) {
 		return
 	}
 
-	c.HTML(200, "editWorker", gin.H{
+	html(c, 200, "editWorker", gin.H{
 		"title":  "Редактировать профиль",
 		"worker": authWorker,
 		"formData": editWorkerData{
@@ -508,7 +569,7 @@ func (s *Services) editWorkerPost(c *gin.Context) {
 	authWorker := s.authenticatedWorker(c)
 	workerID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.HTML(400, "editWorker", gin.H{
+		html(c, 400, "editWorker", gin.H{
 			"title":  "Редактировать профиль",
 			"worker": authWorker,
 			"error":  "Неверный идентификатор исполнителя",
@@ -518,7 +579,7 @@ func (s *Services) editWorkerPost(c *gin.Context) {
 
 	editedWorker, err := s.Services.WorkerService.GetWorkerByID(workerID)
 	if err != nil {
-		c.HTML(400, "editWorker", gin.H{
+		html(c, 400, "editWorker", gin.H{
 			"title":  "Редактировать профиль",
 			"worker": authWorker,
 			"error":  "Исполнитель не найден",
@@ -529,7 +590,7 @@ func (s *Services) editWorkerPost(c *gin.Context) {
 	var data editWorkerData
 	err = c.Bind(&data)
 	if err != nil {
-		c.HTML(400, "editWorker", gin.H{
+		html(c, 400, "editWorker", gin.H{
 			"title":    "Редактировать профиль",
 			"worker":   authWorker,
 			"error":    err.Error(),
@@ -538,7 +599,7 @@ func (s *Services) editWorkerPost(c *gin.Context) {
 		return
 	}
 
-	_, updateErr := s.Services.WorkerService.Update(
+	updatedWorker, updateErr := s.Services.WorkerService.Update(
 		editedWorker.ID,
 		data.Name,
 		data.Surname,
@@ -550,7 +611,7 @@ func (s *Services) editWorkerPost(c *gin.Context) {
 	)
 
 	if updateErr != nil {
-		c.HTML(400, "editWorker", gin.H{
+		html(c, 400, "editWorker", gin.H{
 			"title":  "Редактировать профиль",
 			"worker": authWorker,
 			"formData": editWorkerData{
@@ -566,13 +627,15 @@ func (s *Services) editWorkerPost(c *gin.Context) {
 		return
 	}
 
+	s.audit(c, authWorker, models.AuditWorkerUpdate, models.AuditTargetWorker, workerID.String(), editedWorker, updatedWorker)
+
 	c.Redirect(302, "/worker/"+workerID.String())
 }
 
 func (s *Services) changeWorkerPasswordGet(c *gin.Context) {
 	worker := s.authenticatedWorker(c)
 
-	c.HTML(200, "changePassword", gin.H{
+	html(c, 200, "changePassword", gin.H{
 		"title":  "Изменить пароль",
 		"worker": worker,
 	})
@@ -583,7 +646,7 @@ func (s *Services) changeWorkerPasswordPost(c *gin.Context) {
 
 	var data changePasswordData
 	if err := c.Bind(&data); err != nil {
-		c.HTML(400, "changePassword", gin.H{
+		html(c, 400, "changePassword", gin.H{
 			"title":  "Изменить пароль",
 			"worker": authWorker,
 			"error":  err.Error(),
@@ -592,7 +655,7 @@ func (s *Services) changeWorkerPasswordPost(c *gin.Context) {
 	}
 
 	if data.NewPassword != data.NewPassword2 {
-		c.HTML(400, "changePassword", gin.H{
+		html(c, 400, "changePassword", gin.H{
 			"title": "Изменить пароль",
 			"auth":  authWorker,
 			"error": "Новые пароли не совпадают",
@@ -603,7 +666,7 @@ func (s *Services) changeWorkerPasswordPost(c *gin.Context) {
 	worker, err := s.Services.WorkerService.Login(authWorker.Email, data.OldPassword)
 
 	if err != nil {
-		c.HTML(400, "changePassword", gin.H{
+		html(c, 400, "changePassword", gin.H{
 			"title": "Изменить пароль",
 			"auth":  authWorker,
 			"error": "Старый пароль неверен",
@@ -623,7 +686,7 @@ func (s *Services) changeWorkerPasswordPost(c *gin.Context) {
 	)
 
 	if updateErr != nil {
-		c.HTML(400, "changePassword", gin.H{
+		html(c, 400, "changePassword", gin.H{
 			"title": "Изменить пароль",
 			"auth":  updatedWorker,
 			"error": updateErr.Error(),
@@ -633,3 +696,310 @@ func (s *Services) changeWorkerPasswordPost(c *gin.Context) {
 
 	c.Redirect(302, "/worker/profile")
 }
+
+func (s *Services) workerLogoutEverywhere(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	err := s.Services.SessionService.RevokeWorkerSessions(worker.ID)
+	if err != nil {
+		html(c, http.StatusInternalServerError, "worker-profile", gin.H{
+			"title":  "Профиль",
+			"worker": worker,
+			"error":  "Не удалось завершить сессии",
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/worker-auth/signin")
+}
+
+func (s *Services) revokeWorkerSessions(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "workerDetails", gin.H{"title": "Информация об исполнителе", "error": "Доступ запрещен!"})
+		return
+	}
+
+	workerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		html(c, http.StatusBadRequest, "workerDetails", gin.H{
+			"title": "Информация об исполнителе",
+			"error": "Неверный идентификатор исполнителя",
+		})
+		return
+	}
+
+	err = s.Services.SessionService.RevokeWorkerSessions(workerID)
+	if err != nil {
+		html(c, http.StatusInternalServerError, "workerDetails", gin.H{
+			"title": "Информация об исполнителе",
+			"error": "Не удалось завершить сессии исполнителя",
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/worker/"+workerID.String())
+}
+
+// archiveWorker archives the worker and ends their sessions. The orders of the worker keep the reference.
+func (s *Services) archiveWorker(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "workerDetails", gin.H{"title": "Информация об исполнителе", "error": "Доступ запрещен!"})
+		return
+	}
+
+	workerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		html(c, http.StatusBadRequest, "workerDetails", gin.H{
+			"title": "Информация об исполнителе",
+			"error": "Неверный идентификатор исполнителя",
+		})
+		return
+	}
+
+	if workerID == worker.ID {
+		html(c, http.StatusBadRequest, "workerDetails", gin.H{
+			"title": "Информация об исполнителе",
+			"error": "Нельзя отправить в архив свою учетную запись",
+		})
+		return
+	}
+
+	before, _ := s.Services.WorkerService.GetWorkerByID(workerID)
+
+	err = s.Services.WorkerService.Delete(workerID)
+	if err != nil {
+		html(c, http.StatusBadRequest, "workerDetails", gin.H{
+			"title": "Информация об исполнителе",
+			"error": "Не удалось отправить исполнителя в архив",
+		})
+		return
+	}
+
+	_ = s.Services.SessionService.RevokeWorkerSessions(workerID)
+
+	after, _ := s.Services.WorkerService.GetWorkerByID(workerID)
+	s.audit(c, worker, models.AuditWorkerDelete, models.AuditTargetWorker, workerID.String(), before, after)
+
+	c.Redirect(http.StatusFound, "/worker/directory")
+}
+
+func (s *Services) restoreWorker(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "workerDetails", gin.H{"title": "Информация об исполнителе", "error": "Доступ запрещен!"})
+		return
+	}
+
+	workerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		html(c, http.StatusBadRequest, "workerDetails", gin.H{
+			"title": "Информация об исполнителе",
+			"error": "Неверный идентификатор исполнителя",
+		})
+		return
+	}
+
+	before, _ := s.Services.WorkerService.GetWorkerByID(workerID)
+
+	err = s.Services.WorkerService.Restore(workerID)
+	if err != nil {
+		html(c, http.StatusBadRequest, "workerDetails", gin.H{
+			"title": "Информация об исполнителе",
+			"error": "Исполнитель не найден в архиве",
+		})
+		return
+	}
+
+	after, _ := s.Services.WorkerService.GetWorkerByID(workerID)
+	s.audit(c, worker, models.AuditWorkerRestore, models.AuditTargetWorker, workerID.String(), before, after)
+
+	c.Redirect(http.StatusFound, "/worker/"+workerID.String())
+}
+
+func (s *Services) restoreUser(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "workersDirectory", gin.H{"title": "Список исполнителей", "error": "Доступ запрещен!"})
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		html(c, http.StatusBadRequest, "workersDirectory", gin.H{
+			"title": "Список исполнителей",
+			"error": "Неверный идентификатор клиента",
+		})
+		return
+	}
+
+	before, _ := s.Services.UserService.GetUserByID(userID)
+
+	err = s.Services.UserService.Restore(userID)
+	if err != nil {
+		html(c, http.StatusBadRequest, "workersDirectory", gin.H{
+			"title": "Список исполнителей",
+			"error": "Клиент не найден в архиве",
+		})
+		return
+	}
+
+	after, _ := s.Services.UserService.GetUserByID(userID)
+	s.audit(c, worker, models.AuditUserRestore, models.AuditTargetUser, userID.String(), before, after)
+
+	c.Redirect(http.StatusFound, "/worker/directory?archived=1")
+}
+
+type loginLockoutData struct {
+	Key         string
+	Scope       string
+	Subject     string
+	Failures    int
+	LockedUntil string
+}
+
+var loginScopeNames = map[string]string{
+	models.UserLoginScope:   "Клиент",
+	models.WorkerLoginScope: "Исполнитель",
+	models.IPLoginScope:     "IP-адрес",
+}
+
+func (s *Services) loginLockouts(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "loginLockouts", gin.H{"title": "Блокировки входа", "error": "Доступ запрещен!"})
+		return
+	}
+
+	attempts, err := s.Services.LoginAttemptService.GetLocked()
+	if err != nil {
+		html(c, http.StatusInternalServerError, "loginLockouts", gin.H{
+			"title":  "Блокировки входа",
+			"worker": worker,
+			"error":  "Не удалось получить список блокировок",
+		})
+		return
+	}
+
+	lockouts := make([]loginLockoutData, len(attempts))
+	for i, a := range attempts {
+		scope, subject, _ := strings.Cut(a.Key, ":")
+		lockouts[i] = loginLockoutData{
+			Key:         a.Key,
+			Scope:       loginScopeNames[scope],
+			Subject:     subject,
+			Failures:    a.Failures,
+			LockedUntil: a.LockedUntil.Local().Format("2006-01-02 15:04:05"),
+		}
+	}
+
+	html(c, 200, "loginLockouts", gin.H{
+		"title":    "Блокировки входа",
+		"worker":   worker,
+		"lockouts": lockouts,
+	})
+}
+
+func (s *Services) unlockLogin(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "loginLockouts", gin.H{"title": "Блокировки входа", "error": "Доступ запрещен!"})
+		return
+	}
+
+	key := c.PostForm("key")
+	if key == "" {
+		html(c, http.StatusBadRequest, "loginLockouts", gin.H{
+			"title":  "Блокировки входа",
+			"worker": worker,
+			"error":  "Не указана блокировка",
+		})
+		return
+	}
+
+	err := s.Services.LoginAttemptService.Unlock(key)
+	if err != nil {
+		html(c, http.StatusInternalServerError, "loginLockouts", gin.H{
+			"title":  "Блокировки входа",
+			"worker": worker,
+			"error":  "Не удалось снять блокировку",
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/worker/lockouts")
+}
+
+type cacheStatsData struct {
+	Name      string
+	Hits      uint64
+	Misses    uint64
+	HitRate   string
+	Evictions uint64
+	Entries   string
+	TTL       string
+}
+
+var cacheNames = map[string]string{
+	"tasks":      "Услуги",
+	"categories": "Категории",
+}
+
+func (s *Services) cacheStats(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "cacheStats", gin.H{"title": "Кэш каталога", "error": "Доступ запрещен!"})
+		return
+	}
+
+	stats := s.Services.CacheService.Stats()
+	caches := make([]cacheStatsData, len(stats))
+	for i, cache := range stats {
+		entries := strconv.Itoa(cache.Entries)
+		if cache.MaxEntries > 0 {
+			entries += " из " + strconv.Itoa(cache.MaxEntries)
+		}
+
+		ttl := "без ограничения"
+		if cache.TTL > 0 {
+			ttl = cache.TTL.String()
+		}
+
+		caches[i] = cacheStatsData{
+			Name:      cacheNames[cache.Name],
+			Hits:      cache.Hits,
+			Misses:    cache.Misses,
+			HitRate:   fmt.Sprintf("%.1f%%", cache.HitRate()*100),
+			Evictions: cache.Evictions,
+			Entries:   entries,
+			TTL:       ttl,
+		}
+	}
+
+	html(c, 200, "cacheStats", gin.H{
+		"title":  "Кэш каталога",
+		"worker": worker,
+		"caches": caches,
+	})
+}
+
+func (s *Services) invalidateCache(c *gin.Context) {
+	worker := s.authenticatedWorker(c)
+
+	if worker.Role != models.ManagerRole {
+		html(c, 403, "cacheStats", gin.H{"title": "Кэш каталога", "error": "Доступ запрещен!"})
+		return
+	}
+
+	s.Services.CacheService.Invalidate()
+
+	c.Redirect(http.StatusFound, "/worker/cache")
+}
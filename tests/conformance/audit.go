@@ -0,0 +1,58 @@
+package conformance
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"lab3/internal/models"
+	"testing"
+	"time"
+)
+
+func runAuditTests(t *testing.T, factory Factory) {
+	t.Run("Filter", func(t *testing.T) {
+		repositories := factory(t)
+		actor := uuid.New()
+		other := uuid.New()
+		at := now()
+
+		entries := []*models.AuditEntry{
+			{ActorID: actor, Action: models.AuditTaskCreate, TargetType: models.AuditTargetTask, TargetID: "1", After: `{"name":"Task"}`, IP: "127.0.0.1", CreatedAt: at.Add(-3 * time.Hour)},
+			{ActorID: actor, Action: models.AuditTaskUpdate, TargetType: models.AuditTargetTask, TargetID: "1", CreatedAt: at.Add(-2 * time.Hour)},
+			{ActorID: other, Action: models.AuditCategoryCreate, TargetType: models.AuditTargetCategory, TargetID: "2", CreatedAt: at.Add(-time.Hour)},
+			{ActorID: actor, Action: models.AuditTaskDelete, TargetType: models.AuditTargetTask, TargetID: "3", CreatedAt: at},
+		}
+		for _, entry := range entries {
+			require.NoError(t, repositories.Audit.Create(entry))
+			require.NotEqual(t, uuid.Nil, entry.ID)
+		}
+
+		ids := func(filter models.AuditFilter) []uuid.UUID {
+			result, err := repositories.Audit.Filter(filter)
+			require.NoError(t, err)
+
+			var ids []uuid.UUID
+			for _, entry := range result {
+				ids = append(ids, entry.ID)
+			}
+			return ids
+		}
+
+		// newest first
+		require.Equal(t, []uuid.UUID{entries[3].ID, entries[2].ID, entries[1].ID, entries[0].ID}, ids(models.AuditFilter{}))
+		require.Equal(t, []uuid.UUID{entries[3].ID, entries[2].ID}, ids(models.AuditFilter{Limit: 2}))
+		require.Equal(t, []uuid.UUID{entries[3].ID, entries[1].ID, entries[0].ID}, ids(models.AuditFilter{ActorID: actor}))
+		require.Equal(t, []uuid.UUID{entries[1].ID}, ids(models.AuditFilter{Action: models.AuditTaskUpdate}))
+		require.Equal(t, []uuid.UUID{entries[1].ID, entries[0].ID}, ids(models.AuditFilter{TargetType: models.AuditTargetTask, TargetID: "1"}))
+		// From is inclusive, To is exclusive
+		require.Equal(t, []uuid.UUID{entries[2].ID, entries[1].ID}, ids(models.AuditFilter{From: at.Add(-2 * time.Hour), To: at}))
+		require.Empty(t, ids(models.AuditFilter{ActorID: uuid.New()}))
+
+		stored, err := repositories.Audit.Filter(models.AuditFilter{TargetType: models.AuditTargetTask, Action: models.AuditTaskCreate})
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		require.Equal(t, actor, stored[0].ActorID)
+		require.Equal(t, `{"name":"Task"}`, stored[0].After)
+		require.Equal(t, "127.0.0.1", stored[0].IP)
+		require.WithinDuration(t, entries[0].CreatedAt, stored[0].CreatedAt, time.Second)
+	})
+}
@@ -0,0 +1,155 @@
+package conformance
+
+import (
+	"github.com/stretchr/testify/require"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"testing"
+)
+
+func runCategoryTests(t *testing.T, factory Factory) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repositories := factory(t)
+
+		first, err := repositories.Categories.Create(&models.Category{Name: "First"})
+		require.NoError(t, err)
+		second, err := repositories.Categories.Create(&models.Category{Name: "Second"})
+		require.NoError(t, err)
+		require.Greater(t, second.ID, first.ID)
+
+		byID, err := repositories.Categories.GetByID(second.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Second", byID.Name)
+
+		categories, err := repositories.Categories.GetAll()
+		require.NoError(t, err)
+		require.Len(t, categories, 2)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repositories := factory(t)
+
+		_, err := repositories.Categories.GetByID(1000)
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		_, err = repositories.Categories.Update(&models.Category{ID: 1000, Name: "Missing"})
+		require.ErrorIs(t, err, repository_errors.UpdateError)
+		require.ErrorIs(t, repositories.Categories.Delete(1000), repository_errors.DoesNotExist)
+		require.ErrorIs(t, repositories.Categories.Restore(1000), repository_errors.DoesNotExist)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repositories := factory(t)
+		category, err := repositories.Categories.Create(&models.Category{Name: "Name"})
+		require.NoError(t, err)
+
+		category.Name = "New Name"
+		_, err = repositories.Categories.Update(category)
+		require.NoError(t, err)
+
+		updated, err := repositories.Categories.GetByID(category.ID)
+		require.NoError(t, err)
+		require.Equal(t, "New Name", updated.Name)
+	})
+
+	t.Run("TaxRate", func(t *testing.T) {
+		repositories := factory(t)
+		category, err := repositories.Categories.Create(&models.Category{Name: "Name", TaxRate: "vat10"})
+		require.NoError(t, err)
+		require.Equal(t, "vat10", category.TaxRate)
+
+		category.TaxRate = "vat20"
+		_, err = repositories.Categories.Update(category)
+		require.NoError(t, err)
+
+		updated, err := repositories.Categories.GetByID(category.ID)
+		require.NoError(t, err)
+		require.Equal(t, "vat20", updated.TaxRate)
+
+		all, err := repositories.Categories.GetAll()
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Equal(t, "vat20", all[0].TaxRate)
+	})
+
+	t.Run("DeleteAndRestore", func(t *testing.T) {
+		repositories := factory(t)
+		first, err := repositories.Categories.Create(&models.Category{Name: "First"})
+		require.NoError(t, err)
+		second, err := repositories.Categories.Create(&models.Category{Name: "Second"})
+		require.NoError(t, err)
+
+		require.NoError(t, repositories.Categories.Delete(first.ID))
+		tick()
+		require.NoError(t, repositories.Categories.Delete(second.ID))
+		require.ErrorIs(t, repositories.Categories.Delete(first.ID), repository_errors.DoesNotExist)
+
+		archived, err := repositories.Categories.GetByID(first.ID)
+		require.NoError(t, err)
+		require.True(t, archived.IsDeleted())
+
+		categories, err := repositories.Categories.GetAll()
+		require.NoError(t, err)
+		require.Empty(t, categories)
+
+		deleted, err := repositories.Categories.GetDeleted()
+		require.NoError(t, err)
+		require.Len(t, deleted, 2)
+		require.Equal(t, second.ID, deleted[0].ID)
+		require.Equal(t, first.ID, deleted[1].ID)
+
+		require.NoError(t, repositories.Categories.Restore(first.ID))
+		require.ErrorIs(t, repositories.Categories.Restore(first.ID), repository_errors.DoesNotExist)
+
+		categories, err = repositories.Categories.GetAll()
+		require.NoError(t, err)
+		require.Len(t, categories, 1)
+		require.Equal(t, first.ID, categories[0].ID)
+	})
+
+	t.Run("TreeAndOrder", func(t *testing.T) {
+		repositories := factory(t)
+		first := newCategory(t, repositories, "First")
+		second := newCategory(t, repositories, "Second")
+
+		nested, err := repositories.Categories.Create(&models.Category{Name: "Nested", ParentID: first.ID, Hidden: true})
+		require.NoError(t, err)
+		require.Equal(t, first.ID, nested.ParentID)
+		require.True(t, nested.Hidden)
+		require.Greater(t, second.Position, first.Position)
+		require.Greater(t, nested.Position, second.Position)
+
+		_, err = repositories.Categories.Create(&models.Category{Name: "Orphan", ParentID: nested.ID + 1000})
+		require.ErrorIs(t, err, repository_errors.InsertError)
+
+		nested.Name = "Top"
+		nested.ParentID = 0
+		nested.Hidden = false
+		_, err = repositories.Categories.Update(nested)
+		require.NoError(t, err)
+
+		updated, err := repositories.Categories.GetByID(nested.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Top", updated.Name)
+		require.Zero(t, updated.ParentID)
+		require.False(t, updated.Hidden)
+
+		ids := func() []int {
+			categories, err := repositories.Categories.GetAll()
+			require.NoError(t, err)
+
+			var result []int
+			for _, category := range categories {
+				result = append(result, category.ID)
+			}
+			return result
+		}
+		require.Equal(t, []int{first.ID, second.ID, nested.ID}, ids())
+
+		require.NoError(t, repositories.Categories.Reorder([]int{nested.ID, second.ID, first.ID}))
+		require.Equal(t, []int{nested.ID, second.ID, first.ID}, ids())
+
+		// a failed reorder keeps the order
+		require.ErrorIs(t, repositories.Categories.Reorder([]int{first.ID, nested.ID + 1000}), repository_errors.UpdateError)
+		require.Equal(t, []int{nested.ID, second.ID, first.ID}, ids())
+	})
+}
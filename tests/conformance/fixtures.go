@@ -0,0 +1,62 @@
+package conformance
+
+import (
+	"github.com/stretchr/testify/require"
+	"lab3/internal/models"
+	"testing"
+	"time"
+)
+
+func newUser(t *testing.T, repositories Repositories, email string) *models.User {
+	user, err := repositories.Users.Create(&models.User{
+		Name:        "First Name",
+		Surname:     "Last Name",
+		Address:     "Address",
+		PhoneNumber: "+79999999999",
+		Email:       email,
+		Password:    "hashed_password",
+	})
+	require.NoError(t, err)
+	return user
+}
+
+func newWorker(t *testing.T, repositories Repositories, email string, role int) *models.Worker {
+	worker, err := repositories.Workers.Create(&models.Worker{
+		Name:        "First Name",
+		Surname:     "Last Name",
+		Address:     "Address",
+		PhoneNumber: "+79999999999",
+		Email:       email,
+		Role:        role,
+		Password:    "hashed_password",
+	})
+	require.NoError(t, err)
+	return worker
+}
+
+func newCategory(t *testing.T, repositories Repositories, name string) *models.Category {
+	category, err := repositories.Categories.Create(&models.Category{Name: name})
+	require.NoError(t, err)
+	return category
+}
+
+func newTask(t *testing.T, repositories Repositories, name string, category int) *models.Task {
+	task, err := repositories.Tasks.Create(&models.Task{
+		Name:           name,
+		PricePerSingle: 100,
+		Category:       category,
+	})
+	require.NoError(t, err)
+	return task
+}
+
+func newOrder(t *testing.T, repositories Repositories, user *models.User, orderedTasks ...models.OrderedTask) *models.Order {
+	order, err := repositories.Orders.Create(&models.Order{
+		UserID:   user.ID,
+		Status:   models.NewOrderStatus,
+		Address:  "Test Address",
+		Deadline: time.Now().Add(24 * time.Hour),
+	}, orderedTasks)
+	require.NoError(t, err)
+	return order
+}
@@ -0,0 +1,100 @@
+package conformance
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"testing"
+	"time"
+)
+
+func newInvoice(orderID uuid.UUID, issuedAt time.Time) *models.Invoice {
+	return &models.Invoice{
+		OrderID:  orderID,
+		UserID:   uuid.New(),
+		IssuedAt: issuedAt,
+		Seller:   models.Requisites{Company: "ООО Чистота", INN: "7700000000", Account: "40702810000000000001"},
+		Buyer:    "First Name Last Name",
+		Address:  "Test Address",
+		Lines: []models.InvoiceLine{
+			{Name: "Мытье окон", Price: 100, Quantity: 2, Amount: 200, TaxName: "НДС 20%", Tax: 33.33},
+			{Name: "Уборка", Price: 50.5, Quantity: 1, Amount: 50.5},
+		},
+		Total: 250.5,
+	}
+}
+
+func runInvoiceTests(t *testing.T, factory Factory) {
+	t.Run("Create", func(t *testing.T) {
+		repositories := factory(t)
+		at := now()
+
+		first, err := repositories.Invoices.Create(newInvoice(uuid.New(), at))
+		require.NoError(t, err)
+		require.NotEqual(t, uuid.Nil, first.ID)
+		require.Equal(t, 1, first.Number)
+
+		second, err := repositories.Invoices.Create(newInvoice(uuid.New(), at))
+		require.NoError(t, err)
+		require.Equal(t, 2, second.Number)
+
+		stored, err := repositories.Invoices.GetByID(first.ID)
+		require.NoError(t, err)
+		require.Equal(t, 1, stored.Number)
+		require.Equal(t, first.OrderID, stored.OrderID)
+		require.Equal(t, first.UserID, stored.UserID)
+		require.Equal(t, "ООО Чистота", stored.Seller.Company)
+		require.Equal(t, "40702810000000000001", stored.Seller.Account)
+		require.Equal(t, "First Name Last Name", stored.Buyer)
+		require.Equal(t, "Test Address", stored.Address)
+		require.Equal(t, first.Lines, stored.Lines)
+		require.Equal(t, 250.5, stored.Total)
+		require.WithinDuration(t, at, stored.IssuedAt, time.Second)
+	})
+
+	t.Run("OneInvoicePerOrder", func(t *testing.T) {
+		repositories := factory(t)
+		orderID := uuid.New()
+
+		_, err := repositories.Invoices.Create(newInvoice(orderID, now()))
+		require.NoError(t, err)
+
+		_, err = repositories.Invoices.Create(newInvoice(orderID, now()))
+		require.ErrorIs(t, err, repository_errors.AlreadyExists)
+
+		// the rejected invoice takes no number
+		next, err := repositories.Invoices.Create(newInvoice(uuid.New(), now()))
+		require.NoError(t, err)
+		require.Equal(t, 2, next.Number)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		repositories := factory(t)
+
+		all, err := repositories.Invoices.GetAll()
+		require.NoError(t, err)
+		require.Empty(t, all)
+
+		first, err := repositories.Invoices.Create(newInvoice(uuid.New(), now()))
+		require.NoError(t, err)
+		second, err := repositories.Invoices.Create(newInvoice(uuid.New(), now()))
+		require.NoError(t, err)
+
+		found, err := repositories.Invoices.GetByOrderID(second.OrderID)
+		require.NoError(t, err)
+		require.Equal(t, second.ID, found.ID)
+
+		_, err = repositories.Invoices.GetByOrderID(uuid.New())
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		_, err = repositories.Invoices.GetByID(uuid.New())
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+		// the latest first
+		all, err = repositories.Invoices.GetAll()
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		require.Equal(t, second.ID, all[0].ID)
+		require.Equal(t, first.ID, all[1].ID)
+	})
+}
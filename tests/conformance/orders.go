@@ -0,0 +1,315 @@
+package conformance
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"testing"
+	"time"
+)
+
+func runOrderTests(t *testing.T, factory Factory) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+		category := newCategory(t, repositories, "Category")
+		task := newTask(t, repositories, "Task", category.ID)
+		order := newOrder(t, repositories, user, models.OrderedTask{Task: task, Quantity: 3})
+		require.NotEqual(t, uuid.Nil, order.ID)
+		require.Equal(t, 1, order.Version)
+
+		got, err := repositories.Orders.GetOrderByID(order.ID)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, got.UserID)
+		require.Equal(t, uuid.Nil, got.WorkerID)
+		require.Equal(t, models.NewOrderStatus, got.Status)
+		require.Equal(t, "Test Address", got.Address)
+		require.WithinDuration(t, order.Deadline, got.Deadline, time.Second)
+		require.Equal(t, 1, got.Version)
+
+		tasks, err := repositories.Orders.GetTasksInOrder(order.ID)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		require.Equal(t, task.ID, tasks[0].ID)
+
+		quantity, err := repositories.Orders.GetTaskQuantity(order.ID, task.ID)
+		require.NoError(t, err)
+		require.Equal(t, 3, quantity)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+
+		_, err := repositories.Orders.GetOrderByID(uuid.New())
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		_, err = repositories.Orders.GetCurrentOrderByUserID(user.ID)
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		_, err = repositories.Orders.GetOrderByIdempotencyKey(user.ID, "missing")
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		_, err = repositories.Orders.GetTaskQuantity(uuid.New(), uuid.New())
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		_, err = repositories.Orders.Update(&models.Order{ID: uuid.New(), UserID: user.ID, Version: 1})
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		require.ErrorIs(t, repositories.Orders.Delete(uuid.New()), repository_errors.DoesNotExist)
+
+		orders, err := repositories.Orders.GetAllOrdersByUserID(user.ID)
+		require.NoError(t, err)
+		require.Empty(t, orders)
+	})
+
+	t.Run("UnknownClient", func(t *testing.T) {
+		repositories := factory(t)
+
+		_, err := repositories.Orders.Create(&models.Order{
+			UserID:   uuid.New(),
+			Status:   models.NewOrderStatus,
+			Address:  "Test Address",
+			Deadline: time.Now().Add(24 * time.Hour),
+		}, nil)
+		require.ErrorIs(t, err, repository_errors.InsertError)
+	})
+
+	t.Run("IdempotencyKey", func(t *testing.T) {
+		repositories := factory(t)
+		first := newUser(t, repositories, "first@test.com")
+		second := newUser(t, repositories, "second@test.com")
+
+		create := func(user *models.User) (*models.Order, error) {
+			return repositories.Orders.Create(&models.Order{
+				UserID:         user.ID,
+				Status:         models.NewOrderStatus,
+				Address:        "Test Address",
+				Deadline:       time.Now().Add(24 * time.Hour),
+				IdempotencyKey: "key",
+			}, nil)
+		}
+
+		order, err := create(first)
+		require.NoError(t, err)
+		_, err = create(first)
+		require.ErrorIs(t, err, repository_errors.AlreadyExists)
+		_, err = create(second)
+		require.NoError(t, err)
+
+		byKey, err := repositories.Orders.GetOrderByIdempotencyKey(first.ID, "key")
+		require.NoError(t, err)
+		require.Equal(t, order.ID, byKey.ID)
+
+		// orders without a key never conflict
+		newOrder(t, repositories, first)
+		newOrder(t, repositories, first)
+
+		orders, err := repositories.Orders.GetAllOrdersByUserID(first.ID)
+		require.NoError(t, err)
+		require.Len(t, orders, 3)
+	})
+
+	t.Run("GetCurrentOrderByUserID", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+		other := newUser(t, repositories, "other@test.com")
+
+		newOrder(t, repositories, user)
+		tick()
+		latest := newOrder(t, repositories, user)
+		tick()
+		newOrder(t, repositories, other)
+
+		current, err := repositories.Orders.GetCurrentOrderByUserID(user.ID)
+		require.NoError(t, err)
+		require.Equal(t, latest.ID, current.ID)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+		worker := newWorker(t, repositories, "worker@test.com", models.MasterRole)
+		order, err := repositories.Orders.GetOrderByID(newOrder(t, repositories, user).ID)
+		require.NoError(t, err)
+
+		order.WorkerID = worker.ID
+		order.Status = models.InProgressOrderStatus
+		updated, err := repositories.Orders.Update(order)
+		require.NoError(t, err)
+		require.Equal(t, 2, updated.Version)
+
+		got, err := repositories.Orders.GetOrderByID(order.ID)
+		require.NoError(t, err)
+		require.Equal(t, worker.ID, got.WorkerID)
+		require.Equal(t, models.InProgressOrderStatus, got.Status)
+		require.Equal(t, 2, got.Version)
+
+		// order still holds the version read before the update
+		order.Status = models.CancelledOrderStatus
+		_, err = repositories.Orders.Update(order)
+		require.ErrorIs(t, err, repository_errors.VersionConflict)
+
+		got, err = repositories.Orders.GetOrderByID(order.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.InProgressOrderStatus, got.Status)
+	})
+
+	t.Run("DeleteRemovesTasks", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+		category := newCategory(t, repositories, "Category")
+		task := newTask(t, repositories, "Task", category.ID)
+		order := newOrder(t, repositories, user, models.OrderedTask{Task: task, Quantity: 2})
+
+		require.NoError(t, repositories.Orders.Delete(order.ID))
+
+		_, err := repositories.Orders.GetOrderByID(order.ID)
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		_, err = repositories.Orders.GetTaskQuantity(order.ID, task.ID)
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+		// the task itself is kept
+		_, err = repositories.Tasks.GetTaskByID(task.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("Tasks", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+		category := newCategory(t, repositories, "Category")
+		first := newTask(t, repositories, "First", category.ID)
+		second := newTask(t, repositories, "Second", category.ID)
+		order := newOrder(t, repositories, user)
+
+		tasks, err := repositories.Orders.GetTasksInOrder(order.ID)
+		require.NoError(t, err)
+		require.Empty(t, tasks)
+
+		require.NoError(t, repositories.Orders.AddTaskToOrder(order.ID, first.ID))
+		require.NoError(t, repositories.Orders.AddTaskToOrder(order.ID, second.ID))
+		require.ErrorIs(t, repositories.Orders.AddTaskToOrder(uuid.New(), first.ID), repository_errors.InsertError)
+		require.ErrorIs(t, repositories.Orders.AddTaskToOrder(order.ID, uuid.New()), repository_errors.InsertError)
+
+		quantity, err := repositories.Orders.GetTaskQuantity(order.ID, first.ID)
+		require.NoError(t, err)
+		require.Equal(t, 1, quantity)
+
+		require.NoError(t, repositories.Orders.UpdateTaskQuantity(order.ID, first.ID, 5))
+		quantity, err = repositories.Orders.GetTaskQuantity(order.ID, first.ID)
+		require.NoError(t, err)
+		require.Equal(t, 5, quantity)
+
+		require.NoError(t, repositories.Orders.RemoveTaskFromOrder(order.ID, second.ID))
+		tasks, err = repositories.Orders.GetTasksInOrder(order.ID)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		require.Equal(t, first.ID, tasks[0].ID)
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+		worker := newWorker(t, repositories, "worker@test.com", models.MasterRole)
+
+		unassigned := newOrder(t, repositories, user)
+		assigned, err := repositories.Orders.GetOrderByID(newOrder(t, repositories, user).ID)
+		require.NoError(t, err)
+		assigned.WorkerID = worker.ID
+		assigned.Status = models.InProgressOrderStatus
+		_, err = repositories.Orders.Update(assigned)
+		require.NoError(t, err)
+		completed, err := repositories.Orders.GetOrderByID(newOrder(t, repositories, user).ID)
+		require.NoError(t, err)
+		completed.WorkerID = worker.ID
+		completed.Status = models.CompletedOrderStatus
+		_, err = repositories.Orders.Update(completed)
+		require.NoError(t, err)
+
+		ids := func(params map[string]string) []uuid.UUID {
+			orders, err := repositories.Orders.Filter(params)
+			require.NoError(t, err)
+
+			var result []uuid.UUID
+			for _, order := range orders {
+				result = append(result, order.ID)
+			}
+			return result
+		}
+
+		require.ElementsMatch(t, []uuid.UUID{unassigned.ID}, ids(map[string]string{"worker_id": "null"}))
+		require.ElementsMatch(t, []uuid.UUID{assigned.ID, completed.ID}, ids(map[string]string{"worker_id": "not null"}))
+		require.ElementsMatch(t, []uuid.UUID{assigned.ID}, ids(map[string]string{"status": "2"}))
+		require.ElementsMatch(t, []uuid.UUID{unassigned.ID, completed.ID}, ids(map[string]string{"status": "1,3"}))
+		require.ElementsMatch(t, []uuid.UUID{completed.ID}, ids(map[string]string{"worker_id": worker.ID.String(), "status": "3"}))
+		require.ElementsMatch(t, []uuid.UUID{unassigned.ID, assigned.ID, completed.ID}, ids(map[string]string{"user_id": user.ID.String()}))
+		require.Empty(t, ids(map[string]string{"user_id": uuid.New().String()}))
+	})
+
+	t.Run("Archive", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+		worker := newWorker(t, repositories, "worker@test.com", models.MasterRole)
+		category := newCategory(t, repositories, "Category")
+		task := newTask(t, repositories, "Task", category.ID)
+
+		closeOrder := func(closedAt time.Time, rate int) *models.Order {
+			order, err := repositories.Orders.GetOrderByID(newOrder(t, repositories, user, models.OrderedTask{Task: task, Quantity: 2}).ID)
+			require.NoError(t, err)
+			order.WorkerID = worker.ID
+			order.Status = models.CompletedOrderStatus
+			order.Rate = rate
+			order.ClosedAt = closedAt
+			order, err = repositories.Orders.Update(order)
+			require.NoError(t, err)
+			return order
+		}
+
+		old := closeOrder(now().AddDate(0, -2, 0), 5)
+		recent := closeOrder(now(), 3)
+		open := newOrder(t, repositories, user)
+
+		count, err := repositories.Orders.Archive(now().AddDate(0, -1, 0))
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+
+		count, err = repositories.Orders.Archive(now().AddDate(0, -1, 0))
+		require.NoError(t, err)
+		require.Zero(t, count)
+
+		filtered, err := repositories.Orders.Filter(map[string]string{"user_id": user.ID.String()})
+		require.NoError(t, err)
+		require.Len(t, filtered, 2)
+
+		history, err := repositories.Orders.History(map[string]string{"status": "3,4", "user_id": user.ID.String()})
+		require.NoError(t, err)
+		archived := map[uuid.UUID]bool{}
+		for _, order := range history {
+			archived[order.ID] = order.Archived
+		}
+		require.Equal(t, map[uuid.UUID]bool{old.ID: true, recent.ID: false}, archived)
+
+		got, err := repositories.Orders.GetOrderByID(old.ID)
+		require.NoError(t, err)
+		require.True(t, got.Archived)
+		require.Equal(t, models.CompletedOrderStatus, got.Status)
+		require.WithinDuration(t, old.ClosedAt, got.ClosedAt, time.Millisecond)
+
+		got, err = repositories.Orders.GetOrderByID(open.ID)
+		require.NoError(t, err)
+		require.False(t, got.Archived)
+
+		tasks, err := repositories.Orders.GetTasksInOrder(old.ID)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		quantity, err := repositories.Orders.GetTaskQuantity(old.ID, task.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, quantity)
+
+		// the archive is read-only
+		archivedOrder, err := repositories.Orders.GetOrderByID(old.ID)
+		require.NoError(t, err)
+		_, err = repositories.Orders.Update(archivedOrder)
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+		averageRate, err := repositories.Workers.GetAverageOrderRate(worker)
+		require.NoError(t, err)
+		require.Equal(t, 4.0, averageRate)
+	})
+}
@@ -0,0 +1,222 @@
+package conformance
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"testing"
+	"time"
+)
+
+func runLoginAttemptTests(t *testing.T, factory Factory) {
+	t.Run("RegisterFailure", func(t *testing.T) {
+		repositories := factory(t)
+		at := now()
+
+		_, err := repositories.LoginAttempts.GetByKey("user:user@test.com")
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+		attempt, err := repositories.LoginAttempts.RegisterFailure("user:user@test.com", at, at.Add(-time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, 1, attempt.Failures)
+
+		attempt, err = repositories.LoginAttempts.RegisterFailure("user:user@test.com", at.Add(time.Minute), at.Add(-time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, 2, attempt.Failures)
+
+		// the previous failure is older than resetBefore, so the counter starts again
+		attempt, err = repositories.LoginAttempts.RegisterFailure("user:user@test.com", at.Add(3*time.Hour), at.Add(2*time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, 1, attempt.Failures)
+
+		stored, err := repositories.LoginAttempts.GetByKey("user:user@test.com")
+		require.NoError(t, err)
+		require.Equal(t, 1, stored.Failures)
+		require.WithinDuration(t, at.Add(3*time.Hour), stored.LastFailure, time.Second)
+
+		require.NoError(t, repositories.LoginAttempts.Delete("user:user@test.com"))
+		_, err = repositories.LoginAttempts.GetByKey("user:user@test.com")
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+	})
+
+	t.Run("RegisterFailureAfterLockout", func(t *testing.T) {
+		repositories := factory(t)
+		at := now()
+
+		for i := 0; i < 3; i++ {
+			_, err := repositories.LoginAttempts.RegisterFailure("user:user@test.com", at, at.Add(-time.Hour))
+			require.NoError(t, err)
+		}
+		require.NoError(t, repositories.LoginAttempts.Lock("user:user@test.com", at.Add(time.Minute)))
+
+		// the failures that led to the ended lockout are not counted again
+		attempt, err := repositories.LoginAttempts.RegisterFailure("user:user@test.com", at.Add(2*time.Minute), at.Add(-time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, 1, attempt.Failures)
+		require.True(t, attempt.LockedUntil.IsZero())
+
+		locked, err := repositories.LoginAttempts.GetLocked(at)
+		require.NoError(t, err)
+		require.Empty(t, locked)
+	})
+
+	t.Run("Lock", func(t *testing.T) {
+		repositories := factory(t)
+		at := now()
+
+		require.ErrorIs(t, repositories.LoginAttempts.Lock("ip:127.0.0.1", at.Add(time.Hour)), repository_errors.DoesNotExist)
+
+		for _, key := range []string{"ip:127.0.0.1", "ip:127.0.0.2", "ip:127.0.0.3", "ip:127.0.0.4"} {
+			_, err := repositories.LoginAttempts.RegisterFailure(key, at, at.Add(-time.Hour))
+			require.NoError(t, err)
+		}
+		require.NoError(t, repositories.LoginAttempts.Lock("ip:127.0.0.1", at.Add(time.Hour)))
+		require.NoError(t, repositories.LoginAttempts.Lock("ip:127.0.0.2", at.Add(2*time.Hour)))
+		require.NoError(t, repositories.LoginAttempts.Lock("ip:127.0.0.3", at.Add(-time.Hour)))
+
+		locked, err := repositories.LoginAttempts.GetLocked(at)
+		require.NoError(t, err)
+		require.Len(t, locked, 2)
+		require.Equal(t, "ip:127.0.0.2", locked[0].Key)
+		require.Equal(t, "ip:127.0.0.1", locked[1].Key)
+		require.WithinDuration(t, at.Add(2*time.Hour), locked[0].LockedUntil, time.Second)
+	})
+}
+
+func runOneTimeTokenTests(t *testing.T, factory Factory) {
+	t.Run("Consume", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+		at := now()
+
+		token := &models.OneTimeToken{Hash: "hash", Purpose: models.UserPasswordResetPurpose, UserID: user.ID, ExpiresAt: at.Add(time.Hour), CreatedAt: at}
+		require.NoError(t, repositories.OneTimeTokens.Create(token))
+		require.ErrorIs(t, repositories.OneTimeTokens.Create(token), repository_errors.InsertError)
+
+		_, err := repositories.OneTimeTokens.Consume("hash", models.EmailVerificationPurpose, at)
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+		consumed, err := repositories.OneTimeTokens.Consume("hash", models.UserPasswordResetPurpose, at)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, consumed.UserID)
+		require.Equal(t, uuid.Nil, consumed.WorkerID)
+
+		// a token is single use
+		_, err = repositories.OneTimeTokens.Consume("hash", models.UserPasswordResetPurpose, at)
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+		at := now()
+
+		token := &models.OneTimeToken{Hash: "hash", Purpose: models.EmailVerificationPurpose, UserID: user.ID, ExpiresAt: at, CreatedAt: at.Add(-time.Hour)}
+		require.NoError(t, repositories.OneTimeTokens.Create(token))
+
+		_, err := repositories.OneTimeTokens.Consume("hash", models.EmailVerificationPurpose, at)
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+	})
+
+	t.Run("DeleteByOwner", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+		other := newUser(t, repositories, "other@test.com")
+		worker := newWorker(t, repositories, "worker@test.com", models.MasterRole)
+		at := now()
+
+		for _, token := range []models.OneTimeToken{
+			{Hash: "user-reset", Purpose: models.UserPasswordResetPurpose, UserID: user.ID},
+			{Hash: "user-verification", Purpose: models.EmailVerificationPurpose, UserID: user.ID},
+			{Hash: "other-reset", Purpose: models.UserPasswordResetPurpose, UserID: other.ID},
+			{Hash: "worker-reset", Purpose: models.WorkerPasswordResetPurpose, WorkerID: worker.ID},
+		} {
+			token.ExpiresAt = at.Add(time.Hour)
+			token.CreatedAt = at
+			require.NoError(t, repositories.OneTimeTokens.Create(&token))
+		}
+
+		require.NoError(t, repositories.OneTimeTokens.DeleteByUserID(models.UserPasswordResetPurpose, user.ID))
+		require.NoError(t, repositories.OneTimeTokens.DeleteByWorkerID(models.WorkerPasswordResetPurpose, worker.ID))
+
+		_, err := repositories.OneTimeTokens.Consume("user-reset", models.UserPasswordResetPurpose, at)
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		_, err = repositories.OneTimeTokens.Consume("worker-reset", models.WorkerPasswordResetPurpose, at)
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		_, err = repositories.OneTimeTokens.Consume("user-verification", models.EmailVerificationPurpose, at)
+		require.NoError(t, err)
+		_, err = repositories.OneTimeTokens.Consume("other-reset", models.UserPasswordResetPurpose, at)
+		require.NoError(t, err)
+	})
+}
+
+func runTwoFactorTests(t *testing.T, factory Factory) {
+	t.Run("SaveAndGet", func(t *testing.T) {
+		repositories := factory(t)
+		worker := newWorker(t, repositories, "worker@test.com", models.ManagerRole)
+
+		_, err := repositories.TwoFactor.GetByWorkerID(worker.ID)
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+		require.NoError(t, repositories.TwoFactor.Save(&models.TwoFactor{WorkerID: worker.ID, Secret: "first", CreatedAt: now()}))
+		require.NoError(t, repositories.TwoFactor.Save(&models.TwoFactor{WorkerID: worker.ID, Secret: "second", Enabled: true, CreatedAt: now()}))
+
+		twoFactor, err := repositories.TwoFactor.GetByWorkerID(worker.ID)
+		require.NoError(t, err)
+		require.Equal(t, "second", twoFactor.Secret)
+		require.True(t, twoFactor.Enabled)
+	})
+
+	t.Run("MarkStepUsed", func(t *testing.T) {
+		repositories := factory(t)
+		worker := newWorker(t, repositories, "worker@test.com", models.ManagerRole)
+		require.NoError(t, repositories.TwoFactor.Save(&models.TwoFactor{WorkerID: worker.ID, Secret: "secret", Enabled: true, CreatedAt: now()}))
+
+		require.NoError(t, repositories.TwoFactor.MarkStepUsed(worker.ID, 10))
+		require.ErrorIs(t, repositories.TwoFactor.MarkStepUsed(worker.ID, 10), repository_errors.DoesNotExist)
+		require.ErrorIs(t, repositories.TwoFactor.MarkStepUsed(worker.ID, 9), repository_errors.DoesNotExist)
+		require.NoError(t, repositories.TwoFactor.MarkStepUsed(worker.ID, 11))
+		require.ErrorIs(t, repositories.TwoFactor.MarkStepUsed(uuid.New(), 12), repository_errors.DoesNotExist)
+
+		twoFactor, err := repositories.TwoFactor.GetByWorkerID(worker.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(11), twoFactor.LastUsedStep)
+	})
+
+	t.Run("RecoveryCodes", func(t *testing.T) {
+		repositories := factory(t)
+		worker := newWorker(t, repositories, "worker@test.com", models.ManagerRole)
+		require.NoError(t, repositories.TwoFactor.Save(&models.TwoFactor{WorkerID: worker.ID, Secret: "secret", Enabled: true, CreatedAt: now()}))
+
+		require.NoError(t, repositories.TwoFactor.ReplaceRecoveryCodes(worker.ID, []string{"a", "b", "c"}))
+		require.NoError(t, repositories.TwoFactor.ReplaceRecoveryCodes(worker.ID, []string{"d", "e"}))
+
+		count, err := repositories.TwoFactor.CountRecoveryCodes(worker.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+
+		require.ErrorIs(t, repositories.TwoFactor.ConsumeRecoveryCode(worker.ID, "a"), repository_errors.DoesNotExist)
+		require.NoError(t, repositories.TwoFactor.ConsumeRecoveryCode(worker.ID, "d"))
+		require.ErrorIs(t, repositories.TwoFactor.ConsumeRecoveryCode(worker.ID, "d"), repository_errors.DoesNotExist)
+
+		count, err = repositories.TwoFactor.CountRecoveryCodes(worker.ID)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	})
+
+	t.Run("DeleteRemovesRecoveryCodes", func(t *testing.T) {
+		repositories := factory(t)
+		worker := newWorker(t, repositories, "worker@test.com", models.ManagerRole)
+		require.NoError(t, repositories.TwoFactor.Save(&models.TwoFactor{WorkerID: worker.ID, Secret: "secret", Enabled: true, CreatedAt: now()}))
+		require.NoError(t, repositories.TwoFactor.ReplaceRecoveryCodes(worker.ID, []string{"a", "b"}))
+
+		require.NoError(t, repositories.TwoFactor.Delete(worker.ID))
+
+		_, err := repositories.TwoFactor.GetByWorkerID(worker.ID)
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		count, err := repositories.TwoFactor.CountRecoveryCodes(worker.ID)
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+}
@@ -0,0 +1,111 @@
+package conformance
+
+import (
+	"github.com/stretchr/testify/require"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"testing"
+	"time"
+)
+
+func runSessionTests(t *testing.T, factory Factory) {
+	t.Run("SaveAndGet", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+		createdAt := now().Add(-time.Hour)
+
+		err := repositories.Sessions.Save(&models.Session{ID: "session", UserID: user.ID, Data: "first", CreatedAt: createdAt, LastActivity: createdAt})
+		require.NoError(t, err)
+
+		// saving again replaces the data and keeps the creation time
+		lastActivity := now()
+		err = repositories.Sessions.Save(&models.Session{ID: "session", UserID: user.ID, Data: "second", CreatedAt: lastActivity, LastActivity: lastActivity})
+		require.NoError(t, err)
+
+		session, err := repositories.Sessions.GetSessionByID("session")
+		require.NoError(t, err)
+		require.Equal(t, user.ID, session.UserID)
+		require.Equal(t, "second", session.Data)
+		require.WithinDuration(t, createdAt, session.CreatedAt, time.Second)
+		require.WithinDuration(t, lastActivity, session.LastActivity, time.Second)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repositories := factory(t)
+
+		_, err := repositories.Sessions.GetSessionByID("missing")
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		require.ErrorIs(t, repositories.Sessions.UpdateLastActivity("missing", now()), repository_errors.DoesNotExist)
+		require.ErrorIs(t, repositories.Sessions.Save(&models.Session{}), repository_errors.InsertError)
+	})
+
+	t.Run("UpdateLastActivity", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+		createdAt := now().Add(-time.Hour)
+		require.NoError(t, repositories.Sessions.Save(&models.Session{ID: "session", UserID: user.ID, CreatedAt: createdAt, LastActivity: createdAt}))
+
+		lastActivity := now()
+		require.NoError(t, repositories.Sessions.UpdateLastActivity("session", lastActivity))
+
+		session, err := repositories.Sessions.GetSessionByID("session")
+		require.NoError(t, err)
+		require.WithinDuration(t, lastActivity, session.LastActivity, time.Second)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+		other := newUser(t, repositories, "other@test.com")
+		worker := newWorker(t, repositories, "worker@test.com", models.MasterRole)
+		at := now()
+
+		for _, session := range []models.Session{
+			{ID: "user-1", UserID: user.ID},
+			{ID: "user-2", UserID: user.ID},
+			{ID: "other", UserID: other.ID},
+			{ID: "worker", WorkerID: worker.ID},
+			{ID: "single", UserID: other.ID},
+		} {
+			session.CreatedAt = at
+			session.LastActivity = at
+			require.NoError(t, repositories.Sessions.Save(&session))
+		}
+
+		require.NoError(t, repositories.Sessions.Delete("single"))
+		require.NoError(t, repositories.Sessions.Delete("missing"))
+		require.NoError(t, repositories.Sessions.DeleteByUserID(user.ID))
+		require.NoError(t, repositories.Sessions.DeleteByWorkerID(worker.ID))
+
+		for _, id := range []string{"user-1", "user-2", "worker", "single"} {
+			_, err := repositories.Sessions.GetSessionByID(id)
+			require.ErrorIs(t, err, repository_errors.DoesNotExist, id)
+		}
+		_, err := repositories.Sessions.GetSessionByID("other")
+		require.NoError(t, err)
+	})
+
+	t.Run("DeleteExpired", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+		at := now()
+
+		for _, session := range []models.Session{
+			{ID: "active", CreatedAt: at.Add(-time.Hour), LastActivity: at},
+			{ID: "idle", CreatedAt: at.Add(-time.Hour), LastActivity: at.Add(-40 * time.Minute)},
+			{ID: "old", CreatedAt: at.Add(-48 * time.Hour), LastActivity: at},
+		} {
+			session.UserID = user.ID
+			require.NoError(t, repositories.Sessions.Save(&session))
+		}
+
+		require.NoError(t, repositories.Sessions.DeleteExpired(at.Add(-30*time.Minute), at.Add(-24*time.Hour)))
+
+		_, err := repositories.Sessions.GetSessionByID("active")
+		require.NoError(t, err)
+		_, err = repositories.Sessions.GetSessionByID("idle")
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		_, err = repositories.Sessions.GetSessionByID("old")
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+	})
+}
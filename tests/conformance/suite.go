@@ -0,0 +1,52 @@
+// Package conformance checks that a storage backend implements the documented semantics of
+// repository_interfaces. A backend proves its correctness by running Run with a factory of its repositories.
+package conformance
+
+import (
+	"lab3/internal/repository/repository_interfaces"
+	"testing"
+	"time"
+)
+
+// Repositories are the repositories of one backend sharing one empty database.
+type Repositories struct {
+	Users         repository_interfaces.IUserRepository
+	Workers       repository_interfaces.IWorkerRepository
+	Tasks         repository_interfaces.ITaskRepository
+	Categories    repository_interfaces.ICategoryRepository
+	Orders        repository_interfaces.IOrderRepository
+	Sessions      repository_interfaces.ISessionRepository
+	LoginAttempts repository_interfaces.ILoginAttemptRepository
+	OneTimeTokens repository_interfaces.IOneTimeTokenRepository
+	TwoFactor     repository_interfaces.ITwoFactorRepository
+	Audit         repository_interfaces.IAuditRepository
+	Invoices      repository_interfaces.IInvoiceRepository
+}
+
+// Factory returns repositories over an empty database. It is called once for every test of the suite.
+type Factory func(t *testing.T) Repositories
+
+// Run runs the whole suite against the repositories made by factory.
+func Run(t *testing.T, factory Factory) {
+	t.Run("Users", func(t *testing.T) { runUserTests(t, factory) })
+	t.Run("Workers", func(t *testing.T) { runWorkerTests(t, factory) })
+	t.Run("Tasks", func(t *testing.T) { runTaskTests(t, factory) })
+	t.Run("Categories", func(t *testing.T) { runCategoryTests(t, factory) })
+	t.Run("Orders", func(t *testing.T) { runOrderTests(t, factory) })
+	t.Run("Sessions", func(t *testing.T) { runSessionTests(t, factory) })
+	t.Run("LoginAttempts", func(t *testing.T) { runLoginAttemptTests(t, factory) })
+	t.Run("OneTimeTokens", func(t *testing.T) { runOneTimeTokenTests(t, factory) })
+	t.Run("TwoFactor", func(t *testing.T) { runTwoFactorTests(t, factory) })
+	t.Run("Audit", func(t *testing.T) { runAuditTests(t, factory) })
+	t.Run("Invoices", func(t *testing.T) { runInvoiceTests(t, factory) })
+}
+
+// now is rounded to milliseconds, the precision every backend keeps.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// tick separates timestamps written one after another.
+func tick() {
+	time.Sleep(20 * time.Millisecond)
+}
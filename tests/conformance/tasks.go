@@ -0,0 +1,304 @@
+package conformance
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"testing"
+	"time"
+)
+
+func runTaskTests(t *testing.T, factory Factory) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repositories := factory(t)
+		category := newCategory(t, repositories, "Category")
+		task := newTask(t, repositories, "Task", category.ID)
+		require.NotEqual(t, uuid.Nil, task.ID)
+
+		byID, err := repositories.Tasks.GetTaskByID(task.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Task", byID.Name)
+		require.Equal(t, 100.0, byID.PricePerSingle)
+		require.Equal(t, category.ID, byID.Category)
+
+		byName, err := repositories.Tasks.GetTaskByName("Task")
+		require.NoError(t, err)
+		require.Equal(t, task.ID, byName.ID)
+	})
+
+	t.Run("MissingCategory", func(t *testing.T) {
+		repositories := factory(t)
+		category := newCategory(t, repositories, "Category")
+		task := newTask(t, repositories, "Task", category.ID)
+
+		_, err := repositories.Tasks.Create(&models.Task{Name: "Other", PricePerSingle: 100, Category: category.ID + 1})
+		require.ErrorIs(t, err, repository_errors.InsertError)
+
+		task.Category = category.ID + 1
+		_, err = repositories.Tasks.Update(task)
+		require.ErrorIs(t, err, repository_errors.UpdateError)
+
+		_, err = repositories.Tasks.MoveToCategory(category.ID, category.ID+1)
+		require.ErrorIs(t, err, repository_errors.UpdateError)
+
+		unchanged, err := repositories.Tasks.GetTaskByID(task.ID)
+		require.NoError(t, err)
+		require.Equal(t, category.ID, unchanged.Category)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repositories := factory(t)
+
+		_, err := repositories.Tasks.GetTaskByID(uuid.New())
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		_, err = repositories.Tasks.GetTaskByName("Missing")
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		_, err = repositories.Tasks.Update(&models.Task{ID: uuid.New(), Name: "Missing", PricePerSingle: 100, Category: 1})
+		require.ErrorIs(t, err, repository_errors.UpdateError)
+		require.ErrorIs(t, repositories.Tasks.Delete(uuid.New()), repository_errors.DoesNotExist)
+		require.ErrorIs(t, repositories.Tasks.Restore(uuid.New()), repository_errors.DoesNotExist)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repositories := factory(t)
+		first := newCategory(t, repositories, "First")
+		second := newCategory(t, repositories, "Second")
+		task := newTask(t, repositories, "Task", first.ID)
+
+		task.PricePerSingle = 250
+		task.Category = second.ID
+		_, err := repositories.Tasks.Update(task)
+		require.NoError(t, err)
+
+		updated, err := repositories.Tasks.GetTaskByID(task.ID)
+		require.NoError(t, err)
+		require.Equal(t, 250.0, updated.PricePerSingle)
+		require.Equal(t, second.ID, updated.Category)
+	})
+
+	t.Run("TaxRate", func(t *testing.T) {
+		repositories := factory(t)
+		category := newCategory(t, repositories, "Category")
+
+		task, err := repositories.Tasks.Create(&models.Task{Name: "Task", PricePerSingle: 100, Category: category.ID, TaxRate: "vat10"})
+		require.NoError(t, err)
+		require.Equal(t, "vat10", task.TaxRate)
+
+		stored, err := repositories.Tasks.GetTaskByID(task.ID)
+		require.NoError(t, err)
+		require.Equal(t, "vat10", stored.TaxRate)
+
+		all, err := repositories.Tasks.GetAllTasks()
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Equal(t, "vat10", all[0].TaxRate)
+
+		// an empty rate takes the rate of the category
+		task.TaxRate = ""
+		updated, err := repositories.Tasks.Update(task)
+		require.NoError(t, err)
+		require.Empty(t, updated.TaxRate)
+
+		stored, err = repositories.Tasks.GetTaskByID(task.ID)
+		require.NoError(t, err)
+		require.Empty(t, stored.TaxRate)
+	})
+
+	t.Run("GetTasksInCategory", func(t *testing.T) {
+		repositories := factory(t)
+		firstCategory := newCategory(t, repositories, "First")
+		secondCategory := newCategory(t, repositories, "Second")
+		emptyCategory := newCategory(t, repositories, "Empty")
+		first := newTask(t, repositories, "First", firstCategory.ID)
+		newTask(t, repositories, "Second", secondCategory.ID)
+
+		tasks, err := repositories.Tasks.GetTasksInCategory(firstCategory.ID)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		require.Equal(t, first.ID, tasks[0].ID)
+
+		tasks, err = repositories.Tasks.GetTasksInCategory(emptyCategory.ID)
+		require.NoError(t, err)
+		require.Empty(t, tasks)
+	})
+
+	t.Run("MoveToCategory", func(t *testing.T) {
+		repositories := factory(t)
+		from := newCategory(t, repositories, "From")
+		to := newCategory(t, repositories, "To")
+		other := newCategory(t, repositories, "Other")
+		first := newTask(t, repositories, "First", from.ID)
+		archived := newTask(t, repositories, "Archived", from.ID)
+		untouched := newTask(t, repositories, "Untouched", other.ID)
+		require.NoError(t, repositories.Tasks.Delete(archived.ID))
+
+		moved, err := repositories.Tasks.MoveToCategory(from.ID, to.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, moved)
+
+		tasks, err := repositories.Tasks.GetTasksInCategory(from.ID)
+		require.NoError(t, err)
+		require.Empty(t, tasks)
+
+		tasks, err = repositories.Tasks.GetTasksInCategory(to.ID)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		require.Equal(t, first.ID, tasks[0].ID)
+
+		// archived tasks move too, so that a restored task is listed in the new category
+		byID, err := repositories.Tasks.GetTaskByID(archived.ID)
+		require.NoError(t, err)
+		require.Equal(t, to.ID, byID.Category)
+
+		byID, err = repositories.Tasks.GetTaskByID(untouched.ID)
+		require.NoError(t, err)
+		require.Equal(t, other.ID, byID.Category)
+
+		moved, err = repositories.Tasks.MoveToCategory(from.ID, to.ID)
+		require.NoError(t, err)
+		require.Zero(t, moved)
+	})
+
+	t.Run("DeleteAndRestore", func(t *testing.T) {
+		repositories := factory(t)
+		category := newCategory(t, repositories, "Category")
+		first := newTask(t, repositories, "First", category.ID)
+		second := newTask(t, repositories, "Second", category.ID)
+		third := newTask(t, repositories, "Third", category.ID)
+
+		require.NoError(t, repositories.Tasks.Delete(first.ID))
+		tick()
+		require.NoError(t, repositories.Tasks.Delete(second.ID))
+		require.ErrorIs(t, repositories.Tasks.Delete(first.ID), repository_errors.DoesNotExist)
+
+		archived, err := repositories.Tasks.GetTaskByID(first.ID)
+		require.NoError(t, err)
+		require.True(t, archived.IsDeleted())
+
+		_, err = repositories.Tasks.GetTaskByName("First")
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+		tasks, err := repositories.Tasks.GetAllTasks()
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		require.Equal(t, third.ID, tasks[0].ID)
+
+		tasks, err = repositories.Tasks.GetTasksInCategory(category.ID)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+
+		deleted, err := repositories.Tasks.GetDeletedTasks()
+		require.NoError(t, err)
+		require.Len(t, deleted, 2)
+		require.Equal(t, second.ID, deleted[0].ID)
+		require.Equal(t, first.ID, deleted[1].ID)
+
+		require.NoError(t, repositories.Tasks.Restore(first.ID))
+		require.ErrorIs(t, repositories.Tasks.Restore(first.ID), repository_errors.DoesNotExist)
+
+		restored, err := repositories.Tasks.GetTaskByName("First")
+		require.NoError(t, err)
+		require.False(t, restored.IsDeleted())
+	})
+	t.Run("Search", func(t *testing.T) {
+		repositories := factory(t)
+		category := newCategory(t, repositories, "Category")
+		sills := newTask(t, repositories, "Влажная уборка подоконников, отопительных труб, радиаторов", category.ID)
+		windows := newTask(t, repositories, "Мытье окон", category.ID)
+		general := newTask(t, repositories, "Генеральная уборка квартиры", category.ID)
+		archived := newTask(t, repositories, "Уборка подоконников после ремонта", category.ID)
+		require.NoError(t, repositories.Tasks.Delete(archived.ID))
+
+		ids := func(tasks []models.Task) []uuid.UUID {
+			var result []uuid.UUID
+			for _, task := range tasks {
+				result = append(result, task.ID)
+			}
+			return result
+		}
+
+		// other forms of the words are found
+		tasks, err := repositories.Tasks.Search("подоконник")
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{sills.ID}, ids(tasks))
+
+		tasks, err = repositories.Tasks.Search("Уборку")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uuid.UUID{sills.ID, general.ID}, ids(tasks))
+
+		// every word is required
+		tasks, err = repositories.Tasks.Search("уборка радиаторы")
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{sills.ID}, ids(tasks))
+
+		tasks, err = repositories.Tasks.Search("мытье ковров")
+		require.NoError(t, err)
+		require.Empty(t, tasks)
+
+		tasks, err = repositories.Tasks.Search("окон")
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{windows.ID}, ids(tasks))
+
+		tasks, err = repositories.Tasks.Search(" ,. ")
+		require.NoError(t, err)
+		require.Empty(t, tasks)
+	})
+
+	t.Run("Prices", func(t *testing.T) {
+		repositories := factory(t)
+		category := newCategory(t, repositories, "Category")
+		task := newTask(t, repositories, "Task", category.ID)
+		moment := now()
+
+		_, err := repositories.Tasks.SchedulePrice(&models.TaskPrice{TaskID: uuid.New(), Price: 150, EffectiveFrom: moment})
+		require.ErrorIs(t, err, repository_errors.InsertError)
+
+		past, err := repositories.Tasks.SchedulePrice(&models.TaskPrice{TaskID: task.ID, Price: 120, EffectiveFrom: moment.Add(-time.Hour)})
+		require.NoError(t, err)
+		require.NotEqual(t, uuid.Nil, past.ID)
+		future, err := repositories.Tasks.SchedulePrice(&models.TaskPrice{TaskID: task.ID, Price: 150, EffectiveFrom: moment.Add(time.Hour)})
+		require.NoError(t, err)
+
+		// a price from the same moment replaces the scheduled one
+		replaced, err := repositories.Tasks.SchedulePrice(&models.TaskPrice{TaskID: task.ID, Price: 160, EffectiveFrom: moment.Add(time.Hour)})
+		require.NoError(t, err)
+		require.Equal(t, future.ID, replaced.ID)
+
+		prices, err := repositories.Tasks.GetPrices(task.ID)
+		require.NoError(t, err)
+		require.Len(t, prices, 2)
+		require.Equal(t, past.ID, prices[0].ID)
+		require.Equal(t, 160.0, prices[1].Price)
+		require.True(t, prices[1].EffectiveFrom.Equal(moment.Add(time.Hour)))
+
+		upcoming, err := repositories.Tasks.GetUpcomingPrices(moment)
+		require.NoError(t, err)
+		require.Len(t, upcoming, 1)
+		require.Equal(t, future.ID, upcoming[0].ID)
+
+		changed, err := repositories.Tasks.ApplyPrices(moment)
+		require.NoError(t, err)
+		require.Equal(t, 1, changed)
+		applied, err := repositories.Tasks.GetTaskByID(task.ID)
+		require.NoError(t, err)
+		require.Equal(t, 120.0, applied.PricePerSingle)
+
+		changed, err = repositories.Tasks.ApplyPrices(moment)
+		require.NoError(t, err)
+		require.Zero(t, changed)
+
+		changed, err = repositories.Tasks.ApplyPrices(moment.Add(2 * time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, 1, changed)
+		applied, err = repositories.Tasks.GetTaskByID(task.ID)
+		require.NoError(t, err)
+		require.Equal(t, 160.0, applied.PricePerSingle)
+
+		require.NoError(t, repositories.Tasks.DeletePrice(future.ID))
+		require.ErrorIs(t, repositories.Tasks.DeletePrice(future.ID), repository_errors.DoesNotExist)
+		upcoming, err = repositories.Tasks.GetUpcomingPrices(moment)
+		require.NoError(t, err)
+		require.Empty(t, upcoming)
+	})
+}
@@ -0,0 +1,107 @@
+package conformance
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"testing"
+)
+
+func runUserTests(t *testing.T, factory Factory) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+		require.NotEqual(t, uuid.Nil, user.ID)
+
+		byID, err := repositories.Users.GetUserByID(user.ID)
+		require.NoError(t, err)
+		require.Equal(t, user.Email, byID.Email)
+		require.False(t, byID.IsDeleted())
+
+		byEmail, err := repositories.Users.GetUserByEmail("user@test.com")
+		require.NoError(t, err)
+		require.Equal(t, user.ID, byEmail.ID)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repositories := factory(t)
+
+		_, err := repositories.Users.GetUserByID(uuid.New())
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		_, err = repositories.Users.GetUserByEmail("missing@test.com")
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		_, err = repositories.Users.Update(&models.User{ID: uuid.New(), Name: "Name", Surname: "Surname", Email: "missing@test.com", Password: "hashed_password"})
+		require.ErrorIs(t, err, repository_errors.UpdateError)
+		require.ErrorIs(t, repositories.Users.Delete(uuid.New()), repository_errors.DoesNotExist)
+		require.ErrorIs(t, repositories.Users.Restore(uuid.New()), repository_errors.DoesNotExist)
+	})
+
+	t.Run("DuplicateEmail", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+
+		_, err := repositories.Users.Create(&models.User{Name: "Other", Surname: "Other", Email: "user@test.com", Password: "hashed_password"})
+		require.ErrorIs(t, err, repository_errors.InsertError)
+
+		require.NoError(t, repositories.Users.Delete(user.ID))
+		_, err = repositories.Users.Create(&models.User{Name: "Other", Surname: "Other", Email: "user@test.com", Password: "hashed_password"})
+		require.ErrorIs(t, err, repository_errors.InsertError)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repositories := factory(t)
+		user := newUser(t, repositories, "user@test.com")
+
+		user.Name = "New Name"
+		user.Email = "new@test.com"
+		_, err := repositories.Users.Update(user)
+		require.NoError(t, err)
+
+		updated, err := repositories.Users.GetUserByID(user.ID)
+		require.NoError(t, err)
+		require.Equal(t, "New Name", updated.Name)
+		require.Equal(t, "new@test.com", updated.Email)
+	})
+
+	t.Run("DeleteAndRestore", func(t *testing.T) {
+		repositories := factory(t)
+		first := newUser(t, repositories, "first@test.com")
+		second := newUser(t, repositories, "second@test.com")
+		order := newOrder(t, repositories, first)
+
+		require.NoError(t, repositories.Users.Delete(first.ID))
+		tick()
+		require.NoError(t, repositories.Users.Delete(second.ID))
+		require.ErrorIs(t, repositories.Users.Delete(first.ID), repository_errors.DoesNotExist)
+
+		archived, err := repositories.Users.GetUserByID(first.ID)
+		require.NoError(t, err)
+		require.True(t, archived.IsDeleted())
+
+		_, err = repositories.Users.GetUserByEmail("first@test.com")
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+		users, err := repositories.Users.GetAllUsers()
+		require.NoError(t, err)
+		require.Empty(t, users)
+
+		deleted, err := repositories.Users.GetDeletedUsers()
+		require.NoError(t, err)
+		require.Len(t, deleted, 2)
+		require.Equal(t, second.ID, deleted[0].ID)
+		require.Equal(t, first.ID, deleted[1].ID)
+
+		orders, err := repositories.Orders.GetAllOrdersByUserID(first.ID)
+		require.NoError(t, err)
+		require.Len(t, orders, 1)
+		require.Equal(t, order.ID, orders[0].ID)
+
+		require.NoError(t, repositories.Users.Restore(first.ID))
+		require.ErrorIs(t, repositories.Users.Restore(first.ID), repository_errors.DoesNotExist)
+
+		restored, err := repositories.Users.GetUserByEmail("first@test.com")
+		require.NoError(t, err)
+		require.False(t, restored.IsDeleted())
+	})
+}
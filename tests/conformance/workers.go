@@ -0,0 +1,140 @@
+package conformance
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"testing"
+)
+
+func runWorkerTests(t *testing.T, factory Factory) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repositories := factory(t)
+		worker := newWorker(t, repositories, "worker@test.com", models.MasterRole)
+		require.NotEqual(t, uuid.Nil, worker.ID)
+
+		byID, err := repositories.Workers.GetWorkerByID(worker.ID)
+		require.NoError(t, err)
+		require.Equal(t, worker.Email, byID.Email)
+		require.Equal(t, models.MasterRole, byID.Role)
+
+		byEmail, err := repositories.Workers.GetWorkerByEmail("worker@test.com")
+		require.NoError(t, err)
+		require.Equal(t, worker.ID, byEmail.ID)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repositories := factory(t)
+
+		_, err := repositories.Workers.GetWorkerByID(uuid.New())
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		_, err = repositories.Workers.GetWorkerByEmail("missing@test.com")
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+		_, err = repositories.Workers.Update(&models.Worker{ID: uuid.New(), Name: "Name", Surname: "Surname", Email: "missing@test.com", Role: models.MasterRole, Password: "hashed_password"})
+		require.ErrorIs(t, err, repository_errors.UpdateError)
+		require.ErrorIs(t, repositories.Workers.Delete(uuid.New()), repository_errors.DoesNotExist)
+		require.ErrorIs(t, repositories.Workers.Restore(uuid.New()), repository_errors.DoesNotExist)
+	})
+
+	t.Run("DuplicateEmail", func(t *testing.T) {
+		repositories := factory(t)
+		worker := newWorker(t, repositories, "worker@test.com", models.MasterRole)
+
+		_, err := repositories.Workers.Create(&models.Worker{Name: "Other", Surname: "Other", Email: "worker@test.com", Role: models.MasterRole, Password: "hashed_password"})
+		require.ErrorIs(t, err, repository_errors.InsertError)
+
+		require.NoError(t, repositories.Workers.Delete(worker.ID))
+		_, err = repositories.Workers.Create(&models.Worker{Name: "Other", Surname: "Other", Email: "worker@test.com", Role: models.MasterRole, Password: "hashed_password"})
+		require.ErrorIs(t, err, repository_errors.InsertError)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repositories := factory(t)
+		worker := newWorker(t, repositories, "worker@test.com", models.MasterRole)
+
+		worker.Surname = "New Surname"
+		worker.Role = models.ManagerRole
+		_, err := repositories.Workers.Update(worker)
+		require.NoError(t, err)
+
+		updated, err := repositories.Workers.GetWorkerByID(worker.ID)
+		require.NoError(t, err)
+		require.Equal(t, "New Surname", updated.Surname)
+		require.Equal(t, models.ManagerRole, updated.Role)
+	})
+
+	t.Run("DeleteAndRestore", func(t *testing.T) {
+		repositories := factory(t)
+		first := newWorker(t, repositories, "first@test.com", models.MasterRole)
+		second := newWorker(t, repositories, "second@test.com", models.MasterRole)
+		manager := newWorker(t, repositories, "manager@test.com", models.ManagerRole)
+
+		require.NoError(t, repositories.Workers.Delete(first.ID))
+		tick()
+		require.NoError(t, repositories.Workers.Delete(second.ID))
+		require.ErrorIs(t, repositories.Workers.Delete(first.ID), repository_errors.DoesNotExist)
+
+		archived, err := repositories.Workers.GetWorkerByID(first.ID)
+		require.NoError(t, err)
+		require.True(t, archived.IsDeleted())
+
+		_, err = repositories.Workers.GetWorkerByEmail("first@test.com")
+		require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+		workers, err := repositories.Workers.GetAllWorkers()
+		require.NoError(t, err)
+		require.Len(t, workers, 1)
+		require.Equal(t, manager.ID, workers[0].ID)
+
+		masters, err := repositories.Workers.GetWorkersByRole(models.MasterRole)
+		require.NoError(t, err)
+		require.Empty(t, masters)
+
+		deleted, err := repositories.Workers.GetDeletedWorkers()
+		require.NoError(t, err)
+		require.Len(t, deleted, 2)
+		require.Equal(t, second.ID, deleted[0].ID)
+		require.Equal(t, first.ID, deleted[1].ID)
+
+		require.NoError(t, repositories.Workers.Restore(first.ID))
+		require.ErrorIs(t, repositories.Workers.Restore(first.ID), repository_errors.DoesNotExist)
+
+		masters, err = repositories.Workers.GetWorkersByRole(models.MasterRole)
+		require.NoError(t, err)
+		require.Len(t, masters, 1)
+		require.Equal(t, first.ID, masters[0].ID)
+	})
+
+	t.Run("GetAverageOrderRate", func(t *testing.T) {
+		repositories := factory(t)
+		worker := newWorker(t, repositories, "worker@test.com", models.MasterRole)
+		user := newUser(t, repositories, "user@test.com")
+
+		rate, err := repositories.Workers.GetAverageOrderRate(worker)
+		require.NoError(t, err)
+		require.Zero(t, rate)
+
+		for _, order := range []struct {
+			status int
+			rate   int
+		}{
+			{models.CompletedOrderStatus, 4},
+			{models.CompletedOrderStatus, 5},
+			{models.CompletedOrderStatus, 0},
+			{models.CancelledOrderStatus, 1},
+		} {
+			created, err := repositories.Orders.GetOrderByID(newOrder(t, repositories, user).ID)
+			require.NoError(t, err)
+			created.WorkerID = worker.ID
+			created.Status = order.status
+			created.Rate = order.rate
+			_, err = repositories.Orders.Update(created)
+			require.NoError(t, err)
+		}
+
+		rate, err = repositories.Workers.GetAverageOrderRate(worker)
+		require.NoError(t, err)
+		require.InDelta(t, 4.5, rate, 0.001)
+	})
+}
@@ -108,7 +108,6 @@ func NewTestStorage() (*sqlx.DB, *container.PostgresContainer, map[string]int64,
 	ctr, err := container.Run(
 		context.TODO(),
 		pgImage,
-		container.WithInitScripts("../../db/sql/init.sql"),
 		container.WithDatabase(dbName),
 		container.WithUsername(dbUsername),
 		container.WithPassword(dbPassword),
@@ -155,6 +154,11 @@ func NewTestStorage() (*sqlx.DB, *container.PostgresContainer, map[string]int64,
 		panic(err)
 	}
 
+	_, err = postgres.NewMigrator(conn).Up()
+	if err != nil {
+		panic(err)
+	}
+
 	ids = map[string]int64{}
 	ids["categoryID"] = initTestCategoryStorage(postgres.NewCategoryRepository(conn))
 	ids2 = map[string]uuid.UUID{}
@@ -283,7 +287,7 @@ func (s *CategorySuite) Test_Category_GetCategory(t provider.T) {
 
 		sCtx.WithNewParameters("ctx", ctx, "request", request)
 
-		category, err := s.categoryService.Create(request.Name)
+		category, err := s.categoryService.Create(request.Name, 0, false, "")
 
 		sCtx.Assert().NoError(err)
 		sCtx.Assert().NotNil(category)
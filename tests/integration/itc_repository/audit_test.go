@@ -0,0 +1,57 @@
+package itc_repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"lab3/internal/models"
+	"lab3/internal/repository/postgres"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestAuditRepositoryFilter(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	auditRepository := postgres.NewAuditRepository(db)
+	actorID := uuid.New()
+	otherActorID := uuid.New()
+	now := time.Now()
+
+	entries := []*models.AuditEntry{
+		{ActorID: actorID, Action: models.AuditTaskCreate, TargetType: models.AuditTargetTask, TargetID: "task", After: `{"name":"task"}`, IP: "127.0.0.1", CreatedAt: now.Add(-48 * time.Hour)},
+		{ActorID: actorID, Action: models.AuditTaskUpdate, TargetType: models.AuditTargetTask, TargetID: "task", Before: `{"name":"task"}`, After: `{"name":"new"}`, IP: "127.0.0.1", CreatedAt: now.Add(-time.Hour)},
+		{ActorID: otherActorID, Action: models.AuditOrderStatus, TargetType: models.AuditTargetOrder, TargetID: "order", IP: "10.0.0.1", CreatedAt: now},
+	}
+	for _, entry := range entries {
+		require.NoError(t, auditRepository.Create(entry))
+		require.NotEqual(t, uuid.Nil, entry.ID)
+	}
+
+	all, err := auditRepository.Filter(models.AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	require.Equal(t, models.AuditOrderStatus, all[0].Action)
+
+	byActor, err := auditRepository.Filter(models.AuditFilter{ActorID: actorID})
+	require.NoError(t, err)
+	require.Len(t, byActor, 2)
+
+	byTarget, err := auditRepository.Filter(models.AuditFilter{TargetType: models.AuditTargetTask, TargetID: "task", From: now.Add(-24 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, byTarget, 1)
+	require.Equal(t, `{"name":"new"}`, byTarget[0].After)
+
+	limited, err := auditRepository.Filter(models.AuditFilter{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, limited, 1)
+}
@@ -207,8 +207,21 @@ func TestCategoryRepositoryDelete_Success(t *testing.T) {
 	require.NoError(t, err)
 
 	receivedCategory, err := categoryRepository.GetByID(createdCategory.ID)
-	require.Error(t, err)
-	require.Nil(t, receivedCategory)
+	require.NoError(t, err)
+	require.True(t, receivedCategory.IsDeleted())
+
+	categories, err := categoryRepository.GetAll()
+	require.NoError(t, err)
+	for _, category := range categories {
+		require.NotEqual(t, createdCategory.ID, category.ID)
+	}
+
+	err = categoryRepository.Restore(createdCategory.ID)
+	require.NoError(t, err)
+
+	deletedCategories, err := categoryRepository.GetDeleted()
+	require.NoError(t, err)
+	require.Empty(t, deletedCategories)
 }
 
 func TestCategoryRepositoryDelete_Failure(t *testing.T) {
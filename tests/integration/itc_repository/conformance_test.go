@@ -0,0 +1,77 @@
+package itc_repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"lab3/internal/repository/mongodb"
+	"lab3/internal/repository/postgres"
+	"lab3/tests/conformance"
+	"log"
+	"testing"
+)
+
+func TestPostgresConformance(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	conformance.Run(t, func(t *testing.T) conformance.Repositories {
+		_, err := db.Exec(`TRUNCATE users, workers, orders, tasks, order_contains_tasks, categories, sessions, login_attempts,
+			one_time_tokens, worker_two_factor, worker_recovery_codes, audit_log, orders_archive, order_contains_tasks_archive,
+			task_prices, invoices RESTART IDENTITY CASCADE;`)
+		require.NoError(t, err)
+
+		return conformance.Repositories{
+			Users:         postgres.NewUserRepository(db),
+			Workers:       postgres.NewWorkerRepository(db),
+			Tasks:         postgres.NewTaskRepository(db),
+			Categories:    postgres.NewCategoryRepository(db),
+			Orders:        postgres.NewOrderRepository(db),
+			Sessions:      postgres.NewSessionRepository(db),
+			LoginAttempts: postgres.NewLoginAttemptRepository(db),
+			OneTimeTokens: postgres.NewOneTimeTokenRepository(db),
+			TwoFactor:     postgres.NewTwoFactorRepository(db),
+			Audit:         postgres.NewAuditRepository(db),
+			Invoices:      postgres.NewInvoiceRepository(db),
+		}
+	})
+}
+
+func TestMongoConformance(t *testing.T) {
+	dbContainer, db := SetupTestMongoDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	// every test gets a database of its own, created by the same migrations that the application runs
+	databases := 0
+	conformance.Run(t, func(t *testing.T) conformance.Repositories {
+		databases++
+		testDB := db.Client().Database(fmt.Sprintf("conformance_%d", databases))
+		_, err := mongodb.NewMigrator(testDB).Up()
+		require.NoError(t, err)
+
+		return conformance.Repositories{
+			Users:         mongodb.NewUserRepository(testDB),
+			Workers:       mongodb.NewWorkerRepository(testDB),
+			Tasks:         mongodb.NewTaskRepository(testDB),
+			Categories:    mongodb.NewCategoryRepository(testDB),
+			Orders:        mongodb.NewOrderRepository(testDB),
+			Sessions:      mongodb.NewSessionRepository(testDB),
+			LoginAttempts: mongodb.NewLoginAttemptRepository(testDB),
+			OneTimeTokens: mongodb.NewOneTimeTokenRepository(testDB),
+			TwoFactor:     mongodb.NewTwoFactorRepository(testDB),
+			Audit:         mongodb.NewAuditRepository(testDB),
+			Invoices:      mongodb.NewInvoiceRepository(testDB),
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package itc_repository
+
+import (
+	"context"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"lab3/internal/repository/postgres"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestLoginAttemptRepositoryRegisterFailure_Success(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	loginAttemptRepository := postgres.NewLoginAttemptRepository(db)
+
+	now := time.Now()
+	attempt, err := loginAttemptRepository.RegisterFailure("user:test@gmail.com", now, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, attempt.Failures)
+
+	attempt, err = loginAttemptRepository.RegisterFailure("user:test@gmail.com", now, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 2, attempt.Failures)
+
+	// the previous failure is older than the window, so the counter starts again
+	attempt, err = loginAttemptRepository.RegisterFailure("user:test@gmail.com", now.Add(2*time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, attempt.Failures)
+}
+
+func TestLoginAttemptRepositoryLock_Success(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	loginAttemptRepository := postgres.NewLoginAttemptRepository(db)
+
+	now := time.Now()
+	_, err := loginAttemptRepository.RegisterFailure("ip:127.0.0.1", now, now.Add(-time.Hour))
+	require.NoError(t, err)
+
+	err = loginAttemptRepository.Lock("ip:127.0.0.1", now.Add(time.Hour))
+	require.NoError(t, err)
+
+	locked, err := loginAttemptRepository.GetLocked(now)
+	require.NoError(t, err)
+	require.Len(t, locked, 1)
+	require.Equal(t, "ip:127.0.0.1", locked[0].Key)
+
+	err = loginAttemptRepository.Delete("ip:127.0.0.1")
+	require.NoError(t, err)
+
+	locked, err = loginAttemptRepository.GetLocked(now)
+	require.NoError(t, err)
+	require.Len(t, locked, 0)
+}
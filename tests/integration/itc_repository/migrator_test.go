@@ -0,0 +1,87 @@
+package itc_repository
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"lab3/internal/repository/postgres"
+	"lab3/internal/repository/repository_errors"
+	"log"
+	"testing"
+)
+
+func TestMigratorUp_AlreadyApplied(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	migrator := postgres.NewMigrator(db)
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	require.Empty(t, applied)
+
+	status, err := migrator.Status()
+	require.NoError(t, err)
+	require.NotEmpty(t, status)
+	for _, migration := range status {
+		require.True(t, migration.Applied, migration.Name)
+	}
+}
+
+func TestMigratorDownAndUp(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	migrator := postgres.NewMigrator(db)
+	status, err := migrator.Status()
+	require.NoError(t, err)
+
+	// revert everything, the initial migration drops the tables
+	for i := len(status) - 1; i >= 0; i-- {
+		reverted, err := migrator.Down()
+		require.NoError(t, err)
+		require.Equal(t, status[i].Version, reverted.Version)
+	}
+
+	_, err = migrator.Down()
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+	var tables int
+	err = db.Get(&tables, `SELECT count(*) FROM information_schema.tables WHERE table_name = 'orders';`)
+	require.NoError(t, err)
+	require.Equal(t, 0, tables)
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	require.Len(t, applied, len(status))
+
+	err = db.Get(&tables, `SELECT count(*) FROM information_schema.tables WHERE table_name = 'orders';`)
+	require.NoError(t, err)
+	require.Equal(t, 1, tables)
+}
+
+func TestMigratorUp_UnknownVersion(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	_, err := db.Exec(`INSERT INTO schema_migrations(version, name) VALUES (9999, 'from_the_future');`)
+	require.NoError(t, err)
+
+	_, err = postgres.NewMigrator(db).Up()
+	require.ErrorIs(t, err, repository_errors.UnknownMigration)
+}
@@ -0,0 +1,43 @@
+package itc_repository
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"lab3/internal/models"
+	"lab3/internal/repository/mongodb"
+	"lab3/internal/repository/repository_errors"
+	"log"
+	"testing"
+)
+
+func TestMongoCategoryRepository(t *testing.T) {
+	dbContainer, db := SetupTestMongoDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	categoryRepository := mongodb.NewCategoryRepository(db)
+
+	first, err := categoryRepository.Create(&models.Category{Name: "First"})
+	require.NoError(t, err)
+	second, err := categoryRepository.Create(&models.Category{Name: "Second"})
+	require.NoError(t, err)
+	require.Equal(t, first.ID+1, second.ID)
+
+	_, err = categoryRepository.Create(&models.Category{})
+	require.ErrorIs(t, err, repository_errors.InsertError)
+
+	_, err = categoryRepository.Update(&models.Category{ID: second.ID + 1, Name: "Missing"})
+	require.ErrorIs(t, err, repository_errors.UpdateError)
+
+	_, err = categoryRepository.GetByID(second.ID + 1)
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+	categories, err := categoryRepository.GetAll()
+	require.NoError(t, err)
+	require.Len(t, categories, 2)
+}
@@ -0,0 +1,315 @@
+package itc_repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"go.mongodb.org/mongo-driver/mongo"
+	"lab3/internal/models"
+	"lab3/internal/repository/mongodb"
+	"lab3/internal/repository/repository_errors"
+	"log"
+	"testing"
+	"time"
+)
+
+func createMongoOrderFixture(t *testing.T, db *mongo.Database) (*models.User, *models.Task) {
+	user, err := mongodb.NewUserRepository(db).Create(&models.User{
+		Name:        "First Name",
+		Surname:     "Last Name",
+		Address:     "Address",
+		PhoneNumber: "+79999999999",
+		Email:       "user@email.com",
+		Password:    "hashed_password",
+	})
+	require.NoError(t, err)
+
+	category, err := mongodb.NewCategoryRepository(db).Create(&models.Category{Name: "Category"})
+	require.NoError(t, err)
+
+	task, err := mongodb.NewTaskRepository(db).Create(&models.Task{
+		Name:           "Task",
+		PricePerSingle: 100,
+		Category:       category.ID,
+	})
+	require.NoError(t, err)
+
+	return user, task
+}
+
+func TestMongoOrderRepositoryCreate_Success(t *testing.T) {
+	dbContainer, db := SetupTestMongoDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	orderRepository := mongodb.NewOrderRepository(db)
+	user, task := createMongoOrderFixture(t, db)
+
+	createdOrder, err := orderRepository.Create(&models.Order{
+		UserID:   user.ID,
+		Status:   models.NewOrderStatus,
+		Address:  "Test Address",
+		Deadline: time.Now().Add(24 * time.Hour),
+	}, []models.OrderedTask{{Task: task, Quantity: 3}})
+	require.NoError(t, err)
+	require.Equal(t, 1, createdOrder.Version)
+
+	receivedOrder, err := orderRepository.GetOrderByID(createdOrder.ID)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, receivedOrder.UserID)
+	require.Equal(t, uuid.Nil, receivedOrder.WorkerID)
+
+	tasks, err := orderRepository.GetTasksInOrder(createdOrder.ID)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.Equal(t, task.ID, tasks[0].ID)
+	require.Equal(t, task.Name, tasks[0].Name)
+
+	quantity, err := orderRepository.GetTaskQuantity(createdOrder.ID, task.ID)
+	require.NoError(t, err)
+	require.Equal(t, 3, quantity)
+}
+
+func TestMongoOrderRepositoryCreate_UnknownUser(t *testing.T) {
+	dbContainer, db := SetupTestMongoDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	orderRepository := mongodb.NewOrderRepository(db)
+	_, task := createMongoOrderFixture(t, db)
+
+	createdOrder, err := orderRepository.Create(&models.Order{
+		UserID:   uuid.New(),
+		Status:   models.NewOrderStatus,
+		Address:  "Test Address",
+		Deadline: time.Now().Add(24 * time.Hour),
+	}, []models.OrderedTask{{Task: task, Quantity: 1}})
+	require.ErrorIs(t, err, repository_errors.InsertError)
+	require.Nil(t, createdOrder)
+}
+
+func TestMongoOrderRepositoryCreate_IdempotencyKey(t *testing.T) {
+	dbContainer, db := SetupTestMongoDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	orderRepository := mongodb.NewOrderRepository(db)
+	user, task := createMongoOrderFixture(t, db)
+
+	createdOrder, err := orderRepository.Create(&models.Order{
+		UserID:         user.ID,
+		Status:         models.NewOrderStatus,
+		Address:        "Test Address",
+		Deadline:       time.Now().Add(24 * time.Hour),
+		IdempotencyKey: "key",
+	}, []models.OrderedTask{{Task: task, Quantity: 1}})
+	require.NoError(t, err)
+
+	repeatedOrder, err := orderRepository.Create(&models.Order{
+		UserID:         user.ID,
+		Status:         models.NewOrderStatus,
+		Address:        "Test Address",
+		Deadline:       time.Now().Add(24 * time.Hour),
+		IdempotencyKey: "key",
+	}, []models.OrderedTask{{Task: task, Quantity: 1}})
+	require.ErrorIs(t, err, repository_errors.AlreadyExists)
+	require.Nil(t, repeatedOrder)
+
+	// the lines of the rejected order are rolled back with it
+	orders, err := orderRepository.Filter(map[string]string{"user_id": user.ID.String()})
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	require.Equal(t, createdOrder.ID, orders[0].ID)
+
+	count, err := db.Collection("order_contains_tasks").CountDocuments(context.Background(), map[string]interface{}{"task_id": task.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
+func TestMongoOrderRepositoryDelete_Success(t *testing.T) {
+	dbContainer, db := SetupTestMongoDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	orderRepository := mongodb.NewOrderRepository(db)
+	user, task := createMongoOrderFixture(t, db)
+
+	createdOrder, err := orderRepository.Create(&models.Order{
+		UserID:   user.ID,
+		Status:   models.NewOrderStatus,
+		Address:  "Test Address",
+		Deadline: time.Now().Add(24 * time.Hour),
+	}, []models.OrderedTask{{Task: task, Quantity: 1}})
+	require.NoError(t, err)
+
+	err = orderRepository.Delete(createdOrder.ID)
+	require.NoError(t, err)
+
+	_, err = orderRepository.GetOrderByID(createdOrder.ID)
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+	_, err = orderRepository.GetTaskQuantity(createdOrder.ID, task.ID)
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+	err = orderRepository.Delete(createdOrder.ID)
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+}
+
+func TestMongoOrderRepositoryTasks(t *testing.T) {
+	dbContainer, db := SetupTestMongoDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	orderRepository := mongodb.NewOrderRepository(db)
+	user, task := createMongoOrderFixture(t, db)
+
+	createdOrder, err := orderRepository.Create(&models.Order{
+		UserID:   user.ID,
+		Status:   models.NewOrderStatus,
+		Address:  "Test Address",
+		Deadline: time.Now().Add(24 * time.Hour),
+	}, nil)
+	require.NoError(t, err)
+
+	tasks, err := orderRepository.GetTasksInOrder(createdOrder.ID)
+	require.NoError(t, err)
+	require.Empty(t, tasks)
+
+	err = orderRepository.AddTaskToOrder(createdOrder.ID, task.ID)
+	require.NoError(t, err)
+
+	quantity, err := orderRepository.GetTaskQuantity(createdOrder.ID, task.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, quantity)
+
+	err = orderRepository.UpdateTaskQuantity(createdOrder.ID, task.ID, 5)
+	require.NoError(t, err)
+
+	quantity, err = orderRepository.GetTaskQuantity(createdOrder.ID, task.ID)
+	require.NoError(t, err)
+	require.Equal(t, 5, quantity)
+
+	err = orderRepository.RemoveTaskFromOrder(createdOrder.ID, task.ID)
+	require.NoError(t, err)
+
+	_, err = orderRepository.GetTaskQuantity(createdOrder.ID, task.ID)
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+	err = orderRepository.AddTaskToOrder(uuid.New(), task.ID)
+	require.ErrorIs(t, err, repository_errors.InsertError)
+}
+
+func TestMongoOrderRepositoryFilter(t *testing.T) {
+	dbContainer, db := SetupTestMongoDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	orderRepository := mongodb.NewOrderRepository(db)
+	user, _ := createMongoOrderFixture(t, db)
+	worker, err := mongodb.NewWorkerRepository(db).Create(&models.Worker{
+		Name:     "First Name",
+		Surname:  "Last Name",
+		Email:    "worker@email.com",
+		Role:     models.MasterRole,
+		Password: "hashed_password",
+	})
+	require.NoError(t, err)
+
+	newOrder, err := orderRepository.Create(&models.Order{
+		UserID:   user.ID,
+		Status:   models.NewOrderStatus,
+		Address:  "Test Address",
+		Deadline: time.Now().Add(24 * time.Hour),
+	}, nil)
+	require.NoError(t, err)
+
+	assignedOrder, err := orderRepository.Create(&models.Order{
+		UserID:   user.ID,
+		Status:   models.NewOrderStatus,
+		Address:  "Test Address",
+		Deadline: time.Now().Add(24 * time.Hour),
+	}, nil)
+	require.NoError(t, err)
+	assignedOrder.WorkerID = worker.ID
+	assignedOrder.Status = models.InProgressOrderStatus
+	_, err = orderRepository.Update(assignedOrder)
+	require.NoError(t, err)
+
+	orders, err := orderRepository.Filter(map[string]string{"worker_id": "null", "status": "1,2"})
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	require.Equal(t, newOrder.ID, orders[0].ID)
+
+	orders, err = orderRepository.Filter(map[string]string{"worker_id": worker.ID.String(), "status": "1,2"})
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	require.Equal(t, assignedOrder.ID, orders[0].ID)
+
+	orders, err = orderRepository.Filter(map[string]string{"user_id": user.ID.String()})
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+}
+
+func TestMongoOrderRepositoryUpdate_VersionConflict(t *testing.T) {
+	dbContainer, db := SetupTestMongoDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	orderRepository := mongodb.NewOrderRepository(db)
+	user, _ := createMongoOrderFixture(t, db)
+
+	createdOrder, err := orderRepository.Create(&models.Order{
+		UserID:   user.ID,
+		Status:   models.NewOrderStatus,
+		Address:  "Test Address",
+		Deadline: time.Now().Add(24 * time.Hour),
+	}, nil)
+	require.NoError(t, err)
+
+	firstRead, err := orderRepository.GetOrderByID(createdOrder.ID)
+	require.NoError(t, err)
+	secondRead, err := orderRepository.GetOrderByID(createdOrder.ID)
+	require.NoError(t, err)
+
+	firstRead.Status = models.InProgressOrderStatus
+	updatedOrder, err := orderRepository.Update(firstRead)
+	require.NoError(t, err)
+	require.Equal(t, 2, updatedOrder.Version)
+
+	secondRead.Status = models.CancelledOrderStatus
+	_, err = orderRepository.Update(secondRead)
+	require.ErrorIs(t, err, repository_errors.VersionConflict)
+
+	secondRead.ID = uuid.New()
+	_, err = orderRepository.Update(secondRead)
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+}
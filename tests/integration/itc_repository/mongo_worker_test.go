@@ -0,0 +1,115 @@
+package itc_repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"lab3/internal/models"
+	"lab3/internal/repository/mongodb"
+	"lab3/internal/repository/repository_errors"
+	"log"
+	"testing"
+)
+
+func TestMongoWorkerRepositoryCreate_DuplicateEmail(t *testing.T) {
+	dbContainer, db := SetupTestMongoDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	workerRepository := mongodb.NewWorkerRepository(db)
+	worker := &models.Worker{
+		Name:     "First Name",
+		Surname:  "Last Name",
+		Email:    "test@email.com",
+		Role:     models.MasterRole,
+		Password: "hashed_password",
+	}
+
+	_, err := workerRepository.Create(worker)
+	require.NoError(t, err)
+
+	worker.ID = uuid.Nil
+	createdWorker, err := workerRepository.Create(worker)
+	require.ErrorIs(t, err, repository_errors.InsertError)
+	require.Nil(t, createdWorker)
+}
+
+func TestMongoWorkerRepositoryUpdate(t *testing.T) {
+	dbContainer, db := SetupTestMongoDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	workerRepository := mongodb.NewWorkerRepository(db)
+	createdWorker, err := workerRepository.Create(&models.Worker{
+		Name:     "First Name",
+		Surname:  "Last Name",
+		Email:    "test@email.com",
+		Role:     models.MasterRole,
+		Password: "hashed_password",
+	})
+	require.NoError(t, err)
+
+	createdWorker.Name = "Updated Name"
+	_, err = workerRepository.Update(createdWorker)
+	require.NoError(t, err)
+
+	receivedWorker, err := workerRepository.GetWorkerByID(createdWorker.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Updated Name", receivedWorker.Name)
+
+	createdWorker.ID = uuid.New()
+	_, err = workerRepository.Update(createdWorker)
+	require.ErrorIs(t, err, repository_errors.UpdateError)
+}
+
+func TestMongoWorkerRepositoryDeleteAndRestore(t *testing.T) {
+	dbContainer, db := SetupTestMongoDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	workerRepository := mongodb.NewWorkerRepository(db)
+	createdWorker, err := workerRepository.Create(&models.Worker{
+		Name:     "First Name",
+		Surname:  "Last Name",
+		Email:    "test@email.com",
+		Role:     models.MasterRole,
+		Password: "hashed_password",
+	})
+	require.NoError(t, err)
+
+	err = workerRepository.Delete(createdWorker.ID)
+	require.NoError(t, err)
+
+	workers, err := workerRepository.GetWorkersByRole(models.MasterRole)
+	require.NoError(t, err)
+	require.Empty(t, workers)
+
+	deletedWorkers, err := workerRepository.GetDeletedWorkers()
+	require.NoError(t, err)
+	require.Len(t, deletedWorkers, 1)
+	require.True(t, deletedWorkers[0].IsDeleted())
+
+	err = workerRepository.Restore(createdWorker.ID)
+	require.NoError(t, err)
+
+	workers, err = workerRepository.GetAllWorkers()
+	require.NoError(t, err)
+	require.Len(t, workers, 1)
+	require.Equal(t, models.MasterRole, workers[0].Role)
+
+	err = workerRepository.Delete(uuid.New())
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+}
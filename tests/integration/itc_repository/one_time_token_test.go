@@ -0,0 +1,73 @@
+package itc_repository
+
+import (
+	"context"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"lab3/internal/models"
+	"lab3/internal/repository/postgres"
+	"lab3/internal/repository/repository_errors"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestOneTimeTokenRepositoryConsume_SingleUse(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	tokenRepository := postgres.NewOneTimeTokenRepository(db)
+	worker := createSessionTestWorker(t, postgres.NewWorkerRepository(db))
+
+	now := time.Now()
+	err := tokenRepository.Create(&models.OneTimeToken{
+		Hash:      "hash",
+		Purpose:   models.WorkerPasswordResetPurpose,
+		WorkerID:  worker.ID,
+		ExpiresAt: now.Add(time.Hour),
+		CreatedAt: now,
+	})
+	require.NoError(t, err)
+
+	_, err = tokenRepository.Consume("hash", models.UserPasswordResetPurpose, now)
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+	token, err := tokenRepository.Consume("hash", models.WorkerPasswordResetPurpose, now)
+	require.NoError(t, err)
+	require.Equal(t, worker.ID, token.WorkerID)
+
+	_, err = tokenRepository.Consume("hash", models.WorkerPasswordResetPurpose, now)
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+}
+
+func TestOneTimeTokenRepositoryConsume_Expired(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	tokenRepository := postgres.NewOneTimeTokenRepository(db)
+	worker := createSessionTestWorker(t, postgres.NewWorkerRepository(db))
+
+	now := time.Now()
+	err := tokenRepository.Create(&models.OneTimeToken{
+		Hash:      "hash",
+		Purpose:   models.WorkerPasswordResetPurpose,
+		WorkerID:  worker.ID,
+		ExpiresAt: now.Add(-time.Minute),
+		CreatedAt: now.Add(-time.Hour),
+	})
+	require.NoError(t, err)
+
+	_, err = tokenRepository.Consume("hash", models.WorkerPasswordResetPurpose, now)
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	"lab3/internal/models"
 	"lab3/internal/repository/postgres"
+	"lab3/internal/repository/repository_errors"
 	"log"
 	"testing"
 	"time"
@@ -212,6 +213,7 @@ func TestOrderRepositoryGetTasksInOrder_Success(t *testing.T) {
 			log.Println("Error terminating container:", err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	_ = postgres.NewOrderRepository(db)
 	_ = postgres.NewTaskRepository(db)
@@ -347,6 +349,7 @@ func TestOrderRepositoryAddTaskToOrder_Success(t *testing.T) {
 			log.Println("Error terminating container:", err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	_ = postgres.NewOrderRepository(db)
 	_ = postgres.NewTaskRepository(db)
@@ -393,6 +396,7 @@ func TestOrderRepositoryRemoveTaskFromOrder_Success(t *testing.T) {
 			log.Println("Error terminating container:", err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	_ = postgres.NewOrderRepository(db)
 	_ = postgres.NewTaskRepository(db)
@@ -439,6 +443,7 @@ func TestOrderRepositoryUpdateTaskQuantity_Success(t *testing.T) {
 			log.Println("Error terminating container:", err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	_ = postgres.NewOrderRepository(db)
 	_ = postgres.NewTaskRepository(db)
@@ -485,6 +490,7 @@ func TestOrderRepositoryGetTaskQuantity_Success(t *testing.T) {
 			log.Println("Error terminating container:", err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	_ = postgres.NewOrderRepository(db)
 	_ = postgres.NewTaskRepository(db)
@@ -526,3 +532,104 @@ func TestOrderRepositoryGetTaskQuantity_Failure(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, 0, quantity)
 }
+
+func TestOrderRepositoryCreate_IdempotencyKey(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	orderRepository := postgres.NewOrderRepository(db)
+	user, err := postgres.NewUserRepository(db).Create(&models.User{
+		Name:        "First Name",
+		Surname:     "Last Name",
+		Address:     "Address",
+		PhoneNumber: "+79999999999",
+		Email:       "user@email.com",
+		Password:    "hashed_password",
+	})
+	require.NoError(t, err)
+
+	createdOrder, err := orderRepository.Create(&models.Order{
+		UserID:         user.ID,
+		Status:         models.NewOrderStatus,
+		Address:        "Test Address",
+		Deadline:       time.Now().Add(24 * time.Hour),
+		IdempotencyKey: "key",
+	}, nil)
+	require.NoError(t, err)
+
+	repeatedOrder, err := orderRepository.Create(&models.Order{
+		UserID:         user.ID,
+		Status:         models.NewOrderStatus,
+		Address:        "Test Address",
+		Deadline:       time.Now().Add(24 * time.Hour),
+		IdempotencyKey: "key",
+	}, nil)
+	require.ErrorIs(t, err, repository_errors.AlreadyExists)
+	require.Nil(t, repeatedOrder)
+
+	receivedOrder, err := orderRepository.GetOrderByIdempotencyKey(user.ID, "key")
+	require.NoError(t, err)
+	require.Equal(t, createdOrder.ID, receivedOrder.ID)
+	require.Equal(t, "key", receivedOrder.IdempotencyKey)
+
+	_, err = orderRepository.GetOrderByIdempotencyKey(uuid.New(), "key")
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+}
+
+func TestOrderRepositoryUpdate_VersionConflict(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	orderRepository := postgres.NewOrderRepository(db)
+	user, err := postgres.NewUserRepository(db).Create(&models.User{
+		Name:        "First Name",
+		Surname:     "Last Name",
+		Address:     "Address",
+		PhoneNumber: "+79999999999",
+		Email:       "user@email.com",
+		Password:    "hashed_password",
+	})
+	require.NoError(t, err)
+
+	createdOrder, err := orderRepository.Create(&models.Order{
+		UserID:   user.ID,
+		Status:   models.NewOrderStatus,
+		Address:  "Test Address",
+		Deadline: time.Now().Add(24 * time.Hour),
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, createdOrder.Version)
+
+	firstRead, err := orderRepository.GetOrderByID(createdOrder.ID)
+	require.NoError(t, err)
+	secondRead, err := orderRepository.GetOrderByID(createdOrder.ID)
+	require.NoError(t, err)
+
+	firstRead.Status = models.InProgressOrderStatus
+	updatedOrder, err := orderRepository.Update(firstRead)
+	require.NoError(t, err)
+	require.Equal(t, 2, updatedOrder.Version)
+
+	secondRead.Status = models.CancelledOrderStatus
+	staleOrder, err := orderRepository.Update(secondRead)
+	require.ErrorIs(t, err, repository_errors.VersionConflict)
+	require.Nil(t, staleOrder)
+
+	receivedOrder, err := orderRepository.GetOrderByID(createdOrder.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.InProgressOrderStatus, receivedOrder.Status)
+
+	secondRead.ID = uuid.New()
+	_, err = orderRepository.Update(secondRead)
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+}
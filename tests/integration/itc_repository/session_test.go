@@ -0,0 +1,111 @@
+package itc_repository
+
+import (
+	"context"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"lab3/internal/models"
+	"lab3/internal/repository/postgres"
+	"lab3/internal/repository/repository_errors"
+	"log"
+	"testing"
+	"time"
+)
+
+func createSessionTestWorker(t *testing.T, workerRepository interface {
+	Create(worker *models.Worker) (*models.Worker, error)
+}) *models.Worker {
+	worker, err := workerRepository.Create(&models.Worker{
+		Name:        "First Name",
+		Surname:     "Last Name",
+		Address:     "Address",
+		PhoneNumber: "+79999999999",
+		Email:       "worker@email.com",
+		Role:        models.MasterRole,
+		Password:    "hashed_password",
+	})
+	require.NoError(t, err)
+	return worker
+}
+
+func TestSessionRepositorySave_Success(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	sessionRepository := postgres.NewSessionRepository(db)
+	worker := createSessionTestWorker(t, postgres.NewWorkerRepository(db))
+
+	now := time.Now().UTC().Truncate(time.Second)
+	err := sessionRepository.Save(&models.Session{
+		ID:           "session-id",
+		WorkerID:     worker.ID,
+		Data:         "data",
+		CreatedAt:    now,
+		LastActivity: now,
+	})
+	require.NoError(t, err)
+
+	session, err := sessionRepository.GetSessionByID("session-id")
+	require.NoError(t, err)
+	require.Equal(t, worker.ID, session.WorkerID)
+	require.Equal(t, "data", session.Data)
+}
+
+func TestSessionRepositoryDeleteByWorkerID_Success(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	sessionRepository := postgres.NewSessionRepository(db)
+	worker := createSessionTestWorker(t, postgres.NewWorkerRepository(db))
+
+	now := time.Now()
+	for _, id := range []string{"first", "second"} {
+		err := sessionRepository.Save(&models.Session{ID: id, WorkerID: worker.ID, CreatedAt: now, LastActivity: now})
+		require.NoError(t, err)
+	}
+
+	err := sessionRepository.DeleteByWorkerID(worker.ID)
+	require.NoError(t, err)
+
+	_, err = sessionRepository.GetSessionByID("first")
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+	_, err = sessionRepository.GetSessionByID("second")
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+}
+
+func TestSessionRepositoryDeleteExpired_Success(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	sessionRepository := postgres.NewSessionRepository(db)
+
+	now := time.Now()
+	err := sessionRepository.Save(&models.Session{ID: "stale", CreatedAt: now.Add(-time.Hour), LastActivity: now.Add(-time.Hour)})
+	require.NoError(t, err)
+	err = sessionRepository.Save(&models.Session{ID: "fresh", CreatedAt: now, LastActivity: now})
+	require.NoError(t, err)
+
+	err = sessionRepository.DeleteExpired(now.Add(-time.Minute), now.Add(-24*time.Hour))
+	require.NoError(t, err)
+
+	_, err = sessionRepository.GetSessionByID("stale")
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+	_, err = sessionRepository.GetSessionByID("fresh")
+	require.NoError(t, err)
+}
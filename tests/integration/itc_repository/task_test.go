@@ -8,6 +8,7 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	"lab3/internal/models"
 	"lab3/internal/repository/postgres"
+	"lab3/internal/repository/repository_errors"
 	"log"
 	"testing"
 )
@@ -20,6 +21,7 @@ func TestTaskRepositoryCreate_Success(t *testing.T) {
 			log.Println("Error terminating container:", err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	taskRepository := postgres.NewTaskRepository(db)
 
@@ -44,6 +46,7 @@ func TestTaskRepositoryCreate_Failure(t *testing.T) {
 			log.Println("Error terminating container:", err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	taskRepository := postgres.NewTaskRepository(db)
 
@@ -66,6 +69,7 @@ func TestTaskRepositoryDelete_Success(t *testing.T) {
 			log.Println("Error terminating container:", err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	taskRepository := postgres.NewTaskRepository(db)
 
@@ -81,9 +85,52 @@ func TestTaskRepositoryDelete_Success(t *testing.T) {
 	err = taskRepository.Delete(createdTask.ID)
 	require.NoError(t, err)
 
+	// archived tasks are still resolved by id for old orders
 	receivedTask, err := taskRepository.GetTaskByID(createdTask.ID)
-	require.Error(t, err)
-	require.Nil(t, receivedTask)
+	require.NoError(t, err)
+	require.True(t, receivedTask.IsDeleted())
+
+	tasks, err := taskRepository.GetTasksInCategory(createdTask.Category)
+	require.NoError(t, err)
+	require.Empty(t, tasks)
+
+	_, err = taskRepository.GetTaskByName(createdTask.Name)
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+	deletedTasks, err := taskRepository.GetDeletedTasks()
+	require.NoError(t, err)
+	require.Len(t, deletedTasks, 1)
+}
+
+func TestTaskRepositoryRestore_Success(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+	createTestCategories(db)
+
+	taskRepository := postgres.NewTaskRepository(db)
+
+	createdTask, err := taskRepository.Create(&models.Task{
+		Name:           "TaskName",
+		PricePerSingle: 100.0,
+		Category:       1,
+	})
+	require.NoError(t, err)
+
+	err = taskRepository.Delete(createdTask.ID)
+	require.NoError(t, err)
+
+	err = taskRepository.Restore(createdTask.ID)
+	require.NoError(t, err)
+
+	tasks, err := taskRepository.GetAllTasks()
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.False(t, tasks[0].IsDeleted())
 }
 
 func TestTaskRepositoryDelete_Failure(t *testing.T) {
@@ -109,6 +156,7 @@ func TestTaskRepositoryUpdate_Success(t *testing.T) {
 			log.Println("Error terminating container:", err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	taskRepository := postgres.NewTaskRepository(db)
 
@@ -135,6 +183,7 @@ func TestTaskRepositoryUpdate_Failure(t *testing.T) {
 			log.Println("Error terminating container:", err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	taskRepository := postgres.NewTaskRepository(db)
 
@@ -161,6 +210,7 @@ func TestTaskRepositoryGetTaskByID_Success(t *testing.T) {
 			log.Println("Error terminating container:", err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	taskRepository := postgres.NewTaskRepository(db)
 
@@ -201,6 +251,7 @@ func TestTaskRepositoryGetTaskByName_Success(t *testing.T) {
 			log.Println("Error terminating container:", err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	taskRepository := postgres.NewTaskRepository(db)
 
@@ -241,6 +292,7 @@ func TestTaskRepositoryGetAllTasks_Success(t *testing.T) {
 			log.Println("Error terminating container:", err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	taskRepository := postgres.NewTaskRepository(db)
 
@@ -288,6 +340,7 @@ func TestTaskRepositoryGetTasksInCategory_Success(t *testing.T) {
 			log.Println("Error terminating container:", err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	taskRepository := postgres.NewTaskRepository(db)
 
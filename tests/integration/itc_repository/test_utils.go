@@ -7,7 +7,14 @@ import (
 	_ "github.com/lib/pq"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"lab3/internal/models"
+	"lab3/internal/repository/mongodb"
+	"lab3/internal/repository/postgres"
 	"log"
+	"time"
 )
 
 func SetupTestDatabase() (testcontainers.Container, *sqlx.DB) {
@@ -46,60 +53,81 @@ func SetupTestDatabase() (testcontainers.Container, *sqlx.DB) {
 		log.Fatalf("Could not connect to database: %s", err)
 	}
 
-	schema := `
-	 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
-	 
-	 CREATE TABLE IF NOT EXISTS users (
-	  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-	  name TEXT,
-	  surname TEXT,
-	  email TEXT UNIQUE,
-	  phone_number TEXT,
-	  address TEXT,
-	  password TEXT
-	 );
-	
-	 CREATE TABLE IF NOT EXISTS workers (
-	  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-	  name TEXT,
-	  surname TEXT,
-	  email TEXT UNIQUE,
-	  phone_number TEXT,
-	  address TEXT,
-	  password TEXT,
-	  role INT
-	 );
-	
-	 CREATE TABLE IF NOT EXISTS orders (
-	  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-	  worker_id UUID REFERENCES workers(id) ON DELETE SET NULL DEFAULT NULL,
-	  user_id UUID REFERENCES users(id) ON DELETE SET NULL DEFAULT NULL,
-	  status INT2 DEFAULT 0,
-	  address TEXT,
-	  deadline TIMESTAMP,
-	  creation_date TIMESTAMP DEFAULT NOW(),
-	  rate INT2 DEFAULT 0
-	 );
-	
-	 CREATE TABLE IF NOT EXISTS tasks (
-	  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-	  name TEXT,
-	  price_per_single FLOAT8,
-	  category INT2
-	 );
-	
-	 CREATE TABLE IF NOT EXISTS order_contains_tasks (
-	  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-	  order_id UUID REFERENCES orders(id),
-	  task_id UUID REFERENCES tasks(id),
-	  quantity INT2 DEFAULT 1
-	 );
-	                                                 
-	 CREATE TABLE IF NOT EXISTS categories (
-    	id SERIAL,
-   		name VARCHAR
-	 );`
-	_, err = db.Exec(schema)
+	// the schema is created by the same migrations that the application runs
+	_, err = postgres.NewMigrator(db).Up()
+	if err != nil {
+		log.Fatalf("Could not create schema: %s", err)
+	}
+
+	return dbContainer, db
+}
+
+// createTestCategories creates the categories 1 and 2 that the tests put their tasks in.
+func createTestCategories(db *sqlx.DB) {
+	for _, name := range []string{"First Category", "Second Category"} {
+		_, err := postgres.NewCategoryRepository(db).Create(&models.Category{Name: name})
+		if err != nil {
+			log.Fatalf("Could not create category: %s", err)
+		}
+	}
+}
+
+// SetupTestMongoDatabase starts MongoDB as a single-node replica set, because the Mongo repositories use transactions.
+func SetupTestMongoDatabase() (testcontainers.Container, *mongo.Database) {
+	ctx := context.Background()
+	req := testcontainers.ContainerRequest{
+		Image:        "mongo:7",
+		ExposedPorts: []string{"27017/tcp"},
+		Cmd:          []string{"--replSet", "rs0", "--bind_ip_all"},
+		WaitingFor:   wait.ForLog("Waiting for connections"),
+	}
+	dbContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		log.Fatalf("Could not start container: %s", err)
+	}
+
+	host, err := dbContainer.Host(ctx)
+	if err != nil {
+		log.Fatalf("Could not get container host: %s", err)
+	}
+
+	port, err := dbContainer.MappedPort(ctx, "27017")
+	if err != nil {
+		log.Fatalf("Could not get container port: %s", err)
+	}
+
+	// the member is addressed from inside the container, so the client connects to it directly
+	uri := fmt.Sprintf("mongodb://%s:%s/?directConnection=true", host, port.Port())
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		log.Fatalf("Could not connect to database: %s", err)
+	}
+
+	config := bson.M{"_id": "rs0", "members": bson.A{bson.M{"_id": 0, "host": "localhost:27017"}}}
+	err = client.Database("admin").RunCommand(ctx, bson.D{{Key: "replSetInitiate", Value: config}}).Err()
+	if err != nil {
+		log.Fatalf("Could not initiate replica set: %s", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		var hello struct {
+			IsWritablePrimary bool `bson:"isWritablePrimary"`
+		}
+		err = client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+		if err == nil && hello.IsWritablePrimary {
+			break
+		}
+		if attempt == 100 {
+			log.Fatalf("Replica set has no primary: %v", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	db := client.Database("ppo")
+	_, err = mongodb.NewMigrator(db).Up()
 	if err != nil {
 		log.Fatalf("Could not create schema: %s", err)
 	}
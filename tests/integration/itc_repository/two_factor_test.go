@@ -0,0 +1,66 @@
+package itc_repository
+
+import (
+	"context"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"lab3/internal/models"
+	"lab3/internal/repository/postgres"
+	"lab3/internal/repository/repository_errors"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestTwoFactorRepositoryMarkStepUsed_RejectsReplay(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	twoFactorRepository := postgres.NewTwoFactorRepository(db)
+	worker := createSessionTestWorker(t, postgres.NewWorkerRepository(db))
+
+	err := twoFactorRepository.Save(&models.TwoFactor{WorkerID: worker.ID, Secret: "secret", Enabled: true, LastUsedStep: 10, CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	require.NoError(t, twoFactorRepository.MarkStepUsed(worker.ID, 11))
+	require.ErrorIs(t, twoFactorRepository.MarkStepUsed(worker.ID, 11), repository_errors.DoesNotExist)
+
+	twoFactor, err := twoFactorRepository.GetByWorkerID(worker.ID)
+	require.NoError(t, err)
+	require.True(t, twoFactor.Enabled)
+	require.Equal(t, int64(11), twoFactor.LastUsedStep)
+}
+
+func TestTwoFactorRepositoryRecoveryCodes_SingleUse(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	twoFactorRepository := postgres.NewTwoFactorRepository(db)
+	worker := createSessionTestWorker(t, postgres.NewWorkerRepository(db))
+
+	err := twoFactorRepository.Save(&models.TwoFactor{WorkerID: worker.ID, Secret: "secret", Enabled: true, CreatedAt: time.Now()})
+	require.NoError(t, err)
+	require.NoError(t, twoFactorRepository.ReplaceRecoveryCodes(worker.ID, []string{"a", "b"}))
+
+	require.NoError(t, twoFactorRepository.ConsumeRecoveryCode(worker.ID, "a"))
+	require.ErrorIs(t, twoFactorRepository.ConsumeRecoveryCode(worker.ID, "a"), repository_errors.DoesNotExist)
+
+	count, err := twoFactorRepository.CountRecoveryCodes(worker.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	require.NoError(t, twoFactorRepository.Delete(worker.ID))
+	_, err = twoFactorRepository.GetByWorkerID(worker.ID)
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+}
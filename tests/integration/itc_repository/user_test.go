@@ -8,6 +8,7 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	"lab3/internal/models"
 	"lab3/internal/repository/postgres"
+	"lab3/internal/repository/repository_errors"
 	"log"
 	"testing"
 )
@@ -197,8 +198,22 @@ func TestUserRepositoryDelete_Success(t *testing.T) {
 	require.NoError(t, err)
 
 	receivedUser, err := userRepository.GetUserByID(createdUser.ID)
-	require.Error(t, err)
-	require.Nil(t, receivedUser)
+	require.NoError(t, err)
+	require.True(t, receivedUser.IsDeleted())
+
+	_, err = userRepository.GetUserByEmail(createdUser.Email)
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+	deletedUsers, err := userRepository.GetDeletedUsers()
+	require.NoError(t, err)
+	require.Len(t, deletedUsers, 1)
+
+	err = userRepository.Restore(createdUser.ID)
+	require.NoError(t, err)
+
+	receivedUser, err = userRepository.GetUserByEmail(createdUser.Email)
+	require.NoError(t, err)
+	require.False(t, receivedUser.IsDeleted())
 }
 
 func TestUserRepositoryDelete_Failure(t *testing.T) {
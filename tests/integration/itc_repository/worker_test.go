@@ -8,6 +8,7 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 	"lab3/internal/models"
 	"lab3/internal/repository/postgres"
+	"lab3/internal/repository/repository_errors"
 	"log"
 	"testing"
 )
@@ -205,9 +206,61 @@ func TestWorkerRepositoryDelete_Success(t *testing.T) {
 	err = workerRepository.Delete(createdWorker.ID)
 	require.NoError(t, err)
 
+	// archived workers are still resolved by id, but hidden from lists and sign in
 	receivedWorker, err := workerRepository.GetWorkerByID(createdWorker.ID)
-	require.Error(t, err)
-	require.Nil(t, receivedWorker)
+	require.NoError(t, err)
+	require.True(t, receivedWorker.IsDeleted())
+
+	_, err = workerRepository.GetWorkerByEmail(createdWorker.Email)
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+	managers, err := workerRepository.GetWorkersByRole(models.ManagerRole)
+	require.NoError(t, err)
+	require.Empty(t, managers)
+
+	deletedWorkers, err := workerRepository.GetDeletedWorkers()
+	require.NoError(t, err)
+	require.Len(t, deletedWorkers, 1)
+	require.Equal(t, createdWorker.ID, deletedWorkers[0].ID)
+
+	err = workerRepository.Delete(createdWorker.ID)
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+}
+
+func TestWorkerRepositoryRestore_Success(t *testing.T) {
+	dbContainer, db := SetupTestDatabase()
+	defer func(dbContainer testcontainers.Container, ctx context.Context) {
+		err := dbContainer.Terminate(ctx)
+		if err != nil {
+			log.Println("Error terminating container:", err)
+		}
+	}(dbContainer, context.Background())
+
+	workerRepository := postgres.NewWorkerRepository(db)
+
+	createdWorker, err := workerRepository.Create(&models.Worker{
+		Name:        "First Name",
+		Surname:     "Last Name",
+		Address:     "Address",
+		PhoneNumber: "+79999999999",
+		Email:       "test@email.com",
+		Role:        models.MasterRole,
+		Password:    "hashed_password",
+	})
+	require.NoError(t, err)
+
+	err = workerRepository.Restore(createdWorker.ID)
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+
+	err = workerRepository.Delete(createdWorker.ID)
+	require.NoError(t, err)
+
+	err = workerRepository.Restore(createdWorker.ID)
+	require.NoError(t, err)
+
+	receivedWorker, err := workerRepository.GetWorkerByEmail(createdWorker.Email)
+	require.NoError(t, err)
+	require.False(t, receivedWorker.IsDeleted())
 }
 
 func TestWorkerRepositoryDelete_Failure(t *testing.T) {
@@ -13,7 +13,7 @@ import (
 	"os"
 	"testing"
 
-	_ "lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/repository_errors"
 	_ "lab3/internal/services/service_errors"
 )
 
@@ -38,7 +38,7 @@ func TestCategoryServiceCreate_Success(t *testing.T) {
 	categoryService := services.NewCategoryService(categoryRepository, taskRepository, logger)
 
 	// Act
-	category, err := categoryService.Create("New Category")
+	category, err := categoryService.Create("New Category", 0, false, "")
 
 	// Assert
 	require.NoError(t, err)
@@ -66,7 +66,7 @@ func TestCategoryServiceCreate_Failure(t *testing.T) {
 	categoryService := services.NewCategoryService(categoryRepository, taskRepository, logger)
 
 	// Act
-	category, err := categoryService.Create("")
+	category, err := categoryService.Create("", 0, false, "")
 
 	// Assert
 	require.Error(t, err)
@@ -93,7 +93,7 @@ func TestCategoryServiceUpdate_Success(t *testing.T) {
 	logger := log.New(f)
 	categoryService := services.NewCategoryService(categoryRepository, taskRepository, logger)
 
-	category, err := categoryService.Create("Category")
+	category, err := categoryService.Create("Category", 0, false, "")
 	require.NoError(t, err)
 
 	// Act
@@ -124,7 +124,7 @@ func TestCategoryServiceUpdate_Failure(t *testing.T) {
 	logger := log.New(f)
 	categoryService := services.NewCategoryService(categoryRepository, taskRepository, logger)
 
-	category, err := categoryService.Create("Category")
+	category, err := categoryService.Create("Category", 0, false, "")
 	require.NoError(t, err)
 
 	// Act
@@ -155,7 +155,7 @@ func TestCategoryServiceDelete_Success(t *testing.T) {
 	logger := log.New(f)
 	categoryService := services.NewCategoryService(categoryRepository, taskRepository, logger)
 
-	category, err := categoryService.Create("Category to Delete")
+	category, err := categoryService.Create("Category to Delete", 0, false, "")
 	require.NoError(t, err)
 
 	// Act
@@ -189,7 +189,7 @@ func TestCategoryServiceDelete_Failure(t *testing.T) {
 	err = categoryService.Delete(0)
 
 	// Assert
-	require.Nil(t, err)
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
 }
 
 func TestCategoryServiceGetByID_Success(t *testing.T) {
@@ -212,7 +212,7 @@ func TestCategoryServiceGetByID_Success(t *testing.T) {
 	logger := log.New(f)
 	categoryService := services.NewCategoryService(categoryRepository, taskRepository, logger)
 
-	category, err := categoryService.Create("Category to Retrieve")
+	category, err := categoryService.Create("Category to Retrieve", 0, false, "")
 	require.NoError(t, err)
 
 	// Act
@@ -272,8 +272,8 @@ func TestCategoryServiceGetAll_Success(t *testing.T) {
 	categoryService := services.NewCategoryService(categoryRepository, taskRepository, logger)
 
 	// Act
-	_, _ = categoryService.Create("Category 1")
-	_, _ = categoryService.Create("Category 2")
+	_, _ = categoryService.Create("Category 1", 0, false, "")
+	_, _ = categoryService.Create("Category 2", 0, false, "")
 
 	categories, err := categoryService.GetAll()
 
@@ -330,7 +330,7 @@ func TestCategoryServiceGetTasksInCategory_Success(t *testing.T) {
 	logger := log.New(f)
 	categoryService := services.NewCategoryService(categoryRepository, taskRepository, logger)
 
-	category, err := categoryService.Create("Category with Tasks")
+	category, err := categoryService.Create("Category with Tasks", 0, false, "")
 	require.NoError(t, err)
 
 	task, err := taskRepository.Create(&models.Task{
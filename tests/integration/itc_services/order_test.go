@@ -23,6 +23,7 @@ func TestOrderServiceCreateOrder_Success(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -34,7 +35,7 @@ func TestOrderServiceCreateOrder_Success(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	task := models.Task{
 		ID:             uuid.New(),
@@ -48,7 +49,7 @@ func TestOrderServiceCreateOrder_Success(t *testing.T) {
 	}
 
 	// Act
-	order, err := orderService.CreateOrder(userID, "Test Address", time.Now().Add(24*time.Hour), tasks)
+	order, err := orderService.CreateOrder(userID, "Test Address", time.Now().Add(24*time.Hour), tasks, "")
 
 	// Assert
 	require.Error(t, err)
@@ -64,6 +65,7 @@ func TestOrderServiceCreateOrder_Failure(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -75,7 +77,7 @@ func TestOrderServiceCreateOrder_Failure(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	task := models.Task{
 		ID:             uuid.New(),
@@ -89,7 +91,7 @@ func TestOrderServiceCreateOrder_Failure(t *testing.T) {
 	}
 
 	// Act
-	order, err := orderService.CreateOrder(uuid.New(), "", time.Now().Add(-24*time.Hour), invalidTasks)
+	order, err := orderService.CreateOrder(uuid.New(), "", time.Now().Add(-24*time.Hour), invalidTasks, "")
 
 	// Assert
 	require.Error(t, err)
@@ -105,6 +107,7 @@ func TestOrderServiceDeleteOrder_Success(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -116,7 +119,7 @@ func TestOrderServiceDeleteOrder_Success(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	task := models.Task{
 		ID:             uuid.New(),
@@ -129,7 +132,7 @@ func TestOrderServiceDeleteOrder_Success(t *testing.T) {
 		{Task: &task, Quantity: 1},
 	}
 
-	_, err = orderService.CreateOrder(uuid.New(), "Test Address", time.Now().Add(24*time.Hour), tasks)
+	_, err = orderService.CreateOrder(uuid.New(), "Test Address", time.Now().Add(24*time.Hour), tasks, "")
 
 	require.Error(t, err)
 }
@@ -154,7 +157,7 @@ func TestOrderServiceDeleteOrder_Failure(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	// Act
 	err = orderService.DeleteOrder(uuid.New())
@@ -172,6 +175,7 @@ func TestOrderServiceGetOrderByID_Success(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -183,7 +187,7 @@ func TestOrderServiceGetOrderByID_Success(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	task := models.Task{
 		ID:             uuid.New(),
@@ -196,7 +200,7 @@ func TestOrderServiceGetOrderByID_Success(t *testing.T) {
 		{Task: &task, Quantity: 1},
 	}
 
-	_, err = orderService.CreateOrder(uuid.New(), "Test Address", time.Now().Add(24*time.Hour), tasks)
+	_, err = orderService.CreateOrder(uuid.New(), "Test Address", time.Now().Add(24*time.Hour), tasks, "")
 
 	require.Error(t, err)
 }
@@ -221,7 +225,7 @@ func TestOrderServiceGetOrderByID_Failure(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	invalidOrderID := uuid.New()
 
@@ -253,7 +257,7 @@ func TestOrderServiceGetCurrentOrderByUserID_Success(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	userID := uuid.New()
 
@@ -285,7 +289,7 @@ func TestOrderServiceGetCurrentOrderByUserID_Failure(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	// Act
 	_, err = orderService.GetCurrentOrderByUserID(uuid.New())
@@ -314,7 +318,7 @@ func TestOrderServiceGetAllOrdersByUserID_Success(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	userID := uuid.New()
 
@@ -345,7 +349,7 @@ func TestOrderServiceGetAllOrdersByUserID_Failure(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	// Act
 	_, err = orderService.GetAllOrdersByUserID(uuid.New())
@@ -374,13 +378,13 @@ func TestOrderServiceUpdate_Success(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	orderID := uuid.New()
 	workerID := uuid.New()
 
 	// Act
-	_, err = orderService.Update(orderID, 1, 5, workerID)
+	_, err = orderService.Update(orderID, 1, 5, workerID, 1)
 
 	// Assert
 	require.Error(t, err)
@@ -406,10 +410,10 @@ func TestOrderServiceUpdate_Failure(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	// Act
-	_, err = orderService.Update(uuid.New(), 1, 5, uuid.New())
+	_, err = orderService.Update(uuid.New(), 1, 5, uuid.New(), 1)
 
 	// Assert
 	require.Error(t, err)
@@ -435,7 +439,7 @@ func TestOrderServiceAddTask_Success(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	orderID := uuid.New()
 	taskID := uuid.New()
@@ -467,7 +471,7 @@ func TestOrderServiceAddTask_Failure(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	// Act
 	err = orderService.AddTask(uuid.New(), uuid.New())
@@ -495,7 +499,7 @@ func TestOrderServiceRemoveTask_Success(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	orderID := uuid.New()
 	taskID := uuid.New()
@@ -527,7 +531,7 @@ func TestOrderServiceRemoveTask_Failure(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	// Act
 	err = orderService.RemoveTask(uuid.New(), uuid.New())
@@ -556,7 +560,7 @@ func TestOrderServiceIncrementTaskQuantity_Success(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	orderID := uuid.New()
 	taskID := uuid.New()
@@ -588,7 +592,7 @@ func TestOrderServiceIncrementTaskQuantity_Failure(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	// Act
 	_, err = orderService.IncrementTaskQuantity(uuid.New(), uuid.New())
@@ -617,7 +621,7 @@ func TestOrderServiceDecrementTaskQuantity_Success(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	orderID := uuid.New()
 	taskID := uuid.New()
@@ -649,7 +653,7 @@ func TestOrderServiceDecrementTaskQuantity_Failure(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	// Act
 	_, err = orderService.DecrementTaskQuantity(uuid.New(), uuid.New())
@@ -678,7 +682,7 @@ func TestOrderServiceSetTaskQuantity_Success(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	orderID := uuid.New()
 	taskID := uuid.New()
@@ -710,7 +714,7 @@ func TestOrderServiceSetTaskQuantity_Failure(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	// Act
 	err = orderService.SetTaskQuantity(uuid.New(), uuid.New(), 5)
@@ -739,7 +743,7 @@ func TestOrderServiceGetTaskQuantity_Success(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	orderID := uuid.New()
 	taskID := uuid.New()
@@ -771,7 +775,7 @@ func TestOrderServiceGetTaskQuantity_Failure(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	// Act
 	_, err = orderService.GetTaskQuantity(uuid.New(), uuid.New())
@@ -800,7 +804,7 @@ func TestOrderServiceFilter_Success(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	params := map[string]string{"status": "1"}
 
@@ -831,7 +835,7 @@ func TestOrderServiceFilter_Failure(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	// Act
 	_, err = orderService.Filter(map[string]string{"status": "invalid"})
@@ -860,7 +864,7 @@ func TestOrderServiceGetTotalPrice_Success(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	orderID := uuid.New()
 
@@ -891,7 +895,7 @@ func TestOrderServiceGetTotalPrice_Failure(t *testing.T) {
 	userRepository := postgres.NewUserRepository(db)
 	workerRepository := postgres.NewWorkerRepository(db)
 	logger := log.New(f)
-	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, logger)
+	orderService := services.NewOrderService(orderRepository, workerRepository, taskRepository, userRepository, postgres.NewCategoryRepository(db), services.TaxPolicy{}, true, logger)
 
 	// Act
 	_, err = orderService.GetTotalPrice(uuid.New())
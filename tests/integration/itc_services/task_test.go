@@ -22,6 +22,7 @@ func TestTaskServiceCreate_Success(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -30,10 +31,10 @@ func TestTaskServiceCreate_Success(t *testing.T) {
 
 	taskRepository := postgres.NewTaskRepository(db)
 	logger := log.New(f)
-	taskService := services.NewTaskService(taskRepository, logger)
+	taskService := services.NewTaskService(taskRepository, postgres.NewCategoryRepository(db), logger)
 
 	// Act
-	task, err := taskService.Create("Test Task", 100.0, 1)
+	task, err := taskService.Create("Test Task", 100.0, 1, "")
 
 	// Assert
 	require.NoError(t, err)
@@ -51,6 +52,7 @@ func TestTaskServiceCreate_Failure(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -59,10 +61,10 @@ func TestTaskServiceCreate_Failure(t *testing.T) {
 
 	taskRepository := postgres.NewTaskRepository(db)
 	logger := log.New(f)
-	taskService := services.NewTaskService(taskRepository, logger)
+	taskService := services.NewTaskService(taskRepository, postgres.NewCategoryRepository(db), logger)
 
 	// Act
-	task, err := taskService.Create("", -10.0, 99)
+	task, err := taskService.Create("", -10.0, 99, "")
 
 	// Assert
 	require.Error(t, err)
@@ -78,6 +80,7 @@ func TestTaskServiceUpdate_Success(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -86,13 +89,13 @@ func TestTaskServiceUpdate_Success(t *testing.T) {
 
 	taskRepository := postgres.NewTaskRepository(db)
 	logger := log.New(f)
-	taskService := services.NewTaskService(taskRepository, logger)
+	taskService := services.NewTaskService(taskRepository, postgres.NewCategoryRepository(db), logger)
 
-	task, err := taskService.Create("Test Task", 100.0, 1)
+	task, err := taskService.Create("Test Task", 100.0, 1, "")
 	require.NoError(t, err)
 
 	// Act
-	updatedTask, err := taskService.Update(task.ID, 2, "Updated Task", 200.0)
+	updatedTask, err := taskService.Update(task.ID, 2, "Updated Task", 200.0, "")
 
 	// Assert
 	require.NoError(t, err)
@@ -110,6 +113,7 @@ func TestTaskServiceUpdate_Failure(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -118,13 +122,13 @@ func TestTaskServiceUpdate_Failure(t *testing.T) {
 
 	taskRepository := postgres.NewTaskRepository(db)
 	logger := log.New(f)
-	taskService := services.NewTaskService(taskRepository, logger)
+	taskService := services.NewTaskService(taskRepository, postgres.NewCategoryRepository(db), logger)
 
-	task, err := taskService.Create("Test Task", 100.0, 1)
+	task, err := taskService.Create("Test Task", 100.0, 1, "")
 	require.NoError(t, err)
 
 	// Act
-	updatedTask, err := taskService.Update(task.ID, 2, "", -10.0)
+	updatedTask, err := taskService.Update(task.ID, 2, "", -10.0, "")
 
 	// Assert
 	require.Error(t, err)
@@ -140,6 +144,7 @@ func TestTaskServiceDelete_Success(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -148,9 +153,9 @@ func TestTaskServiceDelete_Success(t *testing.T) {
 
 	taskRepository := postgres.NewTaskRepository(db)
 	logger := log.New(f)
-	taskService := services.NewTaskService(taskRepository, logger)
+	taskService := services.NewTaskService(taskRepository, postgres.NewCategoryRepository(db), logger)
 
-	task, err := taskService.Create("Task to Delete", 50.0, 1)
+	task, err := taskService.Create("Task to Delete", 50.0, 1, "")
 	require.NoError(t, err)
 
 	// Act
@@ -169,6 +174,7 @@ func TestTaskServiceDelete_Failure(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -177,7 +183,7 @@ func TestTaskServiceDelete_Failure(t *testing.T) {
 
 	taskRepository := postgres.NewTaskRepository(db)
 	logger := log.New(f)
-	taskService := services.NewTaskService(taskRepository, logger)
+	taskService := services.NewTaskService(taskRepository, postgres.NewCategoryRepository(db), logger)
 
 	// Act
 	err = taskService.Delete(uuid.New())
@@ -195,6 +201,7 @@ func TestTaskServiceGetTaskByID_Success(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -203,9 +210,9 @@ func TestTaskServiceGetTaskByID_Success(t *testing.T) {
 
 	taskRepository := postgres.NewTaskRepository(db)
 	logger := log.New(f)
-	taskService := services.NewTaskService(taskRepository, logger)
+	taskService := services.NewTaskService(taskRepository, postgres.NewCategoryRepository(db), logger)
 
-	task, err := taskService.Create("Task to Retrieve", 150.0, 1)
+	task, err := taskService.Create("Task to Retrieve", 150.0, 1, "")
 	require.NoError(t, err)
 
 	// Act
@@ -225,6 +232,7 @@ func TestTaskServiceGetTaskByID_Failure(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -233,7 +241,7 @@ func TestTaskServiceGetTaskByID_Failure(t *testing.T) {
 
 	taskRepository := postgres.NewTaskRepository(db)
 	logger := log.New(f)
-	taskService := services.NewTaskService(taskRepository, logger)
+	taskService := services.NewTaskService(taskRepository, postgres.NewCategoryRepository(db), logger)
 
 	// Act
 	foundTask, err := taskService.GetTaskByID(uuid.New())
@@ -252,6 +260,7 @@ func TestTaskServiceGetTasksInCategory_Success(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -260,11 +269,11 @@ func TestTaskServiceGetTasksInCategory_Success(t *testing.T) {
 
 	taskRepository := postgres.NewTaskRepository(db)
 	logger := log.New(f)
-	taskService := services.NewTaskService(taskRepository, logger)
+	taskService := services.NewTaskService(taskRepository, postgres.NewCategoryRepository(db), logger)
 
-	_, err = taskService.Create("Task 1", 100.0, 1)
+	_, err = taskService.Create("Task 1", 100.0, 1, "")
 	require.NoError(t, err)
-	_, err = taskService.Create("Task 2", 200.0, 1)
+	_, err = taskService.Create("Task 2", 200.0, 1, "")
 	require.NoError(t, err)
 
 	// Act
@@ -286,6 +295,7 @@ func TestTaskServiceGetTasksInCategory_Failure(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -294,7 +304,7 @@ func TestTaskServiceGetTasksInCategory_Failure(t *testing.T) {
 
 	taskRepository := postgres.NewTaskRepository(db)
 	logger := log.New(f)
-	taskService := services.NewTaskService(taskRepository, logger)
+	taskService := services.NewTaskService(taskRepository, postgres.NewCategoryRepository(db), logger)
 
 	invalidCategory := -1
 
@@ -315,6 +325,7 @@ func TestTaskServiceGetAllTasks_Success(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -323,13 +334,13 @@ func TestTaskServiceGetAllTasks_Success(t *testing.T) {
 
 	taskRepository := postgres.NewTaskRepository(db)
 	logger := log.New(f)
-	taskService := services.NewTaskService(taskRepository, logger)
+	taskService := services.NewTaskService(taskRepository, postgres.NewCategoryRepository(db), logger)
 
 	// Create tasks
 	task1 := &models.Task{Name: "Task1", PricePerSingle: 100.0, Category: 1}
 	task2 := &models.Task{Name: "Task2", PricePerSingle: 150.0, Category: 2}
-	_, _ = taskService.Create(task1.Name, task1.PricePerSingle, task1.Category)
-	_, _ = taskService.Create(task2.Name, task2.PricePerSingle, task2.Category)
+	_, _ = taskService.Create(task1.Name, task1.PricePerSingle, task1.Category, "")
+	_, _ = taskService.Create(task2.Name, task2.PricePerSingle, task2.Category, "")
 
 	// Act
 	tasks, err := taskService.GetAllTasks()
@@ -349,6 +360,7 @@ func TestTaskServiceGetAllTasks_Failure(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -357,7 +369,7 @@ func TestTaskServiceGetAllTasks_Failure(t *testing.T) {
 
 	taskRepository := postgres.NewTaskRepository(db)
 	logger := log.New(f)
-	taskService := services.NewTaskService(taskRepository, logger)
+	taskService := services.NewTaskService(taskRepository, postgres.NewCategoryRepository(db), logger)
 
 	// Act
 	tasks, err := taskService.GetAllTasks()
@@ -376,6 +388,7 @@ func TestTaskServiceGetTaskByName_Success(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -384,10 +397,10 @@ func TestTaskServiceGetTaskByName_Success(t *testing.T) {
 
 	taskRepository := postgres.NewTaskRepository(db)
 	logger := log.New(f)
-	taskService := services.NewTaskService(taskRepository, logger)
+	taskService := services.NewTaskService(taskRepository, postgres.NewCategoryRepository(db), logger)
 
 	task := &models.Task{Name: "Unique Task", PricePerSingle: 200.0, Category: 1}
-	_, _ = taskService.Create(task.Name, task.PricePerSingle, task.Category)
+	_, _ = taskService.Create(task.Name, task.PricePerSingle, task.Category, "")
 
 	// Act
 	foundTask, err := taskService.GetTaskByName("Unique Task")
@@ -407,6 +420,7 @@ func TestTaskServiceGetTaskByName_Failure(t *testing.T) {
 			log.Fatal(err)
 		}
 	}(dbContainer, context.Background())
+	createTestCategories(db)
 
 	f, err := os.OpenFile("tests.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -415,7 +429,7 @@ func TestTaskServiceGetTaskByName_Failure(t *testing.T) {
 
 	taskRepository := postgres.NewTaskRepository(db)
 	logger := log.New(f)
-	taskService := services.NewTaskService(taskRepository, logger)
+	taskService := services.NewTaskService(taskRepository, postgres.NewCategoryRepository(db), logger)
 
 	// Act
 	foundTask, err := taskService.GetTaskByName("Nonexistent Task")
@@ -7,6 +7,8 @@ import (
 	_ "github.com/lib/pq"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"lab3/internal/models"
+	"lab3/internal/repository/postgres"
 	"log"
 )
 
@@ -46,63 +48,21 @@ func SetupTestDatabase() (testcontainers.Container, *sqlx.DB) {
 		log.Fatalf("Could not connect to database: %s", err)
 	}
 
-	schema := `
-	 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
-	 
-	 CREATE TABLE IF NOT EXISTS users (
-	  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-	  name TEXT,
-	  surname TEXT,
-	  email TEXT UNIQUE,
-	  phone_number TEXT,
-	  address TEXT,
-	  password TEXT
-	 );
-	
-	 CREATE TABLE IF NOT EXISTS workers (
-	  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-	  name TEXT,
-	  surname TEXT,
-	  email TEXT UNIQUE,
-	  phone_number TEXT,
-	  address TEXT,
-	  password TEXT,
-	  role INT
-	 );
-	
-	 CREATE TABLE IF NOT EXISTS orders (
-	  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-	  worker_id UUID REFERENCES workers(id) ON DELETE SET NULL DEFAULT NULL,
-	  user_id UUID REFERENCES users(id) ON DELETE SET NULL DEFAULT NULL,
-	  status INT2 DEFAULT 0,
-	  address TEXT,
-	  deadline TIMESTAMP,
-	  creation_date TIMESTAMP DEFAULT NOW(),
-	  rate INT2 DEFAULT 0
-	 );
-	
-	 CREATE TABLE IF NOT EXISTS tasks (
-	  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-	  name TEXT,
-	  price_per_single FLOAT8,
-	  category INT2
-	 );
-	
-	 CREATE TABLE IF NOT EXISTS order_contains_tasks (
-	  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-	  order_id UUID REFERENCES orders(id),
-	  task_id UUID REFERENCES tasks(id),
-	  quantity INT2 DEFAULT 1
-	 );
-	                                                 
-	 CREATE TABLE IF NOT EXISTS categories (
-    	id SERIAL,
-   		name VARCHAR
-	 );`
-	_, err = db.Exec(schema)
+	// the schema is created by the same migrations that the application runs
+	_, err = postgres.NewMigrator(db).Up()
 	if err != nil {
 		log.Fatalf("Could not create schema: %s", err)
 	}
 
 	return dbContainer, db
 }
+
+// createTestCategories creates the categories 1 and 2 that the tests put their tasks in.
+func createTestCategories(db *sqlx.DB) {
+	for _, name := range []string{"First Category", "Second Category"} {
+		_, err := postgres.NewCategoryRepository(db).Create(&models.Category{Name: name})
+		if err != nil {
+			log.Fatalf("Could not create category: %s", err)
+		}
+	}
+}
@@ -368,10 +368,13 @@ func TestWorkerService_Delete_Success(t *testing.T) {
 	// Assert
 	require.NoError(t, err)
 
-	// Try to get deleted worker
+	// The archived worker is kept for old orders, but can not sign in
 	deletedWorker, err := workerService.GetWorkerByID(createdWorker.ID)
+	require.NoError(t, err)
+	require.True(t, deletedWorker.IsDeleted())
+
+	_, err = workerService.Login(createdWorker.Email, "password123")
 	require.Error(t, err)
-	require.Nil(t, deletedWorker)
 }
 
 func TestWorkerService_Delete_Failure(t *testing.T) {
@@ -93,6 +93,49 @@ func (mr *MockICategoryRepositoryMockRecorder) GetByID(id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockICategoryRepository)(nil).GetByID), id)
 }
 
+// GetDeleted mocks base method.
+func (m *MockICategoryRepository) GetDeleted() ([]models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeleted")
+	ret0, _ := ret[0].([]models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeleted indicates an expected call of GetDeleted.
+func (mr *MockICategoryRepositoryMockRecorder) GetDeleted() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleted", reflect.TypeOf((*MockICategoryRepository)(nil).GetDeleted))
+}
+
+// Reorder mocks base method.
+func (m *MockICategoryRepository) Reorder(ids []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockICategoryRepositoryMockRecorder) Reorder(ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockICategoryRepository)(nil).Reorder), ids)
+}
+
+// Restore mocks base method.
+func (m *MockICategoryRepository) Restore(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockICategoryRepositoryMockRecorder) Restore(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockICategoryRepository)(nil).Restore), id)
+}
+
 // Update mocks base method.
 func (m *MockICategoryRepository) Update(category *models.Category) (*models.Category, error) {
 	m.ctrl.T.Helper()
@@ -7,6 +7,7 @@ package mock_repository_interfaces
 import (
 	models "lab3/internal/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -49,6 +50,21 @@ func (mr *MockIOrderRepositoryMockRecorder) AddTaskToOrder(orderID, taskID inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTaskToOrder", reflect.TypeOf((*MockIOrderRepository)(nil).AddTaskToOrder), orderID, taskID)
 }
 
+// Archive mocks base method.
+func (m *MockIOrderRepository) Archive(closedBefore time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Archive", closedBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockIOrderRepositoryMockRecorder) Archive(closedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockIOrderRepository)(nil).Archive), closedBefore)
+}
+
 // Create mocks base method.
 func (m *MockIOrderRepository) Create(order *models.Order, orderedTasks []models.OrderedTask) (*models.Order, error) {
 	m.ctrl.T.Helper()
@@ -138,6 +154,21 @@ func (mr *MockIOrderRepositoryMockRecorder) GetOrderByID(id interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByID", reflect.TypeOf((*MockIOrderRepository)(nil).GetOrderByID), id)
 }
 
+// GetOrderByIdempotencyKey mocks base method.
+func (m *MockIOrderRepository) GetOrderByIdempotencyKey(userID uuid.UUID, key string) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderByIdempotencyKey", userID, key)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderByIdempotencyKey indicates an expected call of GetOrderByIdempotencyKey.
+func (mr *MockIOrderRepositoryMockRecorder) GetOrderByIdempotencyKey(userID, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByIdempotencyKey", reflect.TypeOf((*MockIOrderRepository)(nil).GetOrderByIdempotencyKey), userID, key)
+}
+
 // GetTaskQuantity mocks base method.
 func (m *MockIOrderRepository) GetTaskQuantity(orderID, taskID uuid.UUID) (int, error) {
 	m.ctrl.T.Helper()
@@ -168,6 +199,21 @@ func (mr *MockIOrderRepositoryMockRecorder) GetTasksInOrder(id interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksInOrder", reflect.TypeOf((*MockIOrderRepository)(nil).GetTasksInOrder), id)
 }
 
+// History mocks base method.
+func (m *MockIOrderRepository) History(params map[string]string) ([]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", params)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockIOrderRepositoryMockRecorder) History(params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockIOrderRepository)(nil).History), params)
+}
+
 // RemoveTaskFromOrder mocks base method.
 func (m *MockIOrderRepository) RemoveTaskFromOrder(orderID, taskID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -7,6 +7,7 @@ package mock_repository_interfaces
 import (
 	models "lab3/internal/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -35,6 +36,21 @@ func (m *MockITaskRepository) EXPECT() *MockITaskRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ApplyPrices mocks base method.
+func (m *MockITaskRepository) ApplyPrices(moment time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyPrices", moment)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyPrices indicates an expected call of ApplyPrices.
+func (mr *MockITaskRepositoryMockRecorder) ApplyPrices(moment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyPrices", reflect.TypeOf((*MockITaskRepository)(nil).ApplyPrices), moment)
+}
+
 // Create mocks base method.
 func (m *MockITaskRepository) Create(task *models.Task) (*models.Task, error) {
 	m.ctrl.T.Helper()
@@ -64,6 +80,20 @@ func (mr *MockITaskRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockITaskRepository)(nil).Delete), id)
 }
 
+// DeletePrice mocks base method.
+func (m *MockITaskRepository) DeletePrice(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePrice", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePrice indicates an expected call of DeletePrice.
+func (mr *MockITaskRepositoryMockRecorder) DeletePrice(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePrice", reflect.TypeOf((*MockITaskRepository)(nil).DeletePrice), id)
+}
+
 // GetAllTasks mocks base method.
 func (m *MockITaskRepository) GetAllTasks() ([]models.Task, error) {
 	m.ctrl.T.Helper()
@@ -79,6 +109,36 @@ func (mr *MockITaskRepositoryMockRecorder) GetAllTasks() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTasks", reflect.TypeOf((*MockITaskRepository)(nil).GetAllTasks))
 }
 
+// GetDeletedTasks mocks base method.
+func (m *MockITaskRepository) GetDeletedTasks() ([]models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedTasks")
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedTasks indicates an expected call of GetDeletedTasks.
+func (mr *MockITaskRepositoryMockRecorder) GetDeletedTasks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedTasks", reflect.TypeOf((*MockITaskRepository)(nil).GetDeletedTasks))
+}
+
+// GetPrices mocks base method.
+func (m *MockITaskRepository) GetPrices(taskID uuid.UUID) ([]models.TaskPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrices", taskID)
+	ret0, _ := ret[0].([]models.TaskPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrices indicates an expected call of GetPrices.
+func (mr *MockITaskRepositoryMockRecorder) GetPrices(taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrices", reflect.TypeOf((*MockITaskRepository)(nil).GetPrices), taskID)
+}
+
 // GetTaskByID mocks base method.
 func (m *MockITaskRepository) GetTaskByID(id uuid.UUID) (*models.Task, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +184,80 @@ func (mr *MockITaskRepositoryMockRecorder) GetTasksInCategory(category interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksInCategory", reflect.TypeOf((*MockITaskRepository)(nil).GetTasksInCategory), category)
 }
 
+// GetUpcomingPrices mocks base method.
+func (m *MockITaskRepository) GetUpcomingPrices(moment time.Time) ([]models.TaskPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpcomingPrices", moment)
+	ret0, _ := ret[0].([]models.TaskPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUpcomingPrices indicates an expected call of GetUpcomingPrices.
+func (mr *MockITaskRepositoryMockRecorder) GetUpcomingPrices(moment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpcomingPrices", reflect.TypeOf((*MockITaskRepository)(nil).GetUpcomingPrices), moment)
+}
+
+// MoveToCategory mocks base method.
+func (m *MockITaskRepository) MoveToCategory(from, to int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveToCategory", from, to)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveToCategory indicates an expected call of MoveToCategory.
+func (mr *MockITaskRepositoryMockRecorder) MoveToCategory(from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveToCategory", reflect.TypeOf((*MockITaskRepository)(nil).MoveToCategory), from, to)
+}
+
+// Restore mocks base method.
+func (m *MockITaskRepository) Restore(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockITaskRepositoryMockRecorder) Restore(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockITaskRepository)(nil).Restore), id)
+}
+
+// SchedulePrice mocks base method.
+func (m *MockITaskRepository) SchedulePrice(price *models.TaskPrice) (*models.TaskPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchedulePrice", price)
+	ret0, _ := ret[0].(*models.TaskPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SchedulePrice indicates an expected call of SchedulePrice.
+func (mr *MockITaskRepositoryMockRecorder) SchedulePrice(price interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchedulePrice", reflect.TypeOf((*MockITaskRepository)(nil).SchedulePrice), price)
+}
+
+// Search mocks base method.
+func (m *MockITaskRepository) Search(query string) ([]models.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", query)
+	ret0, _ := ret[0].([]models.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockITaskRepositoryMockRecorder) Search(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockITaskRepository)(nil).Search), query)
+}
+
 // Update mocks base method.
 func (m *MockITaskRepository) Update(task *models.Task) (*models.Task, error) {
 	m.ctrl.T.Helper()
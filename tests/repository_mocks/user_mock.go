@@ -79,6 +79,21 @@ func (mr *MockIUserRepositoryMockRecorder) GetAllUsers() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockIUserRepository)(nil).GetAllUsers))
 }
 
+// GetDeletedUsers mocks base method.
+func (m *MockIUserRepository) GetDeletedUsers() ([]models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedUsers")
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedUsers indicates an expected call of GetDeletedUsers.
+func (mr *MockIUserRepositoryMockRecorder) GetDeletedUsers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedUsers", reflect.TypeOf((*MockIUserRepository)(nil).GetDeletedUsers))
+}
+
 // GetUserByEmail mocks base method.
 func (m *MockIUserRepository) GetUserByEmail(email string) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +124,20 @@ func (mr *MockIUserRepositoryMockRecorder) GetUserByID(id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockIUserRepository)(nil).GetUserByID), id)
 }
 
+// Restore mocks base method.
+func (m *MockIUserRepository) Restore(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockIUserRepositoryMockRecorder) Restore(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockIUserRepository)(nil).Restore), id)
+}
+
 // Update mocks base method.
 func (m *MockIUserRepository) Update(user *models.User) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +94,21 @@ func (mr *MockIWorkerRepositoryMockRecorder) GetAverageOrderRate(worker interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAverageOrderRate", reflect.TypeOf((*MockIWorkerRepository)(nil).GetAverageOrderRate), worker)
 }
 
+// GetDeletedWorkers mocks base method.
+func (m *MockIWorkerRepository) GetDeletedWorkers() ([]models.Worker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedWorkers")
+	ret0, _ := ret[0].([]models.Worker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedWorkers indicates an expected call of GetDeletedWorkers.
+func (mr *MockIWorkerRepositoryMockRecorder) GetDeletedWorkers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedWorkers", reflect.TypeOf((*MockIWorkerRepository)(nil).GetDeletedWorkers))
+}
+
 // GetWorkerByEmail mocks base method.
 func (m *MockIWorkerRepository) GetWorkerByEmail(email string) (*models.Worker, error) {
 	m.ctrl.T.Helper()
@@ -139,6 +154,20 @@ func (mr *MockIWorkerRepositoryMockRecorder) GetWorkersByRole(role interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkersByRole", reflect.TypeOf((*MockIWorkerRepository)(nil).GetWorkersByRole), role)
 }
 
+// Restore mocks base method.
+func (m *MockIWorkerRepository) Restore(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockIWorkerRepositoryMockRecorder) Restore(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockIWorkerRepository)(nil).Restore), id)
+}
+
 // Update mocks base method.
 func (m *MockIWorkerRepository) Update(worker *models.Worker) (*models.Worker, error) {
 	m.ctrl.T.Helper()
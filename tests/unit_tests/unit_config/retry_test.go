@@ -0,0 +1,75 @@
+package unit_config
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"lab3/config"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	retry := config.RetryConfig{Attempts: 10, InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	expected := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for i, delay := range expected {
+		assert.Equal(t, delay*time.Millisecond, retry.Backoff(i+1))
+	}
+}
+
+func TestRetryBackoff_DefaultMaxDelay(t *testing.T) {
+	retry := config.RetryConfig{Attempts: 100, InitialDelay: time.Second}
+
+	assert.Equal(t, time.Minute, retry.Backoff(100))
+}
+
+func TestRetryBackoff_DefaultInitialDelay(t *testing.T) {
+	retry := config.RetryConfig{Attempts: 3}
+
+	assert.Equal(t, 500*time.Millisecond, retry.Backoff(1))
+	assert.Equal(t, time.Second, retry.Backoff(2))
+}
+
+func TestRetryDo_SucceedsAfterFailures(t *testing.T) {
+	retry := config.RetryConfig{Attempts: 5, InitialDelay: time.Millisecond}
+
+	calls := 0
+	err := retry.Do(log.New(io.Discard), "TEST!", func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetryDo_ReturnsLastError(t *testing.T) {
+	retry := config.RetryConfig{Attempts: 3, InitialDelay: time.Millisecond}
+	last := errors.New("connection refused")
+
+	calls := 0
+	err := retry.Do(log.New(io.Discard), "TEST!", func() error {
+		calls++
+		return last
+	})
+
+	assert.ErrorIs(t, err, last)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetryDo_NoAttemptsConfigured(t *testing.T) {
+	calls := 0
+	err := config.RetryConfig{}.Do(log.New(io.Discard), "TEST!", func() error {
+		calls++
+		return errors.New("connection refused")
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,67 @@
+package unit_pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lab3/internal/pdf"
+)
+
+func TestBytes_Structure(t *testing.T) {
+	document := pdf.New()
+	document.Text(40, 50, 10, false, "Первая")
+	document.AddPage()
+	document.Text(40, 50, 10, true, "Вторая")
+
+	out := document.Bytes()
+
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	assert.Contains(t, string(out), "/Count 2")
+
+	// every entry of the cross-reference table points at its object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	require.NotNil(t, startxref)
+	xref, err := strconv.Atoi(string(startxref[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(out[xref:], []byte("xref\n")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	require.Len(t, entries, 11)
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(string(entry[1]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(out[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d", i+1)
+	}
+}
+
+func TestText_Encoding(t *testing.T) {
+	document := pdf.New()
+	document.Text(0, 0, 10, false, "Счет (№1) ё\\")
+
+	out := document.Bytes()
+
+	// Windows-1251 codes with the parentheses and the backslash escaped
+	assert.Contains(t, string(out), "(\xd1\xf7\xe5\xf2 \\(\xb91\\) \xb8\\\\) Tj")
+}
+
+func TestTextWidth(t *testing.T) {
+	assert.InDelta(t, 5.56, pdf.TextWidth("1", 10, false), 0.001)
+	assert.InDelta(t, 11.12, pdf.TextWidth("11", 10, false), 0.001)
+	assert.Greater(t, pdf.TextWidth("Счет", 10, true), pdf.TextWidth("Счет", 10, false))
+}
+
+func TestWrap(t *testing.T) {
+	width := pdf.TextWidth("мытье окон", 10, false)
+
+	lines := pdf.Wrap("мытье окон и  уборка", 10, false, width)
+
+	assert.Equal(t, []string{"мытье окон", "и уборка"}, lines)
+	assert.Empty(t, pdf.Wrap("  ", 10, false, width))
+	assert.Equal(t, []string{"длинноеслово"}, pdf.Wrap("длинноеслово", 10, false, 1))
+}
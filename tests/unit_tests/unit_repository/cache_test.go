@@ -0,0 +1,192 @@
+package unit_repository
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lab3/internal/models"
+	"lab3/internal/repository/cache"
+	"lab3/internal/repository/memory"
+	"lab3/internal/repository/repository_errors"
+	"lab3/tests/conformance"
+	mock_repository_interfaces "lab3/tests/repository_mocks"
+)
+
+// The cached repositories must keep the semantics of the repositories they wrap.
+func TestCachedMemoryConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) conformance.Repositories {
+		store, err := memory.NewStore("", false)
+		require.NoError(t, err)
+
+		repositories := memoryRepositories(store)
+		repositories.Tasks = cache.NewTaskRepository(repositories.Tasks, cache.New("tasks", time.Minute, 100))
+		repositories.Categories = cache.NewCategoryRepository(repositories.Categories, cache.New("categories", time.Minute, 100))
+		return repositories
+	})
+}
+
+func TestTaskCache_Hit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repository := mock_repository_interfaces.NewMockITaskRepository(ctrl)
+	taskCache := cache.New("tasks", time.Minute, 100)
+	cached := cache.NewTaskRepository(repository, taskCache)
+
+	tasks := []models.Task{{ID: uuid.New(), Name: "Task", PricePerSingle: 100, Category: 1}}
+	repository.EXPECT().GetTasksInCategory(1).Return(tasks, nil).Times(1)
+
+	for i := 0; i < 3; i++ {
+		result, err := cached.GetTasksInCategory(1)
+		require.NoError(t, err)
+		assert.Equal(t, tasks, result)
+	}
+
+	stats := taskCache.Stats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, 1, stats.Entries)
+}
+
+func TestTaskCache_ResultIsCopied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repository := mock_repository_interfaces.NewMockITaskRepository(ctrl)
+	cached := cache.NewTaskRepository(repository, cache.New("tasks", time.Minute, 100))
+
+	id := uuid.New()
+	repository.EXPECT().GetTaskByID(id).Return(&models.Task{ID: id, Name: "Task"}, nil).Times(1)
+
+	task, err := cached.GetTaskByID(id)
+	require.NoError(t, err)
+	task.Name = "Changed"
+
+	task, err = cached.GetTaskByID(id)
+	require.NoError(t, err)
+	assert.Equal(t, "Task", task.Name)
+}
+
+func TestTaskCache_ErrorsAreNotCached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repository := mock_repository_interfaces.NewMockITaskRepository(ctrl)
+	cached := cache.NewTaskRepository(repository, cache.New("tasks", time.Minute, 100))
+
+	repository.EXPECT().GetTaskByName("Task").Return(nil, repository_errors.DoesNotExist).Times(2)
+
+	for i := 0; i < 2; i++ {
+		_, err := cached.GetTaskByName("Task")
+		assert.ErrorIs(t, err, repository_errors.DoesNotExist)
+	}
+}
+
+func TestTaskCache_WriteInvalidates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repository := mock_repository_interfaces.NewMockITaskRepository(ctrl)
+	cached := cache.NewTaskRepository(repository, cache.New("tasks", time.Minute, 100))
+
+	task := models.Task{ID: uuid.New(), Name: "Task", PricePerSingle: 100, Category: 1}
+	updated := task
+	updated.PricePerSingle = 200
+
+	gomock.InOrder(
+		repository.EXPECT().GetAllTasks().Return([]models.Task{task}, nil),
+		repository.EXPECT().Update(&updated).Return(&updated, nil),
+		repository.EXPECT().GetAllTasks().Return([]models.Task{updated}, nil),
+	)
+
+	_, err := cached.GetAllTasks()
+	require.NoError(t, err)
+	_, err = cached.Update(&updated)
+	require.NoError(t, err)
+
+	tasks, err := cached.GetAllTasks()
+	require.NoError(t, err)
+	assert.Equal(t, []models.Task{updated}, tasks)
+}
+
+func TestTaskCache_FailedWriteInvalidates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repository := mock_repository_interfaces.NewMockITaskRepository(ctrl)
+	cached := cache.NewTaskRepository(repository, cache.New("tasks", time.Minute, 100))
+
+	id := uuid.New()
+	repository.EXPECT().GetDeletedTasks().Return(nil, nil).Times(2)
+	repository.EXPECT().Delete(id).Return(repository_errors.UpdateError)
+
+	_, err := cached.GetDeletedTasks()
+	require.NoError(t, err)
+	assert.ErrorIs(t, cached.Delete(id), repository_errors.UpdateError)
+	_, err = cached.GetDeletedTasks()
+	require.NoError(t, err)
+}
+
+func TestCategoryCache_Expires(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repository := mock_repository_interfaces.NewMockICategoryRepository(ctrl)
+	cached := cache.NewCategoryRepository(repository, cache.New("categories", 50*time.Millisecond, 100))
+
+	repository.EXPECT().GetAll().Return([]models.Category{{ID: 1, Name: "Category"}}, nil).Times(2)
+
+	_, err := cached.GetAll()
+	require.NoError(t, err)
+	_, err = cached.GetAll()
+	require.NoError(t, err)
+
+	time.Sleep(60 * time.Millisecond)
+
+	_, err = cached.GetAll()
+	require.NoError(t, err)
+}
+
+func TestCategoryCache_Evicts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repository := mock_repository_interfaces.NewMockICategoryRepository(ctrl)
+	categoryCache := cache.New("categories", time.Minute, 2)
+	cached := cache.NewCategoryRepository(repository, categoryCache)
+
+	for id := 1; id <= 3; id++ {
+		repository.EXPECT().GetByID(id).Return(&models.Category{ID: id}, nil)
+	}
+	// the least recently used category was evicted
+	repository.EXPECT().GetByID(1).Return(&models.Category{ID: 1}, nil)
+
+	for _, id := range []int{1, 2, 3, 2, 3, 1} {
+		category, err := cached.GetByID(id)
+		require.NoError(t, err)
+		assert.Equal(t, id, category.ID)
+	}
+
+	stats := categoryCache.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, uint64(2), stats.Evictions)
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(4), stats.Misses)
+}
+
+func TestCategoryCache_Concurrent(t *testing.T) {
+	store, err := memory.NewStore("", false)
+	require.NoError(t, err)
+	cached := cache.NewCategoryRepository(memory.NewCategoryRepository(store), cache.New("categories", time.Minute, 10))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := cached.Create(&models.Category{Name: "Category"})
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := cached.GetAll()
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	categories, err := cached.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, categories, 10)
+}
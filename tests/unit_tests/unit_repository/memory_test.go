@@ -0,0 +1,120 @@
+package unit_repository
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"lab3/internal/models"
+	"lab3/internal/repository/memory"
+	"lab3/internal/repository/repository_errors"
+	"lab3/tests/conformance"
+)
+
+func memoryRepositories(store *memory.Store) conformance.Repositories {
+	return conformance.Repositories{
+		Users:         memory.NewUserRepository(store),
+		Workers:       memory.NewWorkerRepository(store),
+		Tasks:         memory.NewTaskRepository(store),
+		Categories:    memory.NewCategoryRepository(store),
+		Orders:        memory.NewOrderRepository(store),
+		Sessions:      memory.NewSessionRepository(store),
+		LoginAttempts: memory.NewLoginAttemptRepository(store),
+		OneTimeTokens: memory.NewOneTimeTokenRepository(store),
+		TwoFactor:     memory.NewTwoFactorRepository(store),
+		Audit:         memory.NewAuditRepository(store),
+		Invoices:      memory.NewInvoiceRepository(store),
+	}
+}
+
+func TestMemoryConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) conformance.Repositories {
+		store, err := memory.NewStore("", false)
+		require.NoError(t, err)
+		return memoryRepositories(store)
+	})
+}
+
+func TestMemoryConformance_SaveSnapshot(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) conformance.Repositories {
+		store, err := memory.NewStore(filepath.Join(t.TempDir(), "snapshot.json"), true)
+		require.NoError(t, err)
+		return memoryRepositories(store)
+	})
+}
+
+func TestMemorySnapshot_Reload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "snapshot.json")
+
+	store, err := memory.NewStore(file, true)
+	require.NoError(t, err)
+	repositories := memoryRepositories(store)
+
+	user, err := repositories.Users.Create(&models.User{Name: "Name", Surname: "Surname", Email: "user@test.com", Password: "hashed_password"})
+	require.NoError(t, err)
+	category, err := repositories.Categories.Create(&models.Category{Name: "Category"})
+	require.NoError(t, err)
+	task, err := repositories.Tasks.Create(&models.Task{Name: "Task", PricePerSingle: 100, Category: category.ID})
+	require.NoError(t, err)
+	order, err := repositories.Orders.Create(&models.Order{UserID: user.ID, Status: models.NewOrderStatus, Address: "Address"},
+		[]models.OrderedTask{{Task: task, Quantity: 2}})
+	require.NoError(t, err)
+
+	reloaded, err := memory.NewStore(file, false)
+	require.NoError(t, err)
+	repositories = memoryRepositories(reloaded)
+
+	got, err := repositories.Users.GetUserByEmail("user@test.com")
+	require.NoError(t, err)
+	require.Equal(t, user.ID, got.ID)
+
+	quantity, err := repositories.Orders.GetTaskQuantity(order.ID, task.ID)
+	require.NoError(t, err)
+	require.Equal(t, 2, quantity)
+
+	// the category sequence continues after the loaded categories
+	next, err := repositories.Categories.Create(&models.Category{Name: "Next"})
+	require.NoError(t, err)
+	require.Equal(t, category.ID+1, next.ID)
+}
+
+func TestMemorySnapshot_SaveFailureIsUndone(t *testing.T) {
+	store, err := memory.NewStore(filepath.Join(t.TempDir(), "missing", "snapshot.json"), true)
+	require.NoError(t, err)
+	repositories := memoryRepositories(store)
+
+	_, err = repositories.Users.Create(&models.User{Name: "Name", Surname: "Surname", Email: "user@test.com", Password: "hashed_password"})
+	require.ErrorIs(t, err, repository_errors.TransactionCommitError)
+
+	_, err = repositories.Users.GetUserByEmail("user@test.com")
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+}
+
+func TestMemoryStore_ConcurrentIdempotentCreate(t *testing.T) {
+	store, err := memory.NewStore("", false)
+	require.NoError(t, err)
+	repositories := memoryRepositories(store)
+
+	user, err := repositories.Users.Create(&models.User{Name: "Name", Surname: "Surname", Email: "user@test.com", Password: "hashed_password"})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repositories.Orders.Create(&models.Order{UserID: user.ID, Status: models.NewOrderStatus, IdempotencyKey: "key"}, nil)
+			if err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 1, created)
+}
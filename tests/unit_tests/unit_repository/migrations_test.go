@@ -0,0 +1,62 @@
+package unit_repository
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"lab3/internal/repository/migrations"
+)
+
+func TestLoadSQLMigrations_Success(t *testing.T) {
+	files := fstest.MapFS{
+		"0002_second.up.sql":   {Data: []byte("create table b();")},
+		"0002_second.down.sql": {Data: []byte("drop table b;")},
+		"0001_first.up.sql":    {Data: []byte("create table a();")},
+		"0001_first.down.sql":  {Data: []byte("drop table a;")},
+	}
+
+	loaded, err := migrations.LoadSQL(files)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []migrations.SQLMigration{
+		{Version: 1, Name: "first", Up: "create table a();", Down: "drop table a;"},
+		{Version: 2, Name: "second", Up: "create table b();", Down: "drop table b;"},
+	}, loaded)
+}
+
+func TestLoadSQLMigrations_MissingDown(t *testing.T) {
+	files := fstest.MapFS{
+		"0001_first.up.sql": {Data: []byte("create table a();")},
+	}
+
+	loaded, err := migrations.LoadSQL(files)
+
+	assert.Error(t, err)
+	assert.Nil(t, loaded)
+}
+
+func TestLoadSQLMigrations_DuplicateVersion(t *testing.T) {
+	files := fstest.MapFS{
+		"0001_first.up.sql":    {Data: []byte("create table a();")},
+		"0001_first.down.sql":  {Data: []byte("drop table a;")},
+		"0001_second.up.sql":   {Data: []byte("create table b();")},
+		"0001_second.down.sql": {Data: []byte("drop table b;")},
+	}
+
+	loaded, err := migrations.LoadSQL(files)
+
+	assert.Error(t, err)
+	assert.Nil(t, loaded)
+}
+
+func TestLoadSQLMigrations_InvalidName(t *testing.T) {
+	files := fstest.MapFS{
+		"first.sql": {Data: []byte("create table a();")},
+	}
+
+	loaded, err := migrations.LoadSQL(files)
+
+	assert.Error(t, err)
+	assert.Nil(t, loaded)
+}
@@ -0,0 +1,68 @@
+package unit_repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/sqlite"
+	"lab3/tests/conformance"
+)
+
+func openSQLite(t *testing.T) *sqlx.DB {
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "lab3.db"), 0)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return sqlx.NewDb(db, "sqlite3")
+}
+
+func TestSQLiteConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) conformance.Repositories {
+		db := openSQLite(t)
+		_, err := sqlite.NewMigrator(db).Up()
+		require.NoError(t, err)
+
+		return conformance.Repositories{
+			Users:         sqlite.NewUserRepository(db),
+			Workers:       sqlite.NewWorkerRepository(db),
+			Tasks:         sqlite.NewTaskRepository(db),
+			Categories:    sqlite.NewCategoryRepository(db),
+			Orders:        sqlite.NewOrderRepository(db),
+			Sessions:      sqlite.NewSessionRepository(db),
+			LoginAttempts: sqlite.NewLoginAttemptRepository(db),
+			OneTimeTokens: sqlite.NewOneTimeTokenRepository(db),
+			TwoFactor:     sqlite.NewTwoFactorRepository(db),
+			Audit:         sqlite.NewAuditRepository(db),
+			Invoices:      sqlite.NewInvoiceRepository(db),
+		}
+	})
+}
+
+func TestSQLiteMigrator_UpDown(t *testing.T) {
+	migrator := sqlite.NewMigrator(openSQLite(t))
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	require.NotEmpty(t, applied)
+
+	applied, err = migrator.Up()
+	require.NoError(t, err)
+	require.Empty(t, applied)
+
+	status, err := migrator.Status()
+	require.NoError(t, err)
+	for _, migration := range status {
+		require.True(t, migration.Applied)
+	}
+
+	for range status {
+		_, err = migrator.Down()
+		require.NoError(t, err)
+	}
+
+	_, err = migrator.Down()
+	require.ErrorIs(t, err, repository_errors.DoesNotExist)
+}
@@ -0,0 +1,75 @@
+package unit_repository
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/repository/sqlite"
+)
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "SQLSTATE " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func TestTransient_MatchesKindAndUnavailable(t *testing.T) {
+	cause := fmt.Errorf("query: %w", driver.ErrBadConn)
+
+	err := repository_errors.Transient(cause, repository_errors.SelectError, repository_errors.IsConnectionError)
+
+	assert.ErrorIs(t, err, repository_errors.SelectError)
+	assert.ErrorIs(t, err, repository_errors.Unavailable)
+	assert.ErrorIs(t, err, driver.ErrBadConn)
+	assert.NotErrorIs(t, err, repository_errors.InsertError)
+}
+
+func TestTransient_KeepsPermanentErrors(t *testing.T) {
+	err := repository_errors.Transient(errors.New("syntax error"), repository_errors.SelectError, repository_errors.IsConnectionError)
+	assert.Equal(t, repository_errors.SelectError, err)
+
+	err = repository_errors.Transient(nil, repository_errors.InsertError, repository_errors.IsConnectionError)
+	assert.Equal(t, repository_errors.InsertError, err)
+}
+
+func TestIsConnectionError(t *testing.T) {
+	assert.True(t, repository_errors.IsConnectionError(&net.OpError{Op: "dial", Err: errors.New("connection refused")}))
+	assert.True(t, repository_errors.IsConnectionError(sqlStateError("08006")))
+	assert.True(t, repository_errors.IsConnectionError(sqlStateError("57P01")))
+	assert.True(t, repository_errors.IsConnectionError(sqlStateError("57014")))
+	assert.False(t, repository_errors.IsConnectionError(sqlStateError("23505")))
+	assert.False(t, repository_errors.IsConnectionError(errors.New("syntax error")))
+}
+
+func TestSQLite_LockedDatabaseIsUnavailable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lab3.db")
+
+	holder, err := sqlite.Open(path, 0)
+	require.NoError(t, err)
+	defer holder.Close()
+	_, err = sqlite.NewMigrator(sqlx.NewDb(holder, "sqlite3")).Up()
+	require.NoError(t, err)
+
+	waiter, err := sqlite.Open(path, 50*time.Millisecond)
+	require.NoError(t, err)
+	defer waiter.Close()
+
+	// the transaction takes the write lock when it begins
+	transaction, err := holder.Begin()
+	require.NoError(t, err)
+	defer transaction.Rollback()
+
+	_, err = sqlite.NewCategoryRepository(sqlx.NewDb(waiter, "sqlite3")).Create(&models.Category{Name: "Category"})
+
+	assert.ErrorIs(t, err, repository_errors.InsertError)
+	assert.ErrorIs(t, err, repository_errors.Unavailable)
+}
@@ -0,0 +1,55 @@
+package unit_search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"lab3/internal/models"
+	"lab3/internal/search"
+)
+
+func TestStem(t *testing.T) {
+	cases := map[string]string{
+		"подоконников": "подоконник",
+		"уборку":       "уборк",
+		"уборка":       "уборк",
+		"мытье":        "мыт",
+	}
+
+	for word, stem := range cases {
+		assert.Equal(t, stem, search.Stem(word), word)
+	}
+}
+
+func TestTasks(t *testing.T) {
+	tasks := []models.Task{
+		{Name: "Мытье окон"},
+		{Name: "Генеральная уборка"},
+		{Name: "Уборка"},
+		{Name: "Мытье подоконников"},
+	}
+
+	t.Run("whole words before prefixes, then by name", func(t *testing.T) {
+		found := search.Tasks(tasks, "уборку")
+		assert.Equal(t, []string{"Генеральная уборка", "Уборка"}, names(found))
+
+		found = search.Tasks(tasks, "мыт подо")
+		assert.Equal(t, []string{"Мытье подоконников"}, names(found))
+	})
+
+	t.Run("every word must match", func(t *testing.T) {
+		assert.Empty(t, search.Tasks(tasks, "мытье дверей"))
+	})
+
+	t.Run("empty query finds nothing", func(t *testing.T) {
+		assert.Empty(t, search.Tasks(tasks, " , "))
+	})
+}
+
+func names(tasks []models.Task) []string {
+	var result []string
+	for _, task := range tasks {
+		result = append(result, task.Name)
+	}
+	return result
+}
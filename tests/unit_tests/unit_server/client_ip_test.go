@@ -0,0 +1,89 @@
+package unit_server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lab3/internal/models"
+	"lab3/internal/repository/memory"
+	"lab3/internal/repository/repository_interfaces"
+	services "lab3/internal/services"
+	"lab3/server"
+)
+
+// remoteIP is the peer address httptest.NewRequest sets on requests.
+const remoteIP = "192.0.2.1"
+
+var testSignInPolicy = services.LoginAttemptPolicy{
+	IPMaxAttempts:   3,
+	LockoutDuration: 15 * time.Minute,
+	Window:          15 * time.Minute,
+}
+
+// signinRouter throttles a sign-in endpoint that always fails the way the sign-in handlers do.
+func signinRouter(t *testing.T, trustedProxies []string) (*gin.Engine, repository_interfaces.ILoginAttemptRepository) {
+	gin.SetMode(gin.TestMode)
+
+	store, err := memory.NewStore("", false)
+	require.NoError(t, err)
+	repository := memory.NewLoginAttemptRepository(store)
+	service := services.NewLoginAttemptService(repository, testSignInPolicy, log.New(io.Discard))
+
+	router, err := server.NewEngine(trustedProxies)
+	require.NoError(t, err)
+	router.POST("/signin", func(c *gin.Context) {
+		email := c.PostForm("email")
+		if _, err := service.Check(models.UserLoginScope, email, c.ClientIP()); err != nil {
+			c.Status(http.StatusTooManyRequests)
+			return
+		}
+		_ = service.RegisterFailure(models.UserLoginScope, email, c.ClientIP())
+		c.Status(http.StatusBadRequest)
+	})
+
+	return router, repository
+}
+
+func signin(router *gin.Engine, attempt int) int {
+	request := httptest.NewRequest(http.MethodPost, "/signin", nil)
+	request.PostForm = map[string][]string{"email": {fmt.Sprintf("user%d@gmail.com", attempt)}}
+	request.Header.Set("X-Forwarded-For", fmt.Sprintf("203.0.113.%d", attempt))
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder.Code
+}
+
+func TestSignInThrottle_SpoofedForwardedForIgnored(t *testing.T) {
+	router, repository := signinRouter(t, nil)
+
+	for i := 1; i <= testSignInPolicy.IPMaxAttempts; i++ {
+		assert.Equal(t, http.StatusBadRequest, signin(router, i))
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests, signin(router, testSignInPolicy.IPMaxAttempts+1))
+
+	attempt, err := repository.GetByKey(models.IPLoginScope + ":" + remoteIP)
+	require.NoError(t, err)
+	assert.Equal(t, testSignInPolicy.IPMaxAttempts, attempt.Failures)
+}
+
+func TestSignInThrottle_ForwardedForFromTrustedProxy(t *testing.T) {
+	router, repository := signinRouter(t, []string{remoteIP})
+
+	for i := 1; i <= testSignInPolicy.IPMaxAttempts+1; i++ {
+		assert.Equal(t, http.StatusBadRequest, signin(router, i))
+	}
+
+	attempt, err := repository.GetByKey(models.IPLoginScope + ":203.0.113.1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempt.Failures)
+}
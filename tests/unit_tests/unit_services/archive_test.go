@@ -0,0 +1,112 @@
+package unit_services
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	services "lab3/internal/services"
+	"lab3/internal/services/service_errors"
+	"lab3/internal/services/service_interfaces"
+	"lab3/password_hash"
+	mock_repository_interfaces "lab3/tests/repository_mocks"
+	"testing"
+	"time"
+)
+
+type archiveMocks struct {
+	orderRepository    *mock_repository_interfaces.MockIOrderRepository
+	workerRepository   *mock_repository_interfaces.MockIWorkerRepository
+	taskRepository     *mock_repository_interfaces.MockITaskRepository
+	userRepository     *mock_repository_interfaces.MockIUserRepository
+	categoryRepository *mock_repository_interfaces.MockICategoryRepository
+}
+
+func newArchiveOrderService(t *testing.T) (service_interfaces.IOrderService, archiveMocks) {
+	ctrl := gomock.NewController(t)
+	mocks := archiveMocks{
+		orderRepository:    mock_repository_interfaces.NewMockIOrderRepository(ctrl),
+		workerRepository:   mock_repository_interfaces.NewMockIWorkerRepository(ctrl),
+		taskRepository:     mock_repository_interfaces.NewMockITaskRepository(ctrl),
+		userRepository:     mock_repository_interfaces.NewMockIUserRepository(ctrl),
+		categoryRepository: mock_repository_interfaces.NewMockICategoryRepository(ctrl),
+	}
+	service := services.NewOrderService(mocks.orderRepository, mocks.workerRepository, mocks.taskRepository, mocks.userRepository, mocks.categoryRepository, services.TaxPolicy{}, true, log.New(io.Discard))
+	return service, mocks
+}
+
+func TestCreateOrder_ArchivedTask(t *testing.T) {
+	service, mocks := newArchiveOrderService(t)
+	task := &models.Task{ID: uuid.New(), Name: "Task", PricePerSingle: 100, Category: 1, DeletedAt: time.Now()}
+	orderedTasks := []models.OrderedTask{{Task: task, Quantity: 1}}
+
+	mocks.taskRepository.EXPECT().GetTaskByID(task.ID).Return(task, nil)
+
+	order, err := service.CreateOrder(uuid.New(), "Test Address", time.Now().Add(24*time.Hour), orderedTasks, "")
+
+	assert.ErrorIs(t, err, service_errors.Archived)
+	assert.Nil(t, order)
+}
+
+func TestCreateOrder_ArchivedUser(t *testing.T) {
+	service, mocks := newArchiveOrderService(t)
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), Name: "Task", PricePerSingle: 100, Category: 1}
+	orderedTasks := []models.OrderedTask{{Task: task, Quantity: 1}}
+
+	mocks.taskRepository.EXPECT().GetTaskByID(task.ID).Return(task, nil)
+	mocks.userRepository.EXPECT().GetUserByID(userID).Return(&models.User{ID: userID, DeletedAt: time.Now()}, nil)
+
+	order, err := service.CreateOrder(userID, "Test Address", time.Now().Add(24*time.Hour), orderedTasks, "")
+
+	assert.ErrorIs(t, err, service_errors.Archived)
+	assert.Nil(t, order)
+}
+
+func TestUpdateOrder_AssignArchivedWorker(t *testing.T) {
+	service, mocks := newArchiveOrderService(t)
+	order := &models.Order{ID: uuid.New(), Status: models.NewOrderStatus, Version: 1}
+	worker := &models.Worker{ID: uuid.New(), Role: models.MasterRole, DeletedAt: time.Now()}
+
+	mocks.orderRepository.EXPECT().GetOrderByID(order.ID).Return(order, nil)
+	mocks.workerRepository.EXPECT().GetWorkerByID(worker.ID).Return(worker, nil)
+
+	updatedOrder, err := service.Update(order.ID, models.InProgressOrderStatus, 0, worker.ID, order.Version)
+
+	assert.ErrorIs(t, err, service_errors.Archived)
+	assert.Nil(t, updatedOrder)
+}
+
+func TestUpdateOrder_ArchivedWorkerKeepsAssignedOrder(t *testing.T) {
+	service, mocks := newArchiveOrderService(t)
+	worker := &models.Worker{ID: uuid.New(), Role: models.MasterRole, DeletedAt: time.Now()}
+	order := &models.Order{ID: uuid.New(), WorkerID: worker.ID, Status: models.InProgressOrderStatus, Version: 1}
+
+	mocks.orderRepository.EXPECT().GetOrderByID(order.ID).Return(order, nil)
+	mocks.workerRepository.EXPECT().GetWorkerByID(worker.ID).Return(worker, nil)
+	mocks.orderRepository.EXPECT().Update(gomock.Any()).DoAndReturn(func(order *models.Order) (*models.Order, error) {
+		return order, nil
+	})
+
+	updatedOrder, err := service.Update(order.ID, models.CompletedOrderStatus, 0, worker.ID, order.Version)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.CompletedOrderStatus, updatedOrder.Status)
+}
+
+func TestWorkerServiceDelete_RepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	workerRepository := mock_repository_interfaces.NewMockIWorkerRepository(ctrl)
+	service := services.NewWorkerService(workerRepository, password_hash.NewPasswordHash(), log.New(io.Discard))
+	workerID := uuid.New()
+
+	workerRepository.EXPECT().GetWorkerByID(workerID).Return(&models.Worker{ID: workerID}, nil)
+	workerRepository.EXPECT().Delete(workerID).Return(repository_errors.DoesNotExist)
+
+	err := service.Delete(workerID)
+
+	assert.ErrorIs(t, err, repository_errors.DoesNotExist)
+}
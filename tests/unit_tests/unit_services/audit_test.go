@@ -0,0 +1,96 @@
+package unit_services
+
+import (
+	"errors"
+	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"lab3/internal/models"
+	services "lab3/internal/services"
+	"strings"
+	"testing"
+)
+
+// Mock repository
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Create(entry *models.AuditEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) Filter(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AuditEntry), args.Error(1)
+}
+
+func TestAuditRecord_WorkerSnapshotWithoutPassword(t *testing.T) {
+	repository := new(MockAuditRepository)
+	service := services.NewAuditService(repository, log.New(io.Discard))
+
+	actor := &models.Worker{ID: uuid.New(), Role: models.ManagerRole}
+	target := &models.Worker{ID: uuid.New(), Name: "Ivan", Email: "ivan@gmail.com", Role: models.MasterRole, Password: "$argon2id$secret"}
+	repository.On("Create", mock.MatchedBy(func(entry *models.AuditEntry) bool {
+		return entry.ActorID == actor.ID &&
+			entry.Action == models.AuditWorkerCreate &&
+			entry.TargetID == target.ID.String() &&
+			entry.Before == "" &&
+			strings.Contains(entry.After, "ivan@gmail.com") &&
+			!strings.Contains(entry.After, "secret") &&
+			entry.IP == "127.0.0.1" &&
+			!entry.CreatedAt.IsZero()
+	})).Return(nil)
+
+	err := service.Record(actor, "127.0.0.1", models.AuditWorkerCreate, models.AuditTargetWorker, target.ID.String(), nil, target)
+
+	assert.NoError(t, err)
+	repository.AssertExpectations(t)
+}
+
+func TestAuditRecord_BeforeAndAfter(t *testing.T) {
+	repository := new(MockAuditRepository)
+	service := services.NewAuditService(repository, log.New(io.Discard))
+
+	actor := &models.Worker{ID: uuid.New(), Role: models.ManagerRole}
+	before := &models.Task{ID: uuid.New(), Name: "Мытье окон", PricePerSingle: 100, Category: 3}
+	after := &models.Task{ID: before.ID, Name: "Мытье окон", PricePerSingle: 150, Category: 3}
+	repository.On("Create", mock.MatchedBy(func(entry *models.AuditEntry) bool {
+		return strings.Contains(entry.Before, `"price_per_single":100`) && strings.Contains(entry.After, `"price_per_single":150`)
+	})).Return(nil)
+
+	err := service.Record(actor, "127.0.0.1", models.AuditTaskUpdate, models.AuditTargetTask, before.ID.String(), before, after)
+
+	assert.NoError(t, err)
+	repository.AssertExpectations(t)
+}
+
+func TestAuditRecord_Failure(t *testing.T) {
+	repository := new(MockAuditRepository)
+	service := services.NewAuditService(repository, log.New(io.Discard))
+
+	repository.On("Create", mock.Anything).Return(errors.New("insert failed"))
+
+	err := service.Record(&models.Worker{ID: uuid.New()}, "127.0.0.1", models.AuditCategoryCreate, models.AuditTargetCategory, "1", nil, &models.Category{ID: 1, Name: "Окна"})
+
+	assert.Error(t, err)
+}
+
+func TestAuditFilter_Success(t *testing.T) {
+	repository := new(MockAuditRepository)
+	service := services.NewAuditService(repository, log.New(io.Discard))
+
+	filter := models.AuditFilter{Action: models.AuditOrderReassign, Limit: 10}
+	repository.On("Filter", filter).Return([]models.AuditEntry{{Action: models.AuditOrderReassign}}, nil)
+
+	entries, err := service.Filter(filter)
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,139 @@
+package unit_services
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	services "lab3/internal/services"
+	"lab3/internal/services/service_errors"
+	mock_repository_interfaces "lab3/tests/repository_mocks"
+	"testing"
+	"time"
+)
+
+type categoryMocks struct {
+	categoryRepository *mock_repository_interfaces.MockICategoryRepository
+	taskRepository     *mock_repository_interfaces.MockITaskRepository
+}
+
+func newCategoryMocks(t *testing.T) categoryMocks {
+	ctrl := gomock.NewController(t)
+	return categoryMocks{
+		categoryRepository: mock_repository_interfaces.NewMockICategoryRepository(ctrl),
+		taskRepository:     mock_repository_interfaces.NewMockITaskRepository(ctrl),
+	}
+}
+
+func TestCategoryServiceDelete_NotEmpty(t *testing.T) {
+	mocks := newCategoryMocks(t)
+	service := services.NewCategoryService(mocks.categoryRepository, mocks.taskRepository, log.New(io.Discard))
+
+	mocks.categoryRepository.EXPECT().GetAll().Return(nil, nil)
+	mocks.taskRepository.EXPECT().GetTasksInCategory(1).Return([]models.Task{{Name: "Task", Category: 1}}, nil)
+
+	err := service.Delete(1)
+
+	assert.ErrorIs(t, err, service_errors.CategoryIsNotEmpty)
+}
+
+func TestCategoryServiceDelete_Empty(t *testing.T) {
+	mocks := newCategoryMocks(t)
+	service := services.NewCategoryService(mocks.categoryRepository, mocks.taskRepository, log.New(io.Discard))
+
+	mocks.categoryRepository.EXPECT().GetAll().Return(nil, nil)
+	mocks.taskRepository.EXPECT().GetTasksInCategory(1).Return(nil, nil)
+	mocks.categoryRepository.EXPECT().Delete(1).Return(nil)
+
+	err := service.Delete(1)
+
+	assert.NoError(t, err)
+}
+
+func TestCategoryServiceMerge_Success(t *testing.T) {
+	mocks := newCategoryMocks(t)
+	service := services.NewCategoryService(mocks.categoryRepository, mocks.taskRepository, log.New(io.Discard))
+
+	mocks.categoryRepository.EXPECT().GetByID(1).Return(&models.Category{ID: 1, Name: "From"}, nil)
+	mocks.categoryRepository.EXPECT().GetByID(2).Return(&models.Category{ID: 2, Name: "Into"}, nil).AnyTimes()
+	mocks.categoryRepository.EXPECT().GetAll().Return(nil, nil)
+	gomock.InOrder(
+		mocks.taskRepository.EXPECT().MoveToCategory(1, 2).Return(3, nil),
+		mocks.categoryRepository.EXPECT().Delete(1).Return(nil),
+	)
+
+	err := service.Merge(1, 2)
+
+	assert.NoError(t, err)
+}
+
+func TestCategoryServiceMerge_InvalidTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		into   int
+		target *models.Category
+		err    error
+	}{
+		{name: "itself", into: 1, target: &models.Category{ID: 1, Name: "From"}},
+		{name: "archived", into: 2, target: &models.Category{ID: 2, Name: "Into", DeletedAt: time.Now()}},
+		{name: "missing", into: 3, err: repository_errors.DoesNotExist},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mocks := newCategoryMocks(t)
+			service := services.NewCategoryService(mocks.categoryRepository, mocks.taskRepository, log.New(io.Discard))
+
+			mocks.categoryRepository.EXPECT().GetByID(1).Return(&models.Category{ID: 1, Name: "From"}, nil).AnyTimes()
+			if test.into != 1 {
+				mocks.categoryRepository.EXPECT().GetByID(test.into).Return(test.target, test.err)
+			}
+
+			err := service.Merge(1, test.into)
+
+			assert.ErrorIs(t, err, service_errors.InvalidCategory)
+		})
+	}
+}
+
+func TestTaskServiceCreate_InvalidCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		category *models.Category
+		err      error
+	}{
+		{name: "archived", category: &models.Category{ID: 1, Name: "Category", DeletedAt: time.Now()}},
+		{name: "missing", err: repository_errors.DoesNotExist},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mocks := newCategoryMocks(t)
+			service := services.NewTaskService(mocks.taskRepository, mocks.categoryRepository, log.New(io.Discard))
+
+			mocks.categoryRepository.EXPECT().GetByID(1).Return(test.category, test.err)
+
+			task, err := service.Create("Task", 100, 1, "")
+
+			assert.ErrorIs(t, err, service_errors.InvalidCategory)
+			assert.Nil(t, task)
+		})
+	}
+}
+
+func TestTaskServiceCreate_CreatedCategory(t *testing.T) {
+	mocks := newCategoryMocks(t)
+	service := services.NewTaskService(mocks.taskRepository, mocks.categoryRepository, log.New(io.Discard))
+	task := &models.Task{Name: "Task", PricePerSingle: 100, Category: 9}
+
+	mocks.categoryRepository.EXPECT().GetByID(9).Return(&models.Category{ID: 9, Name: "Ninth"}, nil)
+	mocks.taskRepository.EXPECT().Create(task).Return(task, nil)
+	mocks.taskRepository.EXPECT().SchedulePrice(gomock.Any()).Return(&models.TaskPrice{Price: 100}, nil)
+
+	created, err := service.Create("Task", 100, 9, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 9, created.Category)
+}
@@ -0,0 +1,183 @@
+package unit_services
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"lab3/internal/models"
+	services "lab3/internal/services"
+	"lab3/internal/services/service_errors"
+	"testing"
+)
+
+// categoryTree is ordered by position: "Химчистка" with "Мебель" and the hidden "Ковры" in it,
+// the hidden "Сезонные" with "Шторы" in it, and "Стирка".
+var categoryTree = []models.Category{
+	{ID: 1, Name: "Химчистка", Position: 1},
+	{ID: 4, Name: "Стирка", Position: 2},
+	{ID: 3, Name: "Ковры", ParentID: 1, Position: 3, Hidden: true},
+	{ID: 2, Name: "Мебель", ParentID: 1, Position: 4},
+	{ID: 5, Name: "Сезонные", Position: 5, Hidden: true},
+	{ID: 6, Name: "Шторы", ParentID: 5, Position: 6},
+}
+
+func catalogIDs(catalog []models.CategoryNode) ([]int, []int) {
+	var ids, depths []int
+	for _, category := range catalog {
+		ids = append(ids, category.ID)
+		depths = append(depths, category.Depth)
+	}
+	return ids, depths
+}
+
+func TestCategoryServiceCatalog(t *testing.T) {
+	tests := []struct {
+		name          string
+		includeHidden bool
+		ids           []int
+		depths        []int
+	}{
+		{name: "with hidden", includeHidden: true, ids: []int{1, 3, 2, 4, 5, 6}, depths: []int{0, 1, 1, 0, 0, 1}},
+		{name: "without hidden", ids: []int{1, 2, 4}, depths: []int{0, 1, 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mocks := newCategoryMocks(t)
+			service := services.NewCategoryService(mocks.categoryRepository, mocks.taskRepository, log.New(io.Discard))
+
+			mocks.categoryRepository.EXPECT().GetAll().Return(categoryTree, nil)
+
+			catalog, err := service.Catalog(test.includeHidden)
+
+			assert.NoError(t, err)
+			ids, depths := catalogIDs(catalog)
+			assert.Equal(t, test.ids, ids)
+			assert.Equal(t, test.depths, depths)
+		})
+	}
+}
+
+func TestCategoryServiceCatalog_ArchivedParent(t *testing.T) {
+	mocks := newCategoryMocks(t)
+	service := services.NewCategoryService(mocks.categoryRepository, mocks.taskRepository, log.New(io.Discard))
+
+	mocks.categoryRepository.EXPECT().GetAll().Return([]models.Category{
+		{ID: 2, Name: "Мебель", ParentID: 1, Position: 1},
+		{ID: 4, Name: "Стирка", Position: 2},
+	}, nil)
+
+	catalog, err := service.Catalog(false)
+
+	assert.NoError(t, err)
+	ids, depths := catalogIDs(catalog)
+	assert.Equal(t, []int{2, 4}, ids)
+	assert.Equal(t, []int{0, 0}, depths)
+}
+
+func TestCategoryServiceMove(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     int
+		offset int
+		ids    []int
+	}{
+		{name: "up among subcategories", id: 2, offset: -1, ids: []int{1, 4, 2, 3, 5, 6}},
+		{name: "down at the top level", id: 4, offset: 1, ids: []int{1, 5, 3, 2, 4, 6}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mocks := newCategoryMocks(t)
+			service := services.NewCategoryService(mocks.categoryRepository, mocks.taskRepository, log.New(io.Discard))
+
+			mocks.categoryRepository.EXPECT().GetAll().Return(append([]models.Category(nil), categoryTree...), nil)
+			mocks.categoryRepository.EXPECT().Reorder(test.ids).Return(nil)
+
+			err := service.Move(test.id, test.offset)
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCategoryServiceMove_Edge(t *testing.T) {
+	mocks := newCategoryMocks(t)
+	service := services.NewCategoryService(mocks.categoryRepository, mocks.taskRepository, log.New(io.Discard))
+
+	mocks.categoryRepository.EXPECT().GetAll().Return(append([]models.Category(nil), categoryTree...), nil)
+
+	// the first category stays first, so the order is not saved
+	err := service.Move(1, -1)
+
+	assert.NoError(t, err)
+}
+
+func TestCategoryServiceUpdate_NestedInItself(t *testing.T) {
+	mocks := newCategoryMocks(t)
+	service := services.NewCategoryService(mocks.categoryRepository, mocks.taskRepository, log.New(io.Discard))
+
+	mocks.categoryRepository.EXPECT().GetByID(2).Return(&categoryTree[3], nil)
+
+	category, err := service.Update(&models.Category{ID: 1, Name: "Химчистка", ParentID: 2})
+
+	assert.ErrorIs(t, err, service_errors.InvalidCategory)
+	assert.Nil(t, category)
+}
+
+func TestCategoryServiceCreate_Nested(t *testing.T) {
+	mocks := newCategoryMocks(t)
+	service := services.NewCategoryService(mocks.categoryRepository, mocks.taskRepository, log.New(io.Discard))
+	category := &models.Category{Name: "Ковры", ParentID: 1, Hidden: true}
+
+	mocks.categoryRepository.EXPECT().GetByID(1).Return(&categoryTree[0], nil)
+	mocks.categoryRepository.EXPECT().Create(category).Return(&models.Category{ID: 3, Name: "Ковры", ParentID: 1, Hidden: true}, nil)
+
+	created, err := service.Create("Ковры", 1, true, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created.ParentID)
+}
+
+func TestCategoryServiceDelete_HasSubcategories(t *testing.T) {
+	mocks := newCategoryMocks(t)
+	service := services.NewCategoryService(mocks.categoryRepository, mocks.taskRepository, log.New(io.Discard))
+
+	mocks.categoryRepository.EXPECT().GetAll().Return(categoryTree, nil)
+
+	err := service.Delete(1)
+
+	assert.ErrorIs(t, err, service_errors.CategoryHasSubcategories)
+}
+
+func TestCategoryServiceMerge_Subcategories(t *testing.T) {
+	mocks := newCategoryMocks(t)
+	service := services.NewCategoryService(mocks.categoryRepository, mocks.taskRepository, log.New(io.Discard))
+
+	mocks.categoryRepository.EXPECT().GetByID(1).Return(&categoryTree[0], nil)
+	mocks.categoryRepository.EXPECT().GetByID(4).Return(&categoryTree[1], nil).AnyTimes()
+	mocks.categoryRepository.EXPECT().GetAll().Return(categoryTree, nil)
+	gomock.InOrder(
+		mocks.categoryRepository.EXPECT().Update(&models.Category{ID: 3, Name: "Ковры", ParentID: 4, Position: 3, Hidden: true}).Return(nil, nil),
+		mocks.categoryRepository.EXPECT().Update(&models.Category{ID: 2, Name: "Мебель", ParentID: 4, Position: 4}).Return(nil, nil),
+		mocks.taskRepository.EXPECT().MoveToCategory(1, 4).Return(0, nil),
+		mocks.categoryRepository.EXPECT().Delete(1).Return(nil),
+	)
+
+	err := service.Merge(1, 4)
+
+	assert.NoError(t, err)
+}
+
+func TestCategoryServiceMerge_IntoSubcategory(t *testing.T) {
+	mocks := newCategoryMocks(t)
+	service := services.NewCategoryService(mocks.categoryRepository, mocks.taskRepository, log.New(io.Discard))
+
+	mocks.categoryRepository.EXPECT().GetByID(1).Return(&categoryTree[0], nil)
+	mocks.categoryRepository.EXPECT().GetByID(2).Return(&categoryTree[3], nil).AnyTimes()
+
+	err := service.Merge(1, 2)
+
+	assert.ErrorIs(t, err, service_errors.InvalidCategory)
+}
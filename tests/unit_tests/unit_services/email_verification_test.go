@@ -0,0 +1,90 @@
+package unit_services
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	services "lab3/internal/services"
+	"lab3/internal/services/service_errors"
+	"lab3/mail_sender"
+	mock_repository_interfaces "lab3/tests/repository_mocks"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendUserVerification_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userRepository := mock_repository_interfaces.NewMockIUserRepository(ctrl)
+	tokenRepository := new(MockOneTimeTokenRepository)
+	sender := mail_sender.NewMemorySender()
+	service := services.NewEmailVerificationService(userRepository, tokenRepository, sender, "http://localhost", time.Hour, true, log.New(io.Discard))
+
+	user := &models.User{ID: uuid.New(), Name: "Name", Email: "test@gmail.com"}
+	userRepository.EXPECT().GetUserByID(user.ID).Return(user, nil)
+	tokenRepository.On("DeleteByUserID", models.EmailVerificationPurpose, user.ID).Return(nil)
+	tokenRepository.On("Create", mock.Anything).Return(nil)
+
+	err := service.SendUserVerification(user.ID)
+
+	assert.NoError(t, err)
+	messages := sender.Messages()
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "test@gmail.com", messages[0].To)
+	assert.True(t, strings.Contains(messages[0].Body, "http://localhost/auth/verify-email?token="))
+	tokenRepository.AssertExpectations(t)
+}
+
+func TestSendUserVerification_AlreadyVerified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userRepository := mock_repository_interfaces.NewMockIUserRepository(ctrl)
+	tokenRepository := new(MockOneTimeTokenRepository)
+	sender := mail_sender.NewMemorySender()
+	service := services.NewEmailVerificationService(userRepository, tokenRepository, sender, "http://localhost", time.Hour, true, log.New(io.Discard))
+
+	user := &models.User{ID: uuid.New(), Email: "test@gmail.com", EmailVerified: true}
+	userRepository.EXPECT().GetUserByID(user.ID).Return(user, nil)
+
+	err := service.SendUserVerification(user.ID)
+
+	assert.ErrorIs(t, err, service_errors.EmailAlreadyVerified)
+	assert.Empty(t, sender.Messages())
+}
+
+func TestVerifyUser_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userRepository := mock_repository_interfaces.NewMockIUserRepository(ctrl)
+	tokenRepository := new(MockOneTimeTokenRepository)
+	service := services.NewEmailVerificationService(userRepository, tokenRepository, mail_sender.NewMemorySender(), "http://localhost", time.Hour, true, log.New(io.Discard))
+
+	user := &models.User{ID: uuid.New(), Email: "test@gmail.com"}
+	tokenRepository.On("Consume", mock.Anything, models.EmailVerificationPurpose, mock.Anything).Return(&models.OneTimeToken{UserID: user.ID}, nil)
+	userRepository.EXPECT().GetUserByID(user.ID).Return(user, nil)
+	userRepository.EXPECT().Update(gomock.Any()).DoAndReturn(func(updated *models.User) (*models.User, error) {
+		return updated, nil
+	})
+
+	verified, err := service.VerifyUser("token")
+
+	assert.NoError(t, err)
+	assert.True(t, verified.EmailVerified)
+	tokenRepository.AssertExpectations(t)
+}
+
+func TestVerifyUser_InvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tokenRepository := new(MockOneTimeTokenRepository)
+	service := services.NewEmailVerificationService(mock_repository_interfaces.NewMockIUserRepository(ctrl), tokenRepository, mail_sender.NewMemorySender(), "http://localhost", time.Hour, true, log.New(io.Discard))
+
+	tokenRepository.On("Consume", mock.Anything, models.EmailVerificationPurpose, mock.Anything).Return(nil, repository_errors.DoesNotExist)
+
+	verified, err := service.VerifyUser("token")
+
+	assert.ErrorIs(t, err, service_errors.InvalidToken)
+	assert.Nil(t, verified)
+}
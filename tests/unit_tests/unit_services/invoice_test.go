@@ -0,0 +1,128 @@
+package unit_services
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	services "lab3/internal/services"
+	"lab3/internal/services/service_errors"
+	"lab3/internal/services/service_interfaces"
+	mock_repository_interfaces "lab3/tests/repository_mocks"
+	"testing"
+	"time"
+)
+
+type invoiceMocks struct {
+	invoiceRepository  *mock_repository_interfaces.MockIInvoiceRepository
+	orderRepository    *mock_repository_interfaces.MockIOrderRepository
+	taskRepository     *mock_repository_interfaces.MockITaskRepository
+	userRepository     *mock_repository_interfaces.MockIUserRepository
+	categoryRepository *mock_repository_interfaces.MockICategoryRepository
+}
+
+var seller = models.Requisites{Company: "ООО Чистота", INN: "7700000000"}
+
+func newInvoiceService(t *testing.T, tax services.TaxPolicy) (service_interfaces.IInvoiceService, invoiceMocks) {
+	ctrl := gomock.NewController(t)
+	mocks := invoiceMocks{
+		invoiceRepository:  mock_repository_interfaces.NewMockIInvoiceRepository(ctrl),
+		orderRepository:    mock_repository_interfaces.NewMockIOrderRepository(ctrl),
+		taskRepository:     mock_repository_interfaces.NewMockITaskRepository(ctrl),
+		userRepository:     mock_repository_interfaces.NewMockIUserRepository(ctrl),
+		categoryRepository: mock_repository_interfaces.NewMockICategoryRepository(ctrl),
+	}
+	orderService := services.NewOrderService(mocks.orderRepository, mock_repository_interfaces.NewMockIWorkerRepository(ctrl), mocks.taskRepository, mocks.userRepository, mocks.categoryRepository, tax, true, log.New(io.Discard))
+	service := services.NewInvoiceService(mocks.invoiceRepository, orderService, mocks.userRepository, seller, log.New(io.Discard))
+	return service, mocks
+}
+
+func TestInvoiceServiceIssue_Success(t *testing.T) {
+	service, mocks := newInvoiceService(t, services.TaxPolicy{
+		PricesIncludeTax: true,
+		DefaultRate:      "vat22",
+		Rates:            []models.TaxRate{{Code: "vat22", Name: "НДС 22%", Rate: 22}},
+	})
+	created := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	user := &models.User{ID: uuid.New(), Name: "Иван", Surname: "Иванов"}
+	order := &models.Order{ID: uuid.New(), UserID: user.ID, Address: "Test Address", Status: models.CompletedOrderStatus, CreationDate: created}
+	task := models.Task{ID: uuid.New(), Name: "Уборка", PricePerSingle: 300, Category: 1}
+
+	mocks.invoiceRepository.EXPECT().GetByOrderID(order.ID).Return(nil, repository_errors.DoesNotExist)
+	mocks.orderRepository.EXPECT().GetOrderByID(order.ID).Return(order, nil).Times(2)
+	mocks.userRepository.EXPECT().GetUserByID(user.ID).Return(user, nil)
+	mocks.orderRepository.EXPECT().GetTasksInOrder(order.ID).Return([]models.Task{task}, nil)
+	mocks.categoryRepository.EXPECT().GetByID(1).Return(&models.Category{ID: 1, Name: "Уборка"}, nil)
+	mocks.taskRepository.EXPECT().GetPrices(task.ID).Return([]models.TaskPrice{
+		{TaskID: task.ID, Price: 200, EffectiveFrom: time.Unix(0, 0)},
+		{TaskID: task.ID, Price: 300, EffectiveFrom: created.Add(time.Hour)},
+	}, nil)
+	mocks.orderRepository.EXPECT().GetTaskQuantity(order.ID, task.ID).Return(3, nil)
+	mocks.invoiceRepository.EXPECT().Create(gomock.Any()).DoAndReturn(func(invoice *models.Invoice) (*models.Invoice, error) {
+		created := *invoice
+		created.ID = uuid.New()
+		created.Number = 7
+		return &created, nil
+	})
+
+	invoice, err := service.Issue(order.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, invoice.Number)
+	assert.Equal(t, seller, invoice.Seller)
+	assert.Equal(t, "Иван Иванов", invoice.Buyer)
+	assert.Equal(t, "Test Address", invoice.Address)
+	assert.Equal(t, []models.InvoiceLine{{Name: "Уборка", Price: 200, Quantity: 3, Amount: 600, TaxName: "НДС 22%", Tax: 108.2}}, invoice.Lines)
+	assert.Equal(t, 600.0, invoice.Total)
+	assert.Equal(t, 108.2, invoice.Tax())
+	assert.Equal(t, 491.8, invoice.Net())
+}
+
+func TestInvoiceServiceIssue_AlreadyIssued(t *testing.T) {
+	service, mocks := newInvoiceService(t, services.TaxPolicy{})
+	issued := &models.Invoice{ID: uuid.New(), Number: 1, OrderID: uuid.New()}
+
+	mocks.invoiceRepository.EXPECT().GetByOrderID(issued.OrderID).Return(issued, nil)
+
+	invoice, err := service.Issue(issued.OrderID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, issued, invoice)
+}
+
+func TestInvoiceServiceIssue_NotCompleted(t *testing.T) {
+	service, mocks := newInvoiceService(t, services.TaxPolicy{})
+	order := &models.Order{ID: uuid.New(), Status: models.InProgressOrderStatus}
+
+	mocks.invoiceRepository.EXPECT().GetByOrderID(order.ID).Return(nil, repository_errors.DoesNotExist)
+	mocks.orderRepository.EXPECT().GetOrderByID(order.ID).Return(order, nil)
+
+	invoice, err := service.Issue(order.ID)
+
+	assert.ErrorIs(t, err, service_errors.OrderIsNotCompleted)
+	assert.Nil(t, invoice)
+}
+
+func TestInvoiceServiceIssue_IssuedConcurrently(t *testing.T) {
+	service, mocks := newInvoiceService(t, services.TaxPolicy{})
+	user := &models.User{ID: uuid.New(), Name: "Иван", Surname: "Иванов"}
+	order := &models.Order{ID: uuid.New(), UserID: user.ID, Status: models.CompletedOrderStatus}
+	issued := &models.Invoice{ID: uuid.New(), Number: 1, OrderID: order.ID}
+
+	gomock.InOrder(
+		mocks.invoiceRepository.EXPECT().GetByOrderID(order.ID).Return(nil, repository_errors.DoesNotExist),
+		mocks.invoiceRepository.EXPECT().Create(gomock.Any()).Return(nil, repository_errors.AlreadyExists),
+		mocks.invoiceRepository.EXPECT().GetByOrderID(order.ID).Return(issued, nil),
+	)
+	mocks.orderRepository.EXPECT().GetOrderByID(order.ID).Return(order, nil).Times(2)
+	mocks.userRepository.EXPECT().GetUserByID(user.ID).Return(user, nil)
+	mocks.orderRepository.EXPECT().GetTasksInOrder(order.ID).Return(nil, nil)
+
+	invoice, err := service.Issue(order.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, issued, invoice)
+}
@@ -0,0 +1,127 @@
+package unit_services
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	services "lab3/internal/services"
+	"lab3/internal/services/service_errors"
+	"testing"
+	"time"
+)
+
+// Mock repository
+type MockLoginAttemptRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginAttemptRepository) GetByKey(key string) (*models.LoginAttempt, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginAttempt), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) RegisterFailure(key string, at time.Time, resetBefore time.Time) (*models.LoginAttempt, error) {
+	args := m.Called(key, at, resetBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginAttempt), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) Lock(key string, until time.Time) error {
+	args := m.Called(key, until)
+	return args.Error(0)
+}
+
+func (m *MockLoginAttemptRepository) Delete(key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockLoginAttemptRepository) GetLocked(at time.Time) ([]models.LoginAttempt, error) {
+	args := m.Called(at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LoginAttempt), args.Error(1)
+}
+
+var testLoginAttemptPolicy = services.LoginAttemptPolicy{
+	MaxAttempts:     5,
+	IPMaxAttempts:   20,
+	FreeAttempts:    3,
+	BaseDelay:       time.Second,
+	MaxDelay:        30 * time.Second,
+	LockoutDuration: 15 * time.Minute,
+	Window:          15 * time.Minute,
+}
+
+func TestCheckLoginAttempt_NoFailures(t *testing.T) {
+	mockRepository := new(MockLoginAttemptRepository)
+	mockRepository.On("GetByKey", "worker:default@admin.com").Return(nil, repository_errors.DoesNotExist)
+	mockRepository.On("GetByKey", "ip:127.0.0.1").Return(nil, repository_errors.DoesNotExist)
+	service := services.NewLoginAttemptService(mockRepository, testLoginAttemptPolicy, log.New(io.Discard))
+
+	wait, err := service.Check(models.WorkerLoginScope, "Default@admin.com", "127.0.0.1")
+
+	assert.NoError(t, err)
+	assert.Zero(t, wait)
+	mockRepository.AssertExpectations(t)
+}
+
+func TestCheckLoginAttempt_Locked(t *testing.T) {
+	mockRepository := new(MockLoginAttemptRepository)
+	attempt := &models.LoginAttempt{Key: "user:test@gmail.com", Failures: 5, LastFailure: time.Now(), LockedUntil: time.Now().Add(10 * time.Minute)}
+	mockRepository.On("GetByKey", "user:test@gmail.com").Return(attempt, nil)
+	service := services.NewLoginAttemptService(mockRepository, testLoginAttemptPolicy, log.New(io.Discard))
+
+	wait, err := service.Check(models.UserLoginScope, "test@gmail.com", "127.0.0.1")
+
+	assert.ErrorIs(t, err, service_errors.LoginLocked)
+	assert.Greater(t, wait, 9*time.Minute)
+	mockRepository.AssertExpectations(t)
+}
+
+func TestCheckLoginAttempt_Throttled(t *testing.T) {
+	mockRepository := new(MockLoginAttemptRepository)
+	attempt := &models.LoginAttempt{Key: "user:test@gmail.com", Failures: 4, LastFailure: time.Now()}
+	mockRepository.On("GetByKey", "user:test@gmail.com").Return(attempt, nil)
+	service := services.NewLoginAttemptService(mockRepository, testLoginAttemptPolicy, log.New(io.Discard))
+
+	wait, err := service.Check(models.UserLoginScope, "test@gmail.com", "127.0.0.1")
+
+	assert.ErrorIs(t, err, service_errors.LoginThrottled)
+	assert.Greater(t, wait, time.Second)
+	mockRepository.AssertExpectations(t)
+}
+
+func TestRegisterLoginFailure_LocksAccount(t *testing.T) {
+	mockRepository := new(MockLoginAttemptRepository)
+	mockRepository.On("RegisterFailure", "user:test@gmail.com", mock.Anything, mock.Anything).Return(&models.LoginAttempt{Key: "user:test@gmail.com", Failures: 5}, nil)
+	mockRepository.On("RegisterFailure", "ip:127.0.0.1", mock.Anything, mock.Anything).Return(&models.LoginAttempt{Key: "ip:127.0.0.1", Failures: 5}, nil)
+	mockRepository.On("Lock", "user:test@gmail.com", mock.AnythingOfType("time.Time")).Return(nil)
+	service := services.NewLoginAttemptService(mockRepository, testLoginAttemptPolicy, log.New(io.Discard))
+
+	err := service.RegisterFailure(models.UserLoginScope, "test@gmail.com", "127.0.0.1")
+
+	assert.NoError(t, err)
+	mockRepository.AssertExpectations(t)
+	mockRepository.AssertNotCalled(t, "Lock", "ip:127.0.0.1", mock.Anything)
+}
+
+func TestUnlockLogin_Success(t *testing.T) {
+	mockRepository := new(MockLoginAttemptRepository)
+	mockRepository.On("Delete", "worker:default@admin.com").Return(nil)
+	service := services.NewLoginAttemptService(mockRepository, testLoginAttemptPolicy, log.New(io.Discard))
+
+	err := service.Unlock("worker:default@admin.com")
+
+	assert.NoError(t, err)
+	mockRepository.AssertExpectations(t)
+}
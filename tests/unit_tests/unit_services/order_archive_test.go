@@ -0,0 +1,72 @@
+package unit_services
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"lab3/internal/models"
+	"lab3/internal/services/service_errors"
+	"testing"
+	"time"
+)
+
+func TestArchiveOrders_Success(t *testing.T) {
+	service, mocks := newIdempotentOrderService(t)
+
+	mocks.orderRepository.EXPECT().Archive(gomock.Any()).DoAndReturn(func(closedBefore time.Time) (int, error) {
+		assert.WithinDuration(t, time.Now().AddDate(0, -6, 0), closedBefore, time.Minute)
+		return 2, nil
+	})
+
+	count, err := service.Archive(6)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestArchiveOrders_InvalidPeriod(t *testing.T) {
+	service, _ := newIdempotentOrderService(t)
+
+	count, err := service.Archive(0)
+
+	assert.ErrorIs(t, err, service_errors.InvalidArchivePeriod)
+	assert.Zero(t, count)
+}
+
+func TestUpdateOrder_Archived(t *testing.T) {
+	service, mocks := newIdempotentOrderService(t)
+	order := &models.Order{ID: uuid.New(), Status: models.CompletedOrderStatus, Version: 1, Archived: true}
+
+	mocks.orderRepository.EXPECT().GetOrderByID(order.ID).Return(order, nil)
+
+	updatedOrder, err := service.Update(order.ID, models.CompletedOrderStatus, 5, uuid.Nil, 1)
+
+	assert.ErrorIs(t, err, service_errors.OrderArchived)
+	assert.Nil(t, updatedOrder)
+}
+
+func TestUpdateOrder_ClosedAt(t *testing.T) {
+	service, mocks := newIdempotentOrderService(t)
+	order := &models.Order{ID: uuid.New(), Status: models.InProgressOrderStatus, Version: 1}
+
+	mocks.orderRepository.EXPECT().GetOrderByID(order.ID).Return(order, nil)
+	mocks.orderRepository.EXPECT().Update(gomock.Any()).DoAndReturn(func(order *models.Order) (*models.Order, error) {
+		assert.WithinDuration(t, time.Now(), order.ClosedAt, time.Minute)
+		return order, nil
+	})
+
+	_, err := service.Update(order.ID, models.CompletedOrderStatus, 0, uuid.Nil, 1)
+
+	assert.NoError(t, err)
+}
+
+func TestAddTask_ArchivedOrder(t *testing.T) {
+	service, mocks := newIdempotentOrderService(t)
+	order := &models.Order{ID: uuid.New(), Status: models.CancelledOrderStatus, Archived: true}
+
+	mocks.orderRepository.EXPECT().GetOrderByID(order.ID).Return(order, nil)
+
+	err := service.AddTask(order.ID, uuid.New())
+
+	assert.ErrorIs(t, err, service_errors.OrderArchived)
+}
@@ -0,0 +1,136 @@
+package unit_services
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	services "lab3/internal/services"
+	"lab3/internal/services/service_errors"
+	"lab3/internal/services/service_interfaces"
+	mock_repository_interfaces "lab3/tests/repository_mocks"
+	"strings"
+	"testing"
+	"time"
+)
+
+type orderServiceMocks struct {
+	orderRepository *mock_repository_interfaces.MockIOrderRepository
+	taskRepository  *mock_repository_interfaces.MockITaskRepository
+	userRepository  *mock_repository_interfaces.MockIUserRepository
+}
+
+func newIdempotentOrderService(t *testing.T) (service_interfaces.IOrderService, orderServiceMocks) {
+	return newVerifyingOrderService(t, true)
+}
+
+func newVerifyingOrderService(t *testing.T, requireEmailVerification bool) (service_interfaces.IOrderService, orderServiceMocks) {
+	ctrl := gomock.NewController(t)
+	mocks := orderServiceMocks{
+		orderRepository: mock_repository_interfaces.NewMockIOrderRepository(ctrl),
+		taskRepository:  mock_repository_interfaces.NewMockITaskRepository(ctrl),
+		userRepository:  mock_repository_interfaces.NewMockIUserRepository(ctrl),
+	}
+	service := services.NewOrderService(mocks.orderRepository, mock_repository_interfaces.NewMockIWorkerRepository(ctrl), mocks.taskRepository, mocks.userRepository, mock_repository_interfaces.NewMockICategoryRepository(ctrl), services.TaxPolicy{}, requireEmailVerification, log.New(io.Discard))
+	return service, mocks
+}
+
+func TestCreateOrderIdempotency_NewKey(t *testing.T) {
+	service, mocks := newIdempotentOrderService(t)
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), Name: "Task", PricePerSingle: 100, Category: 1}
+	orderedTasks := []models.OrderedTask{{Task: task, Quantity: 1}}
+
+	mocks.orderRepository.EXPECT().GetOrderByIdempotencyKey(userID, "key").Return(nil, repository_errors.DoesNotExist)
+	mocks.taskRepository.EXPECT().GetTaskByID(task.ID).Return(task, nil)
+	mocks.userRepository.EXPECT().GetUserByID(userID).Return(&models.User{ID: userID, EmailVerified: true}, nil)
+	mocks.orderRepository.EXPECT().Create(gomock.Any(), orderedTasks).DoAndReturn(func(order *models.Order, _ []models.OrderedTask) (*models.Order, error) {
+		order.ID = uuid.New()
+		return order, nil
+	})
+
+	order, err := service.CreateOrder(userID, "Test Address", time.Now().Add(24*time.Hour), orderedTasks, "key")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "key", order.IdempotencyKey)
+}
+
+func TestCreateOrderIdempotency_RepeatedKey(t *testing.T) {
+	service, mocks := newIdempotentOrderService(t)
+	userID := uuid.New()
+	existing := &models.Order{ID: uuid.New(), UserID: userID, IdempotencyKey: "key"}
+
+	mocks.orderRepository.EXPECT().GetOrderByIdempotencyKey(userID, "key").Return(existing, nil)
+
+	order, err := service.CreateOrder(userID, "Test Address", time.Now().Add(24*time.Hour), nil, "key")
+
+	assert.NoError(t, err)
+	assert.Equal(t, existing, order)
+}
+
+func TestCreateOrderIdempotency_ConcurrentKey(t *testing.T) {
+	service, mocks := newIdempotentOrderService(t)
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), Name: "Task", PricePerSingle: 100, Category: 1}
+	orderedTasks := []models.OrderedTask{{Task: task, Quantity: 1}}
+	existing := &models.Order{ID: uuid.New(), UserID: userID, IdempotencyKey: "key"}
+
+	gomock.InOrder(
+		mocks.orderRepository.EXPECT().GetOrderByIdempotencyKey(userID, "key").Return(nil, repository_errors.DoesNotExist),
+		mocks.orderRepository.EXPECT().GetOrderByIdempotencyKey(userID, "key").Return(existing, nil),
+	)
+	mocks.taskRepository.EXPECT().GetTaskByID(task.ID).Return(task, nil)
+	mocks.userRepository.EXPECT().GetUserByID(userID).Return(&models.User{ID: userID, EmailVerified: true}, nil)
+	mocks.orderRepository.EXPECT().Create(gomock.Any(), orderedTasks).Return(nil, repository_errors.AlreadyExists)
+
+	order, err := service.CreateOrder(userID, "Test Address", time.Now().Add(24*time.Hour), orderedTasks, "key")
+
+	assert.NoError(t, err)
+	assert.Equal(t, existing, order)
+}
+
+func TestCreateOrderIdempotency_KeyTooLong(t *testing.T) {
+	service, _ := newIdempotentOrderService(t)
+
+	order, err := service.CreateOrder(uuid.New(), "Test Address", time.Now().Add(24*time.Hour), nil, strings.Repeat("k", 256))
+
+	assert.ErrorIs(t, err, service_errors.InvalidIdempotencyKey)
+	assert.Nil(t, order)
+}
+
+func TestCreateOrder_EmailNotVerified(t *testing.T) {
+	service, mocks := newIdempotentOrderService(t)
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), Name: "Task", PricePerSingle: 100, Category: 1}
+	orderedTasks := []models.OrderedTask{{Task: task, Quantity: 1}}
+
+	mocks.taskRepository.EXPECT().GetTaskByID(task.ID).Return(task, nil)
+	mocks.userRepository.EXPECT().GetUserByID(userID).Return(&models.User{ID: userID}, nil)
+
+	order, err := service.CreateOrder(userID, "Test Address", time.Now().Add(24*time.Hour), orderedTasks, "")
+
+	assert.ErrorIs(t, err, service_errors.EmailNotVerified)
+	assert.Nil(t, order)
+}
+
+func TestCreateOrder_EmailVerificationNotRequired(t *testing.T) {
+	service, mocks := newVerifyingOrderService(t, false)
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), Name: "Task", PricePerSingle: 100, Category: 1}
+	orderedTasks := []models.OrderedTask{{Task: task, Quantity: 1}}
+
+	mocks.taskRepository.EXPECT().GetTaskByID(task.ID).Return(task, nil)
+	mocks.userRepository.EXPECT().GetUserByID(userID).Return(&models.User{ID: userID}, nil)
+	mocks.orderRepository.EXPECT().Create(gomock.Any(), orderedTasks).DoAndReturn(func(order *models.Order, _ []models.OrderedTask) (*models.Order, error) {
+		order.ID = uuid.New()
+		return order, nil
+	})
+
+	order, err := service.CreateOrder(userID, "Test Address", time.Now().Add(24*time.Hour), orderedTasks, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, userID, order.UserID)
+}
@@ -0,0 +1,55 @@
+package unit_services
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	"lab3/internal/services/service_errors"
+	"testing"
+)
+
+func TestUpdateOrder_StaleVersion(t *testing.T) {
+	service, mocks := newIdempotentOrderService(t)
+	order := &models.Order{ID: uuid.New(), Status: models.InProgressOrderStatus, Version: 3}
+
+	mocks.orderRepository.EXPECT().GetOrderByID(order.ID).Return(order, nil)
+
+	updatedOrder, err := service.Update(order.ID, models.CompletedOrderStatus, 0, uuid.Nil, 2)
+
+	assert.ErrorIs(t, err, service_errors.OrderVersionConflict)
+	assert.Nil(t, updatedOrder)
+}
+
+func TestUpdateOrder_ConcurrentUpdate(t *testing.T) {
+	service, mocks := newIdempotentOrderService(t)
+	order := &models.Order{ID: uuid.New(), Status: models.InProgressOrderStatus, Version: 3}
+
+	mocks.orderRepository.EXPECT().GetOrderByID(order.ID).Return(order, nil)
+	mocks.orderRepository.EXPECT().Update(gomock.Any()).Return(nil, repository_errors.VersionConflict)
+
+	updatedOrder, err := service.Update(order.ID, models.CompletedOrderStatus, 0, uuid.Nil, 3)
+
+	assert.ErrorIs(t, err, service_errors.OrderVersionConflict)
+	assert.Nil(t, updatedOrder)
+}
+
+func TestUpdateOrder_CurrentVersion(t *testing.T) {
+	service, mocks := newIdempotentOrderService(t)
+	order := &models.Order{ID: uuid.New(), Status: models.InProgressOrderStatus, Version: 3}
+
+	mocks.orderRepository.EXPECT().GetOrderByID(order.ID).Return(order, nil)
+	mocks.orderRepository.EXPECT().Update(gomock.Any()).DoAndReturn(func(order *models.Order) (*models.Order, error) {
+		assert.Equal(t, 3, order.Version)
+		updatedOrder := *order
+		updatedOrder.Version++
+		return &updatedOrder, nil
+	})
+
+	updatedOrder, err := service.Update(order.ID, models.CompletedOrderStatus, 0, uuid.Nil, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, updatedOrder.Version)
+	assert.Equal(t, models.CompletedOrderStatus, updatedOrder.Status)
+}
@@ -0,0 +1,117 @@
+package unit_services
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"lab3/internal/models"
+	services "lab3/internal/services"
+	"lab3/password_hash"
+	mock_repository_interfaces "lab3/tests/repository_mocks"
+	"strings"
+	"testing"
+)
+
+var testArgon2idParams = password_hash.Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func newTestPasswordHashRegistry() *password_hash.Registry {
+	return password_hash.NewRegistry(password_hash.NewArgon2idHash(testArgon2idParams), password_hash.NewBcryptHash(4))
+}
+
+func TestPasswordHashRegistry_ComparesBothFormats(t *testing.T) {
+	registry := newTestPasswordHashRegistry()
+
+	argon2idHash, err := registry.GetHash("password123")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(argon2idHash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.True(t, registry.CompareHashAndPassword(argon2idHash, "password123"))
+	assert.False(t, registry.CompareHashAndPassword(argon2idHash, "password124"))
+	assert.False(t, registry.NeedsRehash(argon2idHash))
+
+	bcryptHash, err := password_hash.NewBcryptHash(4).GetHash("password123")
+	assert.NoError(t, err)
+	assert.True(t, registry.CompareHashAndPassword(bcryptHash, "password123"))
+	assert.True(t, registry.NeedsRehash(bcryptHash))
+
+	assert.False(t, registry.CompareHashAndPassword("plain", "plain"))
+}
+
+func TestPasswordHashRegistry_NeedsRehashOnChangedParams(t *testing.T) {
+	oldHash, err := password_hash.NewArgon2idHash(testArgon2idParams).GetHash("password123")
+	assert.NoError(t, err)
+
+	params := testArgon2idParams
+	params.Iterations = 2
+	registry := password_hash.NewRegistry(password_hash.NewArgon2idHash(params))
+
+	assert.True(t, registry.CompareHashAndPassword(oldHash, "password123"))
+	assert.True(t, registry.NeedsRehash(oldHash))
+}
+
+func TestUserLogin_RehashesBcryptPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userRepository := mock_repository_interfaces.NewMockIUserRepository(ctrl)
+	service := services.NewUserService(userRepository, newTestPasswordHashRegistry(), log.New(io.Discard))
+
+	bcryptHash, _ := password_hash.NewBcryptHash(4).GetHash("password123")
+	user := &models.User{ID: uuid.New(), Email: "test@gmail.com", Password: bcryptHash}
+	userRepository.EXPECT().GetUserByEmail(user.Email).Return(user, nil)
+	userRepository.EXPECT().Update(gomock.Any()).DoAndReturn(func(updated *models.User) (*models.User, error) {
+		assert.True(t, strings.HasPrefix(updated.Password, "$argon2id$"))
+		return updated, nil
+	})
+
+	loggedIn, err := service.Login(user.Email, "password123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, loggedIn.ID)
+}
+
+func TestUserLogin_KeepsCurrentHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userRepository := mock_repository_interfaces.NewMockIUserRepository(ctrl)
+	registry := newTestPasswordHashRegistry()
+	service := services.NewUserService(userRepository, registry, log.New(io.Discard))
+
+	argon2idHash, _ := registry.GetHash("password123")
+	user := &models.User{ID: uuid.New(), Email: "test@gmail.com", Password: argon2idHash}
+	userRepository.EXPECT().GetUserByEmail(user.Email).Return(user, nil)
+
+	_, err := service.Login(user.Email, "password123")
+
+	assert.NoError(t, err)
+}
+
+func TestWorkerLogin_RehashesBcryptPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	workerRepository := mock_repository_interfaces.NewMockIWorkerRepository(ctrl)
+	service := services.NewWorkerService(workerRepository, newTestPasswordHashRegistry(), log.New(io.Discard))
+
+	bcryptHash, _ := password_hash.NewBcryptHash(4).GetHash("password123")
+	worker := &models.Worker{ID: uuid.New(), Email: "worker@gmail.com", Password: bcryptHash}
+	workerRepository.EXPECT().GetWorkerByEmail(worker.Email).Return(worker, nil)
+	workerRepository.EXPECT().Update(gomock.Any()).DoAndReturn(func(updated *models.Worker) (*models.Worker, error) {
+		assert.True(t, strings.HasPrefix(updated.Password, "$argon2id$"))
+		return updated, nil
+	})
+
+	_, err := service.Login(worker.Email, "password123")
+
+	assert.NoError(t, err)
+}
+
+func TestWorkerLogin_WrongPasswordIsNotRehashed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	workerRepository := mock_repository_interfaces.NewMockIWorkerRepository(ctrl)
+	service := services.NewWorkerService(workerRepository, newTestPasswordHashRegistry(), log.New(io.Discard))
+
+	bcryptHash, _ := password_hash.NewBcryptHash(4).GetHash("password123")
+	worker := &models.Worker{ID: uuid.New(), Email: "worker@gmail.com", Password: bcryptHash}
+	workerRepository.EXPECT().GetWorkerByEmail(worker.Email).Return(worker, nil)
+
+	_, err := service.Login(worker.Email, "password124")
+
+	assert.Error(t, err)
+}
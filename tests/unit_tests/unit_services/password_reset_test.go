@@ -0,0 +1,137 @@
+package unit_services
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	services "lab3/internal/services"
+	"lab3/internal/services/service_errors"
+	"lab3/mail_sender"
+	"lab3/password_hash"
+	mock_repository_interfaces "lab3/tests/repository_mocks"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// Mock repository
+type MockOneTimeTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockOneTimeTokenRepository) Create(token *models.OneTimeToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockOneTimeTokenRepository) Consume(hash string, purpose string, at time.Time) (*models.OneTimeToken, error) {
+	args := m.Called(hash, purpose, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OneTimeToken), args.Error(1)
+}
+
+func (m *MockOneTimeTokenRepository) DeleteByUserID(purpose string, userID uuid.UUID) error {
+	args := m.Called(purpose, userID)
+	return args.Error(0)
+}
+
+func (m *MockOneTimeTokenRepository) DeleteByWorkerID(purpose string, workerID uuid.UUID) error {
+	args := m.Called(purpose, workerID)
+	return args.Error(0)
+}
+
+func TestResetUserPassword_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userRepository := mock_repository_interfaces.NewMockIUserRepository(ctrl)
+	workerRepository := mock_repository_interfaces.NewMockIWorkerRepository(ctrl)
+	tokenRepository := new(MockOneTimeTokenRepository)
+	sessionRepository := new(MockSessionRepository)
+	sender := mail_sender.NewMemorySender()
+	service := services.NewPasswordResetService(userRepository, workerRepository, tokenRepository, sessionRepository, password_hash.NewPasswordHash(), sender, "http://localhost", time.Hour, log.New(io.Discard))
+
+	user := &models.User{ID: uuid.New(), Name: "Name", Email: "test@gmail.com", Password: "old_hash"}
+	userRepository.EXPECT().GetUserByEmail("test@gmail.com").Return(user, nil)
+	userRepository.EXPECT().GetUserByID(user.ID).Return(user, nil)
+	userRepository.EXPECT().Update(gomock.Any()).DoAndReturn(func(updated *models.User) (*models.User, error) {
+		assert.NotEqual(t, "old_hash", updated.Password)
+		return updated, nil
+	})
+
+	var stored *models.OneTimeToken
+	tokenRepository.On("DeleteByUserID", models.UserPasswordResetPurpose, user.ID).Return(nil)
+	tokenRepository.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*models.OneTimeToken)
+	}).Return(nil)
+
+	err := service.RequestUserReset("test@gmail.com")
+	assert.NoError(t, err)
+
+	messages := sender.Messages()
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "test@gmail.com", messages[0].To)
+
+	link := regexp.MustCompile(`http://localhost/auth/reset-password\?token=\S+`).FindString(messages[0].Body)
+	assert.NotEmpty(t, link)
+	parsed, err := url.Parse(link)
+	assert.NoError(t, err)
+	token := parsed.Query().Get("token")
+	assert.NotEqual(t, stored.Hash, token)
+
+	tokenRepository.On("Consume", stored.Hash, models.UserPasswordResetPurpose, mock.Anything).Return(stored, nil)
+	sessionRepository.On("DeleteByUserID", user.ID).Return(nil)
+
+	err = service.ResetUserPassword(token, "newPassword123")
+
+	assert.NoError(t, err)
+	tokenRepository.AssertExpectations(t)
+	sessionRepository.AssertExpectations(t)
+}
+
+func TestRequestUserReset_UnknownEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userRepository := mock_repository_interfaces.NewMockIUserRepository(ctrl)
+	workerRepository := mock_repository_interfaces.NewMockIWorkerRepository(ctrl)
+	tokenRepository := new(MockOneTimeTokenRepository)
+	sender := mail_sender.NewMemorySender()
+	service := services.NewPasswordResetService(userRepository, workerRepository, tokenRepository, new(MockSessionRepository), password_hash.NewPasswordHash(), sender, "http://localhost", time.Hour, log.New(io.Discard))
+
+	userRepository.EXPECT().GetUserByEmail("unknown@gmail.com").Return(nil, repository_errors.DoesNotExist)
+
+	err := service.RequestUserReset("unknown@gmail.com")
+
+	assert.NoError(t, err)
+	assert.Empty(t, sender.Messages())
+	tokenRepository.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestResetWorkerPassword_InvalidPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tokenRepository := new(MockOneTimeTokenRepository)
+	service := services.NewPasswordResetService(mock_repository_interfaces.NewMockIUserRepository(ctrl), mock_repository_interfaces.NewMockIWorkerRepository(ctrl), tokenRepository, new(MockSessionRepository), password_hash.NewPasswordHash(), mail_sender.NewMemorySender(), "http://localhost", time.Hour, log.New(io.Discard))
+
+	err := service.ResetWorkerPassword("token", "short")
+
+	assert.ErrorIs(t, err, service_errors.InvalidPassword)
+	tokenRepository.AssertNotCalled(t, "Consume", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResetWorkerPassword_InvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tokenRepository := new(MockOneTimeTokenRepository)
+	service := services.NewPasswordResetService(mock_repository_interfaces.NewMockIUserRepository(ctrl), mock_repository_interfaces.NewMockIWorkerRepository(ctrl), tokenRepository, new(MockSessionRepository), password_hash.NewPasswordHash(), mail_sender.NewMemorySender(), "http://localhost", time.Hour, log.New(io.Discard))
+
+	tokenRepository.On("Consume", mock.Anything, models.WorkerPasswordResetPurpose, mock.Anything).Return(nil, repository_errors.DoesNotExist)
+
+	err := service.ResetWorkerPassword("token", "newPassword123")
+
+	assert.ErrorIs(t, err, service_errors.InvalidToken)
+	tokenRepository.AssertExpectations(t)
+}
@@ -0,0 +1,124 @@
+package unit_services
+
+import (
+	"errors"
+	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"lab3/internal/models"
+	services "lab3/internal/services"
+	"lab3/internal/services/service_errors"
+	"testing"
+	"time"
+)
+
+// Mock repository
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) Save(session *models.Session) error {
+	args := m.Called(session)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) GetSessionByID(id string) (*models.Session, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) UpdateLastActivity(id string, lastActivity time.Time) error {
+	args := m.Called(id, lastActivity)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) DeleteByUserID(userID uuid.UUID) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) DeleteByWorkerID(workerID uuid.UUID) error {
+	args := m.Called(workerID)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) DeleteExpired(idleBefore time.Time, createdBefore time.Time) error {
+	args := m.Called(idleBefore, createdBefore)
+	return args.Error(0)
+}
+
+func TestResumeSession_Success(t *testing.T) {
+	mockRepository := new(MockSessionRepository)
+	session := &models.Session{ID: "id", CreatedAt: time.Now(), LastActivity: time.Now()}
+	mockRepository.On("GetSessionByID", "id").Return(session, nil)
+	mockRepository.On("UpdateLastActivity", "id", mock.AnythingOfType("time.Time")).Return(nil)
+	service := services.NewSessionService(mockRepository, time.Hour, 24*time.Hour, log.New(io.Discard))
+
+	resumed, err := service.Resume("id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "id", resumed.ID)
+	mockRepository.AssertExpectations(t)
+}
+
+func TestResumeSession_IdleExpired(t *testing.T) {
+	mockRepository := new(MockSessionRepository)
+	session := &models.Session{ID: "id", CreatedAt: time.Now().Add(-2 * time.Hour), LastActivity: time.Now().Add(-2 * time.Hour)}
+	mockRepository.On("GetSessionByID", "id").Return(session, nil)
+	mockRepository.On("Delete", "id").Return(nil)
+	service := services.NewSessionService(mockRepository, time.Hour, 24*time.Hour, log.New(io.Discard))
+
+	resumed, err := service.Resume("id")
+
+	assert.ErrorIs(t, err, service_errors.SessionExpired)
+	assert.Nil(t, resumed)
+	mockRepository.AssertExpectations(t)
+}
+
+func TestResumeSession_AbsoluteExpired(t *testing.T) {
+	mockRepository := new(MockSessionRepository)
+	session := &models.Session{ID: "id", CreatedAt: time.Now().Add(-25 * time.Hour), LastActivity: time.Now()}
+	mockRepository.On("GetSessionByID", "id").Return(session, nil)
+	mockRepository.On("Delete", "id").Return(nil)
+	service := services.NewSessionService(mockRepository, time.Hour, 24*time.Hour, log.New(io.Discard))
+
+	resumed, err := service.Resume("id")
+
+	assert.ErrorIs(t, err, service_errors.SessionExpired)
+	assert.Nil(t, resumed)
+	mockRepository.AssertExpectations(t)
+}
+
+func TestRevokeWorkerSessions_Success(t *testing.T) {
+	mockRepository := new(MockSessionRepository)
+	workerID := uuid.New()
+	mockRepository.On("DeleteByWorkerID", workerID).Return(nil)
+	service := services.NewSessionService(mockRepository, time.Hour, 24*time.Hour, log.New(io.Discard))
+
+	err := service.RevokeWorkerSessions(workerID)
+
+	assert.NoError(t, err)
+	mockRepository.AssertExpectations(t)
+}
+
+func TestRevokeUserSessions_Failure(t *testing.T) {
+	mockRepository := new(MockSessionRepository)
+	userID := uuid.New()
+	mockRepository.On("DeleteByUserID", userID).Return(errors.New("deletion failed"))
+	service := services.NewSessionService(mockRepository, time.Hour, 24*time.Hour, log.New(io.Discard))
+
+	err := service.RevokeUserSessions(userID)
+
+	assert.Error(t, err)
+	mockRepository.AssertExpectations(t)
+}
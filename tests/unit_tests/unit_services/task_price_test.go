@@ -0,0 +1,108 @@
+package unit_services
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	services "lab3/internal/services"
+	"lab3/internal/services/service_errors"
+	"testing"
+	"time"
+)
+
+func TestTaskServiceSchedulePrice_Success(t *testing.T) {
+	mocks := newCategoryMocks(t)
+	service := services.NewTaskService(mocks.taskRepository, mocks.categoryRepository, log.New(io.Discard))
+	task := &models.Task{ID: uuid.New(), Name: "Task", PricePerSingle: 100, Category: 1}
+	effectiveFrom := time.Now().Add(24 * time.Hour)
+	price := &models.TaskPrice{TaskID: task.ID, Price: 120, EffectiveFrom: effectiveFrom}
+
+	mocks.taskRepository.EXPECT().GetTaskByID(task.ID).Return(task, nil)
+	mocks.taskRepository.EXPECT().SchedulePrice(price).Return(&models.TaskPrice{ID: uuid.New(), TaskID: task.ID, Price: 120, EffectiveFrom: effectiveFrom}, nil)
+
+	scheduled, err := service.SchedulePrice(task.ID, 120, effectiveFrom)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 120.0, scheduled.Price)
+}
+
+func TestTaskServiceSchedulePrice_Past(t *testing.T) {
+	mocks := newCategoryMocks(t)
+	service := services.NewTaskService(mocks.taskRepository, mocks.categoryRepository, log.New(io.Discard))
+
+	scheduled, err := service.SchedulePrice(uuid.New(), 120, time.Now().Add(-time.Hour))
+
+	assert.ErrorIs(t, err, service_errors.InvalidEffectiveDate)
+	assert.Nil(t, scheduled)
+}
+
+func TestTaskServiceSchedulePrice_ArchivedTask(t *testing.T) {
+	mocks := newCategoryMocks(t)
+	service := services.NewTaskService(mocks.taskRepository, mocks.categoryRepository, log.New(io.Discard))
+	task := &models.Task{ID: uuid.New(), Name: "Task", PricePerSingle: 100, Category: 1, DeletedAt: time.Now()}
+
+	mocks.taskRepository.EXPECT().GetTaskByID(task.ID).Return(task, nil)
+
+	scheduled, err := service.SchedulePrice(task.ID, 120, time.Now().Add(24*time.Hour))
+
+	assert.ErrorIs(t, err, service_errors.Archived)
+	assert.Nil(t, scheduled)
+}
+
+func TestTaskServiceCancelPriceChange_NotUpcoming(t *testing.T) {
+	mocks := newCategoryMocks(t)
+	service := services.NewTaskService(mocks.taskRepository, mocks.categoryRepository, log.New(io.Discard))
+
+	mocks.taskRepository.EXPECT().GetUpcomingPrices(gomock.Any()).Return([]models.TaskPrice{{ID: uuid.New(), Price: 120}}, nil)
+
+	cancelled, err := service.CancelPriceChange(uuid.New())
+
+	assert.ErrorIs(t, err, repository_errors.DoesNotExist)
+	assert.Nil(t, cancelled)
+}
+
+func TestTaskServiceUpdate_RecordsPrice(t *testing.T) {
+	mocks := newCategoryMocks(t)
+	service := services.NewTaskService(mocks.taskRepository, mocks.categoryRepository, log.New(io.Discard))
+	task := &models.Task{ID: uuid.New(), Name: "Task", PricePerSingle: 100, Category: 1}
+
+	mocks.taskRepository.EXPECT().GetTaskByID(task.ID).Return(task, nil)
+	mocks.taskRepository.EXPECT().SchedulePrice(gomock.Any()).DoAndReturn(func(price *models.TaskPrice) (*models.TaskPrice, error) {
+		assert.Equal(t, task.ID, price.TaskID)
+		assert.Equal(t, 150.0, price.Price)
+		return price, nil
+	})
+	mocks.taskRepository.EXPECT().Update(gomock.Any()).DoAndReturn(func(task *models.Task) (*models.Task, error) {
+		return task, nil
+	})
+
+	updated, err := service.Update(task.ID, 1, "Task", 150, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 150.0, updated.PricePerSingle)
+}
+
+func TestGetTotalPrice_PriceAtCreation(t *testing.T) {
+	service, mocks := newArchiveOrderService(t)
+	created := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	order := &models.Order{ID: uuid.New(), CreationDate: created}
+	task := models.Task{ID: uuid.New(), Name: "Task", PricePerSingle: 300, Category: 1}
+
+	mocks.orderRepository.EXPECT().GetOrderByID(order.ID).Return(order, nil)
+	mocks.orderRepository.EXPECT().GetTasksInOrder(order.ID).Return([]models.Task{task}, nil)
+	mocks.taskRepository.EXPECT().GetPrices(task.ID).Return([]models.TaskPrice{
+		{TaskID: task.ID, Price: 100, EffectiveFrom: time.Unix(0, 0)},
+		{TaskID: task.ID, Price: 200, EffectiveFrom: created.Add(-time.Hour)},
+		{TaskID: task.ID, Price: 300, EffectiveFrom: created.Add(time.Hour)},
+	}, nil)
+	mocks.orderRepository.EXPECT().GetTaskQuantity(order.ID, task.ID).Return(2, nil)
+
+	total, err := service.GetTotalPrice(order.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 400.0, total)
+}
@@ -0,0 +1,115 @@
+package unit_services
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"lab3/internal/models"
+	services "lab3/internal/services"
+	"lab3/internal/services/service_interfaces"
+	mock_repository_interfaces "lab3/tests/repository_mocks"
+	"testing"
+	"time"
+)
+
+var taxRates = []models.TaxRate{
+	{Code: "vat22", Name: "НДС 22%", Rate: 22},
+	{Code: "vat10", Name: "НДС 10%", Rate: 10},
+	{Code: "none", Name: "Без НДС", Rate: 0},
+}
+
+func newTaxOrderService(t *testing.T, pricesIncludeTax bool) (service_interfaces.IOrderService, archiveMocks) {
+	ctrl := gomock.NewController(t)
+	mocks := archiveMocks{
+		orderRepository:    mock_repository_interfaces.NewMockIOrderRepository(ctrl),
+		workerRepository:   mock_repository_interfaces.NewMockIWorkerRepository(ctrl),
+		taskRepository:     mock_repository_interfaces.NewMockITaskRepository(ctrl),
+		userRepository:     mock_repository_interfaces.NewMockIUserRepository(ctrl),
+		categoryRepository: mock_repository_interfaces.NewMockICategoryRepository(ctrl),
+	}
+	service := services.NewOrderService(mocks.orderRepository, mocks.workerRepository, mocks.taskRepository, mocks.userRepository, mocks.categoryRepository,
+		services.TaxPolicy{PricesIncludeTax: pricesIncludeTax, DefaultRate: "vat22", Rates: taxRates}, true, log.New(io.Discard))
+	return service, mocks
+}
+
+func TestCalculateTotals_PricesIncludeTax(t *testing.T) {
+	service, mocks := newTaxOrderService(t, true)
+	own := &models.Task{ID: uuid.New(), Name: "Own", PricePerSingle: 110, Category: 1, TaxRate: "vat10"}
+	inherited := &models.Task{ID: uuid.New(), Name: "Inherited", PricePerSingle: 50, Category: 2}
+	byDefault := &models.Task{ID: uuid.New(), Name: "Default", PricePerSingle: 122, Category: 1}
+
+	// category 2 takes the rate of its parent, category 1 has none and the default rate applies
+	mocks.categoryRepository.EXPECT().GetByID(2).Return(&models.Category{ID: 2, ParentID: 3}, nil)
+	mocks.categoryRepository.EXPECT().GetByID(3).Return(&models.Category{ID: 3, TaxRate: "none"}, nil)
+	mocks.categoryRepository.EXPECT().GetByID(1).Return(&models.Category{ID: 1}, nil)
+
+	totals, err := service.CalculateTotals([]models.OrderedTask{
+		{Task: own, Quantity: 2},
+		{Task: inherited, Quantity: 3},
+		{Task: byDefault, Quantity: 1},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, totals.PricesIncludeTax)
+	assert.Len(t, totals.Lines, 3)
+	assert.Equal(t, models.OrderLine{Task: *own, Price: 110, Quantity: 2, TaxRate: taxRates[1], Net: 200, Tax: 20, Gross: 220}, totals.Lines[0])
+	assert.Equal(t, models.OrderLine{Task: *inherited, Price: 50, Quantity: 3, TaxRate: taxRates[2], Net: 150, Tax: 0, Gross: 150}, totals.Lines[1])
+	assert.Equal(t, models.OrderLine{Task: *byDefault, Price: 122, Quantity: 1, TaxRate: taxRates[0], Net: 100, Tax: 22, Gross: 122}, totals.Lines[2])
+	assert.Equal(t, 450.0, totals.Net)
+	assert.Equal(t, 42.0, totals.Tax)
+	assert.Equal(t, 492.0, totals.Gross)
+}
+
+func TestCalculateTotals_PricesExcludeTax(t *testing.T) {
+	service, _ := newTaxOrderService(t, false)
+	task := &models.Task{ID: uuid.New(), Name: "Task", PricePerSingle: 33.33, Category: 1, TaxRate: "vat22"}
+
+	totals, err := service.CalculateTotals([]models.OrderedTask{{Task: task, Quantity: 3}})
+
+	assert.NoError(t, err)
+	assert.False(t, totals.PricesIncludeTax)
+	assert.Equal(t, 99.99, totals.Net)
+	assert.Equal(t, 22.0, totals.Tax)
+	assert.Equal(t, 121.99, totals.Gross)
+}
+
+func TestCalculateTotals_UnknownRate(t *testing.T) {
+	service, _ := newTaxOrderService(t, true)
+	task := &models.Task{ID: uuid.New(), Name: "Task", PricePerSingle: 122, Category: 1, TaxRate: "removed"}
+
+	totals, err := service.CalculateTotals([]models.OrderedTask{{Task: task, Quantity: 1}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "vat22", totals.Lines[0].TaxRate.Code)
+	assert.Equal(t, 22.0, totals.Tax)
+}
+
+func TestCalculateTotals_NoRates(t *testing.T) {
+	service, _ := newArchiveOrderService(t)
+	task := &models.Task{ID: uuid.New(), Name: "Task", PricePerSingle: 100, Category: 1}
+
+	totals, err := service.CalculateTotals([]models.OrderedTask{{Task: task, Quantity: 2}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200.0, totals.Net)
+	assert.Equal(t, 0.0, totals.Tax)
+	assert.Equal(t, 200.0, totals.Gross)
+}
+
+func TestGetTotalPrice_TaxAdded(t *testing.T) {
+	service, mocks := newTaxOrderService(t, false)
+	order := &models.Order{ID: uuid.New(), CreationDate: time.Now()}
+	task := models.Task{ID: uuid.New(), Name: "Task", PricePerSingle: 100, Category: 1, TaxRate: "vat10"}
+
+	mocks.orderRepository.EXPECT().GetOrderByID(order.ID).Return(order, nil)
+	mocks.orderRepository.EXPECT().GetTasksInOrder(order.ID).Return([]models.Task{task}, nil)
+	mocks.taskRepository.EXPECT().GetPrices(task.ID).Return(nil, nil)
+	mocks.orderRepository.EXPECT().GetTaskQuantity(order.ID, task.ID).Return(2, nil)
+
+	total, err := service.GetTotalPrice(order.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 220.0, total)
+}
@@ -0,0 +1,216 @@
+package unit_services
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"lab3/internal/models"
+	"lab3/internal/repository/repository_errors"
+	services "lab3/internal/services"
+	"lab3/internal/services/service_errors"
+	"testing"
+	"time"
+)
+
+// Mock repository
+type MockTwoFactorRepository struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorRepository) GetByWorkerID(workerID uuid.UUID) (*models.TwoFactor, error) {
+	args := m.Called(workerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TwoFactor), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) Save(twoFactor *models.TwoFactor) error {
+	args := m.Called(twoFactor)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) Delete(workerID uuid.UUID) error {
+	args := m.Called(workerID)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) MarkStepUsed(workerID uuid.UUID, step int64) error {
+	args := m.Called(workerID, step)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) ReplaceRecoveryCodes(workerID uuid.UUID, hashes []string) error {
+	args := m.Called(workerID, hashes)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) ConsumeRecoveryCode(workerID uuid.UUID, hash string) error {
+	args := m.Called(workerID, hash)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) CountRecoveryCodes(workerID uuid.UUID) (int, error) {
+	args := m.Called(workerID)
+	return args.Int(0), args.Error(1)
+}
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXP"
+
+func TestBeginEnrollment_NewSecret(t *testing.T) {
+	repository := new(MockTwoFactorRepository)
+	service := services.NewTwoFactorService(repository, "Test", log.New(io.Discard))
+
+	worker := &models.Worker{ID: uuid.New(), Email: "manager@gmail.com", Role: models.ManagerRole}
+	repository.On("GetByWorkerID", worker.ID).Return(nil, repository_errors.DoesNotExist)
+	repository.On("Save", mock.MatchedBy(func(twoFactor *models.TwoFactor) bool {
+		return twoFactor.WorkerID == worker.ID && !twoFactor.Enabled && twoFactor.Secret != ""
+	})).Return(nil)
+
+	enrollment, err := service.BeginEnrollment(worker)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, enrollment.Secret)
+	assert.Contains(t, enrollment.URL, "otpauth://totp/")
+	assert.Contains(t, enrollment.URL, "secret="+enrollment.Secret)
+	assert.NotEmpty(t, enrollment.QRCode)
+	repository.AssertExpectations(t)
+}
+
+func TestBeginEnrollment_KeepsPendingSecret(t *testing.T) {
+	repository := new(MockTwoFactorRepository)
+	service := services.NewTwoFactorService(repository, "Test", log.New(io.Discard))
+
+	worker := &models.Worker{ID: uuid.New(), Email: "manager@gmail.com"}
+	repository.On("GetByWorkerID", worker.ID).Return(&models.TwoFactor{WorkerID: worker.ID, Secret: testTOTPSecret}, nil)
+
+	enrollment, err := service.BeginEnrollment(worker)
+
+	assert.NoError(t, err)
+	assert.Equal(t, testTOTPSecret, enrollment.Secret)
+	repository.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestBeginEnrollment_AlreadyEnabled(t *testing.T) {
+	repository := new(MockTwoFactorRepository)
+	service := services.NewTwoFactorService(repository, "Test", log.New(io.Discard))
+
+	worker := &models.Worker{ID: uuid.New(), Email: "manager@gmail.com"}
+	repository.On("GetByWorkerID", worker.ID).Return(&models.TwoFactor{WorkerID: worker.ID, Secret: testTOTPSecret, Enabled: true}, nil)
+
+	_, err := service.BeginEnrollment(worker)
+
+	assert.ErrorIs(t, err, service_errors.TwoFactorAlreadyEnabled)
+}
+
+func TestConfirmEnrollment_Success(t *testing.T) {
+	repository := new(MockTwoFactorRepository)
+	service := services.NewTwoFactorService(repository, "Test", log.New(io.Discard))
+
+	workerID := uuid.New()
+	code, _ := totp.GenerateCode(testTOTPSecret, time.Now())
+	repository.On("GetByWorkerID", workerID).Return(&models.TwoFactor{WorkerID: workerID, Secret: testTOTPSecret}, nil)
+	repository.On("Save", mock.MatchedBy(func(twoFactor *models.TwoFactor) bool {
+		return twoFactor.Enabled && twoFactor.LastUsedStep > 0
+	})).Return(nil)
+	repository.On("ReplaceRecoveryCodes", workerID, mock.MatchedBy(func(hashes []string) bool {
+		return len(hashes) == 10
+	})).Return(nil)
+
+	codes, err := service.ConfirmEnrollment(workerID, code)
+
+	assert.NoError(t, err)
+	assert.Len(t, codes, 10)
+	repository.AssertExpectations(t)
+}
+
+func TestConfirmEnrollment_InvalidCode(t *testing.T) {
+	repository := new(MockTwoFactorRepository)
+	service := services.NewTwoFactorService(repository, "Test", log.New(io.Discard))
+
+	workerID := uuid.New()
+	repository.On("GetByWorkerID", workerID).Return(&models.TwoFactor{WorkerID: workerID, Secret: testTOTPSecret}, nil)
+
+	_, err := service.ConfirmEnrollment(workerID, "000000x")
+
+	assert.ErrorIs(t, err, service_errors.InvalidTwoFactorCode)
+	repository.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestVerify_TOTPCode(t *testing.T) {
+	repository := new(MockTwoFactorRepository)
+	service := services.NewTwoFactorService(repository, "Test", log.New(io.Discard))
+
+	workerID := uuid.New()
+	code, _ := totp.GenerateCode(testTOTPSecret, time.Now())
+	repository.On("GetByWorkerID", workerID).Return(&models.TwoFactor{WorkerID: workerID, Secret: testTOTPSecret, Enabled: true}, nil)
+	repository.On("MarkStepUsed", workerID, mock.Anything).Return(nil)
+
+	err := service.Verify(workerID, code)
+
+	assert.NoError(t, err)
+	repository.AssertExpectations(t)
+}
+
+func TestVerify_ReplayedCode(t *testing.T) {
+	repository := new(MockTwoFactorRepository)
+	service := services.NewTwoFactorService(repository, "Test", log.New(io.Discard))
+
+	workerID := uuid.New()
+	code, _ := totp.GenerateCode(testTOTPSecret, time.Now())
+	repository.On("GetByWorkerID", workerID).Return(&models.TwoFactor{WorkerID: workerID, Secret: testTOTPSecret, Enabled: true}, nil)
+	repository.On("MarkStepUsed", workerID, mock.Anything).Return(repository_errors.DoesNotExist)
+
+	err := service.Verify(workerID, code)
+
+	assert.ErrorIs(t, err, service_errors.InvalidTwoFactorCode)
+}
+
+func TestVerify_RecoveryCode(t *testing.T) {
+	repository := new(MockTwoFactorRepository)
+	service := services.NewTwoFactorService(repository, "Test", log.New(io.Discard))
+
+	workerID := uuid.New()
+	repository.On("GetByWorkerID", workerID).Return(&models.TwoFactor{WorkerID: workerID, Secret: testTOTPSecret, Enabled: true}, nil)
+	repository.On("ConsumeRecoveryCode", workerID, mock.Anything).Return(nil).Once()
+
+	err := service.Verify(workerID, "abcde-fghjk")
+
+	assert.NoError(t, err)
+	repository.AssertExpectations(t)
+}
+
+func TestVerify_InvalidRecoveryCode(t *testing.T) {
+	repository := new(MockTwoFactorRepository)
+	service := services.NewTwoFactorService(repository, "Test", log.New(io.Discard))
+
+	workerID := uuid.New()
+	repository.On("GetByWorkerID", workerID).Return(&models.TwoFactor{WorkerID: workerID, Secret: testTOTPSecret, Enabled: true}, nil)
+	repository.On("ConsumeRecoveryCode", workerID, mock.Anything).Return(repository_errors.DoesNotExist)
+
+	err := service.Verify(workerID, "abcde-fghjk")
+
+	assert.ErrorIs(t, err, service_errors.InvalidTwoFactorCode)
+}
+
+func TestVerify_NotEnabled(t *testing.T) {
+	repository := new(MockTwoFactorRepository)
+	service := services.NewTwoFactorService(repository, "Test", log.New(io.Discard))
+
+	workerID := uuid.New()
+	repository.On("GetByWorkerID", workerID).Return(&models.TwoFactor{WorkerID: workerID, Secret: testTOTPSecret}, nil)
+
+	err := service.Verify(workerID, "123456")
+
+	assert.ErrorIs(t, err, service_errors.TwoFactorNotEnabled)
+}
+
+func TestIsRequired_Manager(t *testing.T) {
+	service := services.NewTwoFactorService(new(MockTwoFactorRepository), "Test", log.New(io.Discard))
+
+	assert.True(t, service.IsRequired(&models.Worker{Role: models.ManagerRole}))
+	assert.False(t, service.IsRequired(&models.Worker{Role: models.MasterRole}))
+}
@@ -0,0 +1,7 @@
+package utils
+
+import "strconv"
+
+func FormatMoney(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64) // 1234.50
+}